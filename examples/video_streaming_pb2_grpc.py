@@ -39,6 +39,11 @@ class ImageStub(object):
                 request_serializer=video__streaming__pb2.AnnotateRequest.SerializeToString,
                 response_deserializer=video__streaming__pb2.AnnotateResponse.FromString,
                 )
+        self.AnnotateStream = channel.stream_stream(
+                '/chrys.cloud.videostreaming.v1beta1.Image/AnnotateStream',
+                request_serializer=video__streaming__pb2.AnnotateRequest.SerializeToString,
+                response_deserializer=video__streaming__pb2.AnnotateAck.FromString,
+                )
         self.Proxy = channel.unary_unary(
                 '/chrys.cloud.videostreaming.v1beta1.Image/Proxy',
                 request_serializer=video__streaming__pb2.ProxyRequest.SerializeToString,
@@ -54,6 +59,16 @@ class ImageStub(object):
                 request_serializer=video__streaming__pb2.SystemTimeRequest.SerializeToString,
                 response_deserializer=video__streaming__pb2.SystemTimeResponse.FromString,
                 )
+        self.SearchSignatures = channel.unary_unary(
+                '/chrys.cloud.videostreaming.v1beta1.Image/SearchSignatures',
+                request_serializer=video__streaming__pb2.SearchSignaturesRequest.SerializeToString,
+                response_deserializer=video__streaming__pb2.SearchSignaturesResponse.FromString,
+                )
+        self.DebugOverlay = channel.unary_unary(
+                '/chrys.cloud.videostreaming.v1beta1.Image/DebugOverlay',
+                request_serializer=video__streaming__pb2.DebugOverlayRequest.SerializeToString,
+                response_deserializer=video__streaming__pb2.DebugOverlayResponse.FromString,
+                )
 
 
 class ImageServicer(object):
@@ -89,6 +104,12 @@ class ImageServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def AnnotateStream(self, request_iterator, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def Proxy(self, request, context):
         """Missing associated documentation comment in .proto file."""
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
@@ -107,6 +128,18 @@ class ImageServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def SearchSignatures(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DebugOverlay(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_ImageServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -135,6 +168,11 @@ def add_ImageServicer_to_server(servicer, server):
                     request_deserializer=video__streaming__pb2.AnnotateRequest.FromString,
                     response_serializer=video__streaming__pb2.AnnotateResponse.SerializeToString,
             ),
+            'AnnotateStream': grpc.stream_stream_rpc_method_handler(
+                    servicer.AnnotateStream,
+                    request_deserializer=video__streaming__pb2.AnnotateRequest.FromString,
+                    response_serializer=video__streaming__pb2.AnnotateAck.SerializeToString,
+            ),
             'Proxy': grpc.unary_unary_rpc_method_handler(
                     servicer.Proxy,
                     request_deserializer=video__streaming__pb2.ProxyRequest.FromString,
@@ -150,6 +188,16 @@ def add_ImageServicer_to_server(servicer, server):
                     request_deserializer=video__streaming__pb2.SystemTimeRequest.FromString,
                     response_serializer=video__streaming__pb2.SystemTimeResponse.SerializeToString,
             ),
+            'SearchSignatures': grpc.unary_unary_rpc_method_handler(
+                    servicer.SearchSignatures,
+                    request_deserializer=video__streaming__pb2.SearchSignaturesRequest.FromString,
+                    response_serializer=video__streaming__pb2.SearchSignaturesResponse.SerializeToString,
+            ),
+            'DebugOverlay': grpc.unary_unary_rpc_method_handler(
+                    servicer.DebugOverlay,
+                    request_deserializer=video__streaming__pb2.DebugOverlayRequest.FromString,
+                    response_serializer=video__streaming__pb2.DebugOverlayResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'chrys.cloud.videostreaming.v1beta1.Image', rpc_method_handlers)
@@ -245,6 +293,23 @@ class Image(object):
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
 
+    @staticmethod
+    def AnnotateStream(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_stream(request_iterator, target, '/chrys.cloud.videostreaming.v1beta1.Image/AnnotateStream',
+            video__streaming__pb2.AnnotateRequest.SerializeToString,
+            video__streaming__pb2.AnnotateAck.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
     @staticmethod
     def Proxy(request,
             target,
@@ -295,3 +360,37 @@ class Image(object):
             video__streaming__pb2.SystemTimeResponse.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def SearchSignatures(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/chrys.cloud.videostreaming.v1beta1.Image/SearchSignatures',
+            video__streaming__pb2.SearchSignaturesRequest.SerializeToString,
+            video__streaming__pb2.SearchSignaturesResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def DebugOverlay(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/chrys.cloud.videostreaming.v1beta1.Image/DebugOverlay',
+            video__streaming__pb2.DebugOverlayRequest.SerializeToString,
+            video__streaming__pb2.DebugOverlayResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -19,12 +19,50 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   syntax='proto3',
   serialized_options=None,
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x15video_streaming.proto\x12\"chrys.cloud.videostreaming.v1beta1\"\xd2\x06\n\x0f\x41nnotateRequest\x12\x13\n\x0b\x64\x65vice_name\x18\x01 \x01(\t\x12\x18\n\x10remote_stream_id\x18\x02 \x01(\t\x12\x0c\n\x04type\x18\x03 \x01(\t\x12\x17\n\x0fstart_timestamp\x18\x04 \x01(\x03\x12\x15\n\rend_timestamp\x18\x05 \x01(\x03\x12\x13\n\x0bobject_type\x18\x06 \x01(\t\x12\x11\n\tobject_id\x18\x07 \x01(\t\x12\x1a\n\x12object_tracking_id\x18\x08 \x01(\t\x12\x12\n\nconfidence\x18\t \x01(\x01\x12J\n\x12object_bouding_box\x18\n \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.BoudingBox\x12>\n\x08location\x18\x0b \x01(\x0b\x32,.chrys.cloud.videostreaming.v1beta1.Location\x12I\n\x11object_coordinate\x18\x0c \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12<\n\x04mask\x18\r \x03(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12\x18\n\x10object_signature\x18\x0e \x03(\x01\x12\x10\n\x08ml_model\x18\x0f \x01(\t\x12\x18\n\x10ml_model_version\x18\x10 \x01(\t\x12\r\n\x05width\x18\x11 \x01(\x05\x12\x0e\n\x06height\x18\x12 \x01(\x05\x12\x13\n\x0bis_keyframe\x18\x13 \x01(\x08\x12\x12\n\nvideo_type\x18\x14 \x01(\t\x12\x18\n\x10offset_timestamp\x18\x15 \x01(\x03\x12\x17\n\x0foffset_duration\x18\x16 \x01(\x03\x12\x17\n\x0foffset_frame_id\x18\x17 \x01(\x03\x12\x18\n\x10offset_packet_id\x18\x18 \x01(\x03\x12\x15\n\rcustom_meta_1\x18\x19 \x01(\t\x12\x15\n\rcustom_meta_2\x18\x1a \x01(\t\x12\x15\n\rcustom_meta_3\x18\x1b \x01(\t\x12\x15\n\rcustom_meta_4\x18\x1c \x01(\t\x12\x15\n\rcustom_meta_5\x18\x1d \x01(\t\"h\n\x10\x41nnotateResponse\x12\x13\n\x0b\x64\x65vice_name\x18\x01 \x01(\t\x12\x18\n\x10remote_stream_id\x18\x02 \x01(\t\x12\x0c\n\x04type\x18\x03 \x01(\t\x12\x17\n\x0fstart_timestamp\x18\x04 \x01(\x03\"$\n\x08Location\x12\x0b\n\x03lat\x18\x01 \x01(\x01\x12\x0b\n\x03lon\x18\x02 \x01(\x01\"-\n\nCoordinate\x12\t\n\x01x\x18\x01 \x01(\x01\x12\t\n\x01y\x18\x02 \x01(\x01\x12\t\n\x01z\x18\x03 \x01(\x01\"F\n\nBoudingBox\x12\x0b\n\x03top\x18\x01 \x01(\x05\x12\x0c\n\x04left\x18\x02 \x01(\x05\x12\r\n\x05width\x18\x03 \x01(\x05\x12\x0e\n\x06height\x18\x04 \x01(\x05\"p\n\nShapeProto\x12?\n\x03\x64im\x18\x02 \x03(\x0b\x32\x32.chrys.cloud.videostreaming.v1beta1.ShapeProto.Dim\x1a!\n\x03\x44im\x12\x0c\n\x04size\x18\x01 \x01(\x03\x12\x0c\n\x04name\x18\x02 \x01(\t\"\xe2\x02\n\nVideoFrame\x12\r\n\x05width\x18\x01 \x01(\x03\x12\x0e\n\x06height\x18\x02 \x01(\x03\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\x0c\x12\x11\n\ttimestamp\x18\x04 \x01(\x03\x12\x13\n\x0bis_keyframe\x18\x05 \x01(\x08\x12\x0b\n\x03pts\x18\x06 \x01(\x03\x12\x0b\n\x03\x64ts\x18\x07 \x01(\x03\x12\x12\n\nframe_type\x18\x08 \x01(\t\x12\x12\n\nis_corrupt\x18\t \x01(\x08\x12\x11\n\ttime_base\x18\n \x01(\x01\x12=\n\x05shape\x18\x0b \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.ShapeProto\x12\x11\n\tdevice_id\x18\x0c \x01(\t\x12\x0e\n\x06packet\x18\r \x01(\x03\x12\x10\n\x08keyframe\x18\x0e \x01(\x03\x12\x11\n\textradata\x18\x0f \x01(\x0c\x12\x12\n\ncodec_name\x18\x10 \x01(\t\x12\x0f\n\x07pix_fmt\x18\x11 \x01(\t\">\n\x11VideoFrameRequest\x12\x16\n\x0ekey_frame_only\x18\x01 \x01(\x08\x12\x11\n\tdevice_id\x18\x02 \x01(\t\"\\\n\x19VideoFrameBufferedRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x16\n\x0etimestamp_from\x18\x02 \x01(\x03\x12\x14\n\x0ctimestamp_to\x18\x03 \x01(\x03\"\xde\x01\n\nListStream\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0e\n\x06status\x18\x02 \x01(\t\x12\x16\n\x0e\x66\x61iling_streak\x18\x03 \x01(\x03\x12\x15\n\rhealth_status\x18\x04 \x01(\t\x12\x0c\n\x04\x64\x65\x61\x64\x18\x05 \x01(\x08\x12\x11\n\texit_code\x18\x06 \x01(\x03\x12\x0b\n\x03pid\x18\x07 \x01(\x05\x12\x0f\n\x07running\x18\x08 \x01(\x08\x12\x0e\n\x06paused\x18\t \x01(\x08\x12\x12\n\nrestarting\x18\n \x01(\x08\x12\x11\n\toomkilled\x18\x0b \x01(\x08\x12\r\n\x05\x65rror\x18\x0c \x01(\t\"\x13\n\x11ListStreamRequest\"6\n\x0cProxyRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x13\n\x0bpassthrough\x18\x02 \x01(\x08\"7\n\rProxyResponse\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x13\n\x0bpassthrough\x18\x02 \x01(\x08\"2\n\x0eStorageRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\r\n\x05start\x18\x02 \x01(\x08\"3\n\x0fStorageResponse\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\r\n\x05start\x18\x02 \x01(\x08\"\x88\x01\n\nVideoCodec\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\r\n\x05width\x18\x02 \x01(\x05\x12\x0e\n\x06height\x18\x03 \x01(\x05\x12\x0f\n\x07pix_fmt\x18\x04 \x01(\t\x12\x11\n\textradata\x18\x05 \x01(\x0c\x12\x16\n\x0e\x65xtradata_size\x18\x06 \x01(\x05\x12\x11\n\tlong_name\x18\x07 \x01(\t\"&\n\x11VideoProbeRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\"\x9a\x01\n\x12VideoProbeResponse\x12\x43\n\x0bvideo_codec\x18\x01 \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.VideoCodec\x12?\n\x06\x62uffer\x18\x02 \x01(\x0b\x32/.chrys.cloud.videostreaming.v1beta1.VideoBuffer\"v\n\x0bVideoBuffer\x12\x12\n\nstart_time\x18\x01 \x01(\x03\x12\x10\n\x08\x65nd_time\x18\x02 \x01(\x03\x12\x18\n\x10\x64uration_seconds\x18\x03 \x01(\x03\x12\x17\n\x0f\x61pproximate_fps\x18\x04 \x01(\x05\x12\x0e\n\x06\x66rames\x18\x05 \x01(\x03\"*\n\x12SystemTimeResponse\x12\x14\n\x0c\x63urrent_time\x18\x01 \x01(\x03\"\x13\n\x11SystemTimeRequest2\xe5\x07\n\x05Image\x12{\n\x10VideoLatestImage\x12\x35.chrys.cloud.videostreaming.v1beta1.VideoFrameRequest\x1a..chrys.cloud.videostreaming.v1beta1.VideoFrame\"\x00\x12\x87\x01\n\x12VideoBufferedImage\x12=.chrys.cloud.videostreaming.v1beta1.VideoFrameBufferedRequest\x1a..chrys.cloud.videostreaming.v1beta1.VideoFrame\"\x00\x30\x01\x12}\n\nVideoProbe\x12\x35.chrys.cloud.videostreaming.v1beta1.VideoProbeRequest\x1a\x36.chrys.cloud.videostreaming.v1beta1.VideoProbeResponse\"\x00\x12x\n\x0bListStreams\x12\x35.chrys.cloud.videostreaming.v1beta1.ListStreamRequest\x1a..chrys.cloud.videostreaming.v1beta1.ListStream\"\x00\x30\x01\x12w\n\x08\x41nnotate\x12\x33.chrys.cloud.videostreaming.v1beta1.AnnotateRequest\x1a\x34.chrys.cloud.videostreaming.v1beta1.AnnotateResponse\"\x00\x12n\n\x05Proxy\x12\x30.chrys.cloud.videostreaming.v1beta1.ProxyRequest\x1a\x31.chrys.cloud.videostreaming.v1beta1.ProxyResponse\"\x00\x12t\n\x07Storage\x12\x32.chrys.cloud.videostreaming.v1beta1.StorageRequest\x1a\x33.chrys.cloud.videostreaming.v1beta1.StorageResponse\"\x00\x12}\n\nSystemTime\x12\x35.chrys.cloud.videostreaming.v1beta1.SystemTimeRequest\x1a\x36.chrys.cloud.videostreaming.v1beta1.SystemTimeResponse\"\x00\x62\x06proto3'
+  serialized_pb=b'\n\x15video_streaming.proto\x12\"chrys.cloud.videostreaming.v1beta1\"\xd0\t\n\x0f\x41nnotateRequest\x12\x13\n\x0b\x64\x65vice_name\x18\x01 \x01(\t\x12\x18\n\x10remote_stream_id\x18\x02 \x01(\t\x12\x0c\n\x04type\x18\x03 \x01(\t\x12\x17\n\x0fstart_timestamp\x18\x04 \x01(\x03\x12\x15\n\rend_timestamp\x18\x05 \x01(\x03\x12\x13\n\x0bobject_type\x18\x06 \x01(\t\x12\x11\n\tobject_id\x18\x07 \x01(\t\x12\x1a\n\x12object_tracking_id\x18\x08 \x01(\t\x12\x12\n\nconfidence\x18\t \x01(\x01\x12J\n\x12object_bouding_box\x18\n \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.BoudingBox\x12>\n\x08location\x18\x0b \x01(\x0b\x32,.chrys.cloud.videostreaming.v1beta1.Location\x12I\n\x11object_coordinate\x18\x0c \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12<\n\x04mask\x18\r \x03(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12\x18\n\x10object_signature\x18\x0e \x03(\x01\x12\x10\n\x08ml_model\x18\x0f \x01(\t\x12\x18\n\x10ml_model_version\x18\x10 \x01(\t\x12\r\n\x05width\x18\x11 \x01(\x05\x12\x0e\n\x06height\x18\x12 \x01(\x05\x12\x13\n\x0bis_keyframe\x18\x13 \x01(\x08\x12\x12\n\nvideo_type\x18\x14 \x01(\t\x12\x18\n\x10offset_timestamp\x18\x15 \x01(\x03\x12\x17\n\x0foffset_duration\x18\x16 \x01(\x03\x12\x17\n\x0foffset_frame_id\x18\x17 \x01(\x03\x12\x18\n\x10offset_packet_id\x18\x18 \x01(\x03\x12\x15\n\rcustom_meta_1\x18\x19 \x01(\t\x12\x15\n\rcustom_meta_2\x18\x1a \x01(\t\x12\x15\n\rcustom_meta_3\x18\x1b \x01(\t\x12\x15\n\rcustom_meta_4\x18\x1c \x01(\t\x12\x15\n\rcustom_meta_5\x18\x1d \x01(\t\x12\x43\n\x0bobject_path\x18\x1e \x03(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12\x10\n\x08is_track\x18\x1f \x01(\x08\x12\x10\n\x08sequence\x18  \x01(\x03\x12W\n\nattributes\x18! \x03(\x0b\x32\x43.chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry\x12\x43\n\x07objects\x18\" \x03(\x0b\x32\x32.chrys.cloud.videostreaming.v1beta1.DetectedObject\x12\x42\n\x06labels\x18# \x03(\x0b\x32\x32.chrys.cloud.videostreaming.v1beta1.Classification\x1a\x31\n\x0f\x41ttributesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xa6\x04\n\x0e\x44\x65tectedObject\x12\x13\n\x0bobject_type\x18\x01 \x01(\t\x12\x11\n\tobject_id\x18\x02 \x01(\t\x12\x1a\n\x12object_tracking_id\x18\x03 \x01(\t\x12\x12\n\nconfidence\x18\x04 \x01(\x01\x12J\n\x12object_bouding_box\x18\x05 \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.BoudingBox\x12I\n\x11object_coordinate\x18\x06 \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12<\n\x04mask\x18\x07 \x03(\x0b\x32..chrys.cloud.videostreaming.v1beta1.Coordinate\x12\x18\n\x10object_signature\x18\x08 \x03(\x01\x12V\n\nattributes\x18\t \x03(\x0b\x32\x42.chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry\x12\x42\n\x06labels\x18\n \x03(\x0b\x32\x32.chrys.cloud.videostreaming.v1beta1.Classification\x1a\x31\n\x0f\x41ttributesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"3\n\x0e\x43lassification\x12\r\n\x05label\x18\x01 \x01(\t\x12\x12\n\nconfidence\x18\x02 \x01(\x01\"h\n\x10\x41nnotateResponse\x12\x13\n\x0b\x64\x65vice_name\x18\x01 \x01(\t\x12\x18\n\x10remote_stream_id\x18\x02 \x01(\t\x12\x0c\n\x04type\x18\x03 \x01(\t\x12\x17\n\x0fstart_timestamp\x18\x04 \x01(\x03\"E\n\x08Location\x12\x0b\n\x03lat\x18\x01 \x01(\x01\x12\x0b\n\x03lon\x18\x02 \x01(\x01\x12\x0f\n\x07heading\x18\x03 \x01(\x01\x12\x0e\n\x06height\x18\x04 \x01(\x01\"-\n\nCoordinate\x12\t\n\x01x\x18\x01 \x01(\x01\x12\t\n\x01y\x18\x02 \x01(\x01\x12\t\n\x01z\x18\x03 \x01(\x01\"F\n\nBoudingBox\x12\x0b\n\x03top\x18\x01 \x01(\x05\x12\x0c\n\x04left\x18\x02 \x01(\x05\x12\r\n\x05width\x18\x03 \x01(\x05\x12\x0e\n\x06height\x18\x04 \x01(\x05\"p\n\nShapeProto\x12?\n\x03\x64im\x18\x02 \x03(\x0b\x32\x32.chrys.cloud.videostreaming.v1beta1.ShapeProto.Dim\x1a!\n\x03\x44im\x12\x0c\n\x04size\x18\x01 \x01(\x03\x12\x0c\n\x04name\x18\x02 \x01(\t\"\xe2\x02\n\nVideoFrame\x12\r\n\x05width\x18\x01 \x01(\x03\x12\x0e\n\x06height\x18\x02 \x01(\x03\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\x0c\x12\x11\n\ttimestamp\x18\x04 \x01(\x03\x12\x13\n\x0bis_keyframe\x18\x05 \x01(\x08\x12\x0b\n\x03pts\x18\x06 \x01(\x03\x12\x0b\n\x03\x64ts\x18\x07 \x01(\x03\x12\x12\n\nframe_type\x18\x08 \x01(\t\x12\x12\n\nis_corrupt\x18\t \x01(\x08\x12\x11\n\ttime_base\x18\n \x01(\x01\x12=\n\x05shape\x18\x0b \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.ShapeProto\x12\x11\n\tdevice_id\x18\x0c \x01(\t\x12\x0e\n\x06packet\x18\r \x01(\x03\x12\x10\n\x08keyframe\x18\x0e \x01(\x03\x12\x11\n\textradata\x18\x0f \x01(\x0c\x12\x12\n\ncodec_name\x18\x10 \x01(\t\x12\x0f\n\x07pix_fmt\x18\x11 \x01(\t\">\n\x11VideoFrameRequest\x12\x16\n\x0ekey_frame_only\x18\x01 \x01(\x08\x12\x11\n\tdevice_id\x18\x02 \x01(\t\"\\\n\x19VideoFrameBufferedRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x16\n\x0etimestamp_from\x18\x02 \x01(\x03\x12\x14\n\x0ctimestamp_to\x18\x03 \x01(\x03\"\xd4\x03\n\nListStream\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0e\n\x06status\x18\x02 \x01(\t\x12\x16\n\x0e\x66\x61iling_streak\x18\x03 \x01(\x03\x12\x15\n\rhealth_status\x18\x04 \x01(\t\x12\x0c\n\x04\x64\x65\x61\x64\x18\x05 \x01(\x08\x12\x11\n\texit_code\x18\x06 \x01(\x03\x12\x0b\n\x03pid\x18\x07 \x01(\x05\x12\x0f\n\x07running\x18\x08 \x01(\x08\x12\x0e\n\x06paused\x18\t \x01(\x08\x12\x12\n\nrestarting\x18\n \x01(\x08\x12\x11\n\toomkilled\x18\x0b \x01(\x08\x12\r\n\x05\x65rror\x18\x0c \x01(\t\x12\x15\n\rstream_health\x18\r \x01(\t\x12\r\n\x05group\x18\x0e \x01(\t\x12J\n\x06labels\x18\x0f \x03(\x0b\x32:.chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry\x12\x13\n\x0b\x64\x65scription\x18\x10 \x01(\t\x12>\n\x08location\x18\x11 \x01(\x0b\x32,.chrys.cloud.videostreaming.v1beta1.Location\x1a-\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xa4\x01\n\x11ListStreamRequest\x12\r\n\x05group\x18\x01 \x01(\t\x12Q\n\x06labels\x18\x02 \x03(\x0b\x32\x41.chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry\x1a-\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"6\n\x0cProxyRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x13\n\x0bpassthrough\x18\x02 \x01(\x08\"7\n\rProxyResponse\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x13\n\x0bpassthrough\x18\x02 \x01(\x08\"2\n\x0eStorageRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\r\n\x05start\x18\x02 \x01(\x08\"3\n\x0fStorageResponse\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\r\n\x05start\x18\x02 \x01(\x08\"\x88\x01\n\nVideoCodec\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\r\n\x05width\x18\x02 \x01(\x05\x12\x0e\n\x06height\x18\x03 \x01(\x05\x12\x0f\n\x07pix_fmt\x18\x04 \x01(\t\x12\x11\n\textradata\x18\x05 \x01(\x0c\x12\x16\n\x0e\x65xtradata_size\x18\x06 \x01(\x05\x12\x11\n\tlong_name\x18\x07 \x01(\t\"&\n\x11VideoProbeRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\"\x9a\x01\n\x12VideoProbeResponse\x12\x43\n\x0bvideo_codec\x18\x01 \x01(\x0b\x32..chrys.cloud.videostreaming.v1beta1.VideoCodec\x12?\n\x06\x62uffer\x18\x02 \x01(\x0b\x32/.chrys.cloud.videostreaming.v1beta1.VideoBuffer\"v\n\x0bVideoBuffer\x12\x12\n\nstart_time\x18\x01 \x01(\x03\x12\x10\n\x08\x65nd_time\x18\x02 \x01(\x03\x12\x18\n\x10\x64uration_seconds\x18\x03 \x01(\x03\x12\x17\n\x0f\x61pproximate_fps\x18\x04 \x01(\x05\x12\x0e\n\x06\x66rames\x18\x05 \x01(\x03\"\x9c\x01\n\x17SearchSignaturesRequest\x12\x0e\n\x06vector\x18\x01 \x03(\x01\x12\t\n\x01k\x18\x02 \x01(\x05\x12\x13\n\x0b\x64\x65vice_name\x18\x03 \x01(\t\x12\x13\n\x0bobject_type\x18\x04 \x01(\t\x12\x16\n\x0etimestamp_from\x18\x05 \x01(\x03\x12\x14\n\x0ctimestamp_to\x18\x06 \x01(\x03\x12\x0e\n\x06metric\x18\x07 \x01(\t\"k\n\x0eSignatureMatch\x12\x10\n\x08\x64istance\x18\x01 \x01(\x01\x12G\n\nannotation\x18\x02 \x01(\x0b\x32\x33.chrys.cloud.videostreaming.v1beta1.AnnotateRequest\"_\n\x18SearchSignaturesResponse\x12\x43\n\x07matches\x18\x01 \x03(\x0b\x32\x32.chrys.cloud.videostreaming.v1beta1.SignatureMatch\"Q\n\x13\x44\x65\x62ugOverlayRequest\x12\x11\n\tdevice_id\x18\x01 \x01(\t\x12\x11\n\ttimestamp\x18\x02 \x01(\x03\x12\x14\n\x0ctolerance_ms\x18\x03 \x01(\x03\"\xa7\x01\n\x14\x44\x65\x62ugOverlayResponse\x12\r\n\x05image\x18\x01 \x01(\x0c\x12\x17\n\x0f\x66rame_timestamp\x18\x02 \x01(\x03\x12\r\n\x05width\x18\x03 \x01(\x03\x12\x0e\n\x06height\x18\x04 \x01(\x03\x12H\n\x0b\x61nnotations\x18\x05 \x03(\x0b\x32\x33.chrys.cloud.videostreaming.v1beta1.AnnotateRequest\"Y\n\x11\x41nnotateRejection\x12\x10\n\x08sequence\x18\x01 \x01(\x03\x12\x13\n\x0b\x64\x65vice_name\x18\x02 \x01(\t\x12\x0c\n\x04\x63ode\x18\x03 \x01(\x05\x12\x0f\n\x07message\x18\x04 \x01(\t\"|\n\x0b\x41nnotateAck\x12\x10\n\x08sequence\x18\x01 \x01(\x03\x12\x10\n\x08\x61\x63\x63\x65pted\x18\x02 \x01(\x05\x12I\n\nrejections\x18\x03 \x03(\x0b\x32\x35.chrys.cloud.videostreaming.v1beta1.AnnotateRejection\"*\n\x12SystemTimeResponse\x12\x14\n\x0c\x63urrent_time\x18\x01 \x01(\x03\"\x13\n\x11SystemTimeRequest2\xfb\n\n\x05Image\x12{\n\x10VideoLatestImage\x12\x35.chrys.cloud.videostreaming.v1beta1.VideoFrameRequest\x1a..chrys.cloud.videostreaming.v1beta1.VideoFrame\"\x00\x12\x87\x01\n\x12VideoBufferedImage\x12=.chrys.cloud.videostreaming.v1beta1.VideoFrameBufferedRequest\x1a..chrys.cloud.videostreaming.v1beta1.VideoFrame\"\x00\x30\x01\x12}\n\nVideoProbe\x12\x35.chrys.cloud.videostreaming.v1beta1.VideoProbeRequest\x1a\x36.chrys.cloud.videostreaming.v1beta1.VideoProbeResponse\"\x00\x12x\n\x0bListStreams\x12\x35.chrys.cloud.videostreaming.v1beta1.ListStreamRequest\x1a..chrys.cloud.videostreaming.v1beta1.ListStream\"\x00\x30\x01\x12w\n\x08\x41nnotate\x12\x33.chrys.cloud.videostreaming.v1beta1.AnnotateRequest\x1a\x34.chrys.cloud.videostreaming.v1beta1.AnnotateResponse\"\x00\x12|\n\x0e\x41nnotateStream\x12\x33.chrys.cloud.videostreaming.v1beta1.AnnotateRequest\x1a/.chrys.cloud.videostreaming.v1beta1.AnnotateAck\"\x00(\x01\x30\x01\x12n\n\x05Proxy\x12\x30.chrys.cloud.videostreaming.v1beta1.ProxyRequest\x1a\x31.chrys.cloud.videostreaming.v1beta1.ProxyResponse\"\x00\x12t\n\x07Storage\x12\x32.chrys.cloud.videostreaming.v1beta1.StorageRequest\x1a\x33.chrys.cloud.videostreaming.v1beta1.StorageResponse\"\x00\x12}\n\nSystemTime\x12\x35.chrys.cloud.videostreaming.v1beta1.SystemTimeRequest\x1a\x36.chrys.cloud.videostreaming.v1beta1.SystemTimeResponse\"\x00\x12\x8f\x01\n\x10SearchSignatures\x12;.chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest\x1a<.chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse\"\x00\x12\x83\x01\n\x0c\x44\x65\x62ugOverlay\x12\x37.chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest\x1a\x38.chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse\"\x00\x62\x06proto3'
 )
 
 
 
 
+_ANNOTATEREQUEST_ATTRIBUTESENTRY = _descriptor.Descriptor(
+  name='AttributesEntry',
+  full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='key', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry.key', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry.value', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=b'8\001',
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1245,
+  serialized_end=1294,
+)
+
 _ANNOTATEREQUEST = _descriptor.Descriptor(
   name='AnnotateRequest',
   full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest',
@@ -236,10 +274,52 @@ _ANNOTATEREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_path', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.object_path', index=29,
+      number=30, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='is_track', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.is_track', index=30,
+      number=31, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='sequence', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.sequence', index=31,
+      number=32, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='attributes', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.attributes', index=32,
+      number=33, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='objects', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.objects', index=33,
+      number=34, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='labels', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRequest.labels', index=34,
+      number=35, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
-  nested_types=[],
+  nested_types=[_ANNOTATEREQUEST_ATTRIBUTESENTRY, ],
   enum_types=[
   ],
   serialized_options=None,
@@ -249,7 +329,179 @@ _ANNOTATEREQUEST = _descriptor.Descriptor(
   oneofs=[
   ],
   serialized_start=62,
-  serialized_end=912,
+  serialized_end=1294,
+)
+
+
+_DETECTEDOBJECT_ATTRIBUTESENTRY = _descriptor.Descriptor(
+  name='AttributesEntry',
+  full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='key', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry.key', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry.value', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=b'8\001',
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1245,
+  serialized_end=1294,
+)
+
+_DETECTEDOBJECT = _descriptor.Descriptor(
+  name='DetectedObject',
+  full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='object_type', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.object_type', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_id', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.object_id', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_tracking_id', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.object_tracking_id', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='confidence', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.confidence', index=3,
+      number=4, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_bouding_box', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.object_bouding_box', index=4,
+      number=5, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_coordinate', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.object_coordinate', index=5,
+      number=6, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='mask', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.mask', index=6,
+      number=7, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_signature', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.object_signature', index=7,
+      number=8, type=1, cpp_type=5, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='attributes', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.attributes', index=8,
+      number=9, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='labels', full_name='chrys.cloud.videostreaming.v1beta1.DetectedObject.labels', index=9,
+      number=10, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[_DETECTEDOBJECT_ATTRIBUTESENTRY, ],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1297,
+  serialized_end=1847,
+)
+
+
+_CLASSIFICATION = _descriptor.Descriptor(
+  name='Classification',
+  full_name='chrys.cloud.videostreaming.v1beta1.Classification',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='label', full_name='chrys.cloud.videostreaming.v1beta1.Classification.label', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='confidence', full_name='chrys.cloud.videostreaming.v1beta1.Classification.confidence', index=1,
+      number=2, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1849,
+  serialized_end=1900,
 )
 
 
@@ -301,8 +553,8 @@ _ANNOTATERESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=914,
-  serialized_end=1018,
+  serialized_start=1902,
+  serialized_end=2006,
 )
 
 
@@ -328,6 +580,20 @@ _LOCATION = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='heading', full_name='chrys.cloud.videostreaming.v1beta1.Location.heading', index=2,
+      number=3, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='height', full_name='chrys.cloud.videostreaming.v1beta1.Location.height', index=3,
+      number=4, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -340,8 +606,8 @@ _LOCATION = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1020,
-  serialized_end=1056,
+  serialized_start=2008,
+  serialized_end=2077,
 )
 
 
@@ -386,8 +652,8 @@ _COORDINATE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1058,
-  serialized_end=1103,
+  serialized_start=2079,
+  serialized_end=2124,
 )
 
 
@@ -439,8 +705,8 @@ _BOUDINGBOX = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1105,
-  serialized_end=1175,
+  serialized_start=2126,
+  serialized_end=2196,
 )
 
 
@@ -478,8 +744,8 @@ _SHAPEPROTO_DIM = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1256,
-  serialized_end=1289,
+  serialized_start=2277,
+  serialized_end=2310,
 )
 
 _SHAPEPROTO = _descriptor.Descriptor(
@@ -509,8 +775,8 @@ _SHAPEPROTO = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1177,
-  serialized_end=1289,
+  serialized_start=2198,
+  serialized_end=2310,
 )
 
 
@@ -653,8 +919,8 @@ _VIDEOFRAME = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1292,
-  serialized_end=1646,
+  serialized_start=2313,
+  serialized_end=2667,
 )
 
 
@@ -692,8 +958,8 @@ _VIDEOFRAMEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1648,
-  serialized_end=1710,
+  serialized_start=2669,
+  serialized_end=2731,
 )
 
 
@@ -738,11 +1004,49 @@ _VIDEOFRAMEBUFFEREDREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1712,
-  serialized_end=1804,
+  serialized_start=2733,
+  serialized_end=2825,
 )
 
 
+_LISTSTREAM_LABELSENTRY = _descriptor.Descriptor(
+  name='LabelsEntry',
+  full_name='chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='key', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry.key', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry.value', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=b'8\001',
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3251,
+  serialized_end=3296,
+)
+
 _LISTSTREAM = _descriptor.Descriptor(
   name='ListStream',
   full_name='chrys.cloud.videostreaming.v1beta1.ListStream',
@@ -835,45 +1139,132 @@ _LISTSTREAM = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
-  ],
-  extensions=[
-  ],
-  nested_types=[],
-  enum_types=[
-  ],
-  serialized_options=None,
-  is_extendable=False,
-  syntax='proto3',
-  extension_ranges=[],
-  oneofs=[
-  ],
-  serialized_start=1807,
-  serialized_end=2029,
-)
-
-
-_LISTSTREAMREQUEST = _descriptor.Descriptor(
-  name='ListStreamRequest',
-  full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest',
-  filename=None,
-  file=DESCRIPTOR,
+    _descriptor.FieldDescriptor(
+      name='stream_health', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.stream_health', index=12,
+      number=13, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='group', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.group', index=13,
+      number=14, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='labels', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.labels', index=14,
+      number=15, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='description', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.description', index=15,
+      number=16, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='location', full_name='chrys.cloud.videostreaming.v1beta1.ListStream.location', index=16,
+      number=17, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[_LISTSTREAM_LABELSENTRY, ],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2828,
+  serialized_end=3296,
+)
+
+
+_LISTSTREAMREQUEST_LABELSENTRY = _descriptor.Descriptor(
+  name='LabelsEntry',
+  full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry',
+  filename=None,
+  file=DESCRIPTOR,
   containing_type=None,
   create_key=_descriptor._internal_create_key,
   fields=[
+    _descriptor.FieldDescriptor(
+      name='key', full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry.key', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry.value', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
   nested_types=[],
   enum_types=[
   ],
+  serialized_options=b'8\001',
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3251,
+  serialized_end=3296,
+)
+
+_LISTSTREAMREQUEST = _descriptor.Descriptor(
+  name='ListStreamRequest',
+  full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='group', full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest.group', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='labels', full_name='chrys.cloud.videostreaming.v1beta1.ListStreamRequest.labels', index=1,
+      number=2, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[_LISTSTREAMREQUEST_LABELSENTRY, ],
+  enum_types=[
+  ],
   serialized_options=None,
   is_extendable=False,
   syntax='proto3',
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2031,
-  serialized_end=2050,
+  serialized_start=3299,
+  serialized_end=3463,
 )
 
 
@@ -911,8 +1302,8 @@ _PROXYREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2052,
-  serialized_end=2106,
+  serialized_start=3465,
+  serialized_end=3519,
 )
 
 
@@ -950,8 +1341,8 @@ _PROXYRESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2108,
-  serialized_end=2163,
+  serialized_start=3521,
+  serialized_end=3576,
 )
 
 
@@ -989,8 +1380,8 @@ _STORAGEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2165,
-  serialized_end=2215,
+  serialized_start=3578,
+  serialized_end=3628,
 )
 
 
@@ -1028,8 +1419,8 @@ _STORAGERESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2217,
-  serialized_end=2268,
+  serialized_start=3630,
+  serialized_end=3681,
 )
 
 
@@ -1102,8 +1493,8 @@ _VIDEOCODEC = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2271,
-  serialized_end=2407,
+  serialized_start=3684,
+  serialized_end=3820,
 )
 
 
@@ -1134,8 +1525,8 @@ _VIDEOPROBEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2409,
-  serialized_end=2447,
+  serialized_start=3822,
+  serialized_end=3860,
 )
 
 
@@ -1173,8 +1564,8 @@ _VIDEOPROBERESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2450,
-  serialized_end=2604,
+  serialized_start=3863,
+  serialized_end=4017,
 )
 
 
@@ -1233,8 +1624,358 @@ _VIDEOBUFFER = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2606,
-  serialized_end=2724,
+  serialized_start=4019,
+  serialized_end=4137,
+)
+
+
+_SEARCHSIGNATURESREQUEST = _descriptor.Descriptor(
+  name='SearchSignaturesRequest',
+  full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='vector', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.vector', index=0,
+      number=1, type=1, cpp_type=5, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='k', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.k', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='device_name', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.device_name', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='object_type', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.object_type', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='timestamp_from', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.timestamp_from', index=4,
+      number=5, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='timestamp_to', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.timestamp_to', index=5,
+      number=6, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='metric', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest.metric', index=6,
+      number=7, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4140,
+  serialized_end=4296,
+)
+
+
+_SIGNATUREMATCH = _descriptor.Descriptor(
+  name='SignatureMatch',
+  full_name='chrys.cloud.videostreaming.v1beta1.SignatureMatch',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='distance', full_name='chrys.cloud.videostreaming.v1beta1.SignatureMatch.distance', index=0,
+      number=1, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='annotation', full_name='chrys.cloud.videostreaming.v1beta1.SignatureMatch.annotation', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4298,
+  serialized_end=4405,
+)
+
+
+_SEARCHSIGNATURESRESPONSE = _descriptor.Descriptor(
+  name='SearchSignaturesResponse',
+  full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='matches', full_name='chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse.matches', index=0,
+      number=1, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4407,
+  serialized_end=4502,
+)
+
+
+_DEBUGOVERLAYREQUEST = _descriptor.Descriptor(
+  name='DebugOverlayRequest',
+  full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='device_id', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest.device_id', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='timestamp', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest.timestamp', index=1,
+      number=2, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='tolerance_ms', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest.tolerance_ms', index=2,
+      number=3, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4504,
+  serialized_end=4585,
+)
+
+
+_DEBUGOVERLAYRESPONSE = _descriptor.Descriptor(
+  name='DebugOverlayResponse',
+  full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='image', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse.image', index=0,
+      number=1, type=12, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"",
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='frame_timestamp', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse.frame_timestamp', index=1,
+      number=2, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='width', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse.width', index=2,
+      number=3, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='height', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse.height', index=3,
+      number=4, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='annotations', full_name='chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse.annotations', index=4,
+      number=5, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4588,
+  serialized_end=4755,
+)
+
+
+_ANNOTATEREJECTION = _descriptor.Descriptor(
+  name='AnnotateRejection',
+  full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRejection',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='sequence', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRejection.sequence', index=0,
+      number=1, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='device_name', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRejection.device_name', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='code', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRejection.code', index=2,
+      number=3, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='message', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateRejection.message', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4757,
+  serialized_end=4846,
+)
+
+
+_ANNOTATEACK = _descriptor.Descriptor(
+  name='AnnotateAck',
+  full_name='chrys.cloud.videostreaming.v1beta1.AnnotateAck',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='sequence', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateAck.sequence', index=0,
+      number=1, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='accepted', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateAck.accepted', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='rejections', full_name='chrys.cloud.videostreaming.v1beta1.AnnotateAck.rejections', index=2,
+      number=3, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4848,
+  serialized_end=4972,
 )
 
 
@@ -1265,8 +2006,8 @@ _SYSTEMTIMERESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2726,
-  serialized_end=2768,
+  serialized_start=4974,
+  serialized_end=5016,
 )
 
 
@@ -1290,20 +2031,42 @@ _SYSTEMTIMEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2770,
-  serialized_end=2789,
+  serialized_start=5018,
+  serialized_end=5037,
 )
 
+_ANNOTATEREQUEST_ATTRIBUTESENTRY.containing_type = _ANNOTATEREQUEST
 _ANNOTATEREQUEST.fields_by_name['object_bouding_box'].message_type = _BOUDINGBOX
 _ANNOTATEREQUEST.fields_by_name['location'].message_type = _LOCATION
 _ANNOTATEREQUEST.fields_by_name['object_coordinate'].message_type = _COORDINATE
 _ANNOTATEREQUEST.fields_by_name['mask'].message_type = _COORDINATE
+_ANNOTATEREQUEST.fields_by_name['object_path'].message_type = _COORDINATE
+_ANNOTATEREQUEST.fields_by_name['attributes'].message_type = _ANNOTATEREQUEST_ATTRIBUTESENTRY
+_ANNOTATEREQUEST.fields_by_name['objects'].message_type = _DETECTEDOBJECT
+_ANNOTATEREQUEST.fields_by_name['labels'].message_type = _CLASSIFICATION
+_DETECTEDOBJECT_ATTRIBUTESENTRY.containing_type = _DETECTEDOBJECT
+_DETECTEDOBJECT.fields_by_name['object_bouding_box'].message_type = _BOUDINGBOX
+_DETECTEDOBJECT.fields_by_name['object_coordinate'].message_type = _COORDINATE
+_DETECTEDOBJECT.fields_by_name['mask'].message_type = _COORDINATE
+_DETECTEDOBJECT.fields_by_name['attributes'].message_type = _DETECTEDOBJECT_ATTRIBUTESENTRY
+_DETECTEDOBJECT.fields_by_name['labels'].message_type = _CLASSIFICATION
 _SHAPEPROTO_DIM.containing_type = _SHAPEPROTO
 _SHAPEPROTO.fields_by_name['dim'].message_type = _SHAPEPROTO_DIM
 _VIDEOFRAME.fields_by_name['shape'].message_type = _SHAPEPROTO
+_LISTSTREAM_LABELSENTRY.containing_type = _LISTSTREAM
+_LISTSTREAM.fields_by_name['labels'].message_type = _LISTSTREAM_LABELSENTRY
+_LISTSTREAM.fields_by_name['location'].message_type = _LOCATION
+_LISTSTREAMREQUEST_LABELSENTRY.containing_type = _LISTSTREAMREQUEST
+_LISTSTREAMREQUEST.fields_by_name['labels'].message_type = _LISTSTREAMREQUEST_LABELSENTRY
 _VIDEOPROBERESPONSE.fields_by_name['video_codec'].message_type = _VIDEOCODEC
 _VIDEOPROBERESPONSE.fields_by_name['buffer'].message_type = _VIDEOBUFFER
+_SIGNATUREMATCH.fields_by_name['annotation'].message_type = _ANNOTATEREQUEST
+_SEARCHSIGNATURESRESPONSE.fields_by_name['matches'].message_type = _SIGNATUREMATCH
+_DEBUGOVERLAYRESPONSE.fields_by_name['annotations'].message_type = _ANNOTATEREQUEST
+_ANNOTATEACK.fields_by_name['rejections'].message_type = _ANNOTATEREJECTION
 DESCRIPTOR.message_types_by_name['AnnotateRequest'] = _ANNOTATEREQUEST
+DESCRIPTOR.message_types_by_name['DetectedObject'] = _DETECTEDOBJECT
+DESCRIPTOR.message_types_by_name['Classification'] = _CLASSIFICATION
 DESCRIPTOR.message_types_by_name['AnnotateResponse'] = _ANNOTATERESPONSE
 DESCRIPTOR.message_types_by_name['Location'] = _LOCATION
 DESCRIPTOR.message_types_by_name['Coordinate'] = _COORDINATE
@@ -1322,16 +2085,53 @@ DESCRIPTOR.message_types_by_name['VideoCodec'] = _VIDEOCODEC
 DESCRIPTOR.message_types_by_name['VideoProbeRequest'] = _VIDEOPROBEREQUEST
 DESCRIPTOR.message_types_by_name['VideoProbeResponse'] = _VIDEOPROBERESPONSE
 DESCRIPTOR.message_types_by_name['VideoBuffer'] = _VIDEOBUFFER
+DESCRIPTOR.message_types_by_name['SearchSignaturesRequest'] = _SEARCHSIGNATURESREQUEST
+DESCRIPTOR.message_types_by_name['SignatureMatch'] = _SIGNATUREMATCH
+DESCRIPTOR.message_types_by_name['SearchSignaturesResponse'] = _SEARCHSIGNATURESRESPONSE
+DESCRIPTOR.message_types_by_name['DebugOverlayRequest'] = _DEBUGOVERLAYREQUEST
+DESCRIPTOR.message_types_by_name['DebugOverlayResponse'] = _DEBUGOVERLAYRESPONSE
+DESCRIPTOR.message_types_by_name['AnnotateRejection'] = _ANNOTATEREJECTION
+DESCRIPTOR.message_types_by_name['AnnotateAck'] = _ANNOTATEACK
 DESCRIPTOR.message_types_by_name['SystemTimeResponse'] = _SYSTEMTIMERESPONSE
 DESCRIPTOR.message_types_by_name['SystemTimeRequest'] = _SYSTEMTIMEREQUEST
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
 AnnotateRequest = _reflection.GeneratedProtocolMessageType('AnnotateRequest', (_message.Message,), {
+
+  'AttributesEntry' : _reflection.GeneratedProtocolMessageType('AttributesEntry', (_message.Message,), {
+    'DESCRIPTOR' : _ANNOTATEREQUEST_ATTRIBUTESENTRY,
+    '__module__' : 'video_streaming_pb2'
+    # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry)
+    })
+  ,
   'DESCRIPTOR' : _ANNOTATEREQUEST,
   '__module__' : 'video_streaming_pb2'
   # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.AnnotateRequest)
   })
 _sym_db.RegisterMessage(AnnotateRequest)
+_sym_db.RegisterMessage(AnnotateRequest.AttributesEntry)
+
+DetectedObject = _reflection.GeneratedProtocolMessageType('DetectedObject', (_message.Message,), {
+
+  'AttributesEntry' : _reflection.GeneratedProtocolMessageType('AttributesEntry', (_message.Message,), {
+    'DESCRIPTOR' : _DETECTEDOBJECT_ATTRIBUTESENTRY,
+    '__module__' : 'video_streaming_pb2'
+    # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry)
+    })
+  ,
+  'DESCRIPTOR' : _DETECTEDOBJECT,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.DetectedObject)
+  })
+_sym_db.RegisterMessage(DetectedObject)
+_sym_db.RegisterMessage(DetectedObject.AttributesEntry)
+
+Classification = _reflection.GeneratedProtocolMessageType('Classification', (_message.Message,), {
+  'DESCRIPTOR' : _CLASSIFICATION,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.Classification)
+  })
+_sym_db.RegisterMessage(Classification)
 
 AnnotateResponse = _reflection.GeneratedProtocolMessageType('AnnotateResponse', (_message.Message,), {
   'DESCRIPTOR' : _ANNOTATERESPONSE,
@@ -1398,18 +2198,34 @@ VideoFrameBufferedRequest = _reflection.GeneratedProtocolMessageType('VideoFrame
 _sym_db.RegisterMessage(VideoFrameBufferedRequest)
 
 ListStream = _reflection.GeneratedProtocolMessageType('ListStream', (_message.Message,), {
+
+  'LabelsEntry' : _reflection.GeneratedProtocolMessageType('LabelsEntry', (_message.Message,), {
+    'DESCRIPTOR' : _LISTSTREAM_LABELSENTRY,
+    '__module__' : 'video_streaming_pb2'
+    # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry)
+    })
+  ,
   'DESCRIPTOR' : _LISTSTREAM,
   '__module__' : 'video_streaming_pb2'
   # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.ListStream)
   })
 _sym_db.RegisterMessage(ListStream)
+_sym_db.RegisterMessage(ListStream.LabelsEntry)
 
 ListStreamRequest = _reflection.GeneratedProtocolMessageType('ListStreamRequest', (_message.Message,), {
+
+  'LabelsEntry' : _reflection.GeneratedProtocolMessageType('LabelsEntry', (_message.Message,), {
+    'DESCRIPTOR' : _LISTSTREAMREQUEST_LABELSENTRY,
+    '__module__' : 'video_streaming_pb2'
+    # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry)
+    })
+  ,
   'DESCRIPTOR' : _LISTSTREAMREQUEST,
   '__module__' : 'video_streaming_pb2'
   # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.ListStreamRequest)
   })
 _sym_db.RegisterMessage(ListStreamRequest)
+_sym_db.RegisterMessage(ListStreamRequest.LabelsEntry)
 
 ProxyRequest = _reflection.GeneratedProtocolMessageType('ProxyRequest', (_message.Message,), {
   'DESCRIPTOR' : _PROXYREQUEST,
@@ -1467,6 +2283,55 @@ VideoBuffer = _reflection.GeneratedProtocolMessageType('VideoBuffer', (_message.
   })
 _sym_db.RegisterMessage(VideoBuffer)
 
+SearchSignaturesRequest = _reflection.GeneratedProtocolMessageType('SearchSignaturesRequest', (_message.Message,), {
+  'DESCRIPTOR' : _SEARCHSIGNATURESREQUEST,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest)
+  })
+_sym_db.RegisterMessage(SearchSignaturesRequest)
+
+SignatureMatch = _reflection.GeneratedProtocolMessageType('SignatureMatch', (_message.Message,), {
+  'DESCRIPTOR' : _SIGNATUREMATCH,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.SignatureMatch)
+  })
+_sym_db.RegisterMessage(SignatureMatch)
+
+SearchSignaturesResponse = _reflection.GeneratedProtocolMessageType('SearchSignaturesResponse', (_message.Message,), {
+  'DESCRIPTOR' : _SEARCHSIGNATURESRESPONSE,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse)
+  })
+_sym_db.RegisterMessage(SearchSignaturesResponse)
+
+DebugOverlayRequest = _reflection.GeneratedProtocolMessageType('DebugOverlayRequest', (_message.Message,), {
+  'DESCRIPTOR' : _DEBUGOVERLAYREQUEST,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest)
+  })
+_sym_db.RegisterMessage(DebugOverlayRequest)
+
+DebugOverlayResponse = _reflection.GeneratedProtocolMessageType('DebugOverlayResponse', (_message.Message,), {
+  'DESCRIPTOR' : _DEBUGOVERLAYRESPONSE,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse)
+  })
+_sym_db.RegisterMessage(DebugOverlayResponse)
+
+AnnotateRejection = _reflection.GeneratedProtocolMessageType('AnnotateRejection', (_message.Message,), {
+  'DESCRIPTOR' : _ANNOTATEREJECTION,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.AnnotateRejection)
+  })
+_sym_db.RegisterMessage(AnnotateRejection)
+
+AnnotateAck = _reflection.GeneratedProtocolMessageType('AnnotateAck', (_message.Message,), {
+  'DESCRIPTOR' : _ANNOTATEACK,
+  '__module__' : 'video_streaming_pb2'
+  # @@protoc_insertion_point(class_scope:chrys.cloud.videostreaming.v1beta1.AnnotateAck)
+  })
+_sym_db.RegisterMessage(AnnotateAck)
+
 SystemTimeResponse = _reflection.GeneratedProtocolMessageType('SystemTimeResponse', (_message.Message,), {
   'DESCRIPTOR' : _SYSTEMTIMERESPONSE,
   '__module__' : 'video_streaming_pb2'
@@ -1482,6 +2347,10 @@ SystemTimeRequest = _reflection.GeneratedProtocolMessageType('SystemTimeRequest'
 _sym_db.RegisterMessage(SystemTimeRequest)
 
 
+_ANNOTATEREQUEST_ATTRIBUTESENTRY._options = None
+_DETECTEDOBJECT_ATTRIBUTESENTRY._options = None
+_LISTSTREAM_LABELSENTRY._options = None
+_LISTSTREAMREQUEST_LABELSENTRY._options = None
 
 _IMAGE = _descriptor.ServiceDescriptor(
   name='Image',
@@ -1490,8 +2359,8 @@ _IMAGE = _descriptor.ServiceDescriptor(
   index=0,
   serialized_options=None,
   create_key=_descriptor._internal_create_key,
-  serialized_start=2792,
-  serialized_end=3789,
+  serialized_start=5040,
+  serialized_end=6443,
   methods=[
   _descriptor.MethodDescriptor(
     name='VideoLatestImage',
@@ -1543,10 +2412,20 @@ _IMAGE = _descriptor.ServiceDescriptor(
     serialized_options=None,
     create_key=_descriptor._internal_create_key,
   ),
+  _descriptor.MethodDescriptor(
+    name='AnnotateStream',
+    full_name='chrys.cloud.videostreaming.v1beta1.Image.AnnotateStream',
+    index=5,
+    containing_service=None,
+    input_type=_ANNOTATEREQUEST,
+    output_type=_ANNOTATEACK,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
   _descriptor.MethodDescriptor(
     name='Proxy',
     full_name='chrys.cloud.videostreaming.v1beta1.Image.Proxy',
-    index=5,
+    index=6,
     containing_service=None,
     input_type=_PROXYREQUEST,
     output_type=_PROXYRESPONSE,
@@ -1556,7 +2435,7 @@ _IMAGE = _descriptor.ServiceDescriptor(
   _descriptor.MethodDescriptor(
     name='Storage',
     full_name='chrys.cloud.videostreaming.v1beta1.Image.Storage',
-    index=6,
+    index=7,
     containing_service=None,
     input_type=_STORAGEREQUEST,
     output_type=_STORAGERESPONSE,
@@ -1566,13 +2445,33 @@ _IMAGE = _descriptor.ServiceDescriptor(
   _descriptor.MethodDescriptor(
     name='SystemTime',
     full_name='chrys.cloud.videostreaming.v1beta1.Image.SystemTime',
-    index=7,
+    index=8,
     containing_service=None,
     input_type=_SYSTEMTIMEREQUEST,
     output_type=_SYSTEMTIMERESPONSE,
     serialized_options=None,
     create_key=_descriptor._internal_create_key,
   ),
+  _descriptor.MethodDescriptor(
+    name='SearchSignatures',
+    full_name='chrys.cloud.videostreaming.v1beta1.Image.SearchSignatures',
+    index=9,
+    containing_service=None,
+    input_type=_SEARCHSIGNATURESREQUEST,
+    output_type=_SEARCHSIGNATURESRESPONSE,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
+  _descriptor.MethodDescriptor(
+    name='DebugOverlay',
+    full_name='chrys.cloud.videostreaming.v1beta1.Image.DebugOverlay',
+    index=10,
+    containing_service=None,
+    input_type=_DEBUGOVERLAYREQUEST,
+    output_type=_DEBUGOVERLAYRESPONSE,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
 ])
 _sym_db.RegisterServiceDescriptor(_IMAGE)
 
@@ -29,8 +29,19 @@ from disk_cleanup import CleanupScheduler
 from inmemory_buffer import InMemoryBuffer, packetToInMemoryBuffer, setCodecInfo,getCodecInfo, memoryCleanup
 from global_vars import query_timestamp, RedisIsKeyFrameOnlyPrefix, RedisLastAccessPrefix, ArchivePacketGroup
 import datetime
+import urllib.parse
 
 
+def waitWhilePaused(redis_conn, device_id):
+    '''
+    Blocks while the camera is paused (paused flag set by the edge server in the last access settings)
+    '''
+    while True:
+        paused = redis_conn.hget(RedisLastAccessPrefix + device_id, "paused")
+        if paused is None or paused.decode('utf-8') != "1":
+            return
+        time.sleep(1)
+
 class RTSPtoRTMP(threading.Thread):
 
     def __init__(self, rtsp_endpoint, rtmp_endpoint, packet_queue, device_id, disk_path, redis_conn, memory_buffer, is_decode_packets_event, lock_condition):
@@ -79,6 +90,10 @@ class RTSPtoRTMP(threading.Thread):
         last_loop_run = int(time.time() * 1000)
 
         while True:
+            # don't connect to the camera while paused from the edge server
+            waitWhilePaused(self.redis_conn, self.device_id)
+            paused = False
+
             try:
                 options = {'rtsp_transport': 'tcp','stimeout': '5000000', 'max_delay': '5000000', 'use_wallclock_as_timestamps':"1", "fflags":"+genpts", 'acodec':'aac'}
                 self.in_container = av.open(self.rtsp_endpoint, options=options)
@@ -161,6 +176,11 @@ class RTSPtoRTMP(threading.Thread):
 
                 if settings_dict is not None and len(settings_dict) > 0:
                     settings_dict = { y.decode('utf-8'): settings_dict.get(y).decode('utf-8') for y in settings_dict.keys() } 
+                    # stop producing frames when paused from the edge server
+                    if settings_dict.get("paused") == "1":
+                        paused = True
+                        break
+
                     if "last_query" in settings_dict:
                         ts = settings_dict['last_query']
                     else:
@@ -225,6 +245,12 @@ class RTSPtoRTMP(threading.Thread):
                     print("failed muxing", e)
 
                 current_packet_group.append(packet)
+
+            if paused:
+                print("rtsp paused...waiting to be resumed")
+                self.in_container.close()
+                current_packet_group = []
+                continue
             
             time.sleep(1) # wait a second before trying to get more packets
             print("rtsp stopped streaming...waiting for camera to reappear")
@@ -247,6 +273,20 @@ if __name__ == "__main__":
 
     rtmp = args.rtmp
     rtsp = args.rtsp
+    # credentials are passed separately from the endpoint (username in the environment, password in a file
+    # copied into the container by the edge server, never on the command line)
+    rtsp_username = os.environ.get("rtsp_username")
+    rtsp_password = os.environ.get("rtsp_password")
+    rtsp_password_file = os.environ.get("rtsp_password_file")
+    if rtsp_password_file:
+        with open(rtsp_password_file, "r") as f:
+            rtsp_password = f.read()
+    if rtsp_username or rtsp_password:
+        parsed = urllib.parse.urlsplit(rtsp)
+        userinfo = urllib.parse.quote(rtsp_username or "", safe="")
+        if rtsp_password:
+            userinfo += ":" + urllib.parse.quote(rtsp_password, safe="")
+        rtsp = urllib.parse.urlunsplit((parsed.scheme, userinfo + "@" + parsed.netloc, parsed.path, parsed.query, parsed.fragment))
     device_id = args.device_id
     memory_buffer=args.memory_buffer
     memory_scale=args.memory_scale
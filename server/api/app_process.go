@@ -121,5 +121,48 @@ func (aph *appProcessHandler) Info(c *gin.Context) {
 		AbortWithError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	logs, err := aph.appManager.Logs(deviceID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	info.Logs = logs
 	c.JSON(http.StatusOK, info)
 }
+
+// Restart - restarts the app container (paused app is resumed)
+func (aph *appProcessHandler) Restart(c *gin.Context) {
+	aph.control(c, models.DeviceOperationRestart, aph.appManager.Restart)
+}
+
+// Pause - pauses the app container, container and configuration are kept
+func (aph *appProcessHandler) Pause(c *gin.Context) {
+	aph.control(c, models.DeviceOperationPause, aph.appManager.Pause)
+}
+
+// Resume - unpauses the app container
+func (aph *appProcessHandler) Resume(c *gin.Context) {
+	aph.control(c, models.DeviceOperationResume, aph.appManager.Resume)
+}
+
+func (aph *appProcessHandler) control(c *gin.Context, operation string, perform func(appName string) (*models.AppProcess, error)) {
+	appName := c.Param("name")
+	if appName == "" {
+		AbortWithError(c, http.StatusBadRequest, "required app name")
+		return
+	}
+	app, err := perform(appName)
+	if err != nil {
+		if err == models.ErrProcessNotFoundDatastore || err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		g.Log.Warn("failed to "+operation+" app ", appName, err)
+		AbortWithError(c, http.StatusConflict, err.Error())
+		return
+	}
+	// publish to chrysalis cloud the change
+	utils.PublishToRedis(aph.rdb, appName, models.MQTTProcessOperation(operation), models.ProcessTypeApplication, nil)
+
+	c.JSON(http.StatusOK, app)
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiRoutePrefix      = "/api/v1/"
+	maxAuditedBodyBytes = 1 << 20
+)
+
+// audited route groups (first path segment after /api/v1/) and their target types
+var auditRouteTargets = map[string]string{
+	"process":         models.ProcessTypeRTSP,
+	"processupgrades": models.ProcessTypeRTSP,
+	"processgroup":    models.ProcessTypeRTSP,
+	"appprocess":      models.ProcessTypeApplication,
+	"settings":        models.AuditTargetSettings,
+	"counting":        models.AuditTargetCountingZone,
+	"rules":           models.AuditTargetRule,
+	"privacymask":     models.AuditTargetPrivacyMask,
+	"auth":            models.AuditTargetUser,
+	"users":           models.AuditTargetUser,
+	"apikeys":         models.AuditTargetAPIKey,
+	"reconcile":       models.AuditTargetReconcile,
+	"dockerpull":      models.AuditTargetImage,
+	"config":          models.AuditTargetConfig,
+	"manifest":        models.AuditTargetManifest,
+}
+
+// routes not changing anything even though not GET (or changing even though GET)
+var auditReadOnlyRoutes = map[string]bool{"POST auth/login": true, "POST rules/test": true, "POST process/:name": true}
+var auditMutatingGetRoutes = map[string]bool{"GET dockerpull": true}
+
+type auditHandler struct {
+	auditManager *services.AuditManager
+}
+
+func NewAuditHandler(auditManager *services.AuditManager) *auditHandler {
+	return &auditHandler{
+		auditManager: auditManager,
+	}
+}
+
+// auditResponseWriter keeps the error response to be stored with the failed operation
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest && w.body.Len() < 1024 {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Audit - middleware recording every mutating call with the changes of the stored target
+func (ah *auditHandler) Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), apiRoutePrefix)
+		segment := strings.Split(strings.TrimPrefix(c.FullPath(), apiRoutePrefix), "/")[0]
+		targetType, ok := auditRouteTargets[segment]
+		mutating := (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodOptions && !auditReadOnlyRoutes[route]) || auditMutatingGetRoutes[route]
+		if !ok || !mutating {
+			c.Next()
+			return
+		}
+
+		target := auditTarget(c, segment)
+		before := ah.auditManager.Snapshot(targetType, target)
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		record := &models.AuditRecord{
+			Actor:      actor(c),
+			Source:     models.AuditSourceREST,
+			Action:     route,
+			TargetType: targetType,
+			Target:     target,
+			Result:     models.AuditResultSuccess,
+		}
+		if writer.Status() >= http.StatusBadRequest {
+			record.Result = models.AuditResultFailed
+			var jsonErr JSONError
+			if err := json.Unmarshal(writer.body.Bytes(), &jsonErr); err == nil && jsonErr.Message != "" {
+				record.Message = jsonErr.Message
+			} else {
+				record.Message = http.StatusText(writer.Status())
+			}
+		}
+		after := ah.auditManager.Snapshot(targetType, target)
+		if err := ah.auditManager.RecordChange(record, before, after); err != nil {
+			g.Log.Error("failed to audit", route, target, err)
+		}
+	}
+}
+
+// List - audit records matching query filters, newest first
+func (ah *auditHandler) List(c *gin.Context) {
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	records, err := ah.auditManager.Query(&query)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// auditTarget - name of the target from the path or from the JSON body (body is restored for the handler)
+func auditTarget(c *gin.Context, segment string) string {
+	switch segment {
+	case "settings":
+		return models.SettingDefaultKey
+	case "auth":
+		if principal := getPrincipal(c); principal != nil {
+			return principal.Name
+		}
+	case "apikeys":
+		return c.Param("id")
+	case "processgroup":
+		// cameras are reported in the response
+		return c.Query("group")
+	case "config", "manifest":
+		// bulk import, items are reported in the response
+		return ""
+	}
+	if name := c.Param("name"); name != "" {
+		if zone := c.Param("zone"); zone != "" {
+			return name + "/" + zone
+		}
+		return name
+	}
+	if c.Request.Body == nil || c.Request.ContentLength > maxAuditedBodyBytes {
+		return ""
+	}
+	body, err := ioutil.ReadAll(c.Request.Body)
+	c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var fields struct {
+		Name       string `json:"name"`
+		DeviceName string `json:"device_name"`
+		Username   string `json:"username"`
+	}
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	switch {
+	case segment == "counting" && fields.DeviceName != "":
+		return fields.DeviceName + "/" + fields.Name
+	case fields.DeviceName != "":
+		return fields.DeviceName
+	case fields.Username != "":
+		return fields.Username
+	}
+	return fields.Name
+}
@@ -0,0 +1,274 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const principalContextKey = "principal"
+
+type authHandler struct {
+	authManager *services.AuthManager
+}
+
+func NewAuthHandler(authManager *services.AuthManager) *authHandler {
+	return &authHandler{
+		authManager: authManager,
+	}
+}
+
+// Authenticate - middleware resolving the caller from the bearer token or API key.
+// Credentials are also accepted as access_token query parameter (browsers can't set headers on EventSource and WebSocket).
+func (ah *authHandler) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ah.authManager.Enabled() {
+			c.Set(principalContextKey, &models.Principal{Name: c.ClientIP(), Role: models.RoleAdmin, Method: models.AuthMethodNone})
+			c.Next()
+			return
+		}
+		credential := c.GetHeader("X-API-Key")
+		if credential == "" {
+			credential = strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		}
+		if credential == "" {
+			credential = c.Query("access_token")
+		}
+		if credential == "" {
+			status, err := ah.authManager.Status()
+			if err == nil && status.SetupRequired {
+				AbortWithError(c, http.StatusUnauthorized, models.ErrSetupRequired.Error())
+				return
+			}
+			AbortWithError(c, http.StatusUnauthorized, models.ErrUnauthorized.Error())
+			return
+		}
+		principal, err := ah.authManager.Authenticate(credential)
+		if err != nil {
+			AbortWithError(c, http.StatusUnauthorized, models.ErrUnauthorized.Error())
+			return
+		}
+		c.Set(principalContextKey, principal)
+		c.Next()
+	}
+}
+
+// RequireRole - middleware allowing only callers with the role or higher
+func (ah *authHandler) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := getPrincipal(c)
+		if principal == nil {
+			AbortWithError(c, http.StatusUnauthorized, models.ErrUnauthorized.Error())
+			return
+		}
+		if !models.HasRole(principal.Role, role) {
+			AbortWithError(c, http.StatusForbidden, "requires "+role+" role")
+			return
+		}
+		c.Next()
+	}
+}
+
+// Status - if authentication is enabled and first-run setup still required
+func (ah *authHandler) Status(c *gin.Context) {
+	status, err := ah.authManager.Status()
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// Setup - creates the first admin account and logs in
+func (ah *authHandler) Setup(c *gin.Context) {
+	var req models.UserRequest
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := ah.authManager.Setup(req.Username, req.Password); err != nil {
+		abortUserError(c, err)
+		return
+	}
+	token, err := ah.authManager.Login(req.Username, req.Password)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// Login - issues a token for username and password
+func (ah *authHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	token, err := ah.authManager.Login(req.Username, req.Password)
+	if err != nil {
+		if err == models.ErrUnauthorized {
+			g.Log.Warn("failed login", req.Username, c.ClientIP())
+			AbortWithError(c, http.StatusUnauthorized, "invalid username or password")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// Me - the authenticated caller
+func (ah *authHandler) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, getPrincipal(c))
+}
+
+// ChangePassword - changes password of the logged in user
+func (ah *authHandler) ChangePassword(c *gin.Context) {
+	principal := getPrincipal(c)
+	if principal == nil || principal.Method != models.AuthMethodPassword {
+		AbortWithError(c, http.StatusBadRequest, "password change requires user login")
+		return
+	}
+	var req models.PasswordChangeRequest
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := ah.authManager.ChangePassword(principal.Name, req.OldPassword, req.NewPassword); err != nil {
+		abortUserError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// ListUsers - all local users
+func (ah *authHandler) ListUsers(c *gin.Context) {
+	users, err := ah.authManager.ListUsers()
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// CreateUser - creates a local user
+func (ah *authHandler) CreateUser(c *gin.Context) {
+	var req models.UserRequest
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.RoleViewer
+	}
+	user, err := ah.authManager.CreateUser(req.Username, req.Password, req.Role)
+	if err != nil {
+		abortUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser - removes a local user
+func (ah *authHandler) DeleteUser(c *gin.Context) {
+	if err := ah.authManager.DeleteUser(c.Param("name")); err != nil {
+		abortUserError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// ListAPIKeys - all API keys (keys themselves are never returned)
+func (ah *authHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := ah.authManager.ListAPIKeys()
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey - creates an API key, the key is returned only once
+func (ah *authHandler) CreateAPIKey(c *gin.Context) {
+	var req models.APIKey
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	key, err := ah.authManager.CreateAPIKey(req.Name, req.Role)
+	if err != nil {
+		abortUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+// DeleteAPIKey - revokes an API key
+func (ah *authHandler) DeleteAPIKey(c *gin.Context) {
+	if err := ah.authManager.DeleteAPIKey(c.Param("id")); err != nil {
+		abortUserError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func abortUserError(c *gin.Context, err error) {
+	switch err {
+	case models.ErrStringTooShort:
+		AbortWithError(c, http.StatusBadRequest, "username requires at least 3 and password at least 8 characters")
+	case models.ErrInvalidInputParameters:
+		AbortWithError(c, http.StatusBadRequest, "invalid name or role (viewer, operator or admin)")
+	case models.ErrUnauthorized:
+		AbortWithError(c, http.StatusUnauthorized, err.Error())
+	case models.ErrForbidden:
+		AbortWithError(c, http.StatusForbidden, err.Error())
+	case models.ErrProcessConflict:
+		AbortWithError(c, http.StatusConflict, "already exists")
+	case models.ErrProcessNotFoundDatastore:
+		AbortWithError(c, http.StatusNotFound, "not found")
+	default:
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func getPrincipal(c *gin.Context) *models.Principal {
+	if p, ok := c.Get(principalContextKey); ok {
+		if principal, ok := p.(*models.Principal); ok {
+			return principal
+		}
+	}
+	return nil
+}
+
+// actor - name of the authenticated caller (client IP when unknown)
+func actor(c *gin.Context) string {
+	if principal := getPrincipal(c); principal != nil && principal.Method != models.AuthMethodNone {
+		return principal.Name
+	}
+	return c.ClientIP()
+}
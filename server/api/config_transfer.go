@@ -0,0 +1,112 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportBytes = 10 << 20
+
+type configTransferHandler struct {
+	configTransferManager *services.ConfigTransferManager
+}
+
+func NewConfigTransferHandler(configTransferManager *services.ConfigTransferManager) *configTransferHandler {
+	return &configTransferHandler{
+		configTransferManager: configTransferManager,
+	}
+}
+
+// Export - all camera and app definitions as JSON or YAML
+func (ch *configTransferHandler) Export(c *gin.Context) {
+	var query models.ConfigExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.Format != "" && query.Format != models.ConfigFormatJSON && query.Format != models.ConfigFormatYAML {
+		AbortWithError(c, http.StatusBadRequest, "format must be json or yaml")
+		return
+	}
+	bundle, err := ch.configTransferManager.Export(query.Redact)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	b, err := services.MarshalConfigBundle(bundle, query.Format)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	contentType := "application/json"
+	if query.Format == models.ConfigFormatYAML {
+		contentType = "application/x-yaml"
+	}
+	c.Data(http.StatusOK, contentType, b)
+}
+
+// Import - cameras and apps from JSON, YAML or CSV body. Per item results are reported (also when some items fail).
+func (ch *configTransferHandler) Import(c *gin.Context) {
+	var query models.ConfigImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := query.Format
+	if format == "" {
+		format = detectConfigFormat(c.ContentType(), data)
+	}
+	bundle, err := services.ParseConfigBundle(data, format)
+	if err != nil {
+		g.Log.Warn("failed to parse imported configuration", format, err)
+		if err == models.ErrInvalidInputParameters {
+			AbortWithError(c, http.StatusBadRequest, "format must be json, yaml or csv")
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, ch.configTransferManager.Import(bundle, query.DryRun, query.Concurrency))
+}
+
+// detectConfigFormat from the content type, JSON and YAML are told apart by the content
+func detectConfigFormat(contentType string, data []byte) string {
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return models.ConfigFormatCSV
+	case strings.Contains(contentType, "yaml"):
+		return models.ConfigFormatYAML
+	case strings.Contains(contentType, "json"):
+		return models.ConfigFormatJSON
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return models.ConfigFormatJSON
+	}
+	return models.ConfigFormatYAML
+}
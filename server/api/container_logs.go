@@ -0,0 +1,191 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// logStreamer - streams log lines of a single container
+type logStreamer func(ctx context.Context, name string, query *models.ContainerLogsQuery, found func(line *models.ContainerLogLine) error) error
+
+var logsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// same as CORS, only the configured origins
+	CheckOrigin: func(r *http.Request) bool { return websocketOriginAllowed(r, true) },
+}
+
+// websocketOriginAllowed - browsers send the Origin header, it must be the API host itself or one of the configured
+// origins (auth.allow_origins). Wildcard origin is accepted only if allowWildcard. Requests without Origin are
+// not sent by browsers and are allowed (authentication is still required).
+func websocketOriginAllowed(r *http.Request, allowWildcard bool) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	if g.Conf.Auth == nil {
+		return false
+	}
+	for _, allowed := range g.Conf.Auth.AllowOrigins {
+		if (allowed == "*" && allowWildcard) || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamLogs - camera container logs as Server-Sent Events or over WebSocket (when upgrade requested)
+func (ph *rtspProcessHandler) StreamLogs(c *gin.Context) {
+	deviceID := c.Param("name")
+	if deviceID == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id")
+		return
+	}
+	if _, err := ph.processManager.Info(deviceID); err != nil {
+		abortLogsNotFound(c, err)
+		return
+	}
+	streamLogs(c, deviceID, ph.processManager.StreamLogs)
+}
+
+// StreamLogs - app container logs as Server-Sent Events or over WebSocket (when upgrade requested)
+func (aph *appProcessHandler) StreamLogs(c *gin.Context) {
+	appName := c.Param("name")
+	if appName == "" {
+		AbortWithError(c, http.StatusBadRequest, "required app name")
+		return
+	}
+	if _, err := aph.appManager.Info(appName); err != nil {
+		abortLogsNotFound(c, err)
+		return
+	}
+	streamLogs(c, appName, aph.appManager.StreamLogs)
+}
+
+func abortLogsNotFound(c *gin.Context, err error) {
+	if err == models.ErrProcessNotFound || err == models.ErrProcessNotFoundDatastore {
+		AbortWithError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	AbortWithError(c, http.StatusInternalServerError, err.Error())
+}
+
+func streamLogs(c *gin.Context, name string, stream logStreamer) {
+	var query models.ContainerLogsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !query.Stdout && !query.Stderr {
+		AbortWithError(c, http.StatusBadRequest, "at least one of stdout or stderr required")
+		return
+	}
+	if query.Tail != "" && query.Tail != "all" {
+		if tail, err := strconv.Atoi(query.Tail); err != nil || tail < 0 {
+			AbortWithError(c, http.StatusBadRequest, "tail must be a positive number or all")
+			return
+		}
+	}
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		streamLogsWebSocket(c, name, &query, stream)
+		return
+	}
+	streamLogsSSE(c, name, &query, stream)
+}
+
+// streamLogsSSE sends each line as "log" event and "end" event once the logs are exhausted (not following)
+func streamLogsSSE(c *gin.Context, name string, query *models.ContainerLogsQuery, stream logStreamer) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	err := stream(ctx, name, query, func(line *models.ContainerLogLine) error {
+		c.SSEvent("log", line)
+		c.Writer.Flush()
+		return ctx.Err()
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		g.Log.Warn("container log stream failed", name, err)
+		c.SSEvent("error", &JSONError{Code: http.StatusInternalServerError, Message: err.Error()})
+	} else {
+		c.SSEvent("end", "")
+	}
+	c.Writer.Flush()
+}
+
+// streamLogsWebSocket sends each line as JSON message and closes the connection once the logs are exhausted (not following)
+func streamLogsWebSocket(c *gin.Context, name string, query *models.ContainerLogsQuery, stream logStreamer) {
+	conn, err := logsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		g.Log.Warn("failed to upgrade log stream to websocket", name, err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// client messages are ignored, reading only detects the closed connection
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = stream(ctx, name, query, func(line *models.ContainerLogLine) error {
+		return conn.WriteJSON(line)
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err != nil {
+		g.Log.Warn("container log stream failed", name, err)
+		reason := err.Error()
+		if len(reason) > 120 {
+			// control frame payload is limited to 125 bytes
+			reason = reason[:120]
+		}
+		closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, reason)
+	}
+	conn.WriteMessage(websocket.CloseMessage, closeMsg)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type countingHandler struct {
+	countingManager *services.CountingManager
+}
+
+func NewCountingHandler(countingManager *services.CountingManager) *countingHandler {
+	return &countingHandler{
+		countingManager: countingManager,
+	}
+}
+
+// PutZone creates or overwrites a counting line or area
+func (ch *countingHandler) PutZone(c *gin.Context) {
+	var zone models.CountingZone
+	if err := c.ShouldBindWith(&zone, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	stored, err := ch.countingManager.PutZone(&zone)
+	if err != nil {
+		if err == models.ErrMissingInputParameters || err == models.ErrInvalidInputParameters {
+			AbortWithError(c, http.StatusBadRequest, "name, device_name and type (line with 2 points or area with at least 3 points) required")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, stored)
+}
+
+// ListZones lists counting zones of the device
+func (ch *countingHandler) ListZones(c *gin.Context) {
+	deviceID := c.Param("name")
+	if deviceID == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id")
+		return
+	}
+	zones, err := ch.countingManager.ListZones(deviceID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, zones)
+}
+
+// DeleteZone removes the counting zone from the device
+func (ch *countingHandler) DeleteZone(c *gin.Context) {
+	deviceID := c.Param("name")
+	zone := c.Param("zone")
+	if deviceID == "" || zone == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id and zone")
+		return
+	}
+	err := ch.countingManager.DeleteZone(deviceID, zone)
+	if err != nil {
+		if err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, "zone not found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// Counts returns counting time series of the zone aggregated into buckets (minute, hour, day or duration e.g. 15m)
+func (ch *countingHandler) Counts(c *gin.Context) {
+	deviceID := c.Param("name")
+	zone := c.Query("zone")
+	if deviceID == "" || zone == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id and zone")
+		return
+	}
+	bucket, err := services.CountingBucketDuration(c.Query("bucket"))
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid bucket: "+err.Error())
+		return
+	}
+	from, err := queryInt64(c, "from")
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid from timestamp")
+		return
+	}
+	to, err := queryInt64(c, "to")
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid to timestamp")
+		return
+	}
+
+	samples, err := ch.countingManager.Counts(deviceID, zone, c.Query("object_type"), from, to, bucket)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, samples)
+}
+
+// Occupancy returns current occupancy of device areas
+func (ch *countingHandler) Occupancy(c *gin.Context) {
+	deviceID := c.Param("name")
+	if deviceID == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id")
+		return
+	}
+	c.JSON(http.StatusOK, ch.countingManager.Occupancy(deviceID))
+}
+
+// queryInt64 parses optional int64 query parameter (0 if missing)
+func queryInt64(c *gin.Context, name string) (int64, error) {
+	val := c.Query(name)
+	if val == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+)
+
+type debugOverlayHandler struct {
+	debugOverlayManager *services.DebugOverlayManager
+}
+
+func NewDebugOverlayHandler(debugOverlayManager *services.DebugOverlayManager) *debugOverlayHandler {
+	return &debugOverlayHandler{
+		debugOverlayManager: debugOverlayManager,
+	}
+}
+
+// Overlay returns JPEG of the nearest frame to timestamp with annotations within tolerance drawn on it
+func (dh *debugOverlayHandler) Overlay(c *gin.Context) {
+	deviceID := c.Param("name")
+	timestamp, err := queryInt64(c, "timestamp")
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid timestamp")
+		return
+	}
+	tolerance, err := queryInt64(c, "tolerance_ms")
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid tolerance_ms")
+		return
+	}
+	overlay, err := dh.debugOverlayManager.Render(deviceID, timestamp, tolerance)
+	if err != nil {
+		if err == models.ErrFrameNotFound {
+			AbortWithError(c, http.StatusNotFound, "no frame found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Header("X-Frame-Timestamp", strconv.FormatInt(overlay.FrameTimestamp, 10))
+	c.Header("X-Annotations", strconv.Itoa(len(overlay.Annotations)))
+	c.Data(http.StatusOK, "image/jpeg", overlay.Image)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type discoveryHandler struct {
+	discoveryManager *services.DiscoveryManager
+}
+
+func NewDiscoveryHandler(discoveryManager *services.DiscoveryManager) *discoveryHandler {
+	return &discoveryHandler{
+		discoveryManager: discoveryManager,
+	}
+}
+
+// Scan - ONVIF cameras on the local network with candidate RTSP streams (when credentials given)
+func (dh *discoveryHandler) Scan(c *gin.Context) {
+	var request models.DiscoveryScanRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindWith(&request, binding.JSON); err != nil {
+			AbortWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	cameras, err := dh.discoveryManager.Scan(&request)
+	if err != nil {
+		if err == models.ErrInvalidInputParameters {
+			AbortWithError(c, http.StatusBadRequest, "timeout_ms too long")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, cameras)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+)
+
+type manifestHandler struct {
+	manifestManager *services.ManifestManager
+}
+
+func NewManifestHandler(manifestManager *services.ManifestManager) *manifestHandler {
+	return &manifestHandler{
+		manifestManager: manifestManager,
+	}
+}
+
+// Guard - middleware on manual camera and app changes, rejected (or flagged) while manifest mode is active
+func (mh *manifestHandler) Guard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		segment := strings.Split(strings.TrimPrefix(c.FullPath(), apiRoutePrefix), "/")[0]
+		processType := models.ProcessTypeRTSP
+		switch segment {
+		case "appprocess":
+			processType = models.ProcessTypeApplication
+		case "config":
+			processType = models.AuditTargetConfig
+		}
+		route := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), apiRoutePrefix)
+		if err := mh.manifestManager.ManualChange(processType, auditTarget(c, segment), route, actor(c)); err != nil {
+			AbortWithError(c, http.StatusConflict, err.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+// Status - manifest mode state and the current drift
+func (mh *manifestHandler) Status(c *gin.Context) {
+	status, err := mh.manifestManager.Status()
+	if err != nil && status == nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// Reconcile - applies the manifest now
+func (mh *manifestHandler) Reconcile(c *gin.Context) {
+	status, err := mh.manifestManager.Reconcile()
+	if err != nil {
+		if err == models.ErrForbidden {
+			AbortWithError(c, http.StatusBadRequest, "manifest mode is not enabled")
+			return
+		}
+		if status == nil || status.Error == "" {
+			AbortWithError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		// manifest can't be read or parsed, reported in the status
+	}
+	c.JSON(http.StatusOK, status)
+}
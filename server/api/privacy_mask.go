@@ -0,0 +1,113 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type privacyMaskHandler struct {
+	privacyMaskManager *services.PrivacyMaskManager
+}
+
+func NewPrivacyMaskHandler(privacyMaskManager *services.PrivacyMaskManager) *privacyMaskHandler {
+	return &privacyMaskHandler{
+		privacyMaskManager: privacyMaskManager,
+	}
+}
+
+// Put stores a new version of the camera privacy mask
+func (ph *privacyMaskHandler) Put(c *gin.Context) {
+	var mask models.PrivacyMask
+	if err := c.ShouldBindWith(&mask, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	stored, err := ph.privacyMaskManager.Put(&mask)
+	if err != nil {
+		if err == models.ErrMissingInputParameters || err == models.ErrInvalidInputParameters {
+			AbortWithError(c, http.StatusBadRequest, "device_name, mode (black or pixelate) and regions (polygons with at least 3 points) required")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, stored)
+}
+
+// Get returns current privacy mask of the camera
+func (ph *privacyMaskHandler) Get(c *gin.Context) {
+	mask, err := ph.privacyMaskManager.Get(c.Param("name"))
+	if err != nil {
+		if err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, "privacy mask not found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, mask)
+}
+
+// Delete removes current privacy mask of the camera (previous versions are kept)
+func (ph *privacyMaskHandler) Delete(c *gin.Context) {
+	err := ph.privacyMaskManager.Delete(c.Param("name"))
+	if err != nil {
+		if err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, "privacy mask not found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// Versions lists all privacy mask versions of the camera
+func (ph *privacyMaskHandler) Versions(c *gin.Context) {
+	versions, err := ph.privacyMaskManager.Versions(c.Param("name"))
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// Restore makes the selected version the current privacy mask
+func (ph *privacyMaskHandler) Restore(c *gin.Context) {
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid version")
+		return
+	}
+	mask, err := ph.privacyMaskManager.Restore(c.Param("name"), version)
+	if err != nil {
+		if err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, "privacy mask version not found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, mask)
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+)
+
+type reconcileHandler struct {
+	reconcileManager *services.ReconcileManager
+}
+
+func NewReconcileHandler(reconcileManager *services.ReconcileManager) *reconcileHandler {
+	return &reconcileHandler{
+		reconcileManager: reconcileManager,
+	}
+}
+
+// DryRun reports actions required to bring containers to the desired (datastore) state without performing them
+func (rh *reconcileHandler) DryRun(c *gin.Context) {
+	report, err := rh.reconcileManager.Reconcile(true)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Reconcile brings containers to the desired (datastore) state right away
+func (rh *reconcileHandler) Reconcile(c *gin.Context) {
+	report, err := rh.reconcileManager.Reconcile(false)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
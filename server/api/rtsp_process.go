@@ -16,6 +16,7 @@ package api
 
 import (
 	"crypto/md5"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -55,6 +56,10 @@ func (ph *rtspProcessHandler) StartRTSP(c *gin.Context) {
 		AbortWithError(c, http.StatusBadRequest, "RTP endpoint required")
 		return
 	}
+	if err := models.ValidateCameraMetadata(&streamProcess); err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid labels, group or location")
+		return
+	}
 	deviceID := streamProcess.Name
 	if streamProcess.Name == "" {
 		hash := fmt.Sprintf("%x", md5.Sum([]byte(streamProcess.RTSPEndpoint)))
@@ -73,8 +78,14 @@ func (ph *rtspProcessHandler) StartRTSP(c *gin.Context) {
 		return
 	}
 
-	err = ph.processManager.Start(&streamProcess, currentImagesList)
+	// stream is probed before the camera is created unless forced
+	err = ph.processManager.Start(&streamProcess, currentImagesList, c.Query("force") == "true")
 	if err != nil {
+		var probeErr *models.RTSPProbeError
+		if errors.As(err, &probeErr) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, probeErr.Result)
+			return
+		}
 		g.Log.Warn("failed to start process ", deviceID, err)
 		AbortWithError(c, http.StatusConflict, err.Error())
 		return
@@ -85,6 +96,96 @@ func (ph *rtspProcessHandler) StartRTSP(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// Validate - probes the RTSP endpoint (reachability, authentication, tracks and codecs) without creating the camera
+func (ph *rtspProcessHandler) Validate(c *gin.Context) {
+	var streamProcess models.StreamProcess
+	if err := c.ShouldBindWith(&streamProcess, binding.JSON); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	probe, err := ph.processManager.Probe(&streamProcess)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, probe)
+}
+
+// UpdateRTSP - updates the camera in place (container is recreated only if the stream endpoints changed)
+func (ph *rtspProcessHandler) UpdateRTSP(c *gin.Context) {
+	deviceID := c.Param("name")
+	if deviceID == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id")
+		return
+	}
+	var streamProcess models.StreamProcess
+	if err := c.ShouldBindWith(&streamProcess, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if streamProcess.Name != "" && streamProcess.Name != deviceID {
+		AbortWithError(c, http.StatusBadRequest, "process name can't be changed")
+		return
+	}
+	if err := models.ValidateCameraMetadata(&streamProcess); err != nil {
+		AbortWithError(c, http.StatusBadRequest, "invalid labels, group or location")
+		return
+	}
+
+	updated, err := ph.processManager.Update(deviceID, &streamProcess)
+	if err != nil {
+		if err == models.ErrProcessNotFoundDatastore {
+			AbortWithError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		g.Log.Warn("failed to update process ", deviceID, err)
+		AbortWithError(c, http.StatusConflict, err.Error())
+		return
+	}
+	// publish to chrysalis cloud the change
+	utils.PublishToRedis(ph.rdb, deviceID, models.MQTTProcessOperation(models.DeviceOperationUpdate), models.ProcessTypeRTSP, nil)
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Restart - restarts the camera container (paused camera is resumed)
+func (ph *rtspProcessHandler) Restart(c *gin.Context) {
+	ph.control(c, models.DeviceOperationRestart, ph.processManager.Restart)
+}
+
+// Pause - camera stops producing frames, container and configuration are kept
+func (ph *rtspProcessHandler) Pause(c *gin.Context) {
+	ph.control(c, models.DeviceOperationPause, ph.processManager.Pause)
+}
+
+// Resume - paused camera continues producing frames
+func (ph *rtspProcessHandler) Resume(c *gin.Context) {
+	ph.control(c, models.DeviceOperationResume, ph.processManager.Resume)
+}
+
+func (ph *rtspProcessHandler) control(c *gin.Context, operation string, perform func(deviceID string) (*models.StreamProcess, error)) {
+	deviceID := c.Param("name")
+	if deviceID == "" {
+		AbortWithError(c, http.StatusBadRequest, "required device_id")
+		return
+	}
+	process, err := perform(deviceID)
+	if err != nil {
+		if err == models.ErrProcessNotFoundDatastore || err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, err.Error())
+			return
+		}
+		g.Log.Warn("failed to "+operation+" process ", deviceID, err)
+		AbortWithError(c, http.StatusConflict, err.Error())
+		return
+	}
+	// publish to chrysalis cloud the change
+	utils.PublishToRedis(ph.rdb, deviceID, models.MQTTProcessOperation(operation), models.ProcessTypeRTSP, nil)
+
+	c.JSON(http.StatusOK, process)
+}
+
 // FindUpgrades - checks if each process has an upgradable version available on local disk
 func (ph *rtspProcessHandler) FindRTSPUpgrades(c *gin.Context) {
 
@@ -173,14 +274,67 @@ func (ph *rtspProcessHandler) Info(c *gin.Context) {
 		AbortWithError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	logs, err := ph.processManager.Logs(deviceID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	info.Logs = logs
 	c.JSON(http.StatusOK, info)
 }
 
+// List - all cameras, optionally filtered by group (including sub-groups) and labels (e.g. ?group=site-a&label=zone=entrance)
 func (ph *rtspProcessHandler) List(c *gin.Context) {
-	processes, err := ph.processManager.List()
+	var filter models.ProcessFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	processes, err := ph.processManager.ListGroup(&filter)
 	if err != nil {
 		AbortWithError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 	c.JSON(http.StatusOK, processes)
 }
+
+// RestartGroup - restarts all cameras matching the group and label filter
+func (ph *rtspProcessHandler) RestartGroup(c *gin.Context) {
+	ph.groupOperation(c, models.DeviceOperationRestart, ph.processManager.RestartGroup)
+}
+
+// ProxyGroup - turns RTMP passthrough on or off for all cameras matching the group and label filter
+func (ph *rtspProcessHandler) ProxyGroup(c *gin.Context) {
+	var request models.GroupProxyRequest
+	if err := c.ShouldBindWith(&request, binding.JSON); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	ph.groupOperation(c, models.DeviceOperationUpdate, func(filter *models.ProcessFilter) ([]*models.GroupOperationResult, error) {
+		return ph.processManager.ProxyGroup(filter, request.Passthrough)
+	})
+}
+
+func (ph *rtspProcessHandler) groupOperation(c *gin.Context, operation string, perform func(filter *models.ProcessFilter) ([]*models.GroupOperationResult, error)) {
+	var filter models.ProcessFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Group == "" && len(filter.Labels) == 0 {
+		AbortWithError(c, http.StatusBadRequest, "group or label required")
+		return
+	}
+	results, err := perform(&filter)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// publish to chrysalis cloud the change
+	for _, r := range results {
+		if r.Success {
+			utils.PublishToRedis(ph.rdb, r.Name, models.MQTTProcessOperation(operation), models.ProcessTypeRTSP, nil)
+		}
+	}
+	c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// RuleTestInput sample to evaluate rules against (annotation or device process state)
+type RuleTestInput struct {
+	Rule       *models.Rule        `json:"rule,omitempty"`        // optional: evaluate this rule instead of stored rules
+	Annotation *pb.AnnotateRequest `json:"annotation,omitempty"`  // sample annotation (annotation trigger)
+	DeviceName string              `json:"device_name,omitempty"` // device name (process_state trigger)
+	State      string              `json:"state,omitempty"`       // process state (process_state trigger)
+}
+
+type ruleHandler struct {
+	ruleManager *services.RuleManager
+}
+
+func NewRuleHandler(ruleManager *services.RuleManager) *ruleHandler {
+	return &ruleHandler{
+		ruleManager: ruleManager,
+	}
+}
+
+// Put creates or overwrites an event rule
+func (rh *ruleHandler) Put(c *gin.Context) {
+	var rule models.Rule
+	if err := c.ShouldBindWith(&rule, binding.JSON); err != nil {
+		g.Log.Warn("missing required fields", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	stored, err := rh.ruleManager.Put(&rule)
+	if err != nil {
+		if err == models.ErrMissingInputParameters || err == models.ErrInvalidInputParameters {
+			AbortWithError(c, http.StatusBadRequest, "invalid rule: name, trigger (annotation or process_state) and valid actions (webhook, mqtt or clip) required")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, stored)
+}
+
+// List all event rules with hit counters
+func (rh *ruleHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, rh.ruleManager.List())
+}
+
+// Get a single event rule with hit counters
+func (rh *ruleHandler) Get(c *gin.Context) {
+	rule, err := rh.ruleManager.Get(c.Param("name"))
+	if err != nil {
+		if err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, "rule not found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// Delete removes the event rule
+func (rh *ruleHandler) Delete(c *gin.Context) {
+	err := rh.ruleManager.Delete(c.Param("name"))
+	if err != nil {
+		if err == models.ErrProcessNotFound {
+			AbortWithError(c, http.StatusNotFound, "rule not found")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// Test evaluates rules against a sample annotation or process state without performing actions
+func (rh *ruleHandler) Test(c *gin.Context) {
+	var input RuleTestInput
+	if err := c.ShouldBindWith(&input, binding.JSON); err != nil {
+		g.Log.Warn("invalid test input", err)
+		AbortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	results, err := rh.ruleManager.Test(input.Rule, input.Annotation, input.DeviceName, input.State)
+	if err != nil {
+		if err == models.ErrMissingInputParameters || err == models.ErrInvalidInputParameters {
+			AbortWithError(c, http.StatusBadRequest, "valid rule and annotation or device_name required")
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
@@ -42,7 +42,7 @@ func (sh *settingsHandler) Get(c *gin.Context) {
 		AbortWithError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, s)
+	c.JSON(http.StatusOK, s.Redacted())
 }
 
 // Overwrite settings
@@ -63,7 +63,7 @@ func (sh *settingsHandler) Overwrite(c *gin.Context) {
 		AbortWithError(c, http.StatusExpectationFailed, "Failed to store settings. Make sure you have an internet connection and try again")
 		return
 	}
-	c.JSON(http.StatusOK, newSett)
+	c.JSON(http.StatusOK, newSett.Redacted())
 }
 
 // DockerImagesLocally finds images that correspond with the image_name and returns has_downloadded or maybe if upgraded needed (newer version available)
@@ -0,0 +1,168 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var terminalUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// shell access: same origin or explicitly configured origins only (wildcard is not enough)
+	CheckOrigin: func(r *http.Request) bool { return websocketOriginAllowed(r, false) },
+}
+
+type terminalHandler struct {
+	terminalManager *services.TerminalManager
+}
+
+func NewTerminalHandler(terminalManager *services.TerminalManager) *terminalHandler {
+	return &terminalHandler{
+		terminalManager: terminalManager,
+	}
+}
+
+// Camera - websocket terminal into the camera container
+func (th *terminalHandler) Camera(c *gin.Context) {
+	th.terminal(c, models.ProcessTypeRTSP)
+}
+
+// App - websocket terminal into the app container
+func (th *terminalHandler) App(c *gin.Context) {
+	th.terminal(c, models.ProcessTypeApplication)
+}
+
+// terminal bridges the websocket with the shell TTY. Output is sent as binary messages, input is accepted as
+// binary messages (raw keystrokes) or models.TerminalMessage JSON text messages (input and resize).
+func (th *terminalHandler) terminal(c *gin.Context, processType string) {
+	name := c.Param("name")
+	if name == "" {
+		AbortWithError(c, http.StatusBadRequest, "required name")
+		return
+	}
+	if !th.terminalManager.Enabled() {
+		AbortWithError(c, http.StatusForbidden, "terminal disabled")
+		return
+	}
+	// shell access is admin only (regardless of the route group)
+	if principal := getPrincipal(c); principal == nil || principal.Role != models.RoleAdmin {
+		AbortWithError(c, http.StatusForbidden, "requires "+models.RoleAdmin+" role")
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		AbortWithError(c, http.StatusBadRequest, "websocket upgrade required")
+		return
+	}
+	// checked before the shell is started (the upgrader checks it again)
+	if !websocketOriginAllowed(c.Request, false) {
+		AbortWithError(c, http.StatusForbidden, "origin not allowed")
+		return
+	}
+
+	session, err := th.terminalManager.Open(context.Background(), processType, name, actor(c))
+	if err != nil {
+		switch err {
+		case models.ErrProcessNotFound, models.ErrProcessNotFoundDatastore:
+			AbortWithError(c, http.StatusNotFound, err.Error())
+		case models.ErrForbidden:
+			AbortWithError(c, http.StatusForbidden, err.Error())
+		case models.ErrProcessNotRunning:
+			AbortWithError(c, http.StatusConflict, err.Error())
+		default:
+			AbortWithError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer session.Close()
+
+	cols, _ := strconv.Atoi(c.Query("cols"))
+	rows, _ := strconv.Atoi(c.Query("rows"))
+	if cols > 0 && rows > 0 {
+		if err := session.Resize(uint(rows), uint(cols)); err != nil {
+			g.Log.Warn("failed to set initial terminal size", name, err)
+		}
+	}
+
+	conn, err := terminalUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		g.Log.Warn("failed to upgrade terminal to websocket", name, err)
+		return
+	}
+	defer conn.Close()
+
+	// shell output
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		buf := make([]byte, 4096)
+		for {
+			n, err := session.Read(buf)
+			if n > 0 {
+				if wErr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); wErr != nil {
+					return
+				}
+			}
+			if err != nil {
+				// shell exited
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	// client input
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		if msgType == websocket.BinaryMessage {
+			if _, err := session.Write(data); err != nil {
+				break
+			}
+			continue
+		}
+		var msg models.TerminalMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			g.Log.Warn("invalid terminal message", name, err)
+			continue
+		}
+		switch msg.Type {
+		case models.TerminalMessageInput:
+			_, err = session.Write([]byte(msg.Data))
+		case models.TerminalMessageResize:
+			if msg.Cols > 0 && msg.Rows > 0 {
+				err = session.Resize(msg.Rows, msg.Cols)
+			}
+		}
+		if err != nil {
+			g.Log.Warn("terminal input failed", name, err)
+			break
+		}
+	}
+	session.Close()
+	<-outputDone
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/adjust/rmq/v2"
 	"github.com/chryscloud/go-microkit-plugins/models/ai"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
 	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
 	"github.com/chryscloud/video-edge-ai-proxy/services"
 	"github.com/chryscloud/video-edge-ai-proxy/utils"
@@ -62,7 +63,7 @@ func (ac *AnnotationConsumer) Consume(batch rmq.Deliveries) {
 		return
 	}
 
-	var aiAnnotations []*ai.Annotation
+	var aiAnnotations []*models.Annotation
 
 	for _, b := range batch {
 		payload := []byte(b.Payload())
@@ -77,7 +78,7 @@ func (ac *AnnotationConsumer) Consume(batch rmq.Deliveries) {
 		aiAnnotations = append(aiAnnotations, &aiAnnotation)
 	}
 
-	sendPayload := ai.AnnotationList{
+	sendPayload := models.AnnotationList{
 		Data: aiAnnotations,
 	}
 
@@ -91,8 +92,9 @@ func (ac *AnnotationConsumer) Consume(batch rmq.Deliveries) {
 }
 
 // RequestToAnnotation (currently only REST supported on Chrysalis cloud. Later on GRPC just "push")
-func (ac *AnnotationConsumer) RequestToAnnotation(req *pb.AnnotateRequest) ai.Annotation {
-	aiAnnotation := ai.Annotation{
+func (ac *AnnotationConsumer) RequestToAnnotation(req *pb.AnnotateRequest) models.Annotation {
+	aiAnnotation := models.Annotation{}
+	aiAnnotation.Annotation = ai.Annotation{
 		DeviceName:       req.DeviceName,
 		Confidence:       req.Confidence,
 		CustomMeta1:      req.CustomMeta_1,
@@ -124,22 +126,71 @@ func (ac *AnnotationConsumer) RequestToAnnotation(req *pb.AnnotateRequest) ai.An
 			Lat: req.Location.Lat,
 			Lon: req.Location.Lon,
 		}
+		aiAnnotation.Heading = req.Location.Heading
 	}
-	if req.ObjectBoudingBox != nil {
-		aiAnnotation.ObjectBoundingBox = &ai.BoundingBox{
-			Height: req.ObjectBoudingBox.Height,
-			Width:  req.ObjectBoudingBox.Width,
-			Left:   req.ObjectBoudingBox.Left,
-			Top:    req.ObjectBoudingBox.Top,
+	aiAnnotation.ObjectBoundingBox = toBoundingBox(req.ObjectBoudingBox)
+	aiAnnotation.ObjectMask = toCoordinates(req.Mask)
+	aiAnnotation.ObjectCoordinate = toCoordinate(req.ObjectCoordinate)
+	aiAnnotation.ObjectPath = toCoordinates(req.ObjectPath)
+	aiAnnotation.IsTrack = req.IsTrack
+	aiAnnotation.Attributes = req.Attributes
+	aiAnnotation.Labels = toLabels(req.Labels)
+	for _, obj := range req.Objects {
+		if obj == nil {
+			continue
 		}
+		aiAnnotation.Objects = append(aiAnnotation.Objects, &models.AnnotationObject{
+			ObjectType:        obj.ObjectType,
+			ObjectID:          obj.ObjectId,
+			ObjectTrackingID:  obj.ObjectTrackingId,
+			Confidence:        obj.Confidence,
+			ObjectBoundingBox: toBoundingBox(obj.ObjectBoudingBox),
+			ObjectCoordinate:  toCoordinate(obj.ObjectCoordinate),
+			ObjectMask:        toCoordinates(obj.Mask),
+			ObjectSignature:   obj.ObjectSignature,
+			Attributes:        obj.Attributes,
+			Labels:            toLabels(obj.Labels),
+		})
+	}
+
+	return aiAnnotation
+}
+
+func toBoundingBox(bb *pb.BoudingBox) *ai.BoundingBox {
+	if bb == nil {
+		return nil
+	}
+	return &ai.BoundingBox{
+		Height: bb.Height,
+		Width:  bb.Width,
+		Left:   bb.Left,
+		Top:    bb.Top,
+	}
+}
+
+func toCoordinate(c *pb.Coordinate) *ai.Coordinate {
+	if c == nil {
+		return nil
 	}
-	if req.Mask != nil {
-		var maskPolygon []*ai.Coordinate
-		for _, m := range req.Mask {
-			maskPolygon = append(maskPolygon, &ai.Coordinate{X: m.X, Y: m.Y, Z: m.Z})
+	return &ai.Coordinate{X: c.X, Y: c.Y, Z: c.Z}
+}
+
+func toCoordinates(coordinates []*pb.Coordinate) []*ai.Coordinate {
+	var result []*ai.Coordinate
+	for _, c := range coordinates {
+		if c != nil {
+			result = append(result, toCoordinate(c))
 		}
-		aiAnnotation.ObjectMask = maskPolygon
 	}
+	return result
+}
 
-	return aiAnnotation
+func toLabels(labels []*pb.Classification) []*models.AnnotationLabel {
+	var result []*models.AnnotationLabel
+	for _, l := range labels {
+		if l != nil {
+			result = append(result, &models.AnnotationLabel{Label: l.Label, Confidence: l.Confidence})
+		}
+	}
+	return result
 }
@@ -0,0 +1,72 @@
+package batch
+
+import (
+	"encoding/json"
+	"testing"
+
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+)
+
+func TestRequestToAnnotationObjects(t *testing.T) {
+	req := &pb.AnnotateRequest{
+		DeviceName:     "cam1",
+		Type:           "detection",
+		StartTimestamp: 1600000000000,
+		CustomMeta_1:   "legacy",
+		Attributes:     map[string]string{"zone": "entrance", "weather": "rain"},
+		Labels:         []*pb.Classification{{Label: "crowded", Confidence: 0.7}},
+		Objects: []*pb.DetectedObject{
+			{
+				ObjectType:       "person",
+				ObjectTrackingId: "1",
+				Confidence:       0.9,
+				ObjectBoudingBox: &pb.BoudingBox{Left: 1, Top: 2, Width: 3, Height: 4},
+				Attributes:       map[string]string{"zone": "exit"},
+				Labels:           []*pb.Classification{{Label: "helmet", Confidence: 0.8}},
+			},
+			{ObjectType: "car", Confidence: 0.6},
+		},
+	}
+
+	ac := &AnnotationConsumer{}
+	annotation := ac.RequestToAnnotation(req)
+	if annotation.CustomMeta1 != "legacy" || annotation.Attributes["weather"] != "rain" {
+		t.Fatalf("expected legacy and attribute meta data, got %v", annotation)
+	}
+	if len(annotation.Labels) != 1 || annotation.Labels[0].Label != "crowded" {
+		t.Fatalf("expected frame labels, got %v", annotation.Labels)
+	}
+	if len(annotation.Objects) != 2 || annotation.Objects[0].ObjectBoundingBox.Height != 4 || annotation.Objects[0].Labels[0].Label != "helmet" {
+		t.Fatalf("expected detected objects, got %v", annotation.Objects)
+	}
+	b, err := json.Marshal(annotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded["objects"]; !ok {
+		t.Fatalf("expected objects in json payload: %s", b)
+	}
+
+	expanded := utils.ExpandAnnotationObjects(req)
+	if len(expanded) != 2 {
+		t.Fatalf("expected one annotation per object, got %v", len(expanded))
+	}
+	first := expanded[0]
+	if first.ObjectType != "person" || first.ObjectTrackingId != "1" || first.StartTimestamp != req.StartTimestamp || len(first.Objects) != 0 {
+		t.Fatalf("unexpected expanded annotation %v", first)
+	}
+	if first.Attributes["zone"] != "exit" || first.Attributes["weather"] != "rain" || req.Attributes["zone"] != "entrance" {
+		t.Fatalf("expected object attributes merged over request attributes, got %v", first.Attributes)
+	}
+	if len(first.Labels) != 2 || first.Labels[0].Label != "helmet" || first.Labels[1].Label != "crowded" {
+		t.Fatalf("expected object labels merged with frame labels, got %v", first.Labels)
+	}
+	if len(expanded[1].Labels) != 1 || expanded[1].Labels[0].Label != "crowded" || expanded[1].ObjectBoudingBox != nil {
+		t.Fatalf("expected frame labels and no bounding box on second object, got %v", expanded[1])
+	}
+}
@@ -0,0 +1,215 @@
+package batch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/adjust/rmq/v2"
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	defaultTrackIdleTimeout = time.Second * 5
+	defaultTrackMaxDuration = time.Minute // continuously detected objects are reported at least this often
+	defaultMaxOpenTracks    = 10000       // oldest track is closed when more tracks are open
+	maxTrackPathLength      = 256         // path is downsampled when it grows above this length
+)
+
+// TrackAggregator merges annotations sharing device, type and tracking id into a single track event
+// which is queued for sending to Chrysalis Cloud once the track is idle
+type TrackAggregator struct {
+	msgQueue    rmq.Queue
+	idleTimeout time.Duration
+	maxDuration time.Duration
+	maxTracks   int
+	mux         sync.Mutex
+	tracks      map[string]*openTrack
+	ticker      *time.Ticker
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+// openTrack is a track event still receiving annotations
+type openTrack struct {
+	event    *pb.AnnotateRequest
+	opened   time.Time
+	lastSeen time.Time
+}
+
+// NewTrackAggregator - tracks are closed when idle for idleTimeout or open for maxDuration (re-opened on the next annotation),
+// the oldest track is closed when more than maxTracks are open
+func NewTrackAggregator(msgQueue rmq.Queue, idleTimeout time.Duration, maxDuration time.Duration, maxTracks int) *TrackAggregator {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultTrackIdleTimeout
+	}
+	if maxDuration <= 0 {
+		maxDuration = defaultTrackMaxDuration
+	}
+	if maxTracks <= 0 {
+		maxTracks = defaultMaxOpenTracks
+	}
+	ta := &TrackAggregator{
+		msgQueue:    msgQueue,
+		idleTimeout: idleTimeout,
+		maxDuration: maxDuration,
+		maxTracks:   maxTracks,
+		tracks:      make(map[string]*openTrack),
+		ticker:      time.NewTicker(time.Second),
+		stop:        make(chan struct{}),
+	}
+
+	// check every second for idle tracks
+	go func() {
+		for {
+			select {
+			case t := <-ta.ticker.C:
+				ta.closeIdle(t)
+			case <-ta.stop:
+				return
+			}
+		}
+	}()
+
+	return ta
+}
+
+// Aggregates returns true if the annotation can be aggregated into a track event (has tracking id)
+func (ta *TrackAggregator) Aggregates(req *pb.AnnotateRequest) bool {
+	return req.ObjectTrackingId != ""
+}
+
+// Add merges the annotation into the open track event. Returns false if annotation can't be aggregated (no tracking id)
+func (ta *TrackAggregator) Add(req *pb.AnnotateRequest) bool {
+	if !ta.Aggregates(req) {
+		return false
+	}
+	key := req.DeviceName + "/" + req.Type + "/" + req.ObjectTrackingId
+	endTimestamp := req.EndTimestamp
+	if endTimestamp < req.StartTimestamp {
+		endTimestamp = req.StartTimestamp
+	}
+
+	now := time.Now()
+	var closed []*pb.AnnotateRequest
+
+	ta.mux.Lock()
+	defer func() {
+		ta.mux.Unlock()
+		ta.publish(closed)
+	}()
+
+	track, ok := ta.tracks[key]
+	if ok && now.Sub(track.opened) >= ta.maxDuration {
+		// long running track is reported and continues as a new track event
+		closed = append(closed, track.event)
+		delete(ta.tracks, key)
+		ok = false
+	}
+	if !ok {
+		if len(ta.tracks) >= ta.maxTracks {
+			if oldest := ta.oldestTrack(); oldest != "" {
+				closed = append(closed, ta.tracks[oldest].event)
+				delete(ta.tracks, oldest)
+			}
+		}
+		event := proto.Clone(req).(*pb.AnnotateRequest)
+		event.IsTrack = true
+		event.ObjectPath = nil
+		event.EndTimestamp = endTimestamp
+		track = &openTrack{event: event, opened: now}
+		ta.tracks[key] = track
+	} else {
+		event := track.event
+		// the most confident annotation represents the track (bounding box, mask, ...)
+		if req.Confidence > event.Confidence {
+			representative := proto.Clone(req).(*pb.AnnotateRequest)
+			representative.IsTrack = true
+			representative.StartTimestamp = event.StartTimestamp
+			representative.EndTimestamp = event.EndTimestamp
+			representative.ObjectPath = event.ObjectPath
+			event = representative
+			track.event = event
+		}
+		if req.StartTimestamp < event.StartTimestamp {
+			event.StartTimestamp = req.StartTimestamp
+		}
+		if endTimestamp > event.EndTimestamp {
+			event.EndTimestamp = endTimestamp
+		}
+	}
+
+	if center, ok := utils.AnnotationCenter(req); ok {
+		path := track.event.ObjectPath
+		if len(path) >= maxTrackPathLength {
+			downsampled := make([]*pb.Coordinate, 0, len(path)/2+1)
+			for i := 0; i < len(path); i += 2 {
+				downsampled = append(downsampled, path[i])
+			}
+			path = downsampled
+		}
+		track.event.ObjectPath = append(path, &pb.Coordinate{X: center.X, Y: center.Y, Z: center.Z})
+	}
+	track.lastSeen = now
+
+	return true
+}
+
+// oldestTrack returns the key of the track opened first (expected to be called under lock)
+func (ta *TrackAggregator) oldestTrack() string {
+	oldestKey := ""
+	var oldest time.Time
+	for key, track := range ta.tracks {
+		if oldestKey == "" || track.opened.Before(oldest) {
+			oldestKey = key
+			oldest = track.opened
+		}
+	}
+	return oldestKey
+}
+
+// Flush closes all open tracks
+func (ta *TrackAggregator) Flush() {
+	ta.closeIdle(time.Now().Add(ta.idleTimeout))
+}
+
+// Stop stops checking for idle tracks and queues all open tracks (on shutdown)
+func (ta *TrackAggregator) Stop() {
+	ta.stopOnce.Do(func() {
+		ta.ticker.Stop()
+		close(ta.stop)
+		ta.Flush()
+	})
+}
+
+// closeIdle queues track events that haven't received annotations within the idle timeout or are open for max duration
+func (ta *TrackAggregator) closeIdle(now time.Time) {
+	closed := make([]*pb.AnnotateRequest, 0)
+
+	ta.mux.Lock()
+	for key, track := range ta.tracks {
+		if now.Sub(track.lastSeen) >= ta.idleTimeout || now.Sub(track.opened) >= ta.maxDuration {
+			closed = append(closed, track.event)
+			delete(ta.tracks, key)
+		}
+	}
+	ta.mux.Unlock()
+
+	ta.publish(closed)
+}
+
+// publish queues closed track events
+func (ta *TrackAggregator) publish(closed []*pb.AnnotateRequest) {
+	for _, event := range closed {
+		eventBytes, err := proto.Marshal(event)
+		if err != nil {
+			g.Log.Error("failed to marshal track event", event.DeviceName, event.ObjectTrackingId, err)
+			continue
+		}
+		if ok := ta.msgQueue.PublishBytes(eventBytes); !ok {
+			g.Log.Error("failed to publish track event to msg queue", event.DeviceName, event.ObjectTrackingId)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package batch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adjust/rmq/v2"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestTrackAggregation(t *testing.T) {
+	queue := rmq.NewTestQueue("annotationqueue")
+	ta := NewTrackAggregator(queue, time.Hour, time.Hour, 0)
+	defer ta.Stop()
+
+	ts := time.Now().Unix() * 1000
+	for i := 0; i < 10; i++ {
+		ok := ta.Add(&pb.AnnotateRequest{
+			DeviceName:       "cam1",
+			Type:             "moving",
+			ObjectType:       "person",
+			ObjectTrackingId: "42",
+			StartTimestamp:   ts + int64(i*100),
+			Confidence:       0.5 + float64(i%5)/10,
+			ObjectBoudingBox: &pb.BoudingBox{Left: int32(i * 10), Top: 0, Width: 10, Height: 10},
+		})
+		if !ok {
+			t.Fatal("expected annotation to be aggregated")
+		}
+	}
+	if ta.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", StartTimestamp: ts}) {
+		t.Fatal("annotation without tracking id can't be aggregated")
+	}
+	if len(queue.LastDeliveries) != 0 {
+		t.Fatal("track shouldn't be closed before idle timeout")
+	}
+
+	ta.Flush()
+	if len(queue.LastDeliveries) != 1 {
+		t.Fatalf("expected 1 track event, got %v", len(queue.LastDeliveries))
+	}
+	var event pb.AnnotateRequest
+	err := proto.Unmarshal([]byte(queue.LastDeliveries[0]), &event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !event.IsTrack || event.StartTimestamp != ts || event.EndTimestamp != ts+900 {
+		t.Fatalf("unexpected track event time range: %v - %v", event.StartTimestamp, event.EndTimestamp)
+	}
+	if event.Confidence != 0.9 || event.ObjectBoudingBox.Left != 40 {
+		t.Fatalf("expected the most confident annotation to represent the track, got %v, %v", event.Confidence, event.ObjectBoudingBox)
+	}
+	if len(event.ObjectPath) != 10 || event.ObjectPath[9].X != 95 {
+		t.Fatalf("unexpected track path %v", event.ObjectPath)
+	}
+}
+
+func TestTrackAggregatorStop(t *testing.T) {
+	queue := rmq.NewTestQueue("annotationqueue")
+	ta := NewTrackAggregator(queue, time.Hour, time.Hour, 0)
+
+	ta.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", ObjectTrackingId: "1", StartTimestamp: time.Now().Unix() * 1000})
+	ta.Stop()
+	if len(queue.LastDeliveries) != 1 {
+		t.Fatalf("expected open track to be queued on stop, got %v", len(queue.LastDeliveries))
+	}
+	ta.Stop()
+	if len(queue.LastDeliveries) != 1 {
+		t.Fatalf("expected stop to be idempotent, got %v", len(queue.LastDeliveries))
+	}
+}
+
+func TestTrackAggregatorLimits(t *testing.T) {
+	queue := rmq.NewTestQueue("annotationqueue")
+	ta := NewTrackAggregator(queue, time.Hour, 50*time.Millisecond, 2)
+	defer ta.Stop()
+
+	ts := time.Now().Unix() * 1000
+	// continuously detected object is reported after max duration and continues as a new track
+	ta.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", ObjectTrackingId: "parked", StartTimestamp: ts})
+	time.Sleep(60 * time.Millisecond)
+	ta.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", ObjectTrackingId: "parked", StartTimestamp: ts + 60})
+	if len(queue.LastDeliveries) != 1 {
+		t.Fatalf("expected long running track to be queued, got %v", len(queue.LastDeliveries))
+	}
+	var event pb.AnnotateRequest
+	if err := proto.Unmarshal([]byte(queue.LastDeliveries[0]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.ObjectTrackingId != "parked" || event.StartTimestamp != ts {
+		t.Fatalf("unexpected long running track %v", &event)
+	}
+
+	// the oldest track is closed when too many tracks are open
+	ta.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", ObjectTrackingId: "a", StartTimestamp: ts + 70})
+	ta.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", ObjectTrackingId: "b", StartTimestamp: ts + 80})
+	if len(queue.LastDeliveries) != 2 {
+		t.Fatalf("expected oldest track to be queued, got %v", len(queue.LastDeliveries))
+	}
+	if err := proto.Unmarshal([]byte(queue.LastDeliveries[1]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.ObjectTrackingId != "parked" || event.StartTimestamp != ts+60 {
+		t.Fatalf("expected the oldest track to be closed, got %v", &event)
+	}
+	if len(ta.tracks) != 2 {
+		t.Fatalf("expected 2 open tracks, got %v", len(ta.tracks))
+	}
+}
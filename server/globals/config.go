@@ -32,6 +32,17 @@ type Config struct {
 	Annotation     *AnnotationSubconfig `yaml:"annotation"`
 	API            *ApiSubconfig        `yaml:"api"`
 	Buffer         *BufferSubconfig     `yaml:"buffer"`
+	Counting       *CountingSubconfig   `yaml:"counting"`
+	Signature      *SignatureSubconfig  `yaml:"signature"`
+	Enrichment     *EnrichmentSubconfig `yaml:"enrichment"`
+	Watchdog       *WatchdogSubconfig   `yaml:"watchdog"`
+	Reconcile      *ReconcileSubconfig  `yaml:"reconcile"`
+	Runtime        *RuntimeSubconfig    `yaml:"runtime"`
+	Terminal       *TerminalSubconfig   `yaml:"terminal"`
+	Auth           *AuthSubconfig       `yaml:"auth"`
+	Audit          *AuditSubconfig      `yaml:"audit"`
+	Manifest       *ManifestSubconfig   `yaml:"manifest"`
+	Secrets        *SecretsSubconfig    `yaml:"secrets"`
 }
 
 // RedisSubconfig connnection settings
@@ -43,10 +54,15 @@ type RedisSubconfig struct {
 
 // AnnotationSubconfig - annotation consumer rates
 type AnnotationSubconfig struct {
-	Endpoint       string `yaml:"endpoint"`         // chryscloud annotation endpoint
-	UnackedLimit   int    `yaml:"unacked_limit"`    // maximum number of unacknowledged annotations
-	PollDurationMs int    `yaml:"poll_duration_ms"` // time to wait until new poll of annotations (miliseconds)
-	MaxBatchSize   int    `yaml:"max_batch_size"`   // maximum number of events processed in one batch
+	Endpoint           string `yaml:"endpoint"`              // chryscloud annotation endpoint
+	UnackedLimit       int    `yaml:"unacked_limit"`         // maximum number of unacknowledged annotations
+	PollDurationMs     int    `yaml:"poll_duration_ms"`      // time to wait until new poll of annotations (miliseconds)
+	MaxBatchSize       int    `yaml:"max_batch_size"`        // maximum number of events processed in one batch
+	Forward            string `yaml:"forward"`               // what to forward to chryscloud: raw, tracks or both (default raw)
+	TrackIdleTimeoutMs int    `yaml:"track_idle_timeout_ms"` // close the track event after no annotations for X miliseconds
+	TrackMaxDurationMs int    `yaml:"track_max_duration_ms"` // close (and re-open) the track event after it's open for X miliseconds (default 1 minute)
+	MaxOpenTracks      int    `yaml:"max_open_tracks"`       // close the oldest track event when more tracks are open (default 10000)
+	StreamFlushMs      int    `yaml:"stream_flush_ms"`       // AnnotateStream: queue and acknowledge received annotations at least every X miliseconds
 }
 
 // VideoApiSubconfig - video api specifics
@@ -64,6 +80,88 @@ type BufferSubconfig struct {
 	OnDiskSchedule         string `yaml:"on_disk_schedule"`         // schedule cleanup every X duration
 }
 
+// CountingSubconfig - line crossing and occupancy counting from tracked annotations
+type CountingSubconfig struct {
+	TrackTimeoutMs int `yaml:"track_timeout_ms"` // forget a tracked object after not being seen for X miliseconds
+	RetentionDays  int `yaml:"retention_days"`   // how long to keep counting time series (0 = forever)
+}
+
+// SignatureSubconfig - local object signature similarity search
+type SignatureSubconfig struct {
+	MaxEntries       int `yaml:"max_entries"`       // maximum number of indexed signatures (all devices and object types)
+	RetentionMinutes int `yaml:"retention_minutes"` // forget signatures older than X minutes
+	MaxDimension     int `yaml:"max_dimension"`     // signatures with more dimensions are ignored and can't be searched (default 2048)
+	MaxIndexes       int `yaml:"max_indexes"`       // maximum number of device and object type indexes (default 256)
+}
+
+// EnrichmentSubconfig - server side annotation enrichers (all enabled when section is missing)
+type EnrichmentSubconfig struct {
+	RemoteStreamID bool `yaml:"remote_stream_id"` // remote_stream_id from the camera RTMP key
+	Dimensions     bool `yaml:"dimensions"`       // width and height from the stream video codec
+	Location       bool `yaml:"location"`         // location and heading from the camera metadata
+	Timestamp      bool `yaml:"timestamp"`        // relative pts timestamps normalized to server time
+}
+
+// WatchdogSubconfig - camera stream stall detection and automatic recovery
+type WatchdogSubconfig struct {
+	CheckIntervalMs     int  `yaml:"check_interval_ms"`      // how often to check the age of the newest frame/packet
+	StallTimeoutMs      int  `yaml:"stall_timeout_ms"`       // stream is stalled when no frames/packets for X miliseconds
+	Restart             bool `yaml:"restart"`                // restart stalled camera containers
+	RestartBackoffMs    int  `yaml:"restart_backoff_ms"`     // wait after the first restart (doubled on every consecutive restart)
+	RestartMaxBackoffMs int  `yaml:"restart_max_backoff_ms"` // maximum wait between consecutive restarts
+}
+
+// ReconcileSubconfig - desired state (datastore) reconciliation with running docker containers
+type ReconcileSubconfig struct {
+	IntervalMs    int  `yaml:"interval_ms"`    // reconcile every X miliseconds (and on boot)
+	Adopt         bool `yaml:"adopt"`          // create datastore records for camera containers without one
+	RemoveOrphans bool `yaml:"remove_orphans"` // remove containers created by the edge proxy without datastore record
+}
+
+// RuntimeSubconfig - container runtime the camera and app containers are managed with
+type RuntimeSubconfig struct {
+	Type       string `yaml:"type"`        // docker (default) or memory (in-memory runtime without real containers, for development)
+	Host       string `yaml:"host"`        // docker daemon host (default unix:///var/run/docker.sock)
+	APIVersion string `yaml:"api_version"` // docker API version (default 1.40)
+	TLSCACert  string `yaml:"tls_ca_cert"` // path to the CA certificate of a remote docker daemon
+	TLSCert    string `yaml:"tls_cert"`    // path to the client certificate for a remote docker daemon
+	TLSKey     string `yaml:"tls_key"`     // path to the client key for a remote docker daemon
+}
+
+// TerminalSubconfig - interactive terminal into camera and app containers (enabled when section is missing)
+type TerminalSubconfig struct {
+	Enabled bool   `yaml:"enabled"` // allow terminal sessions
+	Shell   string `yaml:"shell"`   // command started in the container (default /bin/sh)
+}
+
+// AuthSubconfig - REST API authentication (enabled when section is missing)
+type AuthSubconfig struct {
+	Enabled                bool     `yaml:"enabled"`                  // require authentication on REST API
+	AllowOrigins           []string `yaml:"allow_origins"`            // CORS allowed origins (default none, same origin only)
+	TokenExpirationMinutes int      `yaml:"token_expiration_minutes"` // validity of tokens issued after login (default 12 hours)
+}
+
+// AuditSubconfig - retention of the audit log (records beyond either limit are removed, 0 for no limit)
+type AuditSubconfig struct {
+	MaxRecords    int `yaml:"max_records"`    // keep at most X newest records
+	RetentionDays int `yaml:"retention_days"` // keep records for X days
+}
+
+// ManifestSubconfig - cameras and apps declared in a YAML manifest file (disabled when section is missing)
+type ManifestSubconfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Path       string `yaml:"path"`        // manifest file, relative to the data directory (default manifest.yaml)
+	IntervalMs int    `yaml:"interval_ms"` // check the manifest and drift every X miliseconds
+	Policy     string `yaml:"policy"`      // manual changes: reject (default) or flag
+	SelfHeal   bool   `yaml:"self_heal"`   // apply the manifest on every check (otherwise drift is only reported)
+	Prune      bool   `yaml:"prune"`       // remove cameras and apps not in the manifest
+}
+
+// SecretsSubconfig - encryption of camera credentials and cloud secrets at rest
+type SecretsSubconfig struct {
+	MasterKeyFile string `yaml:"master_key_file"` // master key, relative to the data directory (default master.key, created when missing)
+}
+
 func init() {
 	l, err := mclog.NewZapLogger("info")
 	if err != nil {
@@ -2,17 +2,169 @@ package grpcapi
 
 import (
 	"context"
+	"io"
 	"time"
 
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
 	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const defaultAnnotateStreamFlushMs = 100
+
 // Annotate queues a new annotation event to be sent to Chrysalis event servers
 func (gih *grpcImageHandler) Annotate(ctx context.Context, req *pb.AnnotateRequest) (*pb.AnnotateResponse, error) {
+	annotation, err := gih.prepareAnnotation(req)
+	if err != nil {
+		return nil, err
+	}
+	// queue first: a failed publish is retried by the client and must not repeat local processing
+	if annotation.payload != nil {
+		ok := gih.msgQueue.PublishBytes(annotation.payload)
+		if !ok {
+			g.Log.Error("failed to publish to msg queue", ok)
+			return nil, status.Errorf(codes.Internal, "failed to publish to msg queue")
+		}
+	}
+	gih.processAnnotation(annotation)
+
+	resp := &pb.AnnotateResponse{
+		DeviceName:     req.DeviceName,
+		StartTimestamp: req.StartTimestamp,
+		Type:           req.Type,
+	}
+	return resp, nil
+}
+
+// AnnotateStream accepts a continuous stream of annotations. Accepted annotations are queued in batches and
+// acknowledged cumulatively by sequence number. Unacknowledged annotations should be resent by the client (at-least-once).
+func (gih *grpcImageHandler) AnnotateStream(stream pb.Image_AnnotateStreamServer) error {
+	batchSize := g.Conf.Annotation.MaxBatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	flushMs := g.Conf.Annotation.StreamFlushMs
+	if flushMs <= 0 {
+		flushMs = defaultAnnotateStreamFlushMs
+	}
+
+	// bounded channel: when the queue can't keep up receiving stops and grpc flow control pushes back on the client
+	received := make(chan *pb.AnnotateRequest, batchSize)
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(received)
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case received <- req:
+			case <-stream.Context().Done():
+				recvErr <- stream.Context().Err()
+				return
+			}
+		}
+	}()
+
+	var lastSequence int64
+	pending := make([]*pb.AnnotateRequest, 0, batchSize)
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		ack := &pb.AnnotateAck{}
+		accepted := make([]*preparedAnnotation, 0, len(pending))
+		payloads := make([][]byte, 0, len(pending))
+		for _, req := range pending {
+			annotation, err := gih.prepareAnnotation(req)
+			if err != nil {
+				ack.Rejections = append(ack.Rejections, annotateRejection(req, err))
+				continue
+			}
+			if annotation.payload != nil {
+				payloads = append(payloads, annotation.payload)
+			}
+			accepted = append(accepted, annotation)
+			ack.Accepted++
+		}
+		// queue first: the unacknowledged batch is resent by the client and must not repeat local processing
+		if len(payloads) > 0 {
+			if ok := gih.msgQueue.PublishBytes(payloads...); !ok {
+				g.Log.Error("failed to publish annotation batch to msg queue", len(payloads))
+				return status.Errorf(codes.Unavailable, "failed to publish to msg queue")
+			}
+		}
+		for _, annotation := range accepted {
+			gih.processAnnotation(annotation)
+		}
+		ack.Sequence = pending[len(pending)-1].Sequence
+		pending = pending[:0]
+		return stream.Send(ack)
+	}
+
+	ticker := time.NewTicker(time.Duration(flushMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case req, ok := <-received:
+			if !ok {
+				err := <-recvErr
+				if fErr := flush(); fErr != nil {
+					return fErr
+				}
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if req.Sequence == 0 {
+				req.Sequence = lastSequence + 1
+			}
+			if req.Sequence <= lastSequence {
+				// cumulative acks require increasing sequence numbers
+				rejection := annotateRejection(req, status.Errorf(codes.InvalidArgument, "sequence must be greater than %d", lastSequence))
+				if err := flush(); err != nil {
+					return err
+				}
+				if err := stream.Send(&pb.AnnotateAck{Sequence: lastSequence, Rejections: []*pb.AnnotateRejection{rejection}}); err != nil {
+					return err
+				}
+				continue
+			}
+			lastSequence = req.Sequence
+			pending = append(pending, req)
+			if len(pending) >= batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// preparedAnnotation is a validated annotation ready to be queued and processed locally
+type preparedAnnotation struct {
+	objects []*pb.AnnotateRequest // annotation expanded per detected object
+	payload []byte                // marshalled annotation for Chrysalis Cloud, nil if aggregated into track events
+}
+
+// prepareAnnotation validates and enriches the annotation and marshals what is queued for Chrysalis Cloud.
+// It has no local side effects, those are performed by processAnnotation once the annotation is queued.
+func (gih *grpcImageHandler) prepareAnnotation(req *pb.AnnotateRequest) (*preparedAnnotation, error) {
 	if gih.edgeKey == nil {
 		settings, err := gih.settingsManager.Get()
 		if err != nil {
@@ -28,30 +180,86 @@ func (gih *grpcImageHandler) Annotate(ctx context.Context, req *pb.AnnotateReque
 	if req.DeviceName == "" || req.Type == "" || req.StartTimestamp < 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "device_name and type (event type) required")
 	}
+
+	// fill in missing stream id, dimensions, location and normalize relative timestamps
+	gih.annotationEnricher.Enrich(req)
+
 	if req.StartTimestamp < weekPast || req.StartTimestamp > weekFuture {
 		return nil, status.Errorf(codes.InvalidArgument, "start_timestamp must not be older than 7 days and not more than 7 days in the future")
 	}
 
-	edgeKey := *gih.edgeKey
-	if edgeKey == "" {
-		g.Log.Info("WTF>")
+	forward := g.Conf.Annotation.Forward
+	trackForwarding := forward == models.AnnotationForwardTracks || forward == models.AnnotationForwardBoth
+	annotation := &preparedAnnotation{objects: utils.ExpandAnnotationObjects(req)}
+
+	aggregated := false
+	if trackForwarding {
+		for _, single := range annotation.objects {
+			if gih.trackAggregator.Aggregates(single) {
+				aggregated = true
+			}
+		}
 	}
 
+	// forward raw annotation unless aggregated into track events
+	if aggregated && forward != models.AnnotationForwardBoth {
+		if len(req.Objects) == 0 {
+			return annotation, nil
+		}
+		// objects without tracking id are still forwarded as they are
+		untracked := make([]*pb.DetectedObject, 0)
+		for _, obj := range req.Objects {
+			if obj != nil && obj.ObjectTrackingId == "" {
+				untracked = append(untracked, obj)
+			}
+		}
+		if len(untracked) == 0 {
+			return annotation, nil
+		}
+		req = proto.Clone(req).(*pb.AnnotateRequest)
+		req.Objects = untracked
+	}
 	reqBytes, err := proto.Marshal(req)
 	if err != nil {
 		g.Log.Error("invalid proto format for annotation", err)
 		return nil, status.Errorf(codes.InvalidArgument, "invalid annotation proto format")
 	}
-	ok := gih.msgQueue.PublishBytes(reqBytes)
-	if !ok {
-		g.Log.Error("failed to publish to msg queue", ok)
-		return nil, status.Errorf(codes.Internal, "failed to publish to msg queue")
+	annotation.payload = reqBytes
+	return annotation, nil
+}
+
+// processAnnotation passes the queued annotation through local processing (counting, rules, ...)
+func (gih *grpcImageHandler) processAnnotation(annotation *preparedAnnotation) {
+	forward := g.Conf.Annotation.Forward
+	trackForwarding := forward == models.AnnotationForwardTracks || forward == models.AnnotationForwardBoth
+
+	// local processing per detected object
+	for _, single := range annotation.objects {
+		// line crossing and occupancy counting
+		gih.countingManager.Process(single)
+
+		// keep recent annotations locally (debug overlays)
+		gih.annotationStore.Add(single)
+
+		// index object signature for local similarity search
+		gih.signatureManager.Add(single)
+
+		// event rules (actions are performed asynchronously)
+		gih.ruleManager.EvaluateAnnotation(single)
+
+		// aggregate into track events
+		if trackForwarding {
+			gih.trackAggregator.Add(single)
+		}
 	}
+}
 
-	resp := &pb.AnnotateResponse{
-		DeviceName:     req.DeviceName,
-		StartTimestamp: req.StartTimestamp,
-		Type:           req.Type,
+func annotateRejection(req *pb.AnnotateRequest, err error) *pb.AnnotateRejection {
+	st, _ := status.FromError(err)
+	return &pb.AnnotateRejection{
+		Sequence:   req.Sequence,
+		DeviceName: req.DeviceName,
+		Code:       int32(st.Code()),
+		Message:    st.Message(),
 	}
-	return resp, nil
 }
@@ -0,0 +1,181 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adjust/rmq/v2"
+	"github.com/chryscloud/video-edge-ai-proxy/batch"
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	badger "github.com/dgraph-io/badger/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testQueue records published payloads, publishing fails when fail is set
+type testQueue struct {
+	rmq.Queue
+	mux       sync.Mutex
+	fail      bool
+	published [][][]byte
+}
+
+func (q *testQueue) PublishBytes(payload ...[]byte) bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	if q.fail {
+		return false
+	}
+	q.published = append(q.published, payload)
+	return true
+}
+
+// testAnnotateStream replays the requests and records the acknowledgements
+type testAnnotateStream struct {
+	grpc.ServerStream
+	requests []*pb.AnnotateRequest
+	acks     []*pb.AnnotateAck
+}
+
+func (s *testAnnotateStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *testAnnotateStream) Recv() (*pb.AnnotateRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *testAnnotateStream) Send(ack *pb.AnnotateAck) error {
+	s.acks = append(s.acks, ack)
+	return nil
+}
+
+func setupAnnotationHandler(t *testing.T, queue rmq.Queue) *grpcImageHandler {
+	conf := g.Conf
+	g.Conf.Annotation = &g.AnnotationSubconfig{MaxBatchSize: 3, StreamFlushMs: 60000}
+
+	folder, err := ioutil.TempDir("", "grpcannotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := badger.Open(badger.DefaultOptions(folder).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := services.NewStorage(db)
+	countingManager := services.NewCountingManager(storage)
+	signatureManager := services.NewSignatureManager()
+	trackAggregator := batch.NewTrackAggregator(queue, time.Minute, time.Hour, 0)
+	ruleManager := services.NewRuleManager(storage, nil, countingManager, services.NewMemoryRuntime())
+	t.Cleanup(func() {
+		trackAggregator.Stop()
+		ruleManager.Stop()
+		signatureManager.Stop()
+		countingManager.Close()
+		db.Close()
+		os.RemoveAll(folder)
+		g.Conf = conf
+	})
+
+	edgeKey := "testkey"
+	return &grpcImageHandler{
+		countingManager:    countingManager,
+		signatureManager:   signatureManager,
+		ruleManager:        ruleManager,
+		annotationStore:    services.NewAnnotationStore(),
+		annotationEnricher: services.NewAnnotationEnricher(storage, nil),
+		edgeKey:            &edgeKey,
+		msgQueue:           queue,
+		trackAggregator:    trackAggregator,
+	}
+}
+
+func testAnnotations(count int) []*pb.AnnotateRequest {
+	ts := time.Now().Unix() * 1000
+	requests := make([]*pb.AnnotateRequest, 0, count)
+	for i := 0; i < count; i++ {
+		requests = append(requests, &pb.AnnotateRequest{DeviceName: "streamcam", Type: "moving", StartTimestamp: ts + int64(i)})
+	}
+	return requests
+}
+
+func TestAnnotateStreamBatching(t *testing.T) {
+	queue := &testQueue{}
+	gih := setupAnnotationHandler(t, queue)
+
+	stream := &testAnnotateStream{requests: testAnnotations(7)}
+	if err := gih.AnnotateStream(stream); err != nil {
+		t.Fatal(err)
+	}
+
+	// full batches and the remainder when the client closes the stream
+	expected := []struct {
+		sequence int64
+		accepted int32
+	}{{3, 3}, {6, 3}, {7, 1}}
+	if len(stream.acks) != len(expected) {
+		t.Fatalf("expected %v acks, got %v", len(expected), stream.acks)
+	}
+	for i, e := range expected {
+		if ack := stream.acks[i]; ack.Sequence != e.sequence || ack.Accepted != e.accepted || len(ack.Rejections) != 0 {
+			t.Fatalf("expected ack of sequence %v with %v accepted, got %v", e.sequence, e.accepted, ack)
+		}
+	}
+	if len(queue.published) != 3 || len(queue.published[0]) != 3 || len(queue.published[2]) != 1 {
+		t.Fatalf("expected annotations published in batches of 3, 3 and 1, got %v batches", len(queue.published))
+	}
+	if stored := gih.annotationStore.Query("streamcam", 0, time.Now().Unix()*1000+1000); len(stored) != 7 {
+		t.Fatalf("expected 7 annotations processed locally, got %v", len(stored))
+	}
+
+	// invalid annotations are rejected, the rest of the batch is accepted
+	requests := testAnnotations(2)
+	requests = append(requests, &pb.AnnotateRequest{DeviceName: "streamcam"})
+	stream = &testAnnotateStream{requests: requests}
+	if err := gih.AnnotateStream(stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.acks) != 1 || stream.acks[0].Accepted != 2 || len(stream.acks[0].Rejections) != 1 || stream.acks[0].Rejections[0].Sequence != 3 {
+		t.Fatalf("expected 2 accepted and 1 rejected annotation, got %v", stream.acks)
+	}
+}
+
+func TestAnnotateStreamPublishFailure(t *testing.T) {
+	queue := &testQueue{fail: true}
+	gih := setupAnnotationHandler(t, queue)
+
+	stream := &testAnnotateStream{requests: testAnnotations(3)}
+	err := gih.AnnotateStream(stream)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected unavailable, got %v", err)
+	}
+	if len(stream.acks) != 0 {
+		t.Fatalf("expected no acknowledgement, got %v", stream.acks)
+	}
+	// the client resends the batch, nothing must be processed locally before it's queued
+	if stored := gih.annotationStore.Query("streamcam", 0, time.Now().Unix()*1000+1000); len(stored) != 0 {
+		t.Fatalf("expected no local processing of unpublished annotations, got %v", len(stored))
+	}
+
+	queue.fail = false
+	stream = &testAnnotateStream{requests: testAnnotations(3)}
+	if err := gih.AnnotateStream(stream); err != nil {
+		t.Fatal(err)
+	}
+	if stored := gih.annotationStore.Query("streamcam", 0, time.Now().Unix()*1000+1000); len(stored) != 3 {
+		t.Fatalf("expected resent annotations processed once, got %v", len(stored))
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/rs/xid"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -47,14 +48,23 @@ type grpcImageHandler struct {
 	deviceMap               sync.Map
 	processManager          *services.ProcessManager
 	settingsManager         *services.SettingsManager
+	countingManager         *services.CountingManager
+	signatureManager        *services.SignatureManager
+	ruleManager             *services.RuleManager
+	privacyMaskManager      *services.PrivacyMaskManager
+	annotationStore         *services.AnnotationStore
+	annotationEnricher      *services.AnnotationEnricher
+	debugOverlayManager     *services.DebugOverlayManager
+	auditManager            *services.AuditManager
 	edgeKey                 *string
 	msgQueue                rmq.Queue
+	trackAggregator         *batch.TrackAggregator
 	realtimeCache           sync.Map
 	realtimeDeviceQueryTime sync.Map
 }
 
 // NewGrpcImageHandler returns main GRPC API handler
-func NewGrpcImageHandler(processManager *services.ProcessManager, settingsManager *services.SettingsManager, rdb *redis.Client) *grpcImageHandler {
+func NewGrpcImageHandler(processManager *services.ProcessManager, settingsManager *services.SettingsManager, countingManager *services.CountingManager, signatureManager *services.SignatureManager, ruleManager *services.RuleManager, privacyMaskManager *services.PrivacyMaskManager, annotationStore *services.AnnotationStore, annotationEnricher *services.AnnotationEnricher, debugOverlayManager *services.DebugOverlayManager, auditManager *services.AuditManager, rdb *redis.Client) *grpcImageHandler {
 
 	conn := rmq.OpenConnectionWithRedisClient("annotationService", rdb)
 	msgQueue := conn.OpenQueue("annotationqueue")
@@ -64,17 +74,57 @@ func NewGrpcImageHandler(processManager *services.ProcessManager, settingsManage
 	msgQueue.StartConsuming(g.Conf.Annotation.UnackedLimit, time.Duration(g.Conf.Annotation.PollDurationMs)*time.Millisecond)
 	msgQueue.AddBatchConsumerWithTimeout("annotationqueue", g.Conf.Annotation.MaxBatchSize, time.Duration(g.Conf.Annotation.PollDurationMs)*time.Millisecond, annotationConsumer)
 
+	// aggregation of annotations into track events (in front of the annotation queue)
+	trackAggregator := batch.NewTrackAggregator(msgQueue, time.Duration(g.Conf.Annotation.TrackIdleTimeoutMs)*time.Millisecond,
+		time.Duration(g.Conf.Annotation.TrackMaxDurationMs)*time.Millisecond, g.Conf.Annotation.MaxOpenTracks)
+
 	return &grpcImageHandler{
 		redisConn:               rdb,
 		deviceMap:               sync.Map{},
 		processManager:          processManager,
 		settingsManager:         settingsManager,
+		countingManager:         countingManager,
+		signatureManager:        signatureManager,
+		ruleManager:             ruleManager,
+		privacyMaskManager:      privacyMaskManager,
+		annotationStore:         annotationStore,
+		annotationEnricher:      annotationEnricher,
+		debugOverlayManager:     debugOverlayManager,
+		auditManager:            auditManager,
 		msgQueue:                msgQueue,
+		trackAggregator:         trackAggregator,
 		realtimeCache:           sync.Map{},
 		realtimeDeviceQueryTime: sync.Map{},
 	}
 }
 
+// Close queues the open track events (on shutdown)
+func (gih *grpcImageHandler) Close() {
+	gih.trackAggregator.Stop()
+}
+
+// audit records the gRPC operation on the camera, the actor is the remote address of the caller
+func (gih *grpcImageHandler) audit(ctx context.Context, action string, deviceID string, before []byte, opErr error) {
+	record := &models.AuditRecord{
+		Actor:      "unknown",
+		Source:     models.AuditSourceGRPC,
+		Action:     action,
+		TargetType: models.ProcessTypeRTSP,
+		Target:     deviceID,
+		Result:     models.AuditResultSuccess,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		record.Actor = p.Addr.String()
+	}
+	if opErr != nil {
+		record.Result = models.AuditResultFailed
+		record.Message = status.Convert(opErr).Message()
+	}
+	if err := gih.auditManager.RecordChange(record, before, gih.auditManager.Snapshot(models.ProcessTypeRTSP, deviceID)); err != nil {
+		g.Log.Error("failed to audit", action, deviceID, err)
+	}
+}
+
 func (gih *grpcImageHandler) toUint64(object map[string]interface{}, field string) int64 {
 	if val, ok := object[field]; ok {
 		strVal := val.(string)
@@ -87,20 +137,35 @@ func (gih *grpcImageHandler) toUint64(object map[string]interface{}, field strin
 	return 0
 }
 
-// ListStreams returns the list of all streams regardless of their status
+// ListStreams returns the list of all streams (optionally filtered by group and labels) regardless of their status
 func (gih *grpcImageHandler) ListStreams(req *pb.ListStreamRequest, stream pb.Image_ListStreamsServer) error {
-	err := gih.processManager.ListStream(stream.Context(), func(process *models.StreamProcess) error {
+	filter := &models.ProcessFilter{Group: req.Group}
+	for k, v := range req.Labels {
+		if v == "" {
+			filter.Labels = append(filter.Labels, k)
+		} else {
+			filter.Labels = append(filter.Labels, k+"="+v)
+		}
+	}
+	err := gih.processManager.ListStream(stream.Context(), filter, func(process *models.StreamProcess) error {
 		res := &pb.ListStream{
-			Name:       process.Name,
-			Dead:       process.State.Dead,
-			Error:      process.State.Error,
-			ExitCode:   int64(process.State.ExitCode),
-			Oomkilled:  process.State.OOMKilled,
-			Paused:     process.State.Paused,
-			Pid:        int32(process.State.Pid),
-			Restarting: process.State.Restarting,
-			Running:    process.State.Running,
-			Status:     process.Status,
+			Name:         process.Name,
+			Dead:         process.State.Dead,
+			Error:        process.State.Error,
+			ExitCode:     int64(process.State.ExitCode),
+			Oomkilled:    process.State.OOMKilled,
+			Paused:       process.State.Paused,
+			Pid:          int32(process.State.Pid),
+			Restarting:   process.State.Restarting,
+			Running:      process.State.Running,
+			Status:       process.Status,
+			StreamHealth: process.StreamHealth,
+			Group:        process.Group,
+			Labels:       process.Labels,
+			Description:  process.Description,
+		}
+		if loc := process.Location; loc != nil {
+			res.Location = &pb.Location{Lat: loc.Lat, Lon: loc.Lon, Heading: loc.Heading, Height: loc.Height}
 		}
 		if process.State.Health != nil {
 			res.FailingStreak = int64(process.State.Health.FailingStreak)
@@ -294,6 +359,7 @@ func (gih *grpcImageHandler) unmarshalRedisImage(vf *pb.VideoFrame, deviceId str
 			g.Log.Error("failed to unmarshall VideoFrame proto", err)
 		}
 		vf.DeviceId = deviceId
+		gih.privacyMaskManager.Apply(deviceId, vf)
 	}
 
 	return vf
@@ -495,6 +561,7 @@ func (gih *grpcImageHandler) VideoBufferedImage(req *pb.VideoFrameBufferedReques
 					}
 				}
 				if !isReadingDone {
+					gih.privacyMaskManager.Apply(deviceID, vf)
 					if errStr := stream.Send(vf); errStr != nil {
 						g.Log.Error("grpc buffered image send error", errStr)
 					}
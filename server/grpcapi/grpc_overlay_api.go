@@ -0,0 +1,33 @@
+package grpcapi
+
+import (
+	"context"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DebugOverlay returns the nearest decoded frame with recent annotations drawn on it (JPEG)
+func (gih *grpcImageHandler) DebugOverlay(ctx context.Context, req *pb.DebugOverlayRequest) (*pb.DebugOverlayResponse, error) {
+	if req.DeviceId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "device_id required")
+	}
+	overlay, err := gih.debugOverlayManager.Render(req.DeviceId, req.Timestamp, req.ToleranceMs)
+	if err != nil {
+		if err == models.ErrFrameNotFound {
+			return nil, status.Errorf(codes.NotFound, "no frame found for %v at %v", req.DeviceId, req.Timestamp)
+		}
+		g.Log.Error("failed to render debug overlay", req.DeviceId, err)
+		return nil, status.Errorf(codes.Internal, "failed to render debug overlay")
+	}
+	return &pb.DebugOverlayResponse{
+		Image:          overlay.Image,
+		FrameTimestamp: overlay.FrameTimestamp,
+		Width:          int64(overlay.Width),
+		Height:         int64(overlay.Height),
+		Annotations:    overlay.Annotations,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package grpcapi
+
+import (
+	"context"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchSignatures returns annotations with the nearest object signatures to the query vector
+func (gih *grpcImageHandler) SearchSignatures(ctx context.Context, req *pb.SearchSignaturesRequest) (*pb.SearchSignaturesResponse, error) {
+	if len(req.Vector) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "vector required")
+	}
+	if req.Metric != "" && req.Metric != services.SignatureMetricCosine && req.Metric != services.SignatureMetricL2 {
+		return nil, status.Errorf(codes.InvalidArgument, "metric must be cosine or l2")
+	}
+
+	matches, err := gih.signatureManager.Search(&services.SignatureQuery{
+		Vector:        req.Vector,
+		K:             int(req.K),
+		DeviceName:    req.DeviceName,
+		ObjectType:    req.ObjectType,
+		TimestampFrom: req.TimestampFrom,
+		TimestampTo:   req.TimestampTo,
+		Metric:        req.Metric,
+	})
+	if err == models.ErrInvalidInputParameters {
+		return nil, status.Errorf(codes.InvalidArgument, "vector dimension above maximum")
+	}
+	if err != nil {
+		g.Log.Error("failed to search object signatures", err)
+		return nil, status.Errorf(codes.Internal, "failed to search object signatures")
+	}
+
+	resp := &pb.SearchSignaturesResponse{
+		Matches: make([]*pb.SignatureMatch, 0, len(matches)),
+	}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, &pb.SignatureMatch{
+			Distance:   m.Distance,
+			Annotation: m.Annotation,
+		})
+	}
+	return resp, nil
+}
@@ -15,8 +15,15 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// Storage - enable disable storage on Chrysalis Cloud
+// Storage - enable disable storage on Chrysalis Cloud (audited)
 func (gih *grpcImageHandler) Storage(ctx context.Context, req *pb.StorageRequest) (*pb.StorageResponse, error) {
+	before := gih.auditManager.Snapshot(models.ProcessTypeRTSP, req.DeviceId)
+	resp, err := gih.storage(ctx, req)
+	gih.audit(ctx, models.AuditActionStorage, req.DeviceId, before, err)
+	return resp, err
+}
+
+func (gih *grpcImageHandler) storage(ctx context.Context, req *pb.StorageRequest) (*pb.StorageResponse, error) {
 
 	deviceID := req.DeviceId
 
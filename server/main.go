@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"syscall"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"github.com/chryscloud/video-edge-ai-proxy/globals"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/grpcapi"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
 	"github.com/chryscloud/video-edge-ai-proxy/mqtt"
 	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
 	r "github.com/chryscloud/video-edge-ai-proxy/router"
@@ -43,6 +45,8 @@ import (
 var (
 	grpcServer *grpc.Server
 	grpcConn   net.Listener
+	// grpc API handler, closed on shutdown
+	grpcHandler interface{ Close() }
 	// defaultDBPath = "/data/chrysalis"
 	defaultDBPath = "/home/igor/Downloads/temp/chrysedge/data"
 )
@@ -90,10 +94,15 @@ func main() {
 			},
 		}
 		conf.Annotation = &globals.AnnotationSubconfig{
-			Endpoint:       "https://event.chryscloud.com/api/v1/annotate",
-			MaxBatchSize:   299,
-			PollDurationMs: 300,
-			UnackedLimit:   1000,
+			Endpoint:           "https://event.chryscloud.com/api/v1/annotate",
+			MaxBatchSize:       299,
+			PollDurationMs:     300,
+			UnackedLimit:       1000,
+			Forward:            models.AnnotationForwardRaw,
+			TrackIdleTimeoutMs: 5000,
+			TrackMaxDurationMs: 60000,
+			MaxOpenTracks:      10000,
+			StreamFlushMs:      100,
 		}
 		conf.API = &globals.ApiSubconfig{
 			Endpoint: "https://api.chryscloud.com",
@@ -109,6 +118,61 @@ func main() {
 			OnDiskCleanupOlderThan: "30s",
 			OnDiskSchedule:         "@every 5m",
 		}
+		conf.Counting = &globals.CountingSubconfig{
+			TrackTimeoutMs: 10000,
+			RetentionDays:  30,
+		}
+		conf.Signature = &globals.SignatureSubconfig{
+			MaxEntries:       10000,
+			RetentionMinutes: 1440,
+			MaxDimension:     2048,
+			MaxIndexes:       256,
+		}
+		conf.Enrichment = &globals.EnrichmentSubconfig{
+			RemoteStreamID: true,
+			Dimensions:     true,
+			Location:       true,
+			Timestamp:      true,
+		}
+		conf.Watchdog = &globals.WatchdogSubconfig{
+			CheckIntervalMs:     5000,
+			StallTimeoutMs:      30000,
+			Restart:             true,
+			RestartBackoffMs:    30000,
+			RestartMaxBackoffMs: 600000,
+		}
+		conf.Reconcile = &globals.ReconcileSubconfig{
+			IntervalMs:    60000,
+			Adopt:         true,
+			RemoveOrphans: true,
+		}
+		conf.Runtime = &globals.RuntimeSubconfig{
+			Type:       services.RuntimeDocker,
+			Host:       "unix:///var/run/docker.sock",
+			APIVersion: "1.40",
+		}
+		conf.Terminal = &globals.TerminalSubconfig{
+			Enabled: true,
+			Shell:   models.DefaultTerminalShell,
+		}
+		conf.Auth = &globals.AuthSubconfig{
+			Enabled:                true,
+			AllowOrigins:           []string{"http://localhost:8905", "http://127.0.0.1:8905"}, // bundled portal
+			TokenExpirationMinutes: 720,
+		}
+		conf.Audit = &globals.AuditSubconfig{
+			MaxRecords:    100000,
+			RetentionDays: 90,
+		}
+		conf.Manifest = &globals.ManifestSubconfig{
+			Enabled:    false,
+			IntervalMs: 60000,
+			Policy:     models.ManifestPolicyReject,
+			SelfHeal:   true,
+		}
+		conf.Secrets = &globals.SecretsSubconfig{
+			MasterKeyFile: models.DefaultMasterKeyFile,
+		}
 	} else {
 		// custom config file exists
 		err := cfg.NewYamlConfig(defaultDBPath+"/conf.yaml", &conf)
@@ -118,6 +182,25 @@ func main() {
 			panic("Failed to load conf.yaml")
 		}
 	}
+	// manifest is kept in the data directory
+	if conf.Manifest != nil {
+		if conf.Manifest.Path == "" {
+			conf.Manifest.Path = models.DefaultManifestFile
+		}
+		if !filepath.IsAbs(conf.Manifest.Path) {
+			conf.Manifest.Path = filepath.Join(defaultDBPath, conf.Manifest.Path)
+		}
+	}
+	// master key is kept in the data directory
+	if conf.Secrets == nil {
+		conf.Secrets = &globals.SecretsSubconfig{}
+	}
+	if conf.Secrets.MasterKeyFile == "" {
+		conf.Secrets.MasterKeyFile = models.DefaultMasterKeyFile
+	}
+	if !filepath.IsAbs(conf.Secrets.MasterKeyFile) {
+		conf.Secrets.MasterKeyFile = filepath.Join(defaultDBPath, conf.Secrets.MasterKeyFile)
+	}
 	g.Conf = conf
 
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -144,10 +227,53 @@ func main() {
 	storage := services.NewStorage(db)
 
 	// Services
-	settingsService := services.NewSettingsManager(storage)
-	processService := services.NewProcessManager(storage, rdb)
-	appService := services.NewAppManager(storage, rdb)
-	mqttService := mqtt.NewMqttManager(rdb, settingsService, processService, appService)
+	containerRuntime, err := services.NewContainerRuntime()
+	if err != nil {
+		g.Log.Error("failed to initialize container runtime", err)
+		panic(err)
+	}
+	masterKey, err := services.LoadMasterKey(g.Conf.Secrets.MasterKeyFile)
+	if err != nil {
+		g.Log.Error("failed to load secrets master key", err)
+		panic(err)
+	}
+	secretService, err := services.NewSecretManager(masterKey)
+	if err != nil {
+		g.Log.Error("failed to initialize secrets encryption", err)
+		panic(err)
+	}
+	settingsService := services.NewSettingsManager(storage, containerRuntime, secretService)
+	if err := settingsService.EncryptStoredSecrets(); err != nil {
+		g.Log.Error("failed to encrypt stored settings", err)
+	}
+	stateCache := services.NewProcessStateCache(containerRuntime)
+	stateCache.Start()
+	processService := services.NewProcessManager(storage, rdb, containerRuntime, stateCache, secretService)
+	if err := processService.EncryptStoredCredentials(); err != nil {
+		g.Log.Error("failed to encrypt stored camera credentials", err)
+	}
+	streamWatchdog := services.NewStreamWatchdog(storage, processService, rdb)
+	streamWatchdog.Start()
+	appService := services.NewAppManager(storage, rdb, containerRuntime, stateCache)
+	countingService := services.NewCountingManager(storage)
+	signatureService := services.NewSignatureManager()
+	ruleService := services.NewRuleManager(storage, rdb, countingService, containerRuntime)
+	ruleService.StartProcessListener()
+	privacyMaskService := services.NewPrivacyMaskManager(storage)
+	annotationStore := services.NewAnnotationStore()
+	annotationEnricher := services.NewAnnotationEnricher(storage, rdb)
+	debugOverlayService := services.NewDebugOverlayManager(rdb, annotationStore, privacyMaskService)
+	reconcileService := services.NewReconcileManager(storage, processService, appService, settingsService, rdb)
+	reconcileService.Start()
+	auditService := services.NewAuditManager(storage)
+	auditService.Start()
+	terminalService := services.NewTerminalManager(storage, containerRuntime, stateCache, auditService)
+	authService := services.NewAuthManager(storage)
+	configTransferService := services.NewConfigTransferManager(storage, processService, appService, settingsService, rdb)
+	manifestService := services.NewManifestManager(storage, processService, configTransferService, rdb)
+	manifestService.Start()
+	discoveryService := services.NewDiscoveryManager()
+	mqttService := mqtt.NewMqttManager(rdb, settingsService, processService, appService, containerRuntime, auditService, manifestService)
 	mqttService.StartGatewayListener()
 	defer mqttService.StopGateway()
 
@@ -159,16 +285,16 @@ func main() {
 
 	gin.SetMode(conf.Mode)
 
-	router := msrv.NewAPIRouter(&conf.YamlConfig)
-	router = r.ConfigAPI(router, processService, settingsService, appService, rdb)
+	router := r.NewAPIRouter(&conf.YamlConfig)
+	router = r.ConfigAPI(router, processService, settingsService, appService, countingService, ruleService, privacyMaskService, debugOverlayService, reconcileService, terminalService, auditService, authService, configTransferService, manifestService, discoveryService, rdb)
 
 	// start server
 	srv := msrv.Start(&conf.YamlConfig, router, g.Log)
 	// wait for server shutdown
 	go msrv.Shutdown(srv, g.Log, quit, done)
 
-	go startGrpcServer(processService, settingsService, rdb)
-	go shutdownGrpc(quitGrpc)
+	go startGrpcServer(processService, settingsService, countingService, signatureService, ruleService, privacyMaskService, annotationStore, annotationEnricher, debugOverlayService, auditService, rdb)
+	go shutdownGrpc(quitGrpc, countingService.Close, signatureService.Stop, ruleService.Stop)
 
 	g.Log.Info("Server is ready to handle requests at", conf.Port)
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -180,7 +306,7 @@ func main() {
 	g.Log.Info("exit")
 }
 
-func startGrpcServer(processService *services.ProcessManager, settingsService *services.SettingsManager, rdb *redis.Client) error {
+func startGrpcServer(processService *services.ProcessManager, settingsService *services.SettingsManager, countingService *services.CountingManager, signatureService *services.SignatureManager, ruleService *services.RuleManager, privacyMaskService *services.PrivacyMaskManager, annotationStore *services.AnnotationStore, annotationEnricher *services.AnnotationEnricher, debugOverlayService *services.DebugOverlayManager, auditService *services.AuditManager, rdb *redis.Client) error {
 	conn, err := net.Listen("tcp", "0.0.0.0:50001") // TODO: take from conf.yaml file
 	if err != nil {
 		g.Log.Error("Failed to open grpc connection", err)
@@ -189,12 +315,15 @@ func startGrpcServer(processService *services.ProcessManager, settingsService *s
 	grpcConn = conn
 	grpcServer = grpc.NewServer()
 
-	pb.RegisterImageServer(grpcServer, grpcapi.NewGrpcImageHandler(processService, settingsService, rdb))
+	handler := grpcapi.NewGrpcImageHandler(processService, settingsService, countingService, signatureService, ruleService, privacyMaskService, annotationStore, annotationEnricher, debugOverlayService, auditService, rdb)
+	grpcHandler = handler
+	pb.RegisterImageServer(grpcServer, handler)
 	g.Log.Info("Grpc Server is ready to handle requests at 50001")
 	return grpcServer.Serve(grpcConn)
 }
 
-func shutdownGrpc(quit <-chan os.Signal) {
+// shutdownGrpc stops the grpc server and runs the closers (storing pending data) before exit
+func shutdownGrpc(quit <-chan os.Signal, closers ...func()) {
 	<-quit
 
 	grpcConn.Close()
@@ -203,6 +332,12 @@ func shutdownGrpc(quit <-chan os.Signal) {
 		g.Log.Info("stopping grpc server...")
 		grpcServer.Stop()
 	}
+	if grpcHandler != nil {
+		grpcHandler.Close()
+	}
+	for _, close := range closers {
+		close()
+	}
 	g.Log.Info("grpc server quit")
 	os.Exit(0)
 }
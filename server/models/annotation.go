@@ -0,0 +1,60 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "github.com/chryscloud/go-microkit-plugins/models/ai"
+
+const (
+	// annotation forwarding modes to Chrysalis Cloud
+	AnnotationForwardRaw    = "raw"    // every annotation as received from Annotate
+	AnnotationForwardTracks = "tracks" // only aggregated track events (annotations without tracking id are always forwarded)
+	AnnotationForwardBoth   = "both"   // raw annotations and aggregated track events
+)
+
+// Annotation sent to Chrysalis Cloud (extends the ai annotation with edge specific fields)
+type Annotation struct {
+	ai.Annotation
+	ObjectPath []*ai.Coordinate    `json:"object_path,omitempty"` // optional: trajectory of the tracked object
+	IsTrack    bool                `json:"is_track,omitempty"`    // true if aggregated track event
+	Heading    float64             `json:"heading,omitempty"`     // optional: camera heading in degrees (clockwise from north)
+	Attributes map[string]string   `json:"attributes,omitempty"`  // optional: free form event meta data
+	Objects    []*AnnotationObject `json:"objects,omitempty"`     // optional: all detections in the frame
+	Labels     []*AnnotationLabel  `json:"labels,omitempty"`      // optional: classification labels
+}
+
+// AnnotationObject - one of the detected objects within the annotated frame
+type AnnotationObject struct {
+	ObjectType        string             `json:"object_type,omitempty"`
+	ObjectID          string             `json:"object_id,omitempty"`
+	ObjectTrackingID  string             `json:"object_tracking_id,omitempty"`
+	Confidence        float64            `json:"confidence,omitempty"`
+	ObjectBoundingBox *ai.BoundingBox    `json:"object_bouding_box,omitempty"`
+	ObjectCoordinate  *ai.Coordinate     `json:"object_coordinate,omitempty"`
+	ObjectMask        []*ai.Coordinate   `json:"mask,omitempty"`
+	ObjectSignature   []float64          `json:"object_signature,omitempty"`
+	Attributes        map[string]string  `json:"attributes,omitempty"`
+	Labels            []*AnnotationLabel `json:"labels,omitempty"`
+}
+
+// AnnotationLabel - classification label with confidence
+type AnnotationLabel struct {
+	Label      string  `json:"label"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// AnnotationList list of annotations sent to Chrysalis Cloud in one batch
+type AnnotationList struct {
+	Data []*Annotation `json:"data,omitempty"`
+}
@@ -42,6 +42,8 @@ type AppProcess struct {
 	Logs                *microModelDocker.DockerLogs `json:"logs,omitempty"`                  // logs (error and info)
 	Created             int64                        `json:"created,omitempty"`               // unix timestamp in ms when created
 	Modified            int64                        `json:"modified,omitempty"`              // last modificadation date, epoch in ms
+	LastReconciled      *ReconcileStatus             `json:"last_reconciled,omitempty"`       // result of the last desired-state reconciliation
+	Paused              bool                         `json:"paused,omitempty"`                // paused app container (record is kept)
 }
 
 type VarPair struct {
@@ -0,0 +1,82 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	PrefixAudit = "/audit/"
+
+	// where the audited operation came from
+	AuditSourceREST = "rest"
+	AuditSourceGRPC = "grpc"
+	AuditSourceMQTT = "mqtt"
+
+	AuditActorCloud = "chrysalis_cloud" // commands received over MQTT
+
+	AuditResultSuccess = "success"
+	AuditResultFailed  = "failed"
+
+	AuditActionTerminalStart = "terminal_start" // interactive terminal session opened
+	AuditActionTerminalEnd   = "terminal_end"   // interactive terminal session closed
+	AuditActionProxy         = "proxy"          // RTMP passthrough toggled (gRPC Proxy)
+	AuditActionStorage       = "storage"        // cloud storage toggled (gRPC Storage)
+
+	// audited targets besides cameras (ProcessTypeRTSP) and apps (ProcessTypeApplication)
+	AuditTargetSettings     = "settings"
+	AuditTargetCountingZone = "counting_zone"
+	AuditTargetRule         = "rule"
+	AuditTargetPrivacyMask  = "privacy_mask"
+	AuditTargetUser         = "user"
+	AuditTargetAPIKey       = "apikey"
+	AuditTargetReconcile    = "reconcile"
+	AuditTargetImage        = "image"
+	AuditTargetConfig       = "config"   // bulk import
+	AuditTargetManifest     = "manifest" // site manifest reconciliation
+
+	AuditRedacted = "[redacted]" // replaces passwords, keys and secrets in the diff
+)
+
+// AuditRecord - single audited operation
+type AuditRecord struct {
+	ID         string         `json:"id"`
+	Created    int64          `json:"created"`               // unix timestamp in ms
+	Actor      string         `json:"actor"`                 // who performed the operation (user or remote address)
+	Source     string         `json:"source"`                // rest, grpc or mqtt
+	Action     string         `json:"action"`                // what was performed
+	TargetType string         `json:"target_type,omitempty"` // rtsp or app
+	Target     string         `json:"target,omitempty"`      // name of the camera or app
+	Result     string         `json:"result"`                // success or failed
+	Message    string         `json:"message,omitempty"`     // error or additional details
+	Changes    []*AuditChange `json:"changes,omitempty"`     // stored target before and after the operation
+}
+
+// AuditChange - single changed field of the target (nested fields joined with dots)
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditQuery - audit log filters (all optional)
+type AuditQuery struct {
+	Actor      string `form:"actor"`
+	Source     string `form:"source"`
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	Target     string `form:"target"`
+	Result     string `form:"result"`
+	From       int64  `form:"from"`  // unix timestamp in ms (inclusive)
+	To         int64  `form:"to"`    // unix timestamp in ms (exclusive)
+	Limit      int    `form:"limit"` // newest records first (default 100)
+}
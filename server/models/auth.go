@@ -0,0 +1,101 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	PrefixUser       = "/user/"
+	PrefixAPIKey     = "/apikey/"
+	PrefixAuthSecret = "/authsecret/"
+
+	// roles (each role includes permissions of the previous one)
+	RoleViewer   = "viewer"   // read-only access
+	RoleOperator = "operator" // manage cameras, rules, zones and masks
+	RoleAdmin    = "admin"    // settings, apps, terminals, users and API keys
+
+	AuthMethodPassword = "password" // token issued after login
+	AuthMethodAPIKey   = "apikey"   // API key for automation
+	AuthMethodNone     = "none"     // authentication disabled in conf.yaml
+
+	MinPasswordLength = 8
+)
+
+// RoleLevels - role hierarchy
+var RoleLevels = map[string]int{RoleViewer: 1, RoleOperator: 2, RoleAdmin: 3}
+
+// User - local user (password hash is never returned by the API)
+type User struct {
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	PasswordHash string `json:"password_hash,omitempty"` // bcrypt hash
+	Created      int64  `json:"created,omitempty"`       // unix timestamp in ms
+	Modified     int64  `json:"modified,omitempty"`      // unix timestamp in ms
+	TokenVersion int64  `json:"token_version,omitempty"` // incremented on password change (invalidates issued tokens)
+}
+
+// UserRequest - create a user or first-run admin setup
+type UserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role,omitempty"` // ignored on setup (always admin)
+}
+
+// LoginRequest - username and password login
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// PasswordChangeRequest - change of own password
+type PasswordChangeRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// AuthToken - signed token issued after login
+type AuthToken struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"` // unix timestamp in ms
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+}
+
+// APIKey - key for automation (key hash is never returned by the API, key only once on creation)
+type APIKey struct {
+	ID       string `json:"id"`
+	Name     string `json:"name" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+	Key      string `json:"key,omitempty"`      // only returned on creation
+	KeyHash  string `json:"key_hash,omitempty"` // sha256 of the secret part of the key
+	Created  int64  `json:"created,omitempty"`  // unix timestamp in ms
+	LastUsed int64  `json:"last_used,omitempty"`
+}
+
+// Principal - authenticated caller of the API
+type Principal struct {
+	Name   string `json:"name"`   // username or API key name
+	Role   string `json:"role"`   // viewer, operator or admin
+	Method string `json:"method"` // password, apikey or none
+}
+
+// AuthStatus - if first-run setup of the admin account is still required
+type AuthStatus struct {
+	Enabled       bool `json:"enabled"`
+	SetupRequired bool `json:"setup_required"`
+}
+
+// HasRole - role is the same or higher than required
+func HasRole(role string, required string) bool {
+	return RoleLevels[role] > 0 && RoleLevels[role] >= RoleLevels[required]
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+	ConfigFormatCSV  = "csv" // import only: name, rtsp and rtmp columns (cameras)
+
+	ConfigBundleVersion = 1
+
+	RedactedSecret = "xxxxx" // replaces passwords, stream keys and secret environment variables on export
+
+	DefaultImportConcurrency = 4
+	MaxImportConcurrency     = 16
+
+	// per item import results
+	ImportResultCreated   = "created"
+	ImportResultUpdated   = "updated"   // camera endpoints or location changed
+	ImportResultUnchanged = "unchanged" // camera already exists with the same configuration
+	ImportResultSkipped   = "skipped"   // app already installed
+	ImportResultValid     = "valid"     // dry run, would be applied
+	ImportResultInvalid   = "invalid"
+	ImportResultFailed    = "failed"
+)
+
+// ConfigBundle - camera and app definitions (runtime state such as container, status and logs is not included)
+type ConfigBundle struct {
+	Version  int              `json:"version"`
+	Exported int64            `json:"exported,omitempty"` // unix timestamp in ms
+	Redacted bool             `json:"redacted,omitempty"` // secrets replaced with xxxxx
+	Cameras  []*StreamProcess `json:"cameras,omitempty"`
+	Apps     []*AppProcess    `json:"apps,omitempty"`
+}
+
+// ConfigImportQuery - import options
+type ConfigImportQuery struct {
+	Format      string `form:"format"`      // json, yaml or csv (detected from content type when missing)
+	DryRun      bool   `form:"dry_run"`     // only validate and report what would be applied
+	Concurrency int    `form:"concurrency"` // items applied at the same time (default 4, max 16)
+}
+
+// ConfigExportQuery - export options
+type ConfigExportQuery struct {
+	Format string `form:"format"` // json (default) or yaml
+	Redact bool   `form:"redact"` // replace secrets with xxxxx
+}
+
+// ImportItemResult - result of a single camera or app
+type ImportItemResult struct {
+	Type    string `json:"type"` // rtsp or app
+	Name    string `json:"name"`
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// ImportReport - results in the order of the imported items
+type ImportReport struct {
+	DryRun    bool                `json:"dry_run"`
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"` // invalid or failed
+	Results   []*ImportItemResult `json:"results"`
+}
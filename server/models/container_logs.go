@@ -0,0 +1,39 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+
+	DefaultLogTail = "100" // same as the logs snapshot returned with process info
+)
+
+// ContainerLogsQuery - query parameters of the container log stream
+type ContainerLogsQuery struct {
+	Follow bool   `form:"follow"`              // keep streaming new lines as they're written
+	Tail   string `form:"tail"`                // number of lines from the end of the logs or "all" (default 100)
+	Since  string `form:"since"`               // RFC3339 timestamp, unix timestamp or relative duration (e.g. 10m)
+	Until  string `form:"until"`               // RFC3339 timestamp, unix timestamp or relative duration (e.g. 10m)
+	Stdout bool   `form:"stdout,default=true"` // include stdout
+	Stderr bool   `form:"stderr,default=true"` // include stderr
+}
+
+// ContainerLogLine - single line of the container output
+type ContainerLogLine struct {
+	Stream    string `json:"stream"`              // stdout or stderr
+	Timestamp string `json:"timestamp,omitempty"` // RFC3339Nano time docker received the line
+	Line      string `json:"line"`
+}
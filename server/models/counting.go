@@ -0,0 +1,93 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "strings"
+
+const (
+	PrefixCountingZone   = "/countingzone/"
+	PrefixCountingBucket = "/countingbuckets/" // minute samples keyed by device/zone/minute/object type (range scanned by time)
+
+	CountingZoneTypeLine = "line" // virtual line (counts in/out crossings)
+	CountingZoneTypeArea = "area" // virtual area (counts entries/exits and occupancy)
+
+	CountingBucketMinute = "minute"
+	CountingBucketHour   = "hour"
+	CountingBucketDay    = "day"
+)
+
+// CountingZone is a virtual line or area defined on a camera image (pixel coordinates)
+type CountingZone struct {
+	Name        string   `json:"name" binding:"required"`        // name of the zone (unique per device)
+	DeviceName  string   `json:"device_name" binding:"required"` // device (camera) the zone belongs to
+	Type        string   `json:"type" binding:"required"`        // line or area
+	Points      []*Point `json:"points" binding:"required"`      // 2 points for line (crossing from left to right of A->B is in), polygon for area
+	ObjectTypes []string `json:"object_types,omitempty"`         // optional: count only these object types (e.g. person, car)
+	Created     int64    `json:"created,omitempty"`              // unix timestamp in ms when created
+	Modified    int64    `json:"modified,omitempty"`             // last modification date, epoch in ms
+}
+
+// Point within the image
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// CountingSample is an aggregated count for a zone and object type within a time bucket
+type CountingSample struct {
+	DeviceName string `json:"device_name"`
+	Zone       string `json:"zone"`
+	ObjectType string `json:"object_type"`
+	Timestamp  int64  `json:"timestamp"` // start of the bucket, epoch in ms
+	In         int64  `json:"in"`        // number of in crossings (or area entries)
+	Out        int64  `json:"out"`       // number of out crossings (or area exits)
+	Occupancy  int64  `json:"occupancy"` // maximum occupancy within the bucket (areas only)
+}
+
+// CountingOccupancy current number of tracked objects inside of an area
+type CountingOccupancy struct {
+	DeviceName string `json:"device_name"`
+	Zone       string `json:"zone"`
+	ObjectType string `json:"object_type"`
+	Occupancy  int64  `json:"occupancy"`
+}
+
+// ValidateCountingZone checks if zone has valid geometry
+func ValidateCountingZone(zone *CountingZone) error {
+	if zone.Name == "" || zone.DeviceName == "" {
+		return ErrMissingInputParameters
+	}
+	if strings.Contains(zone.Name, "/") || strings.Contains(zone.DeviceName, "/") {
+		return ErrInvalidInputParameters
+	}
+	switch zone.Type {
+	case CountingZoneTypeLine:
+		if len(zone.Points) != 2 {
+			return ErrInvalidInputParameters
+		}
+	case CountingZoneTypeArea:
+		if len(zone.Points) < 3 {
+			return ErrInvalidInputParameters
+		}
+	default:
+		return ErrInvalidInputParameters
+	}
+	for _, p := range zone.Points {
+		if p == nil {
+			return ErrInvalidInputParameters
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	DefaultDiscoveryTimeoutMs = 3000  // how long to wait for WS-Discovery responses
+	MaxDiscoveryTimeoutMs     = 30000 // upper limit of the requested wait
+)
+
+// DiscoveryScanRequest - ONVIF scan of the local network, streams are queried only with credentials
+type DiscoveryScanRequest struct {
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // WS-Discovery wait (default 3000)
+}
+
+// DiscoveredCamera - ONVIF device that answered the WS-Discovery probe
+type DiscoveredCamera struct {
+	Address      string              `json:"address"`                 // WS-Discovery endpoint reference (device UUID)
+	IP           string              `json:"ip,omitempty"`            // host of the device service
+	XAddrs       []string            `json:"xaddrs,omitempty"`        // device service URLs
+	Name         string              `json:"name,omitempty"`          // from onvif://www.onvif.org/name scope
+	Hardware     string              `json:"hardware,omitempty"`      // from onvif://www.onvif.org/hardware scope
+	Location     string              `json:"location,omitempty"`      // from onvif://www.onvif.org/location scope
+	Streams      []*DiscoveredStream `json:"streams,omitempty"`       // stream candidates (only with credentials)
+	Error        string              `json:"error,omitempty"`         // why streams couldn't be queried
+	AlreadyAdded []string            `json:"already_added,omitempty"` // cameras already streaming from this device
+}
+
+// DiscoveredStream - candidate stream of the media profile. Name and rtsp_endpoint (with rtsp_username and rtsp_password)
+// can be posted as they are to create the camera.
+type DiscoveredStream struct {
+	Name         string  `json:"name"`                    // suggested camera name
+	RTSPEndpoint string  `json:"rtsp_endpoint"`           // stream URI from GetStreamUri
+	RTSPUsername string  `json:"rtsp_username,omitempty"` // credentials used for the scan (password isn't returned)
+	Profile      string  `json:"profile"`                 // media profile token
+	ProfileName  string  `json:"profile_name,omitempty"`
+	Codec        string  `json:"codec,omitempty"` // video encoding (H264, H265, JPEG, ...)
+	Width        int     `json:"width,omitempty"`
+	Height       int     `json:"height,omitempty"`
+	FrameRate    float64 `json:"frame_rate,omitempty"`
+}
@@ -21,8 +21,16 @@ var (
 	ErrProcessNotFound          = errors.New("process not found")
 	ErrProcessNotFoundDatastore = errors.New("process not found in datastore")
 	ErrForbidden                = errors.New("operation not allowed")
+	ErrUnauthorized             = errors.New("unauthorized")
+	ErrSetupRequired            = errors.New("admin account setup required")
+	ErrManifestManaged          = errors.New("cameras and apps are managed by the site manifest")
 
 	ErrMissingInputParameters = errors.New("missing required parameters")
+	ErrInvalidInputParameters = errors.New("invalid input parameters")
 	ErrStringTooShort         = errors.New("too short")
 	ErrProcessConflict        = errors.New("process conflict")
+	ErrFrameNotFound          = errors.New("frame not found")
+	ErrProcessUpdateFailed    = errors.New("process update failed, previous container restored")
+	ErrProcessNotRunning      = errors.New("process not running")
+	ErrNoRTMPEndpoint         = errors.New("process has no RTMP endpoint")
 )
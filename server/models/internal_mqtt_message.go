@@ -13,15 +13,25 @@ const (
 
 	DeviceOperationStart  string = "start"  // start a new device on the edge
 	DeviceOperationDelete string = "delete" // delete the device from the edge
+	DeviceOperationUpdate string = "update" // device configuration updated
+
+	DeviceOperationRestart string = "restart" // restart the device container
+	DeviceOperationPause   string = "pause"   // pause the device (record is kept)
+	DeviceOperationResume  string = "resume"  // resume the paused device
 
 	DeviceOperationUpgradeAvailable string = "upgrade_avail" // device has an upgrade available
 	DeviceOperationUpgradeFinished  string = "upgrade"       // device has performed an upgrade
 
 	DeviceOperationError string = "error" // device operation failed
 
+	DeviceOperationRuleFired string = "rule" // event rule fired (message contains the rule event)
+
+	DeviceOperationHealth string = "health" // stream health of the device changed
+
 	ProcessTypeRTSP        string = "rtsp"
 	ProcessTypeApplication string = "app"
 	ProcessTypeStats       string = "stats"
+	ProcessTypeRule        string = "rule"
 	ProcessTypeUnknown     string = "unknown"
 )
 
@@ -35,5 +45,8 @@ type MQTTMessage struct {
 	ProcessOperation MQTTProcessOperation `json:"operation"`                // the state of the internal process
 	ProcessType      MQTTProcessType      `json:"type,omitempty"`           // type of internal process
 	State            string               `json:"state,omitempty"`          // process state (running, restarting, ...)
+	StreamHealth     string               `json:"streamHealth,omitempty"`   // stream health (ok, stalled, no-signal)
+	Group            string               `json:"group,omitempty"`          // camera group or site hierarchy
+	Labels           map[string]string    `json:"labels,omitempty"`         // camera labels
 	Message          []byte               `json:"message,omitempty"`        // optional custom message
 }
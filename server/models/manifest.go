@@ -0,0 +1,61 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	DefaultManifestFile = "manifest.yaml" // in the data directory
+
+	// what happens with manual changes of cameras and apps while manifest mode is active
+	ManifestPolicyReject = "reject" // refused
+	ManifestPolicyFlag   = "flag"   // allowed and listed in the manifest status (reverted by the next reconciliation)
+
+	// drift between the manifest and the stored cameras and apps
+	DriftMissing   = "missing"   // in the manifest, not on the edge
+	DriftChanged   = "changed"   // on the edge with a different configuration
+	DriftUnmanaged = "unmanaged" // on the edge, not in the manifest (removed when pruning)
+
+	ImportResultRemoved = "removed" // pruned item not in the manifest
+)
+
+// ManifestDrift - single camera or app not matching the manifest
+type ManifestDrift struct {
+	Type   string   `json:"type"` // rtsp or app
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`             // missing, changed or unmanaged
+	Fields []string `json:"fields,omitempty"` // changed fields
+}
+
+// ManifestManualChange - change of a camera or app made outside of the manifest (flag policy)
+type ManifestManualChange struct {
+	Created int64  `json:"created"` // unix timestamp in ms
+	Actor   string `json:"actor"`
+	Action  string `json:"action"`
+	Type    string `json:"type"` // rtsp or app
+	Name    string `json:"name,omitempty"`
+}
+
+// ManifestStatus - manifest mode state, current drift and the results of the last reconciliation
+type ManifestStatus struct {
+	Enabled        bool                    `json:"enabled"`
+	Path           string                  `json:"path,omitempty"`
+	Policy         string                  `json:"policy,omitempty"`
+	Checksum       string                  `json:"checksum,omitempty"`        // sha256 of the loaded manifest
+	Loaded         int64                   `json:"loaded,omitempty"`          // unix timestamp in ms
+	LastReconciled int64                   `json:"last_reconciled,omitempty"` // unix timestamp in ms
+	Error          string                  `json:"error,omitempty"`           // manifest can't be read or parsed
+	Drift          []*ManifestDrift        `json:"drift"`
+	Results        []*ImportItemResult     `json:"results,omitempty"` // items applied by the last reconciliation
+	ManualChanges  []*ManifestManualChange `json:"manual_changes,omitempty"`
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "strings"
+
+const (
+	PrefixPrivacyMask        = "/privacymask/"        // current mask per device
+	PrefixPrivacyMaskVersion = "/privacymaskversion/" // all mask versions per device
+
+	PrivacyMaskModeBlack    = "black"    // fill masked regions with black
+	PrivacyMaskModePixelate = "pixelate" // pixelate masked regions
+
+	DefaultPrivacyMaskPixelSize = 16
+)
+
+// PrivacyMask - polygons blacked out or pixelated in every frame of the camera before it leaves the edge
+type PrivacyMask struct {
+	DeviceName string     `json:"device_name" binding:"required"` // device (camera) the mask belongs to
+	Mode       string     `json:"mode,omitempty"`                 // black (default) or pixelate
+	PixelSize  int        `json:"pixel_size,omitempty"`           // pixelate block size in pixels (default 16)
+	Width      int64      `json:"width,omitempty"`                // optional: reference frame width of the polygons (scaled to actual frame size)
+	Height     int64      `json:"height,omitempty"`               // optional: reference frame height of the polygons
+	Regions    [][]*Point `json:"regions" binding:"required"`     // mask polygons in pixel coordinates
+	Version    int64      `json:"version,omitempty"`              // version number (assigned on every change)
+	Created    int64      `json:"created,omitempty"`              // unix timestamp in ms when version was created
+}
+
+// ValidatePrivacyMask checks mask mode and polygons
+func ValidatePrivacyMask(mask *PrivacyMask) error {
+	if mask.DeviceName == "" {
+		return ErrMissingInputParameters
+	}
+	if strings.Contains(mask.DeviceName, "/") {
+		return ErrInvalidInputParameters
+	}
+	if mask.Mode != "" && mask.Mode != PrivacyMaskModeBlack && mask.Mode != PrivacyMaskModePixelate {
+		return ErrInvalidInputParameters
+	}
+	if mask.PixelSize < 0 || mask.Width < 0 || mask.Height < 0 || (mask.Width == 0) != (mask.Height == 0) {
+		return ErrInvalidInputParameters
+	}
+	for _, region := range mask.Regions {
+		if len(region) < 3 {
+			return ErrInvalidInputParameters
+		}
+		for _, p := range region {
+			if p == nil {
+				return ErrInvalidInputParameters
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	// docker labels on containers created by the edge proxy
+	ContainerLabelManaged = "com.chryscloud.managed" // value is the process type (rtsp or app)
+	ContainerLabelName    = "com.chryscloud.name"    // name of the camera or app
+	ContainerLabelGroup   = "com.chryscloud.group"   // camera group
+	ContainerLabelPrefix  = "com.chryscloud.label."  // camera labels are set as com.chryscloud.label.<key>
+
+	// process status when the datastore record has no container
+	ProcessStatusMissing = "missing"
+
+	// reconcile actions
+	ReconcileActionRecreate = "recreate" // container missing, recreated from the datastore record
+	ReconcileActionAdopt    = "adopt"    // camera container without datastore record, record created from the container
+	ReconcileActionFlag     = "flag"     // container not managed by the edge proxy (left as it is)
+	ReconcileActionRemove   = "remove"   // managed container without datastore record removed
+
+	// last reconcile status per process
+	ReconcileStatusInSync    = "in-sync"
+	ReconcileStatusRecreated = "recreated"
+	ReconcileStatusAdopted   = "adopted"
+	ReconcileStatusFailed    = "failed"
+)
+
+// ReconcileStatus - result of the last reconciliation of the process
+type ReconcileStatus struct {
+	Status     string `json:"status"`          // in-sync, recreated, adopted or failed
+	Error      string `json:"error,omitempty"` // reason of the failure
+	Reconciled int64  `json:"reconciled"`      // unix timestamp in ms of the last reconciliation
+}
+
+// ReconcileAction - single change required to bring docker to the desired (datastore) state
+type ReconcileAction struct {
+	Name        string `json:"name"`                   // camera or app name (or container name if unmanaged)
+	ProcessType string `json:"process_type,omitempty"` // rtsp, app or empty if unknown
+	ContainerID string `json:"container_id,omitempty"`
+	Action      string `json:"action"`          // recreate, adopt, flag or remove
+	Reason      string `json:"reason"`          // why the action is required
+	Error       string `json:"error,omitempty"` // action failed
+}
+
+// ReconcileReport - list of actions taken (or to be taken on dry run)
+type ReconcileReport struct {
+	DryRun   bool               `json:"dry_run"`
+	Started  int64              `json:"started"`  // unix timestamp in ms
+	Finished int64              `json:"finished"` // unix timestamp in ms
+	InSync   int                `json:"in_sync"`  // number of processes already in desired state
+	Actions  []*ReconcileAction `json:"actions"`
+}
@@ -25,6 +25,7 @@ const (
 	RedisLastAccessQueryTimeKey = "last_query" // last request query time
 	RedisProxyRTMPKey           = "proxy_rtmp" // if the RTMP should be proxied to the Chrysalis cloud
 	RedisProxyStoreKey          = "store"      // if Chrysalis cloud should store the stream to permanent storage
+	RedisPausedKey              = "paused"     // if the camera is paused (no frames are produced)
 
 	RedisInMemoryBufferChannel       = "memory_buffer_channel" // pub/sub channel to communicate with python process in order to start decoding frames in the memory buffer
 	RedisInMemoryDecodedImagesPrefix = "memory_decoded_"
@@ -0,0 +1,70 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	// RTSP probe results
+	ProbeStatusOK           = "ok"           // stream described and set up
+	ProbeStatusUnreachable  = "unreachable"  // no connection or no RTSP response
+	ProbeStatusUnauthorized = "unauthorized" // missing or wrong credentials
+	ProbeStatusFailed       = "failed"       // camera responded with an error or without usable tracks
+
+	ProbeAuthNone   = "none"
+	ProbeAuthBasic  = "basic"
+	ProbeAuthDigest = "digest"
+)
+
+// RTSPProbeResult - reachability, authentication and stream description of the RTSP endpoint
+type RTSPProbeResult struct {
+	Endpoint   string       `json:"endpoint"`              // probed endpoint (password redacted)
+	Status     string       `json:"status"`                // ok, unreachable, unauthorized or failed
+	Message    string       `json:"message,omitempty"`     // reason if not ok
+	Reachable  bool         `json:"reachable"`             // camera responded to RTSP requests
+	Auth       string       `json:"auth,omitempty"`        // authentication required by the camera (none, basic or digest)
+	Server     string       `json:"server,omitempty"`      // Server header of the camera
+	Methods    []string     `json:"methods,omitempty"`     // methods supported by the camera (OPTIONS)
+	Tracks     []*RTSPTrack `json:"tracks,omitempty"`      // media tracks from the SDP
+	Setup      bool         `json:"setup"`                 // first track set up successfully
+	SDP        string       `json:"sdp,omitempty"`         // session description returned by DESCRIBE
+	DurationMs int64        `json:"duration_ms,omitempty"` // probe duration
+}
+
+// RTSPTrack - media track described in the SDP
+type RTSPTrack struct {
+	Media       string `json:"media"`                // video, audio, application
+	Codec       string `json:"codec,omitempty"`      // encoding name (e.g. H264, H265, PCMA)
+	PayloadType int    `json:"payload_type"`         // RTP payload type
+	ClockRate   int    `json:"clock_rate,omitempty"` // RTP clock rate
+	Channels    int    `json:"channels,omitempty"`   // audio channels
+	Control     string `json:"control,omitempty"`    // track control URL (SETUP)
+	Fmtp        string `json:"fmtp,omitempty"`       // format parameters (e.g. sprop-parameter-sets)
+}
+
+// OK - stream can be consumed by the camera container
+func (r *RTSPProbeResult) OK() bool {
+	return r.Status == ProbeStatusOK
+}
+
+// RTSPProbeError - camera not created because the RTSP endpoint failed the probe
+type RTSPProbeError struct {
+	Result *RTSPProbeResult
+}
+
+func (e *RTSPProbeError) Error() string {
+	if e.Result.Message == "" {
+		return "rtsp probe " + e.Result.Status
+	}
+	return "rtsp probe " + e.Result.Status + ": " + e.Result.Message
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	PrefixRule      = "/rule/"
+	PrefixRuleStats = "/rulestats/"
+	PrefixRuleClip  = "/ruleclip/" // clips waiting for the post event video
+
+	RuleTriggerAnnotation   = "annotation"    // evaluated on every annotation passing through Annotate
+	RuleTriggerProcessState = "process_state" // evaluated on every camera or application state change
+
+	RuleActionWebhook = "webhook" // HTTP POST of the rule event to the url
+	RuleActionMQTT    = "mqtt"    // publish the rule event to MQTT events subfolder (topic)
+	RuleActionClip    = "clip"    // save on-disk video segments around the event
+)
+
+// Rule - if condition is met on trigger then perform actions (at most once per cooldown per device)
+type Rule struct {
+	Name            string         `json:"name" binding:"required"`    // unique name of the rule
+	Enabled         bool           `json:"enabled"`                    // disabled rules are not evaluated
+	Trigger         string         `json:"trigger" binding:"required"` // annotation or process_state
+	Condition       *RuleCondition `json:"condition,omitempty"`        // empty condition matches everything
+	Actions         []*RuleAction  `json:"actions" binding:"required"` // actions performed when rule fires
+	CooldownSeconds int64          `json:"cooldown_seconds,omitempty"` // minimum time between two firings of the rule for the same device
+	Created         int64          `json:"created,omitempty"`          // unix timestamp in ms when created
+	Modified        int64          `json:"modified,omitempty"`         // last modification date, epoch in ms
+	Stats           *RuleStats     `json:"stats,omitempty"`            // hit counters (read only)
+}
+
+// RuleCondition - all non empty fields must match
+type RuleCondition struct {
+	DeviceName    string  `json:"device_name,omitempty"`    // camera or application name
+	Type          string  `json:"type,omitempty"`           // annotation event type (e.g. moving)
+	ObjectType    string  `json:"object_type,omitempty"`    // annotated object type (e.g. person)
+	Zone          string  `json:"zone,omitempty"`           // name of the counting area the annotated object must be in
+	MinConfidence float64 `json:"min_confidence,omitempty"` // confidence must be above this value
+	TimeFrom      string  `json:"time_from,omitempty"`      // local time of day (HH:MM) from which the rule is active
+	TimeTo        string  `json:"time_to,omitempty"`        // local time of day (HH:MM) until the rule is active (can wrap over midnight)
+	State         string  `json:"state,omitempty"`          // process state (e.g. running, exited, restarting)
+}
+
+// RuleAction performed when the rule fires
+type RuleAction struct {
+	Type        string `json:"type" binding:"required"` // webhook, mqtt or clip
+	URL         string `json:"url,omitempty"`           // webhook url
+	Topic       string `json:"topic,omitempty"`         // mqtt events subfolder
+	PreSeconds  int    `json:"pre_seconds,omitempty"`   // clip: seconds of video before the event
+	PostSeconds int    `json:"post_seconds,omitempty"`  // clip: seconds of video after the event
+}
+
+// RuleStats hit counters of a rule
+type RuleStats struct {
+	Name       string `json:"name"`
+	Hits       int64  `json:"hits"`                 // number of times the condition matched
+	Fired      int64  `json:"fired"`                // number of times actions were performed
+	Suppressed int64  `json:"suppressed"`           // number of hits suppressed by cooldown
+	Dropped    int64  `json:"dropped"`              // number of firings dropped because too many actions were pending
+	LastFired  int64  `json:"last_fired,omitempty"` // epoch in ms of the last firing
+}
+
+// ValidateRule checks rule trigger, condition and actions
+func ValidateRule(rule *Rule) error {
+	if rule.Name == "" || rule.Trigger == "" || len(rule.Actions) == 0 {
+		return ErrMissingInputParameters
+	}
+	if strings.Contains(rule.Name, "/") || rule.CooldownSeconds < 0 {
+		return ErrInvalidInputParameters
+	}
+	if rule.Trigger != RuleTriggerAnnotation && rule.Trigger != RuleTriggerProcessState {
+		return ErrInvalidInputParameters
+	}
+	if c := rule.Condition; c != nil {
+		if (c.TimeFrom == "") != (c.TimeTo == "") {
+			return ErrInvalidInputParameters
+		}
+		if c.TimeFrom != "" {
+			if _, err := time.Parse("15:04", c.TimeFrom); err != nil {
+				return ErrInvalidInputParameters
+			}
+			if _, err := time.Parse("15:04", c.TimeTo); err != nil {
+				return ErrInvalidInputParameters
+			}
+		}
+		if c.Zone != "" && c.DeviceName == "" {
+			return ErrInvalidInputParameters
+		}
+	}
+	for _, a := range rule.Actions {
+		if a == nil {
+			return ErrInvalidInputParameters
+		}
+		switch a.Type {
+		case RuleActionWebhook:
+			if !strings.HasPrefix(a.URL, "http://") && !strings.HasPrefix(a.URL, "https://") {
+				return ErrInvalidInputParameters
+			}
+		case RuleActionMQTT:
+			if a.Topic == "" {
+				return ErrInvalidInputParameters
+			}
+		case RuleActionClip:
+			if a.PreSeconds < 0 || a.PostSeconds < 0 {
+				return ErrInvalidInputParameters
+			}
+		default:
+			return ErrInvalidInputParameters
+		}
+	}
+	return nil
+}
+
+// RuleEvent is sent by the rule actions (webhook body, mqtt payload)
+type RuleEvent struct {
+	Rule       string          `json:"rule"`
+	Trigger    string          `json:"trigger"`
+	DeviceName string          `json:"device_name"`
+	Timestamp  int64           `json:"timestamp"`            // epoch in ms of the annotation or state change
+	State      string          `json:"state,omitempty"`      // process state (process_state trigger)
+	Annotation json.RawMessage `json:"annotation,omitempty"` // annotation (annotation trigger)
+}
+
+// RuleClip is a clip to be saved once the video after the event has been recorded
+type RuleClip struct {
+	Rule       string `json:"rule"`
+	DeviceName string `json:"device_name"` // stored camera name
+	Timestamp  int64  `json:"timestamp"`   // epoch in ms of the event
+	From       int64  `json:"from"`        // epoch in ms of the clip start
+	To         int64  `json:"to"`          // epoch in ms of the clip end (clip is saved after)
+}
+
+// RuleMQTTMessage is passed over local redis pub/sub to the MQTT gateway
+type RuleMQTTMessage struct {
+	Topic string     `json:"topic"`
+	Event *RuleEvent `json:"event"`
+}
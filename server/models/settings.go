@@ -35,6 +35,16 @@ type Settings struct {
 	Modified      int64  `json:"modified,omitempty"`
 }
 
+// Redacted returns a copy of the settings without the edge secret and private key (API responses)
+func (s *Settings) Redacted() *Settings {
+	redacted := *s
+	if redacted.EdgeSecret != "" {
+		redacted.EdgeSecret = RedactedSecret
+	}
+	redacted.PrivateRSAKey = nil
+	return &redacted
+}
+
 // SettingsDockerTagVersion - current docker tag and version of the e.g. RTSP camera docker image
 type SettingDockerTagVersion struct {
 	Tag        string `json:"tag" binding:"required"`
@@ -15,6 +15,9 @@
 package models
 
 import (
+	"regexp"
+	"strings"
+
 	microModelDocker "github.com/chryscloud/go-microkit-plugins/models/docker"
 	"github.com/docker/docker/api/types"
 )
@@ -33,12 +36,29 @@ const (
 	// added custom status for chrysalis cloud
 	ProcessStatusFailed     = "failed"
 	ProcessStatusInProgress = "in-progress"
+
+	// stream health reported by the stream watchdog
+	StreamHealthOK       = "ok"        // frames/packets arriving
+	StreamHealthStalled  = "stalled"   // no new frames/packets for longer than the stall timeout
+	StreamHealthNoSignal = "no-signal" // nothing received since the container started
+
+	GroupSeparator = "/" // group hierarchy separator (e.g. site-a/building-1/floor-2)
+
+	maxLabels            = 64
+	maxLabelValue        = 256
+	maxCameraGroup       = 256
+	maxCameraDescription = 1024
 )
 
+// label keys as allowed in docker labels (alphanumerics, dots, dashes and underscores)
+var labelKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]{0,61}[a-zA-Z0-9])?$`)
+
 type StreamProcess struct {
 	Name             string                       `json:"name,omitempty"`                   // name of the streaming process
 	ImageTag         string                       `json:"image_tag,omitempty"`              // imagetag (default is: chryscloud/chrysedgeproxy:latest)
 	RTSPEndpoint     string                       `json:"rtsp_endpoint" binding:"required"` // rtsp_endpoint (always required for RTSP streaming)
+	RTSPUsername     string                       `json:"rtsp_username,omitempty"`          // optional: RTSP username (credentials in the rtsp_endpoint are moved here)
+	RTSPPassword     string                       `json:"rtsp_password,omitempty"`          // optional: RTSP password, write only (stored encrypted, passed to the container on start)
 	RTMPEndpoint     string                       `json:"rtmp_endpoint,omitempty"`          // RTMP endpoint (optional Chrysalis RTMP endpoint)
 	ContainerID      string                       `json:"container_id,omitempty"`           // docker container id
 	Status           string                       `json:"status,omitempty"`                 // State (running, restarting, ...)
@@ -49,6 +69,84 @@ type StreamProcess struct {
 	RTMPStreamStatus *RTMPStreamStatus            `json:"rtmp_stream_status,omitempty"`     // info on if stream is being proxied and if storage set
 	UpgradeAvailable bool                         `json:"upgrade_available,default:false"`  // by default no upgrade available
 	NewerVersion     string                       `json:"newer_version,omitempty"`          // if upgrade true the latest version available
+	Location         *CameraLocation              `json:"location,omitempty"`               // optional: camera location and heading (used to enrich annotations)
+	StreamHealth     string                       `json:"stream_health,omitempty"`          // ok, stalled or no-signal (reported by the stream watchdog)
+	LastReconciled   *ReconcileStatus             `json:"last_reconciled,omitempty"`        // result of the last desired-state reconciliation
+	Paused           bool                         `json:"paused,omitempty"`                 // paused cameras keep the container but don't produce frames
+	Group            string                       `json:"group,omitempty"`                  // optional: group or site hierarchy (e.g. site-a/building-1/floor-2)
+	Labels           map[string]string            `json:"labels,omitempty"`                 // optional: free-form labels (also set as docker container labels)
+	Description      string                       `json:"description,omitempty"`            // optional: free-form description
+}
+
+// CameraLocation - where the camera is mounted and which direction it's facing
+type CameraLocation struct {
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	Heading float64 `json:"heading,omitempty"` // degrees clockwise from north
+	Height  float64 `json:"height,omitempty"`  // mounting height in meters
+}
+
+// ProcessFilter - cameras matching the group (including its sub-groups) and all of the label selectors
+type ProcessFilter struct {
+	Group  string   `form:"group" json:"group,omitempty"`
+	Labels []string `form:"label" json:"labels,omitempty"` // key=value or just key (label present)
+}
+
+// GroupOperationResult - result of a group operation on a single camera
+type GroupOperationResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// GroupProxyRequest - toggles RTMP passthrough for a group of cameras
+type GroupProxyRequest struct {
+	Passthrough bool `json:"passthrough"`
+}
+
+// ValidateCameraMetadata checks labels, group, description and location
+func ValidateCameraMetadata(process *StreamProcess) error {
+	if len(process.Labels) > maxLabels || len(process.Group) > maxCameraGroup || len(process.Description) > maxCameraDescription {
+		return ErrInvalidInputParameters
+	}
+	for k, v := range process.Labels {
+		if !labelKeyRegex.MatchString(k) || len(v) > maxLabelValue {
+			return ErrInvalidInputParameters
+		}
+	}
+	if process.Group != "" {
+		for _, segment := range strings.Split(process.Group, GroupSeparator) {
+			if strings.TrimSpace(segment) == "" {
+				return ErrInvalidInputParameters
+			}
+		}
+	}
+	if l := process.Location; l != nil {
+		if l.Lat < -90 || l.Lat > 90 || l.Lon < -180 || l.Lon > 180 || l.Heading < 0 || l.Heading >= 360 || l.Height < 0 {
+			return ErrInvalidInputParameters
+		}
+	}
+	return nil
+}
+
+// Matches checks if the camera is in the group (or one of its sub-groups) and has all of the selected labels
+func (f *ProcessFilter) Matches(process *StreamProcess) bool {
+	if f == nil {
+		return true
+	}
+	if group := strings.Trim(f.Group, GroupSeparator); group != "" {
+		if process.Group != group && !strings.HasPrefix(process.Group, group+GroupSeparator) {
+			return false
+		}
+	}
+	for _, selector := range f.Labels {
+		kv := strings.SplitN(selector, "=", 2)
+		value, ok := process.Labels[kv[0]]
+		if !ok || (len(kv) == 2 && value != kv[1]) {
+			return false
+		}
+	}
+	return true
 }
 
 type RTMPStreamStatus struct {
@@ -22,6 +22,7 @@ type ProcessStats struct {
 	NetworkRx   int64  `json:"x"`  // network read
 	NetworkTx   int64  `json:"t"`  // network write
 	Status      string `json:"s"`  // status
+	Health      string `json:"h"`  // stream health (ok, stalled, no-signal)
 }
 
 type SystemInfo struct {
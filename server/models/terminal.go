@@ -0,0 +1,30 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+const (
+	TerminalMessageInput  = "input"  // keystrokes typed into the terminal
+	TerminalMessageResize = "resize" // terminal window resized
+
+	DefaultTerminalShell = "/bin/sh"
+)
+
+// TerminalMessage - message sent by the terminal client over the websocket (output is sent back as binary messages)
+type TerminalMessage struct {
+	Type string `json:"type"`           // input or resize
+	Data string `json:"data,omitempty"` // input
+	Cols uint   `json:"cols,omitempty"` // resize
+	Rows uint   `json:"rows,omitempty"` // resize
+}
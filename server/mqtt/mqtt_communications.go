@@ -14,10 +14,6 @@ import (
 	"github.com/chryscloud/video-edge-ai-proxy/services"
 	"github.com/chryscloud/video-edge-ai-proxy/utils"
 	badger "github.com/dgraph-io/badger/v2"
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/events"
-	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/client"
 	qtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/go-redis/redis/v7"
 )
@@ -33,6 +29,9 @@ type mqttManager struct {
 	settingsService          *services.SettingsManager
 	processService           *services.ProcessManager
 	appService               *services.AppProcessManager
+	containerRuntime         services.ContainerRuntime
+	auditManager             *services.AuditManager
+	manifestManager          *services.ManifestManager
 	client                   *qtt.Client
 	clientOpts               *qtt.ClientOptions
 	stop                     chan bool
@@ -44,12 +43,15 @@ type mqttManager struct {
 	mutex                    sync.Mutex
 }
 
-func NewMqttManager(rdb *redis.Client, settingsService *services.SettingsManager, processService *services.ProcessManager, appService *services.AppProcessManager) *mqttManager {
+func NewMqttManager(rdb *redis.Client, settingsService *services.SettingsManager, processService *services.ProcessManager, appService *services.AppProcessManager, containerRuntime services.ContainerRuntime, auditManager *services.AuditManager, manifestManager *services.ManifestManager) *mqttManager {
 	return &mqttManager{
 		rdb:                      rdb,
 		settingsService:          settingsService,
 		processService:           processService,
 		appService:               appService,
+		containerRuntime:         containerRuntime,
+		auditManager:             auditManager,
+		manifestManager:          manifestManager,
 		processEvents:            sync.Map{},
 		lastProcessEventNotified: sync.Map{},
 		mutex:                    sync.Mutex{},
@@ -66,7 +68,7 @@ func (mqtt *mqttManager) onMessage(client qtt.Client, msg qtt.Message) {
 	var edgeConfig models.EdgeCommandPayload
 	err := json.Unmarshal(msg.Payload(), &edgeConfig)
 	if err != nil {
-		g.Log.Error("failed to unmarshal config payload", err, string(msg.Payload()))
+		g.Log.Error("failed to unmarshal config payload", err, utils.RedactURLs(string(msg.Payload())))
 		return
 	}
 
@@ -78,6 +80,8 @@ func (mqtt *mqttManager) onMessage(client qtt.Client, msg qtt.Message) {
 			operation = models.DeviceOperationStart
 		} else if edgeConfig.Operation == "r" {
 			operation = models.DeviceOperationDelete
+		} else if isControlOperation(models.MQTTProcessOperation(edgeConfig.Operation)) {
+			operation = edgeConfig.Operation
 		} else {
 			g.Log.Error("camera command operation not supported: ", edgeConfig.Name, edgeConfig.ImageTag, edgeConfig.Operation)
 			return
@@ -98,7 +102,7 @@ func (mqtt *mqttManager) onConnectionLost(client qtt.Client, err error) {
 
 func (mqtt *mqttManager) configHandler(client qtt.Client, msg qtt.Message) {
 	g.Log.Info("Received config request: ", msg.Topic())
-	g.Log.Info("Message: ", string(msg.Payload()))
+	g.Log.Info("Message: ", utils.RedactURLs(string(msg.Payload())))
 }
 
 // StartGatewayListener checks every 15 seconds if there are any settings for connection to gateway
@@ -154,7 +158,7 @@ func (mqtt *mqttManager) run() error {
 			if err != nil {
 				g.Log.Error("failed to receive mqtt local pubsub message", err)
 			} else {
-				g.Log.Info("redis message received: ", val)
+				g.Log.Info("redis message received: ", val.Channel)
 				payload := []byte(val.Payload)
 				var localMsg models.MQTTMessage
 				err := json.Unmarshal(payload, &localMsg)
@@ -163,6 +167,11 @@ func (mqtt *mqttManager) run() error {
 				} else {
 					g.Log.Info("Received message object from redis pubsub for mqtt: ", localMsg.DeviceID)
 					var opErr error
+					command := isCloudCommand(&localMsg)
+					var before []byte
+					if command {
+						before = mqtt.auditManager.Snapshot(string(localMsg.ProcessType), localMsg.DeviceID)
+					}
 					if localMsg.ProcessType == models.MQTTProcessType(models.ProcessTypeRTSP) {
 						if localMsg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationAdd) {
 
@@ -172,6 +181,14 @@ func (mqtt *mqttManager) run() error {
 
 							opErr = mqtt.unbindDevice(localMsg.DeviceID, models.MQTTProcessType(models.ProcessTypeRTSP))
 
+						} else if localMsg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationUpdate) {
+
+							opErr = mqtt.reportDeviceOperation(localMsg.DeviceID, models.MQTTProcessType(models.ProcessTypeRTSP), localMsg.ProcessOperation)
+
+						} else if isControlOperation(localMsg.ProcessOperation) {
+
+							opErr = mqtt.ControlCamera(localMsg.DeviceID, localMsg.ProcessOperation, localMsg.Message)
+
 						} else if localMsg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationUpgradeAvailable) {
 							// TODO: TBD
 							g.Log.Warn("TBD: process operation upgrade available")
@@ -184,6 +201,9 @@ func (mqtt *mqttManager) run() error {
 						} else if localMsg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationDelete) {
 
 							opErr = mqtt.StopCamera(localMsg.Message)
+						} else if localMsg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationHealth) {
+
+							opErr = mqtt.reportDeviceStateChange(localMsg.DeviceID, "")
 						} else if localMsg.ProcessOperation == models.MQTTProcessOperation(models.DeviceInternalTesting) {
 
 							// **********
@@ -213,15 +233,25 @@ func (mqtt *mqttManager) run() error {
 							// DELETE APPLICATION
 							opErr = mqtt.StopApplication(localMsg.Message)
 
+						} else if isControlOperation(localMsg.ProcessOperation) {
+							// RESTART, PAUSE OR RESUME APPLICATION
+							opErr = mqtt.ControlApplication(localMsg.DeviceID, localMsg.ProcessOperation, localMsg.Message)
+
 						} else {
 							opErr = errors.New("local message application operation not recognized")
 							g.Log.Error("message application operation not recognized: ", localMsg.ProcessOperation, localMsg.DeviceID, localMsg.ProcessType)
 						}
+					} else if localMsg.ProcessType == models.MQTTProcessType(models.ProcessTypeRule) {
+						// EVENT RULE FIRED
+						opErr = mqtt.publishRuleEvent(localMsg.Message)
 					}
 
 					if opErr != nil {
 						g.Log.Error("local pubsub gateway msg failed", opErr)
 					}
+					if command {
+						mqtt.auditCommand(&localMsg, before, opErr)
+					}
 				}
 			}
 		}
@@ -229,18 +259,8 @@ func (mqtt *mqttManager) run() error {
 
 	// reporting device changes
 	go func() {
-		cl, err := client.NewClient("unix:///var/run/docker.sock", "1.40", nil, nil)
-		if err != nil {
-			g.Log.Error("failed to initialize docker event listener")
-			return
-		}
-		filterArgs := filters.NewArgs()
-		filterArgs.Add("type", events.ContainerEventType)
-		opts := types.EventsOptions{
-			Filters: filterArgs,
-		}
-		// listening to events of docker
-		messages, errs := cl.Events(context.Background(), opts)
+		// listening to container events
+		messages, errs := mqtt.containerRuntime.Events(context.Background())
 
 		for {
 			select {
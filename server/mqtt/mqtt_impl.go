@@ -42,6 +42,45 @@ func (mqtt *mqttManager) getMQTTSettings() (*models.Settings, error) {
 	return settings, nil
 }
 
+// isCloudCommand - commands from Chrysalis Cloud carry the command payload, local change notifications don't
+func isCloudCommand(msg *models.MQTTMessage) bool {
+	if len(msg.Message) == 0 {
+		return false
+	}
+	switch msg.ProcessType {
+	case models.MQTTProcessType(models.ProcessTypeRTSP):
+		return msg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationStart) ||
+			msg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationDelete) ||
+			isControlOperation(msg.ProcessOperation)
+	case models.MQTTProcessType(models.ProcessTypeApplication):
+		return msg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationAdd) ||
+			msg.ProcessOperation == models.MQTTProcessOperation(models.DeviceOperationRemove) ||
+			isControlOperation(msg.ProcessOperation)
+	}
+	return false
+}
+
+// auditCommand records the performed cloud command with the changes of the camera or app record
+func (mqtt *mqttManager) auditCommand(msg *models.MQTTMessage, before []byte, opErr error) {
+	targetType := string(msg.ProcessType)
+	record := &models.AuditRecord{
+		Actor:      models.AuditActorCloud,
+		Source:     models.AuditSourceMQTT,
+		Action:     string(msg.ProcessOperation),
+		TargetType: targetType,
+		Target:     msg.DeviceID,
+		Result:     models.AuditResultSuccess,
+	}
+	if opErr != nil {
+		record.Result = models.AuditResultFailed
+		record.Message = opErr.Error()
+	}
+	err := mqtt.auditManager.RecordChange(record, before, mqtt.auditManager.Snapshot(targetType, msg.DeviceID))
+	if err != nil {
+		g.Log.Error("failed to audit cloud command", msg.ProcessOperation, msg.DeviceID, err)
+	}
+}
+
 // config and commans subscribers
 func (mqtt *mqttManager) gatewaySubscribers() error {
 	// wait for connection to be opened and propagate
@@ -147,6 +186,7 @@ func (mqtt *mqttManager) reportDeviceStateChange(deviceID string, status string)
 	var imageTag string
 	var rtmpEndpoint string
 	var rtspEndpoint string
+	var streamHealth string
 
 	device, err := mqtt.processService.Info(deviceID)
 	if err != nil {
@@ -170,6 +210,10 @@ func (mqtt *mqttManager) reportDeviceStateChange(deviceID string, status string)
 		tp = models.MQTTProcessType(models.ProcessTypeRTSP)
 		rtmpEndpoint = device.RTMPEndpoint
 		rtspEndpoint = device.RTSPEndpoint
+		streamHealth = device.StreamHealth
+		if status == "" {
+			status = device.Status
+		}
 	}
 
 	sett, err := mqtt.settingsService.Get()
@@ -184,6 +228,7 @@ func (mqtt *mqttManager) reportDeviceStateChange(deviceID string, status string)
 		RTMPEndpoint:     rtmpEndpoint,
 		RTSPConnection:   rtspEndpoint,
 		State:            status,
+		StreamHealth:     streamHealth,
 		Created:          time.Now().UTC().Unix() * 1000,
 		ProcessOperation: models.MQTTProcessOperation(models.DeviceOperationState),
 		ProcessType:      tp,
@@ -256,6 +301,8 @@ func (mqtt *mqttManager) bindDevice(deviceID string, processType models.MQTTProc
 		RTMPEndpoint:     device.RTMPEndpoint,
 		RTSPConnection:   device.RTSPEndpoint,
 		State:            device.State.Status,
+		Group:            device.Group,
+		Labels:           device.Labels,
 		Created:          time.Now().UTC().Unix() * 1000,
 		ProcessOperation: models.MQTTProcessOperation(models.DeviceOperationAdd),
 		ProcessType:      processType,
@@ -267,6 +314,33 @@ func (mqtt *mqttManager) bindDevice(deviceID string, processType models.MQTTProc
 	return nil
 }
 
+// report operation performed on a device bound to this gateway (e.g. updated configuration, restart, pause)
+func (mqtt *mqttManager) reportDeviceOperation(deviceID string, processType models.MQTTProcessType, operation models.MQTTProcessOperation) error {
+	device, err := mqtt.processService.Info(deviceID)
+	if err != nil {
+		return err
+	}
+	mqttMsg := &models.MQTTMessage{
+		DeviceID:         device.Name,
+		ImageTag:         device.ImageTag,
+		RTMPEndpoint:     device.RTMPEndpoint,
+		RTSPConnection:   device.RTSPEndpoint,
+		State:            device.Status,
+		StreamHealth:     device.StreamHealth,
+		Group:            device.Group,
+		Labels:           device.Labels,
+		Created:          time.Now().UTC().Unix() * 1000,
+		ProcessOperation: operation,
+		ProcessType:      processType,
+	}
+	pErr := utils.PublishMonitoringTelemetry(mqtt.gatewayID, (*mqtt.client), mqttMsg)
+	if pErr != nil {
+		g.Log.Error("failed to publish device operation", device.Name, operation, pErr)
+		return pErr
+	}
+	return nil
+}
+
 // unbiding single device from this gateway
 func (mqtt *mqttManager) unbindDevice(deviceID string, processType models.MQTTProcessType) error {
 	set, err := mqtt.settingsService.Get()
@@ -304,7 +378,9 @@ func (mqtt *mqttManager) bindAllDevices() error {
 			DeviceID:         device.Name,
 			ImageTag:         device.ImageTag,
 			Created:          device.Created,
-			State:            device.State.Status,
+			State:            device.Status,
+			Group:            device.Group,
+			Labels:           device.Labels,
 			ProcessOperation: models.MQTTProcessOperation(models.DeviceOperationAdd),
 			ProcessType:      models.MQTTProcessType(processType),
 		}
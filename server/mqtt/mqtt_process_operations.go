@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/chryscloud/go-microkit-plugins/docker"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/models"
 	"github.com/chryscloud/video-edge-ai-proxy/utils"
@@ -35,6 +34,10 @@ func (mqtt *mqttManager) StopCamera(configPayload []byte) error {
 		return pErr
 	}
 
+	if err := mqtt.manualChange(models.ProcessTypeRTSP, payload.Name, payload.ImageTag, models.DeviceOperationDelete); err != nil {
+		return err
+	}
+
 	// process found, can delete
 	err = mqtt.processService.Stop(payload.Name, models.PrefixRTSPProcess)
 	if err != nil {
@@ -84,6 +87,10 @@ func (mqtt *mqttManager) StartCamera(configPayload []byte) error {
 		}
 	}
 
+	if err := mqtt.manualChange(models.ProcessTypeRTSP, streamProcess.Name, streamProcess.ImageTag, models.DeviceOperationStart); err != nil {
+		return err
+	}
+
 	rtspImageTag := models.CameraTypeToImageTag[payload.Type]
 	if rtspImageTag == "" {
 		g.Log.Error("failed to find payload type", payload.Type)
@@ -113,9 +120,9 @@ func (mqtt *mqttManager) StartCamera(configPayload []byte) error {
 
 	g.Log.Info(highestImgVersion)
 
-	err = mqtt.processService.Start(streamProcess, highestImgVersion)
+	err = mqtt.processService.Start(streamProcess, highestImgVersion, false)
 	if err != nil {
-		g.Log.Error("failed to start new device", streamProcess.Name, streamProcess.ImageTag, streamProcess.RTSPEndpoint, err)
+		g.Log.Error("failed to start new device", streamProcess.Name, streamProcess.ImageTag, utils.RedactURL(streamProcess.RTSPEndpoint), err)
 		return err
 	}
 
@@ -177,10 +184,13 @@ func (mqtt *mqttManager) PullApplication(configPayload []byte) (*models.EdgeComm
 		return nil, err
 	}
 
-	// check if app already pulled
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
+	// install command starts with the pull
+	if err := mqtt.manualChange(models.ProcessTypeApplication, payload.Name, payload.ImageTag, models.DeviceOperationAdd); err != nil {
+		return nil, err
+	}
 
-	images, err := cl.ImagesList()
+	// check if app already pulled
+	images, err := mqtt.settingsService.ListLocalDockerImages()
 	if err != nil {
 		g.Log.Error("failed to retrieve container list", err)
 		return nil, err
@@ -285,6 +295,10 @@ func (mqtt *mqttManager) StopApplication(configPayload []byte) error {
 		return err
 	}
 
+	if err := mqtt.manualChange(models.ProcessTypeApplication, payload.Name, payload.ImageTag, models.DeviceOperationRemove); err != nil {
+		return err
+	}
+
 	err = mqtt.processService.Stop(payload.Name, models.PrefixAppProcess)
 	if err != nil {
 		// only report error if process exists
@@ -299,6 +313,15 @@ func (mqtt *mqttManager) StopApplication(configPayload []byte) error {
 	return nil
 }
 
+// manualChange checks the cloud command against manifest mode (see ManifestManager.ManualChange), rejection is reported to chrys cloud
+func (mqtt *mqttManager) manualChange(processType string, name string, imageTag string, operation string) error {
+	err := mqtt.manifestManager.ManualChange(processType, name, operation, models.AuditActorCloud)
+	if err != nil {
+		mqtt.notifyMqtt(name, imageTag, models.MQTTProcessOperation(models.DeviceOperationError), models.MQTTProcessType(processType), models.ProcessStatusFailed, err.Error())
+	}
+	return err
+}
+
 // mqtt notification message to chrys cloud
 func (mqtt *mqttManager) notifyMqtt(appName string, imageTag string, operation models.MQTTProcessOperation, operationType models.MQTTProcessType, status string, msg string) error {
 
@@ -329,3 +352,96 @@ func (mqtt *mqttManager) notifyMqtt(appName string, imageTag string, operation m
 	}
 	return nil
 }
+
+// publishRuleEvent forwards the fired event rule to Chrysalis Cloud
+func (mqtt *mqttManager) publishRuleEvent(message []byte) error {
+	var ruleMsg models.RuleMQTTMessage
+	err := json.Unmarshal(message, &ruleMsg)
+	if err != nil {
+		g.Log.Error("failed to unmarshal event rule message", err)
+		return err
+	}
+	payload, err := json.Marshal(ruleMsg.Event)
+	if err != nil {
+		g.Log.Error("failed to marshal event rule payload", err)
+		return err
+	}
+	return utils.PublishRuleEvent(mqtt.gatewayID, (*mqtt.client), ruleMsg.Topic, payload)
+}
+
+// isControlOperation - restart, pause or resume of a camera or app
+func isControlOperation(operation models.MQTTProcessOperation) bool {
+	switch string(operation) {
+	case models.DeviceOperationRestart, models.DeviceOperationPause, models.DeviceOperationResume:
+		return true
+	}
+	return false
+}
+
+// ControlCamera - restart, pause or resume of a camera. Commands from Chrysalis Cloud carry the command payload and are
+// performed first, operations already performed through the local API are only reported.
+func (mqtt *mqttManager) ControlCamera(deviceID string, operation models.MQTTProcessOperation, configPayload []byte) error {
+	if len(configPayload) > 0 {
+		var payload models.EdgeCommandPayload
+		err := json.Unmarshal(configPayload, &payload)
+		if err != nil {
+			g.Log.Error("failed to unmarshal config payload", err)
+			return err
+		}
+		deviceID = payload.Name
+
+		switch string(operation) {
+		case models.DeviceOperationRestart:
+			_, err = mqtt.processService.Restart(deviceID)
+		case models.DeviceOperationPause:
+			_, err = mqtt.processService.Pause(deviceID)
+		case models.DeviceOperationResume:
+			_, err = mqtt.processService.Resume(deviceID)
+		}
+		if err != nil {
+			g.Log.Error("failed to "+string(operation)+" camera", deviceID, err)
+			mqtt.notifyMqtt(deviceID, payload.ImageTag, models.MQTTProcessOperation(models.DeviceOperationError), models.MQTTProcessType(models.ProcessTypeRTSP), models.ProcessStatusFailed, string(operation)+" failed")
+			return err
+		}
+	}
+	return mqtt.reportDeviceOperation(deviceID, models.MQTTProcessType(models.ProcessTypeRTSP), operation)
+}
+
+// ControlApplication - restart, pause or resume of an application (same as ControlCamera)
+func (mqtt *mqttManager) ControlApplication(appName string, operation models.MQTTProcessOperation, configPayload []byte) error {
+	imageTag := ""
+	if len(configPayload) > 0 {
+		var payload models.EdgeCommandPayload
+		err := json.Unmarshal(configPayload, &payload)
+		if err != nil {
+			g.Log.Error("failed to unmarshal app config payload", err)
+			return err
+		}
+		appName = payload.Name
+		imageTag = payload.ImageTag
+
+		switch string(operation) {
+		case models.DeviceOperationRestart:
+			_, err = mqtt.appService.Restart(appName)
+		case models.DeviceOperationPause:
+			_, err = mqtt.appService.Pause(appName)
+		case models.DeviceOperationResume:
+			_, err = mqtt.appService.Resume(appName)
+		}
+		if err != nil {
+			g.Log.Error("failed to "+string(operation)+" application", appName, err)
+			mqtt.notifyMqtt(appName, imageTag, models.MQTTProcessOperation(models.DeviceOperationError), models.MQTTProcessType(models.ProcessTypeApplication), models.ProcessStatusFailed, string(operation)+" failed")
+			return err
+		}
+	}
+
+	app, err := mqtt.appService.Info(appName)
+	if err != nil {
+		g.Log.Error("failed to retrieve application", appName, err)
+		return err
+	}
+	if imageTag == "" {
+		imageTag = app.DockerHubUser + "/" + app.DockerhubRepository + ":" + app.DockerHubVersion
+	}
+	return mqtt.notifyMqtt(app.Name, imageTag, operation, models.MQTTProcessType(models.ProcessTypeApplication), app.Status, "")
+}
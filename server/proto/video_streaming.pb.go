@@ -60,6 +60,13 @@ type AnnotateRequest struct {
 	CustomMeta_3 string `protobuf:"bytes,27,opt,name=custom_meta_3,json=customMeta3,proto3" json:"custom_meta_3,omitempty"`
 	CustomMeta_4 string `protobuf:"bytes,28,opt,name=custom_meta_4,json=customMeta4,proto3" json:"custom_meta_4,omitempty"`
 	CustomMeta_5 string `protobuf:"bytes,29,opt,name=custom_meta_5,json=customMeta5,proto3" json:"custom_meta_5,omitempty"`
+	// track events (aggregated annotations of the same object_tracking_id)
+	ObjectPath []*Coordinate     `protobuf:"bytes,30,rep,name=object_path,json=objectPath,proto3" json:"object_path,omitempty"`                                                                       // optional: trajectory of the tracked object
+	IsTrack    bool              `protobuf:"varint,31,opt,name=is_track,json=isTrack,proto3" json:"is_track,omitempty"`                                                                               // true if this is an aggregated track event
+	Sequence   int64             `protobuf:"varint,32,opt,name=sequence,proto3" json:"sequence,omitempty"`                                                                                            // optional: client sequence number on AnnotateStream (assigned by the server if 0)
+	Attributes map[string]string `protobuf:"bytes,33,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // optional: free form event meta data (replaces custom_meta_1..5)
+	Objects    []*DetectedObject `protobuf:"bytes,34,rep,name=objects,proto3" json:"objects,omitempty"`                                                                                               // optional: all detections in the frame (instead of one object per request)
+	Labels     []*Classification `protobuf:"bytes,35,rep,name=labels,proto3" json:"labels,omitempty"`                                                                                                 // optional: classification labels of the frame or single object
 }
 
 func (x *AnnotateRequest) Reset() {
@@ -297,6 +304,222 @@ func (x *AnnotateRequest) GetCustomMeta_5() string {
 	return ""
 }
 
+func (x *AnnotateRequest) GetObjectPath() []*Coordinate {
+	if x != nil {
+		return x.ObjectPath
+	}
+	return nil
+}
+
+func (x *AnnotateRequest) GetIsTrack() bool {
+	if x != nil {
+		return x.IsTrack
+	}
+	return false
+}
+
+func (x *AnnotateRequest) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AnnotateRequest) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *AnnotateRequest) GetObjects() []*DetectedObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *AnnotateRequest) GetLabels() []*Classification {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type DetectedObject struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ObjectType       string            `protobuf:"bytes,1,opt,name=object_type,json=objectType,proto3" json:"object_type,omitempty"`                     // e.g. person, car, face, bag, roadsign,...
+	ObjectId         string            `protobuf:"bytes,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`                           // e.g. object id from the ML model
+	ObjectTrackingId string            `protobuf:"bytes,3,opt,name=object_tracking_id,json=objectTrackingId,proto3" json:"object_tracking_id,omitempty"` // tracking id of the object
+	Confidence       float64           `protobuf:"fixed64,4,opt,name=confidence,proto3" json:"confidence,omitempty"`                                     // confidence of inference [0-1.0]
+	ObjectBoudingBox *BoudingBox       `protobuf:"bytes,5,opt,name=object_bouding_box,json=objectBoudingBox,proto3" json:"object_bouding_box,omitempty"`
+	ObjectCoordinate *Coordinate       `protobuf:"bytes,6,opt,name=object_coordinate,json=objectCoordinate,proto3" json:"object_coordinate,omitempty"`
+	Mask             []*Coordinate     `protobuf:"bytes,7,rep,name=mask,proto3" json:"mask,omitempty"`
+	ObjectSignature  []float64         `protobuf:"fixed64,8,rep,packed,name=object_signature,json=objectSignature,proto3" json:"object_signature,omitempty"`
+	Attributes       map[string]string `protobuf:"bytes,9,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // object specific meta data (merged over request attributes)
+	Labels           []*Classification `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty"`                                                                                                // classification labels of the object
+}
+
+func (x *DetectedObject) Reset() {
+	*x = DetectedObject{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DetectedObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectedObject) ProtoMessage() {}
+
+func (x *DetectedObject) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectedObject.ProtoReflect.Descriptor instead.
+func (*DetectedObject) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DetectedObject) GetObjectType() string {
+	if x != nil {
+		return x.ObjectType
+	}
+	return ""
+}
+
+func (x *DetectedObject) GetObjectId() string {
+	if x != nil {
+		return x.ObjectId
+	}
+	return ""
+}
+
+func (x *DetectedObject) GetObjectTrackingId() string {
+	if x != nil {
+		return x.ObjectTrackingId
+	}
+	return ""
+}
+
+func (x *DetectedObject) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *DetectedObject) GetObjectBoudingBox() *BoudingBox {
+	if x != nil {
+		return x.ObjectBoudingBox
+	}
+	return nil
+}
+
+func (x *DetectedObject) GetObjectCoordinate() *Coordinate {
+	if x != nil {
+		return x.ObjectCoordinate
+	}
+	return nil
+}
+
+func (x *DetectedObject) GetMask() []*Coordinate {
+	if x != nil {
+		return x.Mask
+	}
+	return nil
+}
+
+func (x *DetectedObject) GetObjectSignature() []float64 {
+	if x != nil {
+		return x.ObjectSignature
+	}
+	return nil
+}
+
+func (x *DetectedObject) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *DetectedObject) GetLabels() []*Classification {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type Classification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Label      string  `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Confidence float64 `protobuf:"fixed64,2,opt,name=confidence,proto3" json:"confidence,omitempty"` // [0-1.0]
+}
+
+func (x *Classification) Reset() {
+	*x = Classification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Classification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Classification) ProtoMessage() {}
+
+func (x *Classification) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Classification.ProtoReflect.Descriptor instead.
+func (*Classification) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Classification) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Classification) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
 type AnnotateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -311,7 +534,7 @@ type AnnotateResponse struct {
 func (x *AnnotateResponse) Reset() {
 	*x = AnnotateResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[1]
+		mi := &file_video_streaming_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -324,7 +547,7 @@ func (x *AnnotateResponse) String() string {
 func (*AnnotateResponse) ProtoMessage() {}
 
 func (x *AnnotateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[1]
+	mi := &file_video_streaming_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,7 +560,7 @@ func (x *AnnotateResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AnnotateResponse.ProtoReflect.Descriptor instead.
 func (*AnnotateResponse) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{1}
+	return file_video_streaming_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AnnotateResponse) GetDeviceName() string {
@@ -373,14 +596,16 @@ type Location struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Lat float64 `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"` // latitude
-	Lon float64 `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"` // longitude
+	Lat     float64 `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`         // latitude
+	Lon     float64 `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`         // longitude
+	Heading float64 `protobuf:"fixed64,3,opt,name=heading,proto3" json:"heading,omitempty"` // optional: camera heading in degrees (clockwise from north)
+	Height  float64 `protobuf:"fixed64,4,opt,name=height,proto3" json:"height,omitempty"`   // optional: camera mounting height in meters
 }
 
 func (x *Location) Reset() {
 	*x = Location{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[2]
+		mi := &file_video_streaming_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -393,7 +618,7 @@ func (x *Location) String() string {
 func (*Location) ProtoMessage() {}
 
 func (x *Location) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[2]
+	mi := &file_video_streaming_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -406,7 +631,7 @@ func (x *Location) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Location.ProtoReflect.Descriptor instead.
 func (*Location) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{2}
+	return file_video_streaming_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Location) GetLat() float64 {
@@ -423,6 +648,20 @@ func (x *Location) GetLon() float64 {
 	return 0
 }
 
+func (x *Location) GetHeading() float64 {
+	if x != nil {
+		return x.Heading
+	}
+	return 0
+}
+
+func (x *Location) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
 type Coordinate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -436,7 +675,7 @@ type Coordinate struct {
 func (x *Coordinate) Reset() {
 	*x = Coordinate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[3]
+		mi := &file_video_streaming_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -449,7 +688,7 @@ func (x *Coordinate) String() string {
 func (*Coordinate) ProtoMessage() {}
 
 func (x *Coordinate) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[3]
+	mi := &file_video_streaming_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -462,7 +701,7 @@ func (x *Coordinate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Coordinate.ProtoReflect.Descriptor instead.
 func (*Coordinate) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{3}
+	return file_video_streaming_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Coordinate) GetX() float64 {
@@ -500,7 +739,7 @@ type BoudingBox struct {
 func (x *BoudingBox) Reset() {
 	*x = BoudingBox{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[4]
+		mi := &file_video_streaming_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -513,7 +752,7 @@ func (x *BoudingBox) String() string {
 func (*BoudingBox) ProtoMessage() {}
 
 func (x *BoudingBox) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[4]
+	mi := &file_video_streaming_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,7 +765,7 @@ func (x *BoudingBox) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BoudingBox.ProtoReflect.Descriptor instead.
 func (*BoudingBox) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{4}
+	return file_video_streaming_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BoudingBox) GetTop() int32 {
@@ -569,7 +808,7 @@ type ShapeProto struct {
 func (x *ShapeProto) Reset() {
 	*x = ShapeProto{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[5]
+		mi := &file_video_streaming_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -582,7 +821,7 @@ func (x *ShapeProto) String() string {
 func (*ShapeProto) ProtoMessage() {}
 
 func (x *ShapeProto) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[5]
+	mi := &file_video_streaming_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -595,7 +834,7 @@ func (x *ShapeProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShapeProto.ProtoReflect.Descriptor instead.
 func (*ShapeProto) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{5}
+	return file_video_streaming_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ShapeProto) GetDim() []*ShapeProto_Dim {
@@ -632,7 +871,7 @@ type VideoFrame struct {
 func (x *VideoFrame) Reset() {
 	*x = VideoFrame{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[6]
+		mi := &file_video_streaming_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -645,7 +884,7 @@ func (x *VideoFrame) String() string {
 func (*VideoFrame) ProtoMessage() {}
 
 func (x *VideoFrame) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[6]
+	mi := &file_video_streaming_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -658,7 +897,7 @@ func (x *VideoFrame) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoFrame.ProtoReflect.Descriptor instead.
 func (*VideoFrame) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{6}
+	return file_video_streaming_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *VideoFrame) GetWidth() int64 {
@@ -792,7 +1031,7 @@ type VideoFrameRequest struct {
 func (x *VideoFrameRequest) Reset() {
 	*x = VideoFrameRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[7]
+		mi := &file_video_streaming_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -805,7 +1044,7 @@ func (x *VideoFrameRequest) String() string {
 func (*VideoFrameRequest) ProtoMessage() {}
 
 func (x *VideoFrameRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[7]
+	mi := &file_video_streaming_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -818,7 +1057,7 @@ func (x *VideoFrameRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoFrameRequest.ProtoReflect.Descriptor instead.
 func (*VideoFrameRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{7}
+	return file_video_streaming_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *VideoFrameRequest) GetKeyFrameOnly() bool {
@@ -848,7 +1087,7 @@ type VideoFrameBufferedRequest struct {
 func (x *VideoFrameBufferedRequest) Reset() {
 	*x = VideoFrameBufferedRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[8]
+		mi := &file_video_streaming_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -861,7 +1100,7 @@ func (x *VideoFrameBufferedRequest) String() string {
 func (*VideoFrameBufferedRequest) ProtoMessage() {}
 
 func (x *VideoFrameBufferedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[8]
+	mi := &file_video_streaming_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -874,7 +1113,7 @@ func (x *VideoFrameBufferedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoFrameBufferedRequest.ProtoReflect.Descriptor instead.
 func (*VideoFrameBufferedRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{8}
+	return file_video_streaming_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *VideoFrameBufferedRequest) GetDeviceId() string {
@@ -904,24 +1143,29 @@ type ListStream struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	FailingStreak int64  `protobuf:"varint,3,opt,name=failing_streak,json=failingStreak,proto3" json:"failing_streak,omitempty"`
-	HealthStatus  string `protobuf:"bytes,4,opt,name=health_status,json=healthStatus,proto3" json:"health_status,omitempty"`
-	Dead          bool   `protobuf:"varint,5,opt,name=dead,proto3" json:"dead,omitempty"`
-	ExitCode      int64  `protobuf:"varint,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
-	Pid           int32  `protobuf:"varint,7,opt,name=pid,proto3" json:"pid,omitempty"`
-	Running       bool   `protobuf:"varint,8,opt,name=running,proto3" json:"running,omitempty"`
-	Paused        bool   `protobuf:"varint,9,opt,name=paused,proto3" json:"paused,omitempty"`
-	Restarting    bool   `protobuf:"varint,10,opt,name=restarting,proto3" json:"restarting,omitempty"`
-	Oomkilled     bool   `protobuf:"varint,11,opt,name=oomkilled,proto3" json:"oomkilled,omitempty"`
-	Error         string `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	Name          string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string            `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	FailingStreak int64             `protobuf:"varint,3,opt,name=failing_streak,json=failingStreak,proto3" json:"failing_streak,omitempty"`
+	HealthStatus  string            `protobuf:"bytes,4,opt,name=health_status,json=healthStatus,proto3" json:"health_status,omitempty"`
+	Dead          bool              `protobuf:"varint,5,opt,name=dead,proto3" json:"dead,omitempty"`
+	ExitCode      int64             `protobuf:"varint,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Pid           int32             `protobuf:"varint,7,opt,name=pid,proto3" json:"pid,omitempty"`
+	Running       bool              `protobuf:"varint,8,opt,name=running,proto3" json:"running,omitempty"`
+	Paused        bool              `protobuf:"varint,9,opt,name=paused,proto3" json:"paused,omitempty"`
+	Restarting    bool              `protobuf:"varint,10,opt,name=restarting,proto3" json:"restarting,omitempty"`
+	Oomkilled     bool              `protobuf:"varint,11,opt,name=oomkilled,proto3" json:"oomkilled,omitempty"`
+	Error         string            `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	StreamHealth  string            `protobuf:"bytes,13,opt,name=stream_health,json=streamHealth,proto3" json:"stream_health,omitempty"` // ok, stalled or no-signal (empty if unknown)
+	Group         string            `protobuf:"bytes,14,opt,name=group,proto3" json:"group,omitempty"`                                   // group or site hierarchy (e.g. site-a/building-1/floor-2)
+	Labels        map[string]string `protobuf:"bytes,15,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Description   string            `protobuf:"bytes,16,opt,name=description,proto3" json:"description,omitempty"`
+	Location      *Location         `protobuf:"bytes,17,opt,name=location,proto3" json:"location,omitempty"` // camera location, heading and mounting height
 }
 
 func (x *ListStream) Reset() {
 	*x = ListStream{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[9]
+		mi := &file_video_streaming_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -934,7 +1178,7 @@ func (x *ListStream) String() string {
 func (*ListStream) ProtoMessage() {}
 
 func (x *ListStream) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[9]
+	mi := &file_video_streaming_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -947,7 +1191,7 @@ func (x *ListStream) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListStream.ProtoReflect.Descriptor instead.
 func (*ListStream) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{9}
+	return file_video_streaming_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListStream) GetName() string {
@@ -1034,16 +1278,54 @@ func (x *ListStream) GetError() string {
 	return ""
 }
 
+func (x *ListStream) GetStreamHealth() string {
+	if x != nil {
+		return x.StreamHealth
+	}
+	return ""
+}
+
+func (x *ListStream) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ListStream) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListStream) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ListStream) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
 type ListStreamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Group  string            `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`                                                                                           // optional: streams in the group (including sub-groups)
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // optional: streams with all of the labels (empty value matches any value)
 }
 
 func (x *ListStreamRequest) Reset() {
 	*x = ListStreamRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[10]
+		mi := &file_video_streaming_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1056,7 +1338,7 @@ func (x *ListStreamRequest) String() string {
 func (*ListStreamRequest) ProtoMessage() {}
 
 func (x *ListStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[10]
+	mi := &file_video_streaming_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1069,7 +1351,21 @@ func (x *ListStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListStreamRequest.ProtoReflect.Descriptor instead.
 func (*ListStreamRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{10}
+	return file_video_streaming_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListStreamRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ListStreamRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
 // Proxy messages
@@ -1085,7 +1381,7 @@ type ProxyRequest struct {
 func (x *ProxyRequest) Reset() {
 	*x = ProxyRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[11]
+		mi := &file_video_streaming_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1098,7 +1394,7 @@ func (x *ProxyRequest) String() string {
 func (*ProxyRequest) ProtoMessage() {}
 
 func (x *ProxyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[11]
+	mi := &file_video_streaming_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1111,7 +1407,7 @@ func (x *ProxyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProxyRequest.ProtoReflect.Descriptor instead.
 func (*ProxyRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{11}
+	return file_video_streaming_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ProxyRequest) GetDeviceId() string {
@@ -1140,7 +1436,7 @@ type ProxyResponse struct {
 func (x *ProxyResponse) Reset() {
 	*x = ProxyResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[12]
+		mi := &file_video_streaming_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1153,7 +1449,7 @@ func (x *ProxyResponse) String() string {
 func (*ProxyResponse) ProtoMessage() {}
 
 func (x *ProxyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[12]
+	mi := &file_video_streaming_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1166,7 +1462,7 @@ func (x *ProxyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProxyResponse.ProtoReflect.Descriptor instead.
 func (*ProxyResponse) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{12}
+	return file_video_streaming_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ProxyResponse) GetDeviceId() string {
@@ -1196,7 +1492,7 @@ type StorageRequest struct {
 func (x *StorageRequest) Reset() {
 	*x = StorageRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[13]
+		mi := &file_video_streaming_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1209,7 +1505,7 @@ func (x *StorageRequest) String() string {
 func (*StorageRequest) ProtoMessage() {}
 
 func (x *StorageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[13]
+	mi := &file_video_streaming_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1222,7 +1518,7 @@ func (x *StorageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StorageRequest.ProtoReflect.Descriptor instead.
 func (*StorageRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{13}
+	return file_video_streaming_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *StorageRequest) GetDeviceId() string {
@@ -1251,7 +1547,7 @@ type StorageResponse struct {
 func (x *StorageResponse) Reset() {
 	*x = StorageResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[14]
+		mi := &file_video_streaming_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1264,7 +1560,7 @@ func (x *StorageResponse) String() string {
 func (*StorageResponse) ProtoMessage() {}
 
 func (x *StorageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[14]
+	mi := &file_video_streaming_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1277,7 +1573,7 @@ func (x *StorageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StorageResponse.ProtoReflect.Descriptor instead.
 func (*StorageResponse) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{14}
+	return file_video_streaming_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *StorageResponse) GetDeviceId() string {
@@ -1312,7 +1608,7 @@ type VideoCodec struct {
 func (x *VideoCodec) Reset() {
 	*x = VideoCodec{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[15]
+		mi := &file_video_streaming_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1325,7 +1621,7 @@ func (x *VideoCodec) String() string {
 func (*VideoCodec) ProtoMessage() {}
 
 func (x *VideoCodec) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[15]
+	mi := &file_video_streaming_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1338,7 +1634,7 @@ func (x *VideoCodec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoCodec.ProtoReflect.Descriptor instead.
 func (*VideoCodec) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{15}
+	return file_video_streaming_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *VideoCodec) GetName() string {
@@ -1401,7 +1697,7 @@ type VideoProbeRequest struct {
 func (x *VideoProbeRequest) Reset() {
 	*x = VideoProbeRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[16]
+		mi := &file_video_streaming_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1414,7 +1710,7 @@ func (x *VideoProbeRequest) String() string {
 func (*VideoProbeRequest) ProtoMessage() {}
 
 func (x *VideoProbeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[16]
+	mi := &file_video_streaming_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1427,7 +1723,7 @@ func (x *VideoProbeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoProbeRequest.ProtoReflect.Descriptor instead.
 func (*VideoProbeRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{16}
+	return file_video_streaming_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *VideoProbeRequest) GetDeviceId() string {
@@ -1449,7 +1745,7 @@ type VideoProbeResponse struct {
 func (x *VideoProbeResponse) Reset() {
 	*x = VideoProbeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[17]
+		mi := &file_video_streaming_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1462,7 +1758,7 @@ func (x *VideoProbeResponse) String() string {
 func (*VideoProbeResponse) ProtoMessage() {}
 
 func (x *VideoProbeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[17]
+	mi := &file_video_streaming_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1475,7 +1771,7 @@ func (x *VideoProbeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoProbeResponse.ProtoReflect.Descriptor instead.
 func (*VideoProbeResponse) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{17}
+	return file_video_streaming_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *VideoProbeResponse) GetVideoCodec() *VideoCodec {
@@ -1507,7 +1803,7 @@ type VideoBuffer struct {
 func (x *VideoBuffer) Reset() {
 	*x = VideoBuffer{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[18]
+		mi := &file_video_streaming_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1520,7 +1816,7 @@ func (x *VideoBuffer) String() string {
 func (*VideoBuffer) ProtoMessage() {}
 
 func (x *VideoBuffer) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[18]
+	mi := &file_video_streaming_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1533,7 +1829,7 @@ func (x *VideoBuffer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VideoBuffer.ProtoReflect.Descriptor instead.
 func (*VideoBuffer) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{18}
+	return file_video_streaming_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *VideoBuffer) GetStartTime() int64 {
@@ -1571,6 +1867,482 @@ func (x *VideoBuffer) GetFrames() int64 {
 	return 0
 }
 
+// Signature search messages
+type SearchSignaturesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vector        []float64 `protobuf:"fixed64,1,rep,packed,name=vector,proto3" json:"vector,omitempty"`                            // required: object signature to search for
+	K             int32     `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`                                              // optional: number of nearest annotations to return (default 10)
+	DeviceName    string    `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`           // optional: filter by device
+	ObjectType    string    `protobuf:"bytes,4,opt,name=object_type,json=objectType,proto3" json:"object_type,omitempty"`           // optional: filter by object type
+	TimestampFrom int64     `protobuf:"varint,5,opt,name=timestamp_from,json=timestampFrom,proto3" json:"timestamp_from,omitempty"` // optional: annotations starting at or after (ms)
+	TimestampTo   int64     `protobuf:"varint,6,opt,name=timestamp_to,json=timestampTo,proto3" json:"timestamp_to,omitempty"`       // optional: annotations starting at or before (ms)
+	Metric        string    `protobuf:"bytes,7,opt,name=metric,proto3" json:"metric,omitempty"`                                     // optional: cosine (default) or l2
+}
+
+func (x *SearchSignaturesRequest) Reset() {
+	*x = SearchSignaturesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchSignaturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSignaturesRequest) ProtoMessage() {}
+
+func (x *SearchSignaturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSignaturesRequest.ProtoReflect.Descriptor instead.
+func (*SearchSignaturesRequest) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SearchSignaturesRequest) GetVector() []float64 {
+	if x != nil {
+		return x.Vector
+	}
+	return nil
+}
+
+func (x *SearchSignaturesRequest) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *SearchSignaturesRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *SearchSignaturesRequest) GetObjectType() string {
+	if x != nil {
+		return x.ObjectType
+	}
+	return ""
+}
+
+func (x *SearchSignaturesRequest) GetTimestampFrom() int64 {
+	if x != nil {
+		return x.TimestampFrom
+	}
+	return 0
+}
+
+func (x *SearchSignaturesRequest) GetTimestampTo() int64 {
+	if x != nil {
+		return x.TimestampTo
+	}
+	return 0
+}
+
+func (x *SearchSignaturesRequest) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+type SignatureMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Distance   float64          `protobuf:"fixed64,1,opt,name=distance,proto3" json:"distance,omitempty"`
+	Annotation *AnnotateRequest `protobuf:"bytes,2,opt,name=annotation,proto3" json:"annotation,omitempty"`
+}
+
+func (x *SignatureMatch) Reset() {
+	*x = SignatureMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureMatch) ProtoMessage() {}
+
+func (x *SignatureMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureMatch.ProtoReflect.Descriptor instead.
+func (*SignatureMatch) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SignatureMatch) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *SignatureMatch) GetAnnotation() *AnnotateRequest {
+	if x != nil {
+		return x.Annotation
+	}
+	return nil
+}
+
+type SearchSignaturesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches []*SignatureMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+}
+
+func (x *SearchSignaturesResponse) Reset() {
+	*x = SearchSignaturesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchSignaturesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSignaturesResponse) ProtoMessage() {}
+
+func (x *SearchSignaturesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSignaturesResponse.ProtoReflect.Descriptor instead.
+func (*SearchSignaturesResponse) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchSignaturesResponse) GetMatches() []*SignatureMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+// Debug overlay messages
+type DebugOverlayRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceId    string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`           // required: device (camera) name
+	Timestamp   int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                        // optional: frame timestamp (ms), default is now
+	ToleranceMs int64  `protobuf:"varint,3,opt,name=tolerance_ms,json=toleranceMs,proto3" json:"tolerance_ms,omitempty"` // optional: render annotations within +/- tolerance of the frame timestamp (default 500)
+}
+
+func (x *DebugOverlayRequest) Reset() {
+	*x = DebugOverlayRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebugOverlayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugOverlayRequest) ProtoMessage() {}
+
+func (x *DebugOverlayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugOverlayRequest.ProtoReflect.Descriptor instead.
+func (*DebugOverlayRequest) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DebugOverlayRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DebugOverlayRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DebugOverlayRequest) GetToleranceMs() int64 {
+	if x != nil {
+		return x.ToleranceMs
+	}
+	return 0
+}
+
+type DebugOverlayResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image          []byte             `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`                                          // JPEG encoded frame with annotations drawn on it
+	FrameTimestamp int64              `protobuf:"varint,2,opt,name=frame_timestamp,json=frameTimestamp,proto3" json:"frame_timestamp,omitempty"` // timestamp (ms) of the returned frame
+	Width          int64              `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height         int64              `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Annotations    []*AnnotateRequest `protobuf:"bytes,5,rep,name=annotations,proto3" json:"annotations,omitempty"` // annotations drawn on the frame
+}
+
+func (x *DebugOverlayResponse) Reset() {
+	*x = DebugOverlayResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebugOverlayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugOverlayResponse) ProtoMessage() {}
+
+func (x *DebugOverlayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugOverlayResponse.ProtoReflect.Descriptor instead.
+func (*DebugOverlayResponse) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DebugOverlayResponse) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *DebugOverlayResponse) GetFrameTimestamp() int64 {
+	if x != nil {
+		return x.FrameTimestamp
+	}
+	return 0
+}
+
+func (x *DebugOverlayResponse) GetWidth() int64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *DebugOverlayResponse) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *DebugOverlayResponse) GetAnnotations() []*AnnotateRequest {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+// AnnotateStream messages
+type AnnotateRejection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sequence   int64  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // sequence number of the rejected annotation
+	DeviceName string `protobuf:"bytes,2,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	Code       int32  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`      // grpc status code
+	Message    string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"` // reason for rejection
+}
+
+func (x *AnnotateRejection) Reset() {
+	*x = AnnotateRejection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnnotateRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnotateRejection) ProtoMessage() {}
+
+func (x *AnnotateRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnotateRejection.ProtoReflect.Descriptor instead.
+func (*AnnotateRejection) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AnnotateRejection) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AnnotateRejection) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *AnnotateRejection) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AnnotateRejection) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type AnnotateAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sequence   int64                `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`    // cumulative: all annotations up to and including this sequence number are processed
+	Accepted   int32                `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`    // number of annotations queued since the previous ack
+	Rejections []*AnnotateRejection `protobuf:"bytes,3,rep,name=rejections,proto3" json:"rejections,omitempty"` // annotations rejected since the previous ack
+}
+
+func (x *AnnotateAck) Reset() {
+	*x = AnnotateAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_video_streaming_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnnotateAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnotateAck) ProtoMessage() {}
+
+func (x *AnnotateAck) ProtoReflect() protoreflect.Message {
+	mi := &file_video_streaming_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnotateAck.ProtoReflect.Descriptor instead.
+func (*AnnotateAck) Descriptor() ([]byte, []int) {
+	return file_video_streaming_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AnnotateAck) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AnnotateAck) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *AnnotateAck) GetRejections() []*AnnotateRejection {
+	if x != nil {
+		return x.Rejections
+	}
+	return nil
+}
+
 type SystemTimeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1582,7 +2354,7 @@ type SystemTimeResponse struct {
 func (x *SystemTimeResponse) Reset() {
 	*x = SystemTimeResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[19]
+		mi := &file_video_streaming_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1595,7 +2367,7 @@ func (x *SystemTimeResponse) String() string {
 func (*SystemTimeResponse) ProtoMessage() {}
 
 func (x *SystemTimeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[19]
+	mi := &file_video_streaming_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1608,7 +2380,7 @@ func (x *SystemTimeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SystemTimeResponse.ProtoReflect.Descriptor instead.
 func (*SystemTimeResponse) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{19}
+	return file_video_streaming_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *SystemTimeResponse) GetCurrentTime() int64 {
@@ -1627,7 +2399,7 @@ type SystemTimeRequest struct {
 func (x *SystemTimeRequest) Reset() {
 	*x = SystemTimeRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[20]
+		mi := &file_video_streaming_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1640,7 +2412,7 @@ func (x *SystemTimeRequest) String() string {
 func (*SystemTimeRequest) ProtoMessage() {}
 
 func (x *SystemTimeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[20]
+	mi := &file_video_streaming_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1653,7 +2425,7 @@ func (x *SystemTimeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SystemTimeRequest.ProtoReflect.Descriptor instead.
 func (*SystemTimeRequest) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{20}
+	return file_video_streaming_proto_rawDescGZIP(), []int{29}
 }
 
 type ShapeProto_Dim struct {
@@ -1670,7 +2442,7 @@ type ShapeProto_Dim struct {
 func (x *ShapeProto_Dim) Reset() {
 	*x = ShapeProto_Dim{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_video_streaming_proto_msgTypes[21]
+		mi := &file_video_streaming_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1683,7 +2455,7 @@ func (x *ShapeProto_Dim) String() string {
 func (*ShapeProto_Dim) ProtoMessage() {}
 
 func (x *ShapeProto_Dim) ProtoReflect() protoreflect.Message {
-	mi := &file_video_streaming_proto_msgTypes[21]
+	mi := &file_video_streaming_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1696,7 +2468,7 @@ func (x *ShapeProto_Dim) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShapeProto_Dim.ProtoReflect.Descriptor instead.
 func (*ShapeProto_Dim) Descriptor() ([]byte, []int) {
-	return file_video_streaming_proto_rawDescGZIP(), []int{5, 0}
+	return file_video_streaming_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *ShapeProto_Dim) GetSize() int64 {
@@ -1719,7 +2491,7 @@ var file_video_streaming_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
 	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x22, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63,
 	0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x22, 0xcb, 0x09, 0x0a, 0x0f,
+	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x22, 0x91, 0x0d, 0x0a, 0x0f,
 	0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65,
@@ -1796,168 +2568,346 @@ var file_video_streaming_proto_rawDesc = []byte{
 	0x5f, 0x34, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d,
 	0x4d, 0x65, 0x74, 0x61, 0x34, 0x12, 0x22, 0x0a, 0x0d, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f,
 	0x6d, 0x65, 0x74, 0x61, 0x5f, 0x35, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x75,
-	0x73, 0x74, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x61, 0x35, 0x22, 0x9a, 0x01, 0x0a, 0x10, 0x41, 0x6e,
-	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f,
-	0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
-	0x28, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x27, 0x0a,
-	0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x2e, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x03, 0x6c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x03, 0x6c, 0x6f, 0x6e, 0x22, 0x36, 0x0a, 0x0a, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69,
-	0x6e, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79,
-	0x12, 0x0c, 0x0a, 0x01, 0x7a, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x7a, 0x22, 0x60,
-	0x0a, 0x0a, 0x42, 0x6f, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x6f, 0x78, 0x12, 0x10, 0x0a, 0x03,
-	0x74, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x74, 0x6f, 0x70, 0x12, 0x12,
-	0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x65,
-	0x66, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
-	0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
-	0x22, 0x81, 0x01, 0x0a, 0x0a, 0x53, 0x68, 0x61, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x44, 0x0a, 0x03, 0x64, 0x69, 0x6d, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63,
+	0x73, 0x74, 0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x61, 0x35, 0x12, 0x4f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x1e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e,
+	0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64,
+	0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65,
+	0x74, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x0a,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73,
+	0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x20, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x63, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18,
+	0x21, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69,
+	0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x4c, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x18, 0x22, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x44, 0x65, 0x74,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x07, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x73, 0x12, 0x4a, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x23,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x1a, 0x3d, 0x0a, 0x0f, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xb5, 0x05, 0x0a, 0x0e, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64,
+	0x12, 0x2c, 0x0a, 0x12, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x69, 0x6e, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x49, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x5c,
+	0x0a, 0x12, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6f, 0x75, 0x64, 0x69, 0x6e, 0x67,
+	0x5f, 0x62, 0x6f, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72,
+	0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e,
+	0x42, 0x6f, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x6f, 0x78, 0x52, 0x10, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x42, 0x6f, 0x75, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x6f, 0x78, 0x12, 0x5b, 0x0a, 0x11,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x10, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x43,
+	0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x42, 0x0a, 0x04, 0x6d, 0x61, 0x73,
+	0x6b, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x12, 0x29, 0x0a,
+	0x10, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x08, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x62, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x63,
 	0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f,
 	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61,
-	0x31, 0x2e, 0x53, 0x68, 0x61, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x6d,
-	0x52, 0x03, 0x64, 0x69, 0x6d, 0x1a, 0x2d, 0x0a, 0x03, 0x44, 0x69, 0x6d, 0x12, 0x12, 0x0a, 0x04,
-	0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x22, 0xf9, 0x03, 0x0a, 0x0a, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x46, 0x72,
-	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61,
-	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x73, 0x4b, 0x65, 0x79, 0x66,
-	0x72, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x03, 0x70, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x74, 0x73, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x03, 0x64, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x61, 0x6d,
-	0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72,
-	0x61, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x63, 0x6f,
-	0x72, 0x72, 0x75, 0x70, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x43,
-	0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x62,
-	0x61, 0x73, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x42,
-	0x61, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
-	0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e,
-	0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x68, 0x61, 0x70, 0x65, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x52, 0x05, 0x73, 0x68, 0x61, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
-	0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x1a,
-	0x0a, 0x08, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x08, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78,
-	0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65,
-	0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x64, 0x65,
-	0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f,
-	0x64, 0x65, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x69, 0x78, 0x5f, 0x66,
-	0x6d, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x69, 0x78, 0x46, 0x6d, 0x74,
-	0x22, 0x56, 0x0a, 0x11, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x72, 0x61,
-	0x6d, 0x65, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6b,
-	0x65, 0x79, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x64,
-	0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0x82, 0x01, 0x0a, 0x19, 0x56, 0x69, 0x64,
-	0x65, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63,
-	0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x54, 0x6f, 0x22, 0xcd, 0x02,
-	0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c,
-	0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x12,
-	0x23, 0x0a, 0x0d, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x04, 0x64, 0x65, 0x61, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x69, 0x74,
-	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x69,
-	0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69,
-	0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e,
-	0x67, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73,
-	0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72,
-	0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x6f, 0x6d,
-	0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x6f,
-	0x6d, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x13, 0x0a,
-	0x11, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0x4d, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12,
-	0x20, 0x0a, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67,
-	0x68, 0x22, 0x4e, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12,
-	0x20, 0x0a, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67,
-	0x68, 0x22, 0x43, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64,
-	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0x44, 0x0a, 0x0f, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0xc9, 0x01, 0x0a,
-	0x0a, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
-	0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x17, 0x0a,
-	0x07, 0x70, 0x69, 0x78, 0x5f, 0x66, 0x6d, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x70, 0x69, 0x78, 0x46, 0x6d, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61,
-	0x64, 0x61, 0x74, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x65, 0x78,
-	0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c,
-	0x6f, 0x6e, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x6c, 0x6f, 0x6e, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x30, 0x0a, 0x11, 0x56, 0x69, 0x64, 0x65,
-	0x6f, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x31, 0x2e, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x06,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63,
+	0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61,
+	0x31, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x41, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x46, 0x0a, 0x0e, 0x43, 0x6c, 0x61, 0x73, 0x73,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x22,
+	0x9a, 0x01, 0x0a, 0x10, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x60, 0x0a, 0x08,
+	0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x61, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x68,
+	0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x36,
+	0x0a, 0x0a, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x0a, 0x01,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x7a, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x01, 0x7a, 0x22, 0x60, 0x0a, 0x0a, 0x42, 0x6f, 0x75, 0x64, 0x69, 0x6e,
+	0x67, 0x42, 0x6f, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x03, 0x74, 0x6f, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69,
+	0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x81, 0x01, 0x0a, 0x0a, 0x53, 0x68, 0x61,
+	0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x44, 0x0a, 0x03, 0x64, 0x69, 0x6d, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x68, 0x61, 0x70, 0x65, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x6d, 0x52, 0x03, 0x64, 0x69, 0x6d, 0x1a, 0x2d, 0x0a,
+	0x03, 0x44, 0x69, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xf9, 0x03, 0x0a,
+	0x0a, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x69,
+	0x73, 0x5f, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x69, 0x73, 0x4b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x70, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x70, 0x74, 0x73, 0x12, 0x10,
+	0x0a, 0x03, 0x64, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x64, 0x74, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x63, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x43, 0x6f, 0x72, 0x72, 0x75, 0x70, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x61, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x05, 0x73,
+	0x68, 0x61, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72,
+	0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e,
+	0x53, 0x68, 0x61, 0x70, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x05, 0x73, 0x68, 0x61, 0x70,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61,
+	0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61,
+	0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x70, 0x69, 0x78, 0x5f, 0x66, 0x6d, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x69, 0x78, 0x46, 0x6d, 0x74, 0x22, 0x56, 0x0a, 0x11, 0x56, 0x69, 0x64, 0x65,
+	0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a,
+	0x0e, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6b, 0x65, 0x79, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x4f,
+	0x6e, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64,
+	0x22, 0x82, 0x01, 0x0a, 0x19, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x42,
+	0x75, 0x66, 0x66, 0x65, 0x72, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f,
+	0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x54, 0x6f, 0x22, 0x83, 0x05, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x25, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x69, 0x6e,
+	0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6b, 0x12, 0x23, 0x0a, 0x0d, 0x68, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x65, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x65, 0x61, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x70, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x75,
+	0x73, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65,
+	0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e,
+	0x67, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x6f, 0x6d, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x6f, 0x6d, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x12, 0x52, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3a, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76,
+	0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31,
+	0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x48, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x4c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xbf, 0x01, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x59, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x4d, 0x0a,
+	0x0c, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
 	0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0xae, 0x01, 0x0a, 0x12, 0x56,
-	0x69, 0x64, 0x65, 0x6f, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x4f, 0x0a, 0x0b, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x63,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63,
-	0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x56, 0x69, 0x64, 0x65,
-	0x6f, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x52, 0x0a, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x43, 0x6f, 0x64,
-	0x65, 0x63, 0x12, 0x47, 0x0a, 0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64,
-	0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e,
-	0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x42, 0x75, 0x66,
-	0x66, 0x65, 0x72, 0x52, 0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x22, 0xb3, 0x01, 0x0a, 0x0b,
-	0x56, 0x69, 0x64, 0x65, 0x6f, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e,
-	0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x6e,
-	0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
-	0x12, 0x27, 0x0a, 0x0f, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x5f,
-	0x66, 0x70, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x61, 0x70, 0x70, 0x72, 0x6f,
-	0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x46, 0x70, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x61,
-	0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61,
+	0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x22, 0x4e, 0x0a, 0x0d,
+	0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61,
+	0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x22, 0x43, 0x0a, 0x0e,
+	0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x22, 0x44, 0x0a, 0x0f, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0xc9, 0x01, 0x0a, 0x0a, 0x56, 0x69, 0x64, 0x65,
+	0x6f, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69,
+	0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x69, 0x78, 0x5f,
+	0x66, 0x6d, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x69, 0x78, 0x46, 0x6d,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x25, 0x0a, 0x0e, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x65, 0x78, 0x74, 0x72, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x6e, 0x67, 0x4e,
+	0x61, 0x6d, 0x65, 0x22, 0x30, 0x0a, 0x11, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0xae, 0x01, 0x0a, 0x12, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x50,
+	0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0b,
+	0x76, 0x69, 0x64, 0x65, 0x6f, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2e, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e,
+	0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76,
+	0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x43, 0x6f, 0x64, 0x65,
+	0x63, 0x52, 0x0a, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x12, 0x47, 0x0a,
+	0x06, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65,
+	0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74,
+	0x61, 0x31, 0x2e, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x52, 0x06,
+	0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x22, 0xb3, 0x01, 0x0a, 0x0b, 0x56, 0x69, 0x64, 0x65, 0x6f,
+	0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x29, 0x0a, 0x10, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x61,
+	0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x70, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74,
+	0x65, 0x46, 0x70, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x22, 0xe3, 0x01, 0x0a,
+	0x17, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x01, 0x52, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x12, 0x0c, 0x0a, 0x01, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6b, 0x12, 0x1f,
+	0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x25, 0x0a, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x66, 0x72,
+	0x6f, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x5f, 0x74, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x54, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x22, 0x81, 0x01, 0x0a, 0x0e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x53, 0x0a, 0x0a, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c,
+	0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69,
+	0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x68, 0x0a, 0x18, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4c, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67,
+	0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
+	0x22, 0x73, 0x0a, 0x13, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61,
+	0x6e, 0x63, 0x65, 0x4d, 0x73, 0x22, 0xda, 0x01, 0x0a, 0x14, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4f,
+	0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x66,
+	0x72, 0x61, 0x6d, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a,
+	0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x77, 0x69,
+	0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x55, 0x0a, 0x0b, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x33, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76,
+	0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31,
+	0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x7e, 0x0a, 0x11, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x0b, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x41,
+	0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x55, 0x0a, 0x0a, 0x72, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x35,
+	0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64,
+	0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65,
+	0x74, 0x61, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
 	0x73, 0x22, 0x37, 0x0a, 0x12, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x52,
 	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75, 0x72, 0x72, 0x65,
 	0x6e, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63,
 	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x53, 0x79,
 	0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x32,
-	0xe5, 0x07, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x7b, 0x0a, 0x10, 0x56, 0x69, 0x64,
+	0xfb, 0x0a, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x7b, 0x0a, 0x10, 0x56, 0x69, 0x64,
 	0x65, 0x6f, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x35, 0x2e,
 	0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65,
 	0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74,
@@ -1997,29 +2947,55 @@ var file_video_streaming_proto_rawDesc = []byte{
 	0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74,
 	0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e,
 	0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x6e, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x30, 0x2e, 0x63, 0x68,
+	0x22, 0x00, 0x12, 0x7c, 0x0a, 0x0e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x33, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x63, 0x68, 0x72, 0x79,
+	0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x41,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x41, 0x63, 0x6b, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01,
+	0x12, 0x6e, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x30, 0x2e, 0x63, 0x68, 0x72, 0x79,
+	0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x63, 0x68,
 	0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73,
 	0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31,
-	0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e,
-	0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65,
-	0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74,
-	0x61, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x74, 0x0a, 0x07, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x32, 0x2e,
-	0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65,
-	0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74,
-	0x61, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x33, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e,
+	0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x74, 0x0a, 0x07, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x32, 0x2e, 0x63, 0x68,
+	0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31,
+	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x33, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69,
+	0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62,
+	0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x7d, 0x0a, 0x0a, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f,
+	0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e, 0x63, 0x68,
+	0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31,
+	0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x8f, 0x01, 0x0a, 0x10, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x3b, 0x2e, 0x63, 0x68, 0x72,
+	0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e,
+	0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x83, 0x01, 0x0a, 0x0c, 0x44, 0x65, 0x62, 0x75,
+	0x67, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x12, 0x37, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73,
+	0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x44, 0x65,
+	0x62, 0x75, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x38, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e,
 	0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76,
-	0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x7d, 0x0a, 0x0a, 0x53, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x2e, 0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63,
-	0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e,
-	0x63, 0x68, 0x72, 0x79, 0x73, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2e, 0x76, 0x69, 0x64, 0x65,
-	0x6f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x62, 0x65, 0x74,
-	0x61, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x31, 0x62, 0x65, 0x74, 0x61, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4f, 0x76, 0x65, 0x72,
+	0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2034,61 +3010,96 @@ func file_video_streaming_proto_rawDescGZIP() []byte {
 	return file_video_streaming_proto_rawDescData
 }
 
-var file_video_streaming_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_video_streaming_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_video_streaming_proto_goTypes = []interface{}{
 	(*AnnotateRequest)(nil),           // 0: chrys.cloud.videostreaming.v1beta1.AnnotateRequest
-	(*AnnotateResponse)(nil),          // 1: chrys.cloud.videostreaming.v1beta1.AnnotateResponse
-	(*Location)(nil),                  // 2: chrys.cloud.videostreaming.v1beta1.Location
-	(*Coordinate)(nil),                // 3: chrys.cloud.videostreaming.v1beta1.Coordinate
-	(*BoudingBox)(nil),                // 4: chrys.cloud.videostreaming.v1beta1.BoudingBox
-	(*ShapeProto)(nil),                // 5: chrys.cloud.videostreaming.v1beta1.ShapeProto
-	(*VideoFrame)(nil),                // 6: chrys.cloud.videostreaming.v1beta1.VideoFrame
-	(*VideoFrameRequest)(nil),         // 7: chrys.cloud.videostreaming.v1beta1.VideoFrameRequest
-	(*VideoFrameBufferedRequest)(nil), // 8: chrys.cloud.videostreaming.v1beta1.VideoFrameBufferedRequest
-	(*ListStream)(nil),                // 9: chrys.cloud.videostreaming.v1beta1.ListStream
-	(*ListStreamRequest)(nil),         // 10: chrys.cloud.videostreaming.v1beta1.ListStreamRequest
-	(*ProxyRequest)(nil),              // 11: chrys.cloud.videostreaming.v1beta1.ProxyRequest
-	(*ProxyResponse)(nil),             // 12: chrys.cloud.videostreaming.v1beta1.ProxyResponse
-	(*StorageRequest)(nil),            // 13: chrys.cloud.videostreaming.v1beta1.StorageRequest
-	(*StorageResponse)(nil),           // 14: chrys.cloud.videostreaming.v1beta1.StorageResponse
-	(*VideoCodec)(nil),                // 15: chrys.cloud.videostreaming.v1beta1.VideoCodec
-	(*VideoProbeRequest)(nil),         // 16: chrys.cloud.videostreaming.v1beta1.VideoProbeRequest
-	(*VideoProbeResponse)(nil),        // 17: chrys.cloud.videostreaming.v1beta1.VideoProbeResponse
-	(*VideoBuffer)(nil),               // 18: chrys.cloud.videostreaming.v1beta1.VideoBuffer
-	(*SystemTimeResponse)(nil),        // 19: chrys.cloud.videostreaming.v1beta1.SystemTimeResponse
-	(*SystemTimeRequest)(nil),         // 20: chrys.cloud.videostreaming.v1beta1.SystemTimeRequest
-	(*ShapeProto_Dim)(nil),            // 21: chrys.cloud.videostreaming.v1beta1.ShapeProto.Dim
+	(*DetectedObject)(nil),            // 1: chrys.cloud.videostreaming.v1beta1.DetectedObject
+	(*Classification)(nil),            // 2: chrys.cloud.videostreaming.v1beta1.Classification
+	(*AnnotateResponse)(nil),          // 3: chrys.cloud.videostreaming.v1beta1.AnnotateResponse
+	(*Location)(nil),                  // 4: chrys.cloud.videostreaming.v1beta1.Location
+	(*Coordinate)(nil),                // 5: chrys.cloud.videostreaming.v1beta1.Coordinate
+	(*BoudingBox)(nil),                // 6: chrys.cloud.videostreaming.v1beta1.BoudingBox
+	(*ShapeProto)(nil),                // 7: chrys.cloud.videostreaming.v1beta1.ShapeProto
+	(*VideoFrame)(nil),                // 8: chrys.cloud.videostreaming.v1beta1.VideoFrame
+	(*VideoFrameRequest)(nil),         // 9: chrys.cloud.videostreaming.v1beta1.VideoFrameRequest
+	(*VideoFrameBufferedRequest)(nil), // 10: chrys.cloud.videostreaming.v1beta1.VideoFrameBufferedRequest
+	(*ListStream)(nil),                // 11: chrys.cloud.videostreaming.v1beta1.ListStream
+	(*ListStreamRequest)(nil),         // 12: chrys.cloud.videostreaming.v1beta1.ListStreamRequest
+	(*ProxyRequest)(nil),              // 13: chrys.cloud.videostreaming.v1beta1.ProxyRequest
+	(*ProxyResponse)(nil),             // 14: chrys.cloud.videostreaming.v1beta1.ProxyResponse
+	(*StorageRequest)(nil),            // 15: chrys.cloud.videostreaming.v1beta1.StorageRequest
+	(*StorageResponse)(nil),           // 16: chrys.cloud.videostreaming.v1beta1.StorageResponse
+	(*VideoCodec)(nil),                // 17: chrys.cloud.videostreaming.v1beta1.VideoCodec
+	(*VideoProbeRequest)(nil),         // 18: chrys.cloud.videostreaming.v1beta1.VideoProbeRequest
+	(*VideoProbeResponse)(nil),        // 19: chrys.cloud.videostreaming.v1beta1.VideoProbeResponse
+	(*VideoBuffer)(nil),               // 20: chrys.cloud.videostreaming.v1beta1.VideoBuffer
+	(*SearchSignaturesRequest)(nil),   // 21: chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest
+	(*SignatureMatch)(nil),            // 22: chrys.cloud.videostreaming.v1beta1.SignatureMatch
+	(*SearchSignaturesResponse)(nil),  // 23: chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse
+	(*DebugOverlayRequest)(nil),       // 24: chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest
+	(*DebugOverlayResponse)(nil),      // 25: chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse
+	(*AnnotateRejection)(nil),         // 26: chrys.cloud.videostreaming.v1beta1.AnnotateRejection
+	(*AnnotateAck)(nil),               // 27: chrys.cloud.videostreaming.v1beta1.AnnotateAck
+	(*SystemTimeResponse)(nil),        // 28: chrys.cloud.videostreaming.v1beta1.SystemTimeResponse
+	(*SystemTimeRequest)(nil),         // 29: chrys.cloud.videostreaming.v1beta1.SystemTimeRequest
+	nil,                               // 30: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry
+	nil,                               // 31: chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry
+	(*ShapeProto_Dim)(nil),            // 32: chrys.cloud.videostreaming.v1beta1.ShapeProto.Dim
+	nil,                               // 33: chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry
+	nil,                               // 34: chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry
 }
 var file_video_streaming_proto_depIdxs = []int32{
-	4,  // 0: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.object_bouding_box:type_name -> chrys.cloud.videostreaming.v1beta1.BoudingBox
-	2,  // 1: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.location:type_name -> chrys.cloud.videostreaming.v1beta1.Location
-	3,  // 2: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.object_coordinate:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
-	3,  // 3: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.mask:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
-	21, // 4: chrys.cloud.videostreaming.v1beta1.ShapeProto.dim:type_name -> chrys.cloud.videostreaming.v1beta1.ShapeProto.Dim
-	5,  // 5: chrys.cloud.videostreaming.v1beta1.VideoFrame.shape:type_name -> chrys.cloud.videostreaming.v1beta1.ShapeProto
-	15, // 6: chrys.cloud.videostreaming.v1beta1.VideoProbeResponse.video_codec:type_name -> chrys.cloud.videostreaming.v1beta1.VideoCodec
-	18, // 7: chrys.cloud.videostreaming.v1beta1.VideoProbeResponse.buffer:type_name -> chrys.cloud.videostreaming.v1beta1.VideoBuffer
-	7,  // 8: chrys.cloud.videostreaming.v1beta1.Image.VideoLatestImage:input_type -> chrys.cloud.videostreaming.v1beta1.VideoFrameRequest
-	8,  // 9: chrys.cloud.videostreaming.v1beta1.Image.VideoBufferedImage:input_type -> chrys.cloud.videostreaming.v1beta1.VideoFrameBufferedRequest
-	16, // 10: chrys.cloud.videostreaming.v1beta1.Image.VideoProbe:input_type -> chrys.cloud.videostreaming.v1beta1.VideoProbeRequest
-	10, // 11: chrys.cloud.videostreaming.v1beta1.Image.ListStreams:input_type -> chrys.cloud.videostreaming.v1beta1.ListStreamRequest
-	0,  // 12: chrys.cloud.videostreaming.v1beta1.Image.Annotate:input_type -> chrys.cloud.videostreaming.v1beta1.AnnotateRequest
-	11, // 13: chrys.cloud.videostreaming.v1beta1.Image.Proxy:input_type -> chrys.cloud.videostreaming.v1beta1.ProxyRequest
-	13, // 14: chrys.cloud.videostreaming.v1beta1.Image.Storage:input_type -> chrys.cloud.videostreaming.v1beta1.StorageRequest
-	20, // 15: chrys.cloud.videostreaming.v1beta1.Image.SystemTime:input_type -> chrys.cloud.videostreaming.v1beta1.SystemTimeRequest
-	6,  // 16: chrys.cloud.videostreaming.v1beta1.Image.VideoLatestImage:output_type -> chrys.cloud.videostreaming.v1beta1.VideoFrame
-	6,  // 17: chrys.cloud.videostreaming.v1beta1.Image.VideoBufferedImage:output_type -> chrys.cloud.videostreaming.v1beta1.VideoFrame
-	17, // 18: chrys.cloud.videostreaming.v1beta1.Image.VideoProbe:output_type -> chrys.cloud.videostreaming.v1beta1.VideoProbeResponse
-	9,  // 19: chrys.cloud.videostreaming.v1beta1.Image.ListStreams:output_type -> chrys.cloud.videostreaming.v1beta1.ListStream
-	1,  // 20: chrys.cloud.videostreaming.v1beta1.Image.Annotate:output_type -> chrys.cloud.videostreaming.v1beta1.AnnotateResponse
-	12, // 21: chrys.cloud.videostreaming.v1beta1.Image.Proxy:output_type -> chrys.cloud.videostreaming.v1beta1.ProxyResponse
-	14, // 22: chrys.cloud.videostreaming.v1beta1.Image.Storage:output_type -> chrys.cloud.videostreaming.v1beta1.StorageResponse
-	19, // 23: chrys.cloud.videostreaming.v1beta1.Image.SystemTime:output_type -> chrys.cloud.videostreaming.v1beta1.SystemTimeResponse
-	16, // [16:24] is the sub-list for method output_type
-	8,  // [8:16] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	6,  // 0: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.object_bouding_box:type_name -> chrys.cloud.videostreaming.v1beta1.BoudingBox
+	4,  // 1: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.location:type_name -> chrys.cloud.videostreaming.v1beta1.Location
+	5,  // 2: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.object_coordinate:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
+	5,  // 3: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.mask:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
+	5,  // 4: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.object_path:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
+	30, // 5: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.attributes:type_name -> chrys.cloud.videostreaming.v1beta1.AnnotateRequest.AttributesEntry
+	1,  // 6: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.objects:type_name -> chrys.cloud.videostreaming.v1beta1.DetectedObject
+	2,  // 7: chrys.cloud.videostreaming.v1beta1.AnnotateRequest.labels:type_name -> chrys.cloud.videostreaming.v1beta1.Classification
+	6,  // 8: chrys.cloud.videostreaming.v1beta1.DetectedObject.object_bouding_box:type_name -> chrys.cloud.videostreaming.v1beta1.BoudingBox
+	5,  // 9: chrys.cloud.videostreaming.v1beta1.DetectedObject.object_coordinate:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
+	5,  // 10: chrys.cloud.videostreaming.v1beta1.DetectedObject.mask:type_name -> chrys.cloud.videostreaming.v1beta1.Coordinate
+	31, // 11: chrys.cloud.videostreaming.v1beta1.DetectedObject.attributes:type_name -> chrys.cloud.videostreaming.v1beta1.DetectedObject.AttributesEntry
+	2,  // 12: chrys.cloud.videostreaming.v1beta1.DetectedObject.labels:type_name -> chrys.cloud.videostreaming.v1beta1.Classification
+	32, // 13: chrys.cloud.videostreaming.v1beta1.ShapeProto.dim:type_name -> chrys.cloud.videostreaming.v1beta1.ShapeProto.Dim
+	7,  // 14: chrys.cloud.videostreaming.v1beta1.VideoFrame.shape:type_name -> chrys.cloud.videostreaming.v1beta1.ShapeProto
+	33, // 15: chrys.cloud.videostreaming.v1beta1.ListStream.labels:type_name -> chrys.cloud.videostreaming.v1beta1.ListStream.LabelsEntry
+	4,  // 16: chrys.cloud.videostreaming.v1beta1.ListStream.location:type_name -> chrys.cloud.videostreaming.v1beta1.Location
+	34, // 17: chrys.cloud.videostreaming.v1beta1.ListStreamRequest.labels:type_name -> chrys.cloud.videostreaming.v1beta1.ListStreamRequest.LabelsEntry
+	17, // 18: chrys.cloud.videostreaming.v1beta1.VideoProbeResponse.video_codec:type_name -> chrys.cloud.videostreaming.v1beta1.VideoCodec
+	20, // 19: chrys.cloud.videostreaming.v1beta1.VideoProbeResponse.buffer:type_name -> chrys.cloud.videostreaming.v1beta1.VideoBuffer
+	0,  // 20: chrys.cloud.videostreaming.v1beta1.SignatureMatch.annotation:type_name -> chrys.cloud.videostreaming.v1beta1.AnnotateRequest
+	22, // 21: chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse.matches:type_name -> chrys.cloud.videostreaming.v1beta1.SignatureMatch
+	0,  // 22: chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse.annotations:type_name -> chrys.cloud.videostreaming.v1beta1.AnnotateRequest
+	26, // 23: chrys.cloud.videostreaming.v1beta1.AnnotateAck.rejections:type_name -> chrys.cloud.videostreaming.v1beta1.AnnotateRejection
+	9,  // 24: chrys.cloud.videostreaming.v1beta1.Image.VideoLatestImage:input_type -> chrys.cloud.videostreaming.v1beta1.VideoFrameRequest
+	10, // 25: chrys.cloud.videostreaming.v1beta1.Image.VideoBufferedImage:input_type -> chrys.cloud.videostreaming.v1beta1.VideoFrameBufferedRequest
+	18, // 26: chrys.cloud.videostreaming.v1beta1.Image.VideoProbe:input_type -> chrys.cloud.videostreaming.v1beta1.VideoProbeRequest
+	12, // 27: chrys.cloud.videostreaming.v1beta1.Image.ListStreams:input_type -> chrys.cloud.videostreaming.v1beta1.ListStreamRequest
+	0,  // 28: chrys.cloud.videostreaming.v1beta1.Image.Annotate:input_type -> chrys.cloud.videostreaming.v1beta1.AnnotateRequest
+	0,  // 29: chrys.cloud.videostreaming.v1beta1.Image.AnnotateStream:input_type -> chrys.cloud.videostreaming.v1beta1.AnnotateRequest
+	13, // 30: chrys.cloud.videostreaming.v1beta1.Image.Proxy:input_type -> chrys.cloud.videostreaming.v1beta1.ProxyRequest
+	15, // 31: chrys.cloud.videostreaming.v1beta1.Image.Storage:input_type -> chrys.cloud.videostreaming.v1beta1.StorageRequest
+	29, // 32: chrys.cloud.videostreaming.v1beta1.Image.SystemTime:input_type -> chrys.cloud.videostreaming.v1beta1.SystemTimeRequest
+	21, // 33: chrys.cloud.videostreaming.v1beta1.Image.SearchSignatures:input_type -> chrys.cloud.videostreaming.v1beta1.SearchSignaturesRequest
+	24, // 34: chrys.cloud.videostreaming.v1beta1.Image.DebugOverlay:input_type -> chrys.cloud.videostreaming.v1beta1.DebugOverlayRequest
+	8,  // 35: chrys.cloud.videostreaming.v1beta1.Image.VideoLatestImage:output_type -> chrys.cloud.videostreaming.v1beta1.VideoFrame
+	8,  // 36: chrys.cloud.videostreaming.v1beta1.Image.VideoBufferedImage:output_type -> chrys.cloud.videostreaming.v1beta1.VideoFrame
+	19, // 37: chrys.cloud.videostreaming.v1beta1.Image.VideoProbe:output_type -> chrys.cloud.videostreaming.v1beta1.VideoProbeResponse
+	11, // 38: chrys.cloud.videostreaming.v1beta1.Image.ListStreams:output_type -> chrys.cloud.videostreaming.v1beta1.ListStream
+	3,  // 39: chrys.cloud.videostreaming.v1beta1.Image.Annotate:output_type -> chrys.cloud.videostreaming.v1beta1.AnnotateResponse
+	27, // 40: chrys.cloud.videostreaming.v1beta1.Image.AnnotateStream:output_type -> chrys.cloud.videostreaming.v1beta1.AnnotateAck
+	14, // 41: chrys.cloud.videostreaming.v1beta1.Image.Proxy:output_type -> chrys.cloud.videostreaming.v1beta1.ProxyResponse
+	16, // 42: chrys.cloud.videostreaming.v1beta1.Image.Storage:output_type -> chrys.cloud.videostreaming.v1beta1.StorageResponse
+	28, // 43: chrys.cloud.videostreaming.v1beta1.Image.SystemTime:output_type -> chrys.cloud.videostreaming.v1beta1.SystemTimeResponse
+	23, // 44: chrys.cloud.videostreaming.v1beta1.Image.SearchSignatures:output_type -> chrys.cloud.videostreaming.v1beta1.SearchSignaturesResponse
+	25, // 45: chrys.cloud.videostreaming.v1beta1.Image.DebugOverlay:output_type -> chrys.cloud.videostreaming.v1beta1.DebugOverlayResponse
+	35, // [35:46] is the sub-list for method output_type
+	24, // [24:35] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_video_streaming_proto_init() }
@@ -2110,7 +3121,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AnnotateResponse); i {
+			switch v := v.(*DetectedObject); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2122,7 +3133,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Location); i {
+			switch v := v.(*Classification); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2134,7 +3145,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Coordinate); i {
+			switch v := v.(*AnnotateResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2146,7 +3157,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BoudingBox); i {
+			switch v := v.(*Location); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2158,7 +3169,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShapeProto); i {
+			switch v := v.(*Coordinate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2170,7 +3181,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoFrame); i {
+			switch v := v.(*BoudingBox); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2182,7 +3193,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoFrameRequest); i {
+			switch v := v.(*ShapeProto); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2194,7 +3205,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoFrameBufferedRequest); i {
+			switch v := v.(*VideoFrame); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2206,7 +3217,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListStream); i {
+			switch v := v.(*VideoFrameRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2218,7 +3229,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListStreamRequest); i {
+			switch v := v.(*VideoFrameBufferedRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2230,7 +3241,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProxyRequest); i {
+			switch v := v.(*ListStream); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2242,7 +3253,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProxyResponse); i {
+			switch v := v.(*ListStreamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2254,7 +3265,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StorageRequest); i {
+			switch v := v.(*ProxyRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2266,7 +3277,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StorageResponse); i {
+			switch v := v.(*ProxyResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2278,7 +3289,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoCodec); i {
+			switch v := v.(*StorageRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2290,7 +3301,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoProbeRequest); i {
+			switch v := v.(*StorageResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2302,7 +3313,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoProbeResponse); i {
+			switch v := v.(*VideoCodec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2314,7 +3325,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VideoBuffer); i {
+			switch v := v.(*VideoProbeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2326,7 +3337,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SystemTimeResponse); i {
+			switch v := v.(*VideoProbeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2338,7 +3349,7 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SystemTimeRequest); i {
+			switch v := v.(*VideoBuffer); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2350,6 +3361,114 @@ func file_video_streaming_proto_init() {
 			}
 		}
 		file_video_streaming_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchSignaturesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignatureMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchSignaturesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebugOverlayRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebugOverlayResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnnotateRejection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnnotateAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SystemTimeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SystemTimeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_video_streaming_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ShapeProto_Dim); i {
 			case 0:
 				return &v.state
@@ -2368,7 +3487,7 @@ func file_video_streaming_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_video_streaming_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   22,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2399,9 +3518,12 @@ type ImageClient interface {
 	VideoProbe(ctx context.Context, in *VideoProbeRequest, opts ...grpc.CallOption) (*VideoProbeResponse, error)
 	ListStreams(ctx context.Context, in *ListStreamRequest, opts ...grpc.CallOption) (Image_ListStreamsClient, error)
 	Annotate(ctx context.Context, in *AnnotateRequest, opts ...grpc.CallOption) (*AnnotateResponse, error)
+	AnnotateStream(ctx context.Context, opts ...grpc.CallOption) (Image_AnnotateStreamClient, error)
 	Proxy(ctx context.Context, in *ProxyRequest, opts ...grpc.CallOption) (*ProxyResponse, error)
 	Storage(ctx context.Context, in *StorageRequest, opts ...grpc.CallOption) (*StorageResponse, error)
 	SystemTime(ctx context.Context, in *SystemTimeRequest, opts ...grpc.CallOption) (*SystemTimeResponse, error)
+	SearchSignatures(ctx context.Context, in *SearchSignaturesRequest, opts ...grpc.CallOption) (*SearchSignaturesResponse, error)
+	DebugOverlay(ctx context.Context, in *DebugOverlayRequest, opts ...grpc.CallOption) (*DebugOverlayResponse, error)
 }
 
 type imageClient struct {
@@ -2503,6 +3625,37 @@ func (c *imageClient) Annotate(ctx context.Context, in *AnnotateRequest, opts ..
 	return out, nil
 }
 
+func (c *imageClient) AnnotateStream(ctx context.Context, opts ...grpc.CallOption) (Image_AnnotateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Image_serviceDesc.Streams[2], "/chrys.cloud.videostreaming.v1beta1.Image/AnnotateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &imageAnnotateStreamClient{stream}
+	return x, nil
+}
+
+type Image_AnnotateStreamClient interface {
+	Send(*AnnotateRequest) error
+	Recv() (*AnnotateAck, error)
+	grpc.ClientStream
+}
+
+type imageAnnotateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *imageAnnotateStreamClient) Send(m *AnnotateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *imageAnnotateStreamClient) Recv() (*AnnotateAck, error) {
+	m := new(AnnotateAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *imageClient) Proxy(ctx context.Context, in *ProxyRequest, opts ...grpc.CallOption) (*ProxyResponse, error) {
 	out := new(ProxyResponse)
 	err := c.cc.Invoke(ctx, "/chrys.cloud.videostreaming.v1beta1.Image/Proxy", in, out, opts...)
@@ -2530,6 +3683,24 @@ func (c *imageClient) SystemTime(ctx context.Context, in *SystemTimeRequest, opt
 	return out, nil
 }
 
+func (c *imageClient) SearchSignatures(ctx context.Context, in *SearchSignaturesRequest, opts ...grpc.CallOption) (*SearchSignaturesResponse, error) {
+	out := new(SearchSignaturesResponse)
+	err := c.cc.Invoke(ctx, "/chrys.cloud.videostreaming.v1beta1.Image/SearchSignatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imageClient) DebugOverlay(ctx context.Context, in *DebugOverlayRequest, opts ...grpc.CallOption) (*DebugOverlayResponse, error) {
+	out := new(DebugOverlayResponse)
+	err := c.cc.Invoke(ctx, "/chrys.cloud.videostreaming.v1beta1.Image/DebugOverlay", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ImageServer is the server API for Image service.
 type ImageServer interface {
 	VideoLatestImage(context.Context, *VideoFrameRequest) (*VideoFrame, error)
@@ -2537,9 +3708,12 @@ type ImageServer interface {
 	VideoProbe(context.Context, *VideoProbeRequest) (*VideoProbeResponse, error)
 	ListStreams(*ListStreamRequest, Image_ListStreamsServer) error
 	Annotate(context.Context, *AnnotateRequest) (*AnnotateResponse, error)
+	AnnotateStream(Image_AnnotateStreamServer) error
 	Proxy(context.Context, *ProxyRequest) (*ProxyResponse, error)
 	Storage(context.Context, *StorageRequest) (*StorageResponse, error)
 	SystemTime(context.Context, *SystemTimeRequest) (*SystemTimeResponse, error)
+	SearchSignatures(context.Context, *SearchSignaturesRequest) (*SearchSignaturesResponse, error)
+	DebugOverlay(context.Context, *DebugOverlayRequest) (*DebugOverlayResponse, error)
 }
 
 // UnimplementedImageServer can be embedded to have forward compatible implementations.
@@ -2561,6 +3735,9 @@ func (*UnimplementedImageServer) ListStreams(*ListStreamRequest, Image_ListStrea
 func (*UnimplementedImageServer) Annotate(context.Context, *AnnotateRequest) (*AnnotateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Annotate not implemented")
 }
+func (*UnimplementedImageServer) AnnotateStream(Image_AnnotateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method AnnotateStream not implemented")
+}
 func (*UnimplementedImageServer) Proxy(context.Context, *ProxyRequest) (*ProxyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Proxy not implemented")
 }
@@ -2570,6 +3747,12 @@ func (*UnimplementedImageServer) Storage(context.Context, *StorageRequest) (*Sto
 func (*UnimplementedImageServer) SystemTime(context.Context, *SystemTimeRequest) (*SystemTimeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SystemTime not implemented")
 }
+func (*UnimplementedImageServer) SearchSignatures(context.Context, *SearchSignaturesRequest) (*SearchSignaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchSignatures not implemented")
+}
+func (*UnimplementedImageServer) DebugOverlay(context.Context, *DebugOverlayRequest) (*DebugOverlayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DebugOverlay not implemented")
+}
 
 func RegisterImageServer(s *grpc.Server, srv ImageServer) {
 	s.RegisterService(&_Image_serviceDesc, srv)
@@ -2671,6 +3854,32 @@ func _Image_Annotate_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Image_AnnotateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ImageServer).AnnotateStream(&imageAnnotateStreamServer{stream})
+}
+
+type Image_AnnotateStreamServer interface {
+	Send(*AnnotateAck) error
+	Recv() (*AnnotateRequest, error)
+	grpc.ServerStream
+}
+
+type imageAnnotateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *imageAnnotateStreamServer) Send(m *AnnotateAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *imageAnnotateStreamServer) Recv() (*AnnotateRequest, error) {
+	m := new(AnnotateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Image_Proxy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ProxyRequest)
 	if err := dec(in); err != nil {
@@ -2725,6 +3934,42 @@ func _Image_SystemTime_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Image_SearchSignatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchSignaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImageServer).SearchSignatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chrys.cloud.videostreaming.v1beta1.Image/SearchSignatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImageServer).SearchSignatures(ctx, req.(*SearchSignaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Image_DebugOverlay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugOverlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImageServer).DebugOverlay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chrys.cloud.videostreaming.v1beta1.Image/DebugOverlay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImageServer).DebugOverlay(ctx, req.(*DebugOverlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Image_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "chrys.cloud.videostreaming.v1beta1.Image",
 	HandlerType: (*ImageServer)(nil),
@@ -2753,6 +3998,14 @@ var _Image_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SystemTime",
 			Handler:    _Image_SystemTime_Handler,
 		},
+		{
+			MethodName: "SearchSignatures",
+			Handler:    _Image_SearchSignatures_Handler,
+		},
+		{
+			MethodName: "DebugOverlay",
+			Handler:    _Image_DebugOverlay_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2765,6 +4018,12 @@ var _Image_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Image_ListStreams_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AnnotateStream",
+			Handler:       _Image_AnnotateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "video_streaming.proto",
 }
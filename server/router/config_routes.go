@@ -15,7 +15,15 @@
 package router
 
 import (
+	"fmt"
+	"regexp"
+	"time"
+
+	mauth "github.com/chryscloud/go-microkit-plugins/auth"
+	cfg "github.com/chryscloud/go-microkit-plugins/config"
 	api "github.com/chryscloud/video-edge-ai-proxy/api"
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
 	"github.com/chryscloud/video-edge-ai-proxy/services"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -24,43 +32,167 @@ import (
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 )
 
+// access tokens in query strings (websocket clients can't set headers) must not end up in the request log
+var accessTokenQuery = regexp.MustCompile(`([?&]access_token=)[^&]*`)
+
+// NewAPIRouter - gin engine with recovery and request logging (access tokens in the query string are redacted)
+func NewAPIRouter(conf *cfg.YamlConfig) *gin.Engine {
+	if conf.Mode == "release" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.LoggerWithFormatter(requestLogFormatter))
+	router.Use(gin.Recovery())
+	router.Use(mauth.TokenMiddleware(conf))
+	return router
+}
+
+// requestLogFormatter - gin's default request log line with redacted access tokens
+func requestLogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency - param.Latency%time.Second
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		accessTokenQuery.ReplaceAllString(param.Path, "${1}REDACTED"),
+		param.ErrorMessage,
+	)
+}
+
 // ConfigAPI - configuring RESTapi services
-func ConfigAPI(router *gin.Engine, processService *services.ProcessManager, settingsService *services.SettingsManager, appService *services.AppProcessManager, rdb *redis.Client) *gin.Engine {
+func ConfigAPI(router *gin.Engine, processService *services.ProcessManager, settingsService *services.SettingsManager, appService *services.AppProcessManager, countingService *services.CountingManager, ruleService *services.RuleManager, privacyMaskService *services.PrivacyMaskManager, debugOverlayService *services.DebugOverlayManager, reconcileService *services.ReconcileManager, terminalService *services.TerminalManager, auditService *services.AuditManager, authService *services.AuthManager, configTransferService *services.ConfigTransferManager, manifestService *services.ManifestManager, discoveryService *services.DiscoveryManager, rdb *redis.Client) *gin.Engine {
 
-	// if g.Conf.CorsSubConfig.Enabled {
-	router.Use(cors.New(cors.Config{
-		AllowCredentials: true,
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"*"},
-		AllowHeaders:     []string{"*"},
-	}))
+	// no cross-origin access unless origins are configured (auth.allow_origins)
+	if g.Conf.Auth != nil && len(g.Conf.Auth.AllowOrigins) > 0 {
+		allowOrigins := g.Conf.Auth.AllowOrigins
+		wildcard := false
+		for _, origin := range allowOrigins {
+			if origin == "*" {
+				wildcard = true
+			}
+		}
+		router.Use(cors.New(cors.Config{
+			// credentials are never allowed together with any origin
+			AllowCredentials: !wildcard,
+			AllowOrigins:     allowOrigins,
+			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key"},
+		}))
+	} else {
+		g.Log.Info("no cross-origin REST API access (auth.allow_origins not configured)")
+	}
 
 	// APIs
+	authAPI := api.NewAuthHandler(authService)
+	auditAPI := api.NewAuditHandler(auditService)
 	processAPI := api.NewRTSPProcessHandler(rdb, processService, settingsService)
 	appsAPI := api.NewAppProcessHandler(rdb, appService, processService, settingsService)
 	settingsAPI := api.NewSettingsHandler(settingsService)
+	countingAPI := api.NewCountingHandler(countingService)
+	rulesAPI := api.NewRuleHandler(ruleService)
+	privacyMaskAPI := api.NewPrivacyMaskHandler(privacyMaskService)
+	debugOverlayAPI := api.NewDebugOverlayHandler(debugOverlayService)
+	reconcileAPI := api.NewReconcileHandler(reconcileService)
+	terminalAPI := api.NewTerminalHandler(terminalService)
+	configTransferAPI := api.NewConfigTransferHandler(configTransferService)
+	manifestAPI := api.NewManifestHandler(manifestService)
+	discoveryAPI := api.NewDiscoveryHandler(discoveryService)
 	testAPI := api.NewTestApiHandler(rdb)
 
-	api := router.Group("/api/v1")
+	// first-run setup and login (no authentication)
+	public := router.Group("/api/v1/auth", auditAPI.Audit())
+	{
+		public.GET("setup", authAPI.Status)
+		public.POST("setup", authAPI.Setup)
+		public.POST("login", authAPI.Login)
+	}
+
+	api := router.Group("/api/v1", authAPI.Authenticate(), auditAPI.Audit())
+
+	viewer := api.Group("", authAPI.RequireRole(models.RoleViewer))
+	{
+		viewer.GET("auth/me", authAPI.Me)
+		viewer.POST("auth/password", authAPI.ChangePassword)
+		viewer.GET("process/:name/logs", processAPI.StreamLogs)
+		viewer.GET("process/:name", processAPI.Info)
+		viewer.GET("processlist", processAPI.List)
+		viewer.GET("processupgrades", processAPI.FindRTSPUpgrades)
+		viewer.GET("devicedockerimages", settingsAPI.DeviceDockerImagesLocally)
+		viewer.GET("alldockerimages", settingsAPI.ListAllDockerImages)
+		viewer.GET("appprocess/:name/logs", appsAPI.StreamLogs)
+		viewer.GET("appprocesslist", appsAPI.ListApps)
+		viewer.GET("appprocess/:name", appsAPI.Info)
+		viewer.GET("counting/zones/:name", countingAPI.ListZones)
+		viewer.GET("counting/counts/:name", countingAPI.Counts)
+		viewer.GET("counting/occupancy/:name", countingAPI.Occupancy)
+		viewer.GET("rules", rulesAPI.List)
+		viewer.GET("rules/:name", rulesAPI.Get)
+		viewer.GET("privacymask/:name", privacyMaskAPI.Get)
+		viewer.GET("privacymask/:name/versions", privacyMaskAPI.Versions)
+		viewer.GET("debug/overlay/:name", debugOverlayAPI.Overlay)
+		viewer.GET("reconcile", reconcileAPI.DryRun)
+		viewer.GET("manifest", manifestAPI.Status)
+	}
+
+	operator := api.Group("", authAPI.RequireRole(models.RoleOperator))
+	{
+		operator.POST("process", manifestAPI.Guard(), processAPI.StartRTSP)
+		operator.POST("processvalidate", processAPI.Validate)
+		operator.PUT("process/:name", manifestAPI.Guard(), processAPI.UpdateRTSP)
+		operator.DELETE("process/:name", manifestAPI.Guard(), processAPI.Stop)
+		operator.POST("process/:name/restart", processAPI.Restart)
+		operator.POST("process/:name/pause", processAPI.Pause)
+		operator.POST("process/:name/resume", processAPI.Resume)
+		operator.POST("processgroup/restart", processAPI.RestartGroup)
+		operator.POST("processgroup/proxy", processAPI.ProxyGroup)
+		operator.POST("discovery/scan", discoveryAPI.Scan)
+		operator.POST("processupgrades", manifestAPI.Guard(), processAPI.UpgradeContainer)
+		operator.GET("dockerpull", settingsAPI.DockerPullImage)
+		operator.POST("appprocess/:name/restart", appsAPI.Restart)
+		operator.POST("appprocess/:name/pause", appsAPI.Pause)
+		operator.POST("appprocess/:name/resume", appsAPI.Resume)
+		operator.POST("counting/zones", countingAPI.PutZone)
+		operator.DELETE("counting/zones/:name/:zone", countingAPI.DeleteZone)
+		operator.POST("rules", rulesAPI.Put)
+		operator.DELETE("rules/:name", rulesAPI.Delete)
+		operator.POST("rules/test", rulesAPI.Test)
+		operator.POST("privacymask", privacyMaskAPI.Put)
+		operator.DELETE("privacymask/:name", privacyMaskAPI.Delete)
+		operator.POST("privacymask/:name/versions/:version", privacyMaskAPI.Restore)
+		operator.POST("reconcile", reconcileAPI.Reconcile)
+		operator.POST("manifest/reconcile", manifestAPI.Reconcile)
+	}
+
+	admin := api.Group("", authAPI.RequireRole(models.RoleAdmin))
 	{
-		api.POST("process", processAPI.StartRTSP)
-		api.DELETE("process/:name", processAPI.Stop)
-		api.GET("process/:name", processAPI.Info)
-		api.GET("processlist", processAPI.List)
-		api.GET("processupgrades", processAPI.FindRTSPUpgrades)
-		api.POST("processupgrades", processAPI.UpgradeContainer)
-		api.GET("settings", settingsAPI.Get)
-		api.POST("settings", settingsAPI.Overwrite)
-		api.GET("devicedockerimages", settingsAPI.DeviceDockerImagesLocally)
-		api.GET("alldockerimages", settingsAPI.ListAllDockerImages)
-		api.GET("dockerpull", settingsAPI.DockerPullImage)
-		api.POST("appprocess", appsAPI.InstallApp)
-		api.DELETE("appprocess/:name", appsAPI.RemoveApp)
-		api.GET("appprocesslist", appsAPI.ListApps)
-		api.GET("appprocess/:name", appsAPI.Info)
+		admin.GET("settings", settingsAPI.Get)
+		admin.POST("settings", settingsAPI.Overwrite)
+		admin.POST("appprocess", manifestAPI.Guard(), appsAPI.InstallApp)
+		admin.DELETE("appprocess/:name", manifestAPI.Guard(), appsAPI.RemoveApp)
+		admin.GET("process/:name/terminal", terminalAPI.Camera)
+		admin.GET("appprocess/:name/terminal", terminalAPI.App)
+		admin.GET("users", authAPI.ListUsers)
+		admin.POST("users", authAPI.CreateUser)
+		admin.DELETE("users/:name", authAPI.DeleteUser)
+		admin.GET("apikeys", authAPI.ListAPIKeys)
+		admin.POST("apikeys", authAPI.CreateAPIKey)
+		admin.DELETE("apikeys/:id", authAPI.DeleteAPIKey)
+		admin.GET("audit", auditAPI.List)
+		admin.GET("config/export", configTransferAPI.Export)
+		admin.POST("config/import", manifestAPI.Guard(), configTransferAPI.Import)
 	}
 
-	testapimqtt := router.Group("/testmqtt/api/v1")
+	testapimqtt := router.Group("/testmqtt/api/v1", authAPI.Authenticate(), authAPI.RequireRole(models.RoleAdmin))
 	{
 		testapimqtt.GET("/devicestatus", testAPI.TestMqttDeviceStatus)
 	}
@@ -0,0 +1,241 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/go-redis/redis/v7"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	enricherCacheTTL   = time.Second * 30
+	enricherIdleTTL    = time.Minute * 10 // devices without annotations for this long are dropped from the cache
+	enricherMaxDevices = 1024             // maximum number of cached devices (least recently used are dropped first)
+
+	// timestamps below this value (2001-09-09 in ms) are considered relative pts in ms from the start of the stream
+	relativeTimestampThreshold = int64(1000000000000)
+	// re-anchor relative timestamps when they drift away from server time more than this (ms)
+	relativeTimestampMaxDrift = int64(60000)
+)
+
+// AnnotationEnricher - fills in annotation fields clients usually leave empty (stream id, dimensions, location, server time)
+type AnnotationEnricher struct {
+	storage *Storage
+	rdb     *redis.Client
+
+	mux     sync.Mutex // guards the devices map only, device fields are guarded by the device lock
+	devices map[string]*enricherDevice
+}
+
+type enricherDevice struct {
+	used time.Time // last annotation of the device (guarded by the enricher lock)
+
+	mux        sync.Mutex
+	fetched    time.Time
+	refreshing bool
+	info       *enricherDeviceInfo
+
+	anchor  int64 // server time (ms) of relative timestamp 0
+	lastPts int64
+}
+
+// enricherDeviceInfo camera info read from the datastore and redis
+type enricherDeviceInfo struct {
+	process  *models.StreamProcess // nil if not a stored RTSP camera
+	codec    *pb.VideoCodec        // nil if unknown
+	streamID string                // parsed RTMP key
+}
+
+func NewAnnotationEnricher(storage *Storage, rdb *redis.Client) *AnnotationEnricher {
+	return &AnnotationEnricher{
+		storage: storage,
+		rdb:     rdb,
+		devices: make(map[string]*enricherDevice),
+	}
+}
+
+// Enrich sets missing annotation fields in place. Values provided by the client are never overwritten.
+func (ae *AnnotationEnricher) Enrich(req *pb.AnnotateRequest) {
+	conf := g.Conf.Enrichment
+	if conf == nil {
+		conf = &g.EnrichmentSubconfig{RemoteStreamID: true, Dimensions: true, Location: true, Timestamp: true}
+	}
+	if !conf.RemoteStreamID && !conf.Dimensions && !conf.Location && !conf.Timestamp {
+		return
+	}
+
+	now := time.Now()
+	device := ae.device(req.DeviceName, now)
+	ae.refresh(device, req.DeviceName, now, conf)
+
+	device.mux.Lock()
+	defer device.mux.Unlock()
+
+	if conf.Timestamp {
+		device.normalizeTimestamps(req, now.UnixNano()/int64(time.Millisecond))
+	}
+	info := device.info
+	if info == nil {
+		return
+	}
+	if conf.RemoteStreamID && req.RemoteStreamId == "" {
+		req.RemoteStreamId = info.streamID
+	}
+	if conf.Dimensions && req.Width == 0 && req.Height == 0 && info.codec != nil {
+		req.Width = info.codec.Width
+		req.Height = info.codec.Height
+	}
+	if conf.Location && req.Location == nil && info.process != nil && info.process.Location != nil {
+		loc := info.process.Location
+		req.Location = &pb.Location{
+			Lat:     loc.Lat,
+			Lon:     loc.Lon,
+			Heading: loc.Heading,
+			Height:  loc.Height,
+		}
+	}
+}
+
+// device returns the cached device, idle or least recently used devices are evicted when the cache is full
+func (ae *AnnotationEnricher) device(deviceName string, now time.Time) *enricherDevice {
+	ae.mux.Lock()
+	defer ae.mux.Unlock()
+
+	device, ok := ae.devices[deviceName]
+	if !ok {
+		if len(ae.devices) >= enricherMaxDevices {
+			ae.evict(now)
+		}
+		device = &enricherDevice{}
+		ae.devices[deviceName] = device
+	}
+	device.used = now
+	return device
+}
+
+// evict drops idle devices, or the least recently used one if none is idle (enricher lock must be held)
+func (ae *AnnotationEnricher) evict(now time.Time) {
+	oldest := ""
+	for name, d := range ae.devices {
+		if now.Sub(d.used) > enricherIdleTTL {
+			delete(ae.devices, name)
+			continue
+		}
+		if oldest == "" || d.used.Before(ae.devices[oldest].used) {
+			oldest = name
+		}
+	}
+	if len(ae.devices) >= enricherMaxDevices {
+		delete(ae.devices, oldest)
+	}
+}
+
+// refresh reloads camera info every enricherCacheTTL. Datastore and redis are read without holding any lock,
+// concurrent annotations of the device use the previous info in the meantime.
+func (ae *AnnotationEnricher) refresh(device *enricherDevice, deviceName string, now time.Time, conf *g.EnrichmentSubconfig) {
+	device.mux.Lock()
+	if device.refreshing || now.Sub(device.fetched) < enricherCacheTTL {
+		device.mux.Unlock()
+		return
+	}
+	device.refreshing = true
+	device.mux.Unlock()
+
+	info := &enricherDeviceInfo{}
+	if conf.RemoteStreamID || conf.Location {
+		info.process = ae.storedProcess(deviceName)
+		if info.process != nil && info.process.RTMPEndpoint != "" {
+			key, err := utils.ParseRTMPKey(info.process.RTMPEndpoint)
+			if err != nil {
+				g.Log.Warn("failed to parse rtmp key for annotation enrichment", deviceName, err)
+			} else {
+				info.streamID = key
+			}
+		}
+	}
+	if conf.Dimensions {
+		info.codec = ae.videoCodec(deviceName)
+	}
+
+	device.mux.Lock()
+	device.info = info
+	device.fetched = now
+	device.refreshing = false
+	device.mux.Unlock()
+}
+
+func (ae *AnnotationEnricher) storedProcess(deviceName string) *models.StreamProcess {
+	b, err := ae.storage.Get(models.PrefixRTSPProcess, deviceName)
+	if err != nil {
+		return nil
+	}
+	var process models.StreamProcess
+	if err := json.Unmarshal(b, &process); err != nil {
+		g.Log.Error("failed to unmarshal stored process", deviceName, err)
+		return nil
+	}
+	return &process
+}
+
+func (ae *AnnotationEnricher) videoCodec(deviceName string) *pb.VideoCodec {
+	if ae.rdb == nil {
+		return nil
+	}
+	b, err := ae.rdb.Get(models.RedisCodecVideoInfo + deviceName).Bytes()
+	if err != nil {
+		return nil
+	}
+	codec := &pb.VideoCodec{}
+	if err := proto.Unmarshal(b, codec); err != nil {
+		g.Log.Error("failed to unmarshal codec info", deviceName, err)
+		return nil
+	}
+	if codec.Width <= 0 || codec.Height <= 0 {
+		return nil
+	}
+	return codec
+}
+
+// normalizeTimestamps converts relative pts (ms) to server time. The first relative timestamp of the device
+// is anchored to the time it was received. The anchor is reset when pts goes backwards (stream restart)
+// or drifts away from server time. Original pts is kept in offset_timestamp.
+func (d *enricherDevice) normalizeTimestamps(req *pb.AnnotateRequest, nowMs int64) {
+	pts := req.StartTimestamp
+	if pts < 0 || pts >= relativeTimestampThreshold {
+		return
+	}
+	ts := d.anchor + pts
+	if d.anchor == 0 || pts < d.lastPts || ts > nowMs+relativeTimestampMaxDrift || ts < nowMs-relativeTimestampMaxDrift {
+		d.anchor = nowMs - pts
+		ts = nowMs
+	}
+	d.lastPts = pts
+
+	if req.OffsetTimestamp == 0 {
+		req.OffsetTimestamp = pts
+	}
+	req.StartTimestamp = ts
+	if req.EndTimestamp > 0 && req.EndTimestamp < relativeTimestampThreshold {
+		req.EndTimestamp += d.anchor
+	}
+}
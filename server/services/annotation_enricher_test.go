@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func TestAnnotationEnrichment(t *testing.T) {
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	process := &models.StreamProcess{
+		Name:         "enrichcam",
+		RTSPEndpoint: "rtsp://localhost/stream",
+		RTMPEndpoint: "rtmp://rtmp.chryscloud.com/live/abc123",
+		Location:     &models.CameraLocation{Lat: 46.05, Lon: 14.5, Heading: 270},
+	}
+	b, err := json.Marshal(process)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put(models.PrefixRTSPProcess, process.Name, b); err != nil {
+		t.Fatal(err)
+	}
+
+	ae := NewAnnotationEnricher(storage, nil)
+
+	req := &pb.AnnotateRequest{DeviceName: "enrichcam", Type: "moving", StartTimestamp: 1500, EndTimestamp: 2500}
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	ae.Enrich(req)
+
+	if req.RemoteStreamId != "abc123" {
+		t.Fatalf("expected remote stream id from rtmp key, got %v", req.RemoteStreamId)
+	}
+	if req.Location == nil || req.Location.Lat != 46.05 || req.Location.Heading != 270 {
+		t.Fatalf("expected camera location, got %v", req.Location)
+	}
+	if req.StartTimestamp < before || req.EndTimestamp-req.StartTimestamp != 1000 || req.OffsetTimestamp != 1500 {
+		t.Fatalf("expected relative timestamps normalized to server time, got %v-%v (offset %v)", req.StartTimestamp, req.EndTimestamp, req.OffsetTimestamp)
+	}
+
+	// subsequent relative timestamps keep the same anchor
+	next := &pb.AnnotateRequest{DeviceName: "enrichcam", Type: "moving", StartTimestamp: 1700}
+	ae.Enrich(next)
+	if next.StartTimestamp-req.StartTimestamp != 200 {
+		t.Fatalf("expected 200ms after previous annotation, got %v", next.StartTimestamp-req.StartTimestamp)
+	}
+
+	// client provided values are kept
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	own := &pb.AnnotateRequest{DeviceName: "enrichcam", Type: "moving", StartTimestamp: now, RemoteStreamId: "own", Location: &pb.Location{Lat: 1, Lon: 2}}
+	ae.Enrich(own)
+	if own.StartTimestamp != now || own.RemoteStreamId != "own" || own.Location.Lat != 1 || own.OffsetTimestamp != 0 {
+		t.Fatalf("expected client values to be kept, got %v", own)
+	}
+
+	// device cache is bounded, idle devices are dropped first
+	ae.devices["enrichcam"].used = time.Now().Add(-enricherIdleTTL * 2)
+	for i := 0; i < enricherMaxDevices; i++ {
+		ae.Enrich(&pb.AnnotateRequest{DeviceName: "cam" + strconv.Itoa(i), Type: "moving"})
+	}
+	if _, ok := ae.devices["enrichcam"]; ok || len(ae.devices) != enricherMaxDevices {
+		t.Fatalf("expected idle device evicted and %v cached devices, got %v", enricherMaxDevices, len(ae.devices))
+	}
+	ae.Enrich(&pb.AnnotateRequest{DeviceName: "overflow", Type: "moving"})
+	if _, ok := ae.devices["cam0"]; ok || len(ae.devices) != enricherMaxDevices {
+		t.Fatalf("expected least recently used device evicted, got %v cached devices", len(ae.devices))
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	defaultAnnotationStoreRetention = time.Minute * 10
+	defaultAnnotationStoreMax       = 10000 // per device
+)
+
+// AnnotationStore - recent annotations per device kept in memory (e.g. for debug overlays)
+type AnnotationStore struct {
+	mux          sync.RWMutex
+	annotations  map[string][]*storedAnnotation
+	retention    time.Duration
+	maxPerDevice int
+}
+
+type storedAnnotation struct {
+	received   time.Time
+	annotation *pb.AnnotateRequest
+}
+
+func NewAnnotationStore() *AnnotationStore {
+	as := &AnnotationStore{
+		annotations:  make(map[string][]*storedAnnotation),
+		retention:    defaultAnnotationStoreRetention,
+		maxPerDevice: defaultAnnotationStoreMax,
+	}
+
+	// drop expired annotations every minute
+	ticker := time.NewTicker(time.Minute)
+	go func() {
+		for t := range ticker.C {
+			as.expire(t)
+		}
+	}()
+
+	return as
+}
+
+// Add stores a copy of the annotation
+func (as *AnnotationStore) Add(req *pb.AnnotateRequest) {
+	stored := &storedAnnotation{
+		received:   time.Now(),
+		annotation: proto.Clone(req).(*pb.AnnotateRequest),
+	}
+
+	as.mux.Lock()
+	defer as.mux.Unlock()
+
+	list := append(as.annotations[req.DeviceName], stored)
+	if len(list) > as.maxPerDevice {
+		list = list[len(list)-as.maxPerDevice:]
+	}
+	as.annotations[req.DeviceName] = list
+}
+
+// Query returns device annotations with start timestamp within [from, to] (ms)
+func (as *AnnotationStore) Query(deviceName string, from, to int64) []*pb.AnnotateRequest {
+	as.mux.RLock()
+	defer as.mux.RUnlock()
+
+	result := make([]*pb.AnnotateRequest, 0)
+	for _, stored := range as.annotations[deviceName] {
+		ts := stored.annotation.StartTimestamp
+		if ts >= from && ts <= to {
+			result = append(result, stored.annotation)
+		}
+	}
+	return result
+}
+
+func (as *AnnotationStore) expire(now time.Time) {
+	as.mux.Lock()
+	defer as.mux.Unlock()
+	for deviceName, list := range as.annotations {
+		i := 0
+		for i < len(list) && now.Sub(list[i].received) > as.retention {
+			i++
+		}
+		if i == len(list) {
+			delete(as.annotations, deviceName)
+		} else if i > 0 {
+			as.annotations[deviceName] = append([]*storedAnnotation{}, list[i:]...)
+		}
+	}
+}
@@ -18,29 +18,34 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/chryscloud/go-microkit-plugins/docker"
+	microModelDocker "github.com/chryscloud/go-microkit-plugins/models/docker"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/dgraph-io/badger/v2"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
-	dockerErrors "github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 	"github.com/go-redis/redis/v7"
 )
 
 // ProcessManager - start, stop of docker containers
 type AppProcessManager struct {
-	storage *Storage
-	rdb     *redis.Client
+	storage          *Storage
+	rdb              *redis.Client
+	containerRuntime ContainerRuntime
+	stateCache       *ProcessStateCache
+	reconcileStatus  sync.Map // app name -> result of the last reconciliation
 }
 
-func NewAppManager(storage *Storage, rdb *redis.Client) *AppProcessManager {
+func NewAppManager(storage *Storage, rdb *redis.Client, containerRuntime ContainerRuntime, stateCache *ProcessStateCache) *AppProcessManager {
 	return &AppProcessManager{
-		storage: storage,
-		rdb:     rdb,
+		storage:          storage,
+		rdb:              rdb,
+		containerRuntime: containerRuntime,
+		stateCache:       stateCache,
 	}
 }
 
@@ -48,10 +53,9 @@ func NewAppManager(storage *Storage, rdb *redis.Client) *AppProcessManager {
 func (am *AppProcessManager) Install(app *models.AppProcess) (*models.AppProcess, error) {
 
 	// installation process
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
+	cl := am.containerRuntime
 
-	fl := filters.NewArgs()
-	pruneReport, pruneErr := cl.ContainersPrune(fl)
+	pruneReport, pruneErr := cl.ContainersPrune()
 	if pruneErr != nil {
 		g.Log.Error("container prunning fialed", pruneErr)
 		return nil, pruneErr
@@ -124,13 +128,14 @@ func (am *AppProcessManager) Install(app *models.AppProcess) (*models.AppProcess
 
 	// preapre container configuration
 	containerConf := &container.Config{
-		Image: imageTag,
-		Env:   envVars,
+		Image:  imageTag,
+		Env:    envVars,
+		Labels: map[string]string{models.ContainerLabelManaged: models.ProcessTypeApplication, models.ContainerLabelName: app.Name},
 	}
 	if len(portSet) > 0 {
 		containerConf.ExposedPorts = portSet
 	}
-	_, ccErr := cl.ContainerCreate(strings.ToLower(app.Name), containerConf, hostConfig, nil)
+	ccErr := cl.ContainerCreate(strings.ToLower(app.Name), containerConf, hostConfig)
 
 	if ccErr != nil {
 
@@ -179,16 +184,16 @@ func (am *AppProcessManager) ListApps() ([]*models.AppProcess, error) {
 		}
 		processes = append(processes, &process)
 	}
-	deleteProcesses := make([]*models.AppProcess, 0)
 	cleanProcesses := make([]*models.AppProcess, 0)
-	// clean up and update the list
+	// update the list (datastore is the desired state, missing containers are recreated by the reconciler)
 	for _, proc := range processes {
 		info, err := am.Info(proc.Name)
 		if err != nil {
-			g.Log.Warn("failed to load process", err)
 			if err == models.ErrProcessNotFound {
-				// remove from the list and datastore
-				deleteProcesses = append(deleteProcesses, proc)
+				g.Log.Warn("container missing for app", proc.Name)
+				proc.Status = models.ProcessStatusMissing
+				proc.State = nil
+				cleanProcesses = append(cleanProcesses, proc)
 				continue
 			}
 			g.Log.Error("failed to get process info", err)
@@ -196,35 +201,13 @@ func (am *AppProcessManager) ListApps() ([]*models.AppProcess, error) {
 		}
 		cleanProcesses = append(cleanProcesses, info)
 	}
-	if len(deleteProcesses) > 0 {
-		for _, proc := range deleteProcesses {
-			err := am.storage.Del(models.PrefixRTSPProcess, proc.Name)
-			if err != nil {
-				g.Log.Error("failed to delete process with name", proc.Name, err)
-				return nil, err
-			}
-		}
-	}
 	return cleanProcesses, nil
 }
 
 func (am *AppProcessManager) Info(appName string) (*models.AppProcess, error) {
-	// Info - return information on the streaming docker container (it also updates the process status)
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
-	container, err := cl.ContainerGet(appName)
+	// Info - return information on the app docker container (container state is served from the state cache)
+	container, err := am.stateCache.Get(appName)
 	if err != nil {
-		if dockerErrors.IsErrNotFound(err) {
-			g.Log.Info("container not found to be stopeed", err)
-			return nil, models.ErrProcessNotFound
-		}
-		g.Log.Error("failed to retrieve container", err)
-		return nil, err
-	}
-
-	// max 100 lines of logs
-	logs, err := cl.ContainerLogs(container.ID, 100, time.Unix(0, 0))
-	if err != nil {
-		g.Log.Error("failed to retrieve container logs", err)
 		return nil, err
 	}
 
@@ -240,25 +223,148 @@ func (am *AppProcessManager) Info(appName string) (*models.AppProcess, error) {
 		return nil, err
 	}
 	status.ContainerID = container.ID
-	if container != nil {
+	if container.State != nil {
 		status.State = container.State
 		status.Status = container.State.Status
 	} else {
 		status.Status = "unknown"
 	}
-	status.Logs = logs
-	status.Modified = time.Now().Unix() * 1000
+	status.LastReconciled = am.ReconcileStatus(appName)
+
+	return &status, nil
+}
+
+// SetReconcileStatus - result of the last reconciliation of the app
+func (am *AppProcessManager) SetReconcileStatus(appName string, status *models.ReconcileStatus) {
+	am.reconcileStatus.Store(appName, status)
+}
+
+// ReconcileStatus - result of the last reconciliation of the app (nil if not reconciled yet)
+func (am *AppProcessManager) ReconcileStatus(appName string) *models.ReconcileStatus {
+	if s, ok := am.reconcileStatus.Load(appName); ok {
+		return s.(*models.ReconcileStatus)
+	}
+	return nil
+}
+
+// Logs - last 100 lines of the app docker container logs
+func (am *AppProcessManager) Logs(appName string) (*microModelDocker.DockerLogs, error) {
+	return containerLogs(am.stateCache, appName)
+}
 
-	b, err := json.Marshal(&status)
+// Restart - restarts the app container. Paused app is resumed.
+func (am *AppProcessManager) Restart(appName string) (*models.AppProcess, error) {
+	app, err := am.storedApp(appName)
 	if err != nil {
-		g.Log.Error("failed to marshal process", err)
 		return nil, err
 	}
-	err = am.storage.Put(models.PrefixAppProcess, status.Name, b)
+	c, err := am.stateCache.Get(appName)
 	if err != nil {
-		g.Log.Error("failed to store process after info", err)
 		return nil, err
 	}
+	if c.State != nil && c.State.Paused {
+		if err := am.containerRuntime.ContainerUnpause(c.ID); err != nil {
+			g.Log.Error("failed to unpause container before restart", appName, err)
+			return nil, err
+		}
+	}
+	err = am.containerRuntime.ContainerRestart(c.ID, time.Second*10)
+	if err != nil {
+		g.Log.Error("failed to restart container", appName, err)
+		return nil, err
+	}
+	if app.Paused {
+		app.Paused = false
+		if err := am.storeApp(app); err != nil {
+			return nil, err
+		}
+	}
+	am.stateCache.Refresh(appName)
+	return am.Info(appName)
+}
 
-	return &status, nil
+// Pause - pauses the app container. Container and datastore record are kept.
+func (am *AppProcessManager) Pause(appName string) (*models.AppProcess, error) {
+	app, err := am.storedApp(appName)
+	if err != nil {
+		return nil, err
+	}
+	c, err := am.stateCache.Get(appName)
+	if err != nil {
+		return nil, err
+	}
+	if c.State == nil || !c.State.Paused {
+		if err := am.containerRuntime.ContainerPause(c.ID); err != nil {
+			g.Log.Error("failed to pause container", appName, err)
+			return nil, err
+		}
+	}
+	if !app.Paused {
+		app.Paused = true
+		if err := am.storeApp(app); err != nil {
+			return nil, err
+		}
+	}
+	am.stateCache.Refresh(appName)
+	return am.Info(appName)
+}
+
+// Resume - unpauses the paused app container
+func (am *AppProcessManager) Resume(appName string) (*models.AppProcess, error) {
+	app, err := am.storedApp(appName)
+	if err != nil {
+		return nil, err
+	}
+	c, err := am.stateCache.Get(appName)
+	if err != nil {
+		return nil, err
+	}
+	if c.State != nil && c.State.Paused {
+		if err := am.containerRuntime.ContainerUnpause(c.ID); err != nil {
+			g.Log.Error("failed to unpause container", appName, err)
+			return nil, err
+		}
+	}
+	if app.Paused {
+		app.Paused = false
+		if err := am.storeApp(app); err != nil {
+			return nil, err
+		}
+	}
+	am.stateCache.Refresh(appName)
+	return am.Info(appName)
+}
+
+// storedApp - app as stored in the datastore
+func (am *AppProcessManager) storedApp(appName string) (*models.AppProcess, error) {
+	b, err := am.storage.Get(models.PrefixAppProcess, appName)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, models.ErrProcessNotFoundDatastore
+		}
+		g.Log.Error("failed to retrieve app from datastore", appName, err)
+		return nil, err
+	}
+	var app models.AppProcess
+	err = json.Unmarshal(b, &app)
+	if err != nil {
+		g.Log.Error("failed to unmarshal stored app", err)
+		return nil, err
+	}
+	return &app, nil
+}
+
+func (am *AppProcessManager) storeApp(app *models.AppProcess) error {
+	app.Modified = time.Now().Unix() * 1000
+	b, err := json.Marshal(app)
+	if err != nil {
+		g.Log.Error("failed to marshal app", err)
+		return err
+	}
+	err = am.storage.Put(models.PrefixAppProcess, app.Name, b)
+	if err != nil {
+		g.Log.Error("failed to store app", err)
+		return err
+	}
+	return nil
 }
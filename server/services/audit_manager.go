@@ -0,0 +1,303 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/rs/xid"
+)
+
+const (
+	defaultAuditMaxRecords    = 100000
+	defaultAuditRetentionDays = 90
+	defaultAuditQueryLimit    = 100
+	auditPruneInterval        = time.Hour
+)
+
+// datastore records of audited targets (counting zones are keyed by device/zone)
+var auditTargetPrefixes = map[string]string{
+	models.ProcessTypeRTSP:         models.PrefixRTSPProcess,
+	models.ProcessTypeApplication:  models.PrefixAppProcess,
+	models.AuditTargetSettings:     models.PrefixSettingsKey,
+	models.AuditTargetCountingZone: models.PrefixCountingZone,
+	models.AuditTargetRule:         models.PrefixRule,
+	models.AuditTargetPrivacyMask:  models.PrefixPrivacyMask,
+	models.AuditTargetUser:         models.PrefixUser,
+	models.AuditTargetAPIKey:       models.PrefixAPIKey,
+}
+
+// field names (or their parts) never stored in the audit log
+var auditSensitiveFields = []string{"password", "secret", "private", "key_hash", "token"}
+
+// fields not worth comparing (container logs copied into the record)
+var auditIgnoredFields = map[string]bool{"logs": true}
+
+// AuditManager - audit trail of operations stored in the datastore
+type AuditManager struct {
+	storage *Storage
+}
+
+func NewAuditManager(storage *Storage) *AuditManager {
+	return &AuditManager{
+		storage: storage,
+	}
+}
+
+// Record stores the audit record (ID and created time are set if missing)
+func (am *AuditManager) Record(record *models.AuditRecord) error {
+	if record.ID == "" {
+		record.ID = xid.New().String()
+	}
+	if record.Created == 0 {
+		record.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		g.Log.Error("failed to marshal audit record", err)
+		return err
+	}
+	err = am.storage.Put(models.PrefixAudit, auditKey(record), b)
+	if err != nil {
+		g.Log.Error("failed to store audit record", record.Action, record.Target, err)
+		return err
+	}
+	return nil
+}
+
+// RecordChange stores the audit record with the changes between the target before and after the operation (see Snapshot)
+func (am *AuditManager) RecordChange(record *models.AuditRecord, before []byte, after []byte) error {
+	record.Changes = auditChanges(before, after)
+	return am.Record(record)
+}
+
+// Snapshot returns the stored target (nil if it doesn't exist or isn't stored in the datastore)
+func (am *AuditManager) Snapshot(targetType string, target string) []byte {
+	prefix, ok := auditTargetPrefixes[targetType]
+	if !ok || target == "" {
+		return nil
+	}
+	b, err := am.storage.Get(prefix, target)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// Query returns audit records matching the query, newest first (records are scanned newest first within the time range until the page is full)
+func (am *AuditManager) Query(query *models.AuditQuery) ([]*models.AuditRecord, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditQueryLimit
+	}
+	from, to := "", ""
+	if query.From > 0 {
+		from = auditTimeKey(query.From)
+	}
+	if query.To > 0 {
+		// keys of the last millisecond before To, followed by the ID
+		to = auditTimeKey(query.To-1) + "~"
+	}
+	records := make([]*models.AuditRecord, 0)
+	err := am.storage.IterateRange(models.PrefixAudit, from, to, true, func(key string, value []byte) bool {
+		var r models.AuditRecord
+		if err := json.Unmarshal(value, &r); err != nil {
+			g.Log.Error("failed to unmarshal audit record", key, err)
+			return true
+		}
+		if (query.Actor != "" && r.Actor != query.Actor) ||
+			(query.Source != "" && r.Source != query.Source) ||
+			(query.Action != "" && r.Action != query.Action) ||
+			(query.TargetType != "" && r.TargetType != query.TargetType) ||
+			(query.Target != "" && r.Target != query.Target) ||
+			(query.Result != "" && r.Result != query.Result) {
+			return true
+		}
+		records = append(records, &r)
+		return len(records) < limit
+	})
+	if err != nil {
+		g.Log.Error("failed to query audit records", err)
+		return nil, err
+	}
+	return records, nil
+}
+
+// Start removes audit records outside of the retention limits every hour (and on boot)
+func (am *AuditManager) Start() {
+	go func() {
+		for {
+			if _, err := am.Prune(); err != nil {
+				g.Log.Error("audit log retention failed", err)
+			}
+			time.Sleep(auditPruneInterval)
+		}
+	}()
+}
+
+// Prune removes records older than retention days and the oldest records beyond max records. Returns the number of removed records.
+func (am *AuditManager) Prune() (int, error) {
+	maxRecords, retentionDays := defaultAuditMaxRecords, defaultAuditRetentionDays
+	if g.Conf.Audit != nil {
+		maxRecords, retentionDays = g.Conf.Audit.MaxRecords, g.Conf.Audit.RetentionDays
+	}
+	oldest := ""
+	if retentionDays > 0 {
+		oldest = models.PrefixAudit + auditTimeKey(time.Now().Add(-time.Duration(retentionDays)*24*time.Hour).UnixNano()/int64(time.Millisecond))
+	}
+	// keys start with the creation time, newest are kept (values aren't read)
+	remove := make([]string, 0)
+	kept := 0
+	err := am.storage.IterateKeysRange(models.PrefixAudit, "", "", true, func(key string) bool {
+		if (maxRecords > 0 && kept >= maxRecords) || key < oldest {
+			remove = append(remove, strings.TrimPrefix(key, models.PrefixAudit))
+		} else {
+			kept++
+		}
+		return true
+	})
+	if err != nil {
+		g.Log.Error("failed to list audit records", err)
+		return 0, err
+	}
+	for _, k := range remove {
+		if err := am.storage.Del(models.PrefixAudit, k); err != nil {
+			g.Log.Error("failed to remove audit record", k, err)
+			return 0, err
+		}
+	}
+	if len(remove) > 0 {
+		g.Log.Info("audit records removed by retention", len(remove))
+	}
+	return len(remove), nil
+}
+
+// List returns audit records, newest first
+func (am *AuditManager) List() ([]*models.AuditRecord, error) {
+	records := make([]*models.AuditRecord, 0)
+	err := am.storage.IterateRange(models.PrefixAudit, "", "", true, func(key string, value []byte) bool {
+		var record models.AuditRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			g.Log.Error("failed to unmarshal audit record", key, err)
+			return true
+		}
+		records = append(records, &record)
+		return true
+	})
+	if err != nil {
+		g.Log.Error("failed to list audit records", err)
+		return nil, err
+	}
+	return records, nil
+}
+
+// records are keyed by creation time (sortable) and ID
+func auditKey(record *models.AuditRecord) string {
+	return auditTimeKey(record.Created) + "_" + record.ID
+}
+
+// auditTimeKey - sortable creation time part of the key
+func auditTimeKey(created int64) string {
+	return fmt.Sprintf("%013d", created)
+}
+
+// auditChanges compares the JSON of the target before and after the operation field by field
+func auditChanges(before []byte, after []byte) []*models.AuditChange {
+	if len(before) == 0 && len(after) == 0 {
+		return nil
+	}
+	beforeFields := make(map[string]interface{})
+	afterFields := make(map[string]interface{})
+	flattenAuditFields("", unmarshalAuditTarget(before), beforeFields)
+	flattenAuditFields("", unmarshalAuditTarget(after), afterFields)
+
+	changes := make([]*models.AuditChange, 0)
+	for field, b := range beforeFields {
+		a, ok := afterFields[field]
+		if !ok || !reflect.DeepEqual(a, b) {
+			changes = append(changes, &models.AuditChange{Field: field, Before: redactAuditField(field, b), After: redactAuditField(field, a)})
+		}
+	}
+	for field, a := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes = append(changes, &models.AuditChange{Field: field, After: redactAuditField(field, a)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func unmarshalAuditTarget(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		// not JSON, compared as a whole
+		return string(b)
+	}
+	return v
+}
+
+// flattenAuditFields joins nested object fields with dots, arrays are compared as a whole
+func flattenAuditFields(path string, v interface{}, fields map[string]interface{}) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		if v != nil {
+			fields[path] = v
+		}
+		return
+	}
+	for k, fv := range obj {
+		if path == "" && auditIgnoredFields[k] {
+			continue
+		}
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		flattenAuditFields(p, fv, fields)
+	}
+}
+
+func redactAuditField(field string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	lower := strings.ToLower(field)
+	for _, s := range auditSensitiveFields {
+		if strings.Contains(lower, s) {
+			return models.AuditRedacted
+		}
+	}
+	// credentials in RTSP and RTMP URLs
+	if str, ok := v.(string); ok && strings.Contains(str, "://") && strings.Contains(str, "@") {
+		if u, err := url.Parse(str); err == nil && u.User != nil {
+			if _, hasPassword := u.User.Password(); hasPassword {
+				u.User = url.UserPassword(u.User.Username(), "xxxxx")
+				return u.String()
+			}
+		}
+	}
+	return v
+}
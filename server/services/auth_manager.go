@@ -0,0 +1,438 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/rs/xid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultTokenExpiration = time.Hour * 12
+	authSecretKey          = "jwt"
+	apiKeyPrefix           = "chrys_" // API keys are chrys_<id>.<secret>
+	// authentication is required when conf.yaml has no auth section (custom configs from older versions)
+	authEnabledByDefault = true
+)
+
+// AuthManager - local users, API keys and tokens for the REST API
+type AuthManager struct {
+	storage *Storage
+	mux     sync.Mutex
+	secret  []byte // token signing secret (generated on first use)
+}
+
+// authClaims - claims of tokens issued after login
+type authClaims struct {
+	Role    string `json:"role"`
+	Version int64  `json:"ver,omitempty"` // token version of the user at login
+	jwt.StandardClaims
+}
+
+func NewAuthManager(storage *Storage) *AuthManager {
+	return &AuthManager{
+		storage: storage,
+	}
+}
+
+// Enabled - authentication is required unless disabled in conf.yaml
+func (am *AuthManager) Enabled() bool {
+	if g.Conf.Auth == nil {
+		return authEnabledByDefault
+	}
+	return g.Conf.Auth.Enabled
+}
+
+// Status - if authentication is enabled and if the first admin account still has to be created
+func (am *AuthManager) Status() (*models.AuthStatus, error) {
+	users, err := am.storage.List(models.PrefixUser)
+	if err != nil {
+		g.Log.Error("failed to list users", err)
+		return nil, err
+	}
+	return &models.AuthStatus{Enabled: am.Enabled(), SetupRequired: len(users) == 0}, nil
+}
+
+// Setup - creates the first admin account (only allowed while there are no users)
+func (am *AuthManager) Setup(username string, password string) (*models.User, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+	users, err := am.storage.List(models.PrefixUser)
+	if err != nil {
+		g.Log.Error("failed to list users", err)
+		return nil, err
+	}
+	if len(users) > 0 {
+		return nil, models.ErrForbidden
+	}
+	return am.createUser(username, password, models.RoleAdmin)
+}
+
+// CreateUser - creates a new local user
+func (am *AuthManager) CreateUser(username string, password string, role string) (*models.User, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+	if _, err := am.storage.Get(models.PrefixUser, username); err == nil {
+		return nil, models.ErrProcessConflict
+	}
+	return am.createUser(username, password, role)
+}
+
+func (am *AuthManager) createUser(username string, password string, role string) (*models.User, error) {
+	if len(username) < 3 || len(password) < models.MinPasswordLength {
+		return nil, models.ErrStringTooShort
+	}
+	if _, ok := models.RoleLevels[role]; !ok || strings.ContainsAny(username, "/ ") {
+		return nil, models.ErrInvalidInputParameters
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		g.Log.Error("failed to hash password", err)
+		return nil, err
+	}
+	now := time.Now().Unix() * 1000
+	user := &models.User{
+		Username:     username,
+		Role:         role,
+		PasswordHash: string(hash),
+		Created:      now,
+		Modified:     now,
+	}
+	if err := am.putUser(user); err != nil {
+		return nil, err
+	}
+	user.PasswordHash = ""
+	return user, nil
+}
+
+// ListUsers - all local users (without password hashes)
+func (am *AuthManager) ListUsers() ([]*models.User, error) {
+	objects, err := am.storage.List(models.PrefixUser)
+	if err != nil {
+		g.Log.Error("failed to list users", err)
+		return nil, err
+	}
+	users := make([]*models.User, 0, len(objects))
+	for _, v := range objects {
+		var user models.User
+		if err := json.Unmarshal(v, &user); err != nil {
+			g.Log.Error("failed to unmarshal user", err)
+			continue
+		}
+		user.PasswordHash = ""
+		users = append(users, &user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
+
+// DeleteUser - removes the user (the last admin can't be removed)
+func (am *AuthManager) DeleteUser(username string) error {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+	user, err := am.getUser(username)
+	if err != nil {
+		return err
+	}
+	if user.Role == models.RoleAdmin {
+		users, err := am.ListUsers()
+		if err != nil {
+			return err
+		}
+		admins := 0
+		for _, u := range users {
+			if u.Role == models.RoleAdmin {
+				admins++
+			}
+		}
+		if admins <= 1 {
+			return models.ErrForbidden
+		}
+	}
+	return am.storage.Del(models.PrefixUser, username)
+}
+
+// ChangePassword - changes the password after verifying the old one
+func (am *AuthManager) ChangePassword(username string, oldPassword string, newPassword string) error {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+	user, err := am.getUser(username)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)) != nil {
+		return models.ErrUnauthorized
+	}
+	if len(newPassword) < models.MinPasswordLength {
+		return models.ErrStringTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		g.Log.Error("failed to hash password", err)
+		return err
+	}
+	user.PasswordHash = string(hash)
+	user.Modified = time.Now().Unix() * 1000
+	// tokens issued before the change are refused
+	user.TokenVersion++
+	return am.putUser(user)
+}
+
+// Login - issues a signed token for valid username and password
+func (am *AuthManager) Login(username string, password string) (*models.AuthToken, error) {
+	user, err := am.getUser(username)
+	if err != nil {
+		// same cost as a wrong password, doesn't reveal if the user exists
+		bcrypt.CompareHashAndPassword([]byte("$2a$10$7EqJtq98hPqEX7fNZaFWoOhi5BWX4Z3ZjLsd1lZ7YVJ3l4IqH2Hry"), []byte(password))
+		return nil, models.ErrUnauthorized
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, models.ErrUnauthorized
+	}
+	secret, err := am.signingSecret()
+	if err != nil {
+		return nil, err
+	}
+	expiration := defaultTokenExpiration
+	if g.Conf.Auth != nil && g.Conf.Auth.TokenExpirationMinutes > 0 {
+		expiration = time.Duration(g.Conf.Auth.TokenExpirationMinutes) * time.Minute
+	}
+	now := time.Now()
+	expiresAt := now.Add(expiration)
+	claims := &authClaims{
+		Role:    user.Role,
+		Version: user.TokenVersion,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.Username,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		g.Log.Error("failed to sign token", err)
+		return nil, err
+	}
+	return &models.AuthToken{
+		Token:     token,
+		ExpiresAt: expiresAt.Unix() * 1000,
+		Username:  user.Username,
+		Role:      user.Role,
+	}, nil
+}
+
+// Authenticate - principal of the token issued after login or of the API key.
+// Roles are always read from the datastore so removed users and keys lose access immediately,
+// tokens issued before a password change are refused.
+func (am *AuthManager) Authenticate(credential string) (*models.Principal, error) {
+	if credential == "" {
+		return nil, models.ErrUnauthorized
+	}
+	if strings.HasPrefix(credential, apiKeyPrefix) {
+		return am.authenticateAPIKey(credential)
+	}
+	secret, err := am.signingSecret()
+	if err != nil {
+		return nil, err
+	}
+	var claims authClaims
+	token, err := jwt.ParseWithClaims(credential, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, models.ErrUnauthorized
+		}
+		return secret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, models.ErrUnauthorized
+	}
+	user, err := am.getUser(claims.Subject)
+	if err != nil || claims.Version != user.TokenVersion {
+		return nil, models.ErrUnauthorized
+	}
+	return &models.Principal{Name: user.Username, Role: user.Role, Method: models.AuthMethodPassword}, nil
+}
+
+// CreateAPIKey - new API key (the key is only returned here, only its hash is stored)
+func (am *AuthManager) CreateAPIKey(name string, role string) (*models.APIKey, error) {
+	if _, ok := models.RoleLevels[role]; !ok || name == "" {
+		return nil, models.ErrInvalidInputParameters
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	secretHex := hex.EncodeToString(secret)
+	key := &models.APIKey{
+		ID:      xid.New().String(),
+		Name:    name,
+		Role:    role,
+		KeyHash: hashAPIKeySecret(secretHex),
+		Created: time.Now().Unix() * 1000,
+	}
+	if err := am.putAPIKey(key); err != nil {
+		return nil, err
+	}
+	key.Key = apiKeyPrefix + key.ID + "." + secretHex
+	key.KeyHash = ""
+	return key, nil
+}
+
+// ListAPIKeys - all API keys (without keys and hashes)
+func (am *AuthManager) ListAPIKeys() ([]*models.APIKey, error) {
+	objects, err := am.storage.List(models.PrefixAPIKey)
+	if err != nil {
+		g.Log.Error("failed to list api keys", err)
+		return nil, err
+	}
+	keys := make([]*models.APIKey, 0, len(objects))
+	for _, v := range objects {
+		var key models.APIKey
+		if err := json.Unmarshal(v, &key); err != nil {
+			g.Log.Error("failed to unmarshal api key", err)
+			continue
+		}
+		key.KeyHash = ""
+		keys = append(keys, &key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created < keys[j].Created })
+	return keys, nil
+}
+
+// DeleteAPIKey - revokes the API key
+func (am *AuthManager) DeleteAPIKey(id string) error {
+	if _, err := am.storage.Get(models.PrefixAPIKey, id); err != nil {
+		if err == badger.ErrKeyNotFound {
+			return models.ErrProcessNotFoundDatastore
+		}
+		return err
+	}
+	return am.storage.Del(models.PrefixAPIKey, id)
+}
+
+func (am *AuthManager) authenticateAPIKey(credential string) (*models.Principal, error) {
+	parts := strings.SplitN(strings.TrimPrefix(credential, apiKeyPrefix), ".", 2)
+	if len(parts) != 2 {
+		return nil, models.ErrUnauthorized
+	}
+	b, err := am.storage.Get(models.PrefixAPIKey, parts[0])
+	if err != nil {
+		return nil, models.ErrUnauthorized
+	}
+	var key models.APIKey
+	if err := json.Unmarshal(b, &key); err != nil {
+		g.Log.Error("failed to unmarshal api key", err)
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKeySecret(parts[1]))) != 1 {
+		return nil, models.ErrUnauthorized
+	}
+	// last used is informative only, at most once per minute
+	now := time.Now().Unix() * 1000
+	if now-key.LastUsed > 60*1000 {
+		key.LastUsed = now
+		if err := am.putAPIKey(&key); err != nil {
+			g.Log.Warn("failed to store api key last used time", key.ID, err)
+		}
+	}
+	return &models.Principal{Name: key.Name, Role: key.Role, Method: models.AuthMethodAPIKey}, nil
+}
+
+func (am *AuthManager) getUser(username string) (*models.User, error) {
+	b, err := am.storage.Get(models.PrefixUser, username)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, models.ErrProcessNotFoundDatastore
+		}
+		g.Log.Error("failed to retrieve user", username, err)
+		return nil, err
+	}
+	var user models.User
+	if err := json.Unmarshal(b, &user); err != nil {
+		g.Log.Error("failed to unmarshal user", err)
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (am *AuthManager) putUser(user *models.User) error {
+	b, err := json.Marshal(user)
+	if err != nil {
+		g.Log.Error("failed to marshal user", err)
+		return err
+	}
+	if err := am.storage.Put(models.PrefixUser, user.Username, b); err != nil {
+		g.Log.Error("failed to store user", user.Username, err)
+		return err
+	}
+	return nil
+}
+
+func (am *AuthManager) putAPIKey(key *models.APIKey) error {
+	b, err := json.Marshal(key)
+	if err != nil {
+		g.Log.Error("failed to marshal api key", err)
+		return err
+	}
+	if err := am.storage.Put(models.PrefixAPIKey, key.ID, b); err != nil {
+		g.Log.Error("failed to store api key", key.ID, err)
+		return err
+	}
+	return nil
+}
+
+// signingSecret - token signing secret, generated and stored on first use
+func (am *AuthManager) signingSecret() ([]byte, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+	if am.secret != nil {
+		return am.secret, nil
+	}
+	secret, err := am.storage.Get(models.PrefixAuthSecret, authSecretKey)
+	if err != nil {
+		if err != badger.ErrKeyNotFound {
+			g.Log.Error("failed to retrieve token signing secret", err)
+			return nil, err
+		}
+		secret = make([]byte, 64)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		if err := am.storage.Put(models.PrefixAuthSecret, authSecretKey, secret); err != nil {
+			g.Log.Error("failed to store token signing secret", err)
+			return nil, err
+		}
+	}
+	am.secret = secret
+	return secret, nil
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
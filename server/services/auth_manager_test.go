@@ -0,0 +1,115 @@
+package services
+
+import (
+	"testing"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+func TestAuthUsersAndAPIKeys(t *testing.T) {
+	conf := g.Conf
+	defer func() { g.Conf = conf }()
+	g.Conf.Auth = &g.AuthSubconfig{Enabled: true, TokenExpirationMinutes: 5}
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	am := NewAuthManager(NewStorage(db))
+
+	status, err := am.Status()
+	if err != nil || !status.SetupRequired {
+		t.Fatalf("expected setup required, got %v %v", status, err)
+	}
+	if _, err := am.Setup("admin", "short"); err != models.ErrStringTooShort {
+		t.Fatalf("expected too short password, got %v", err)
+	}
+	if _, err := am.Setup("admin", "supersecret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := am.Setup("other", "supersecret"); err != models.ErrForbidden {
+		t.Fatalf("expected second setup to be refused, got %v", err)
+	}
+
+	if _, err := am.Login("admin", "wrongpassword"); err != models.ErrUnauthorized {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+	token, err := am.Login("admin", "supersecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, err := am.Authenticate(token.Token)
+	if err != nil || principal.Name != "admin" || principal.Role != models.RoleAdmin || principal.Method != models.AuthMethodPassword {
+		t.Fatalf("unexpected principal %v %v", principal, err)
+	}
+	if _, err := am.Authenticate(token.Token + "x"); err != models.ErrUnauthorized {
+		t.Fatalf("expected tampered token to be refused, got %v", err)
+	}
+
+	if _, err := am.CreateUser("watcher", "watchpassword", "superuser"); err != models.ErrInvalidInputParameters {
+		t.Fatalf("expected invalid role, got %v", err)
+	}
+	if _, err := am.CreateUser("watcher", "watchpassword", models.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	users, err := am.ListUsers()
+	if err != nil || len(users) != 2 || users[0].PasswordHash != "" {
+		t.Fatalf("expected 2 users without password hashes, got %v %v", users, err)
+	}
+	watcherToken, err := am.Login("watcher", "watchpassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := am.ChangePassword("watcher", "watchpassword", "newpassword"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := am.Login("watcher", "watchpassword"); err != models.ErrUnauthorized {
+		t.Fatalf("expected old password to be refused, got %v", err)
+	}
+	// tokens issued before the password change are refused
+	if _, err := am.Authenticate(watcherToken.Token); err != models.ErrUnauthorized {
+		t.Fatalf("expected unauthorized for token issued before password change, got %v", err)
+	}
+	watcherToken, err = am.Login("watcher", "newpassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := am.Authenticate(watcherToken.Token); err != nil {
+		t.Fatalf("expected token issued after password change to be valid, got %v", err)
+	}
+	if err := am.DeleteUser("watcher"); err != nil {
+		t.Fatal(err)
+	}
+	// tokens of removed users are refused
+	if _, err := am.Authenticate(watcherToken.Token); err != models.ErrUnauthorized {
+		t.Fatalf("expected unauthorized for removed user, got %v", err)
+	}
+	if err := am.DeleteUser("admin"); err != models.ErrForbidden {
+		t.Fatalf("expected last admin to be kept, got %v", err)
+	}
+
+	key, err := am.CreateAPIKey("ci", models.RoleOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, err = am.Authenticate(key.Key)
+	if err != nil || principal.Name != "ci" || principal.Role != models.RoleOperator || principal.Method != models.AuthMethodAPIKey {
+		t.Fatalf("unexpected api key principal %v %v", principal, err)
+	}
+	keys, err := am.ListAPIKeys()
+	if err != nil || len(keys) != 1 || keys[0].Key != "" || keys[0].KeyHash != "" || keys[0].LastUsed == 0 {
+		t.Fatalf("unexpected api keys %v %v", keys, err)
+	}
+	if err := am.DeleteAPIKey(key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := am.Authenticate(key.Key); err != models.ErrUnauthorized {
+		t.Fatalf("expected revoked key to be refused, got %v", err)
+	}
+
+	if !models.HasRole(models.RoleAdmin, models.RoleOperator) || models.HasRole(models.RoleViewer, models.RoleOperator) {
+		t.Fatal("unexpected role hierarchy")
+	}
+}
@@ -0,0 +1,481 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/go-redis/redis/v7"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigTransferManager - bulk export and import of camera and app definitions
+type ConfigTransferManager struct {
+	storage         *Storage
+	processManager  *ProcessManager
+	appManager      *AppProcessManager
+	settingsManager *SettingsManager
+	rdb             *redis.Client
+	cameraImage     func() (*models.ImageUpgrade, error) // highest local camera image (replaced in tests, checks docker hub)
+}
+
+func NewConfigTransferManager(storage *Storage, processManager *ProcessManager, appManager *AppProcessManager, settingsManager *SettingsManager, rdb *redis.Client) *ConfigTransferManager {
+	return &ConfigTransferManager{
+		storage:         storage,
+		processManager:  processManager,
+		appManager:      appManager,
+		settingsManager: settingsManager,
+		rdb:             rdb,
+		cameraImage: func() (*models.ImageUpgrade, error) {
+			return settingsManager.ListDockerImages(models.CameraTypeToImageTag["rtsp"])
+		},
+	}
+}
+
+// Export - all stored camera and app definitions sorted by name, secrets replaced with xxxxx when redacted
+func (cm *ConfigTransferManager) Export(redact bool) (*models.ConfigBundle, error) {
+	bundle := &models.ConfigBundle{
+		Version:  models.ConfigBundleVersion,
+		Exported: time.Now().Unix() * 1000,
+		Redacted: redact,
+		Cameras:  make([]*models.StreamProcess, 0),
+		Apps:     make([]*models.AppProcess, 0),
+	}
+	cameras, err := cm.storage.List(models.PrefixRTSPProcess)
+	if err != nil {
+		g.Log.Error("failed to list cameras", err)
+		return nil, err
+	}
+	for _, v := range cameras {
+		var process models.StreamProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("failed to unmarshal camera", err)
+			return nil, err
+		}
+		// exported endpoints include the credentials (stored separately and encrypted)
+		rtspEndpoint, err := cm.processManager.rtspEndpointWithCredentials(&process)
+		if err != nil {
+			return nil, err
+		}
+		camera := &models.StreamProcess{
+			Name:         process.Name,
+			ImageTag:     process.ImageTag,
+			RTSPEndpoint: rtspEndpoint,
+			RTMPEndpoint: process.RTMPEndpoint,
+			Location:     process.Location,
+			Group:        process.Group,
+			Labels:       process.Labels,
+			Description:  process.Description,
+		}
+		if redact {
+			camera.RTSPEndpoint = utils.RedactURL(camera.RTSPEndpoint)
+			camera.RTMPEndpoint = redactRTMPKey(utils.RedactURL(camera.RTMPEndpoint))
+		}
+		bundle.Cameras = append(bundle.Cameras, camera)
+	}
+	apps, err := cm.storage.List(models.PrefixAppProcess)
+	if err != nil {
+		g.Log.Error("failed to list apps", err)
+		return nil, err
+	}
+	for _, v := range apps {
+		var process models.AppProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("failed to unmarshal app", err)
+			return nil, err
+		}
+		app := &models.AppProcess{
+			Name:                process.Name,
+			DockerHubUser:       process.DockerHubUser,
+			DockerhubRepository: process.DockerhubRepository,
+			DockerHubVersion:    process.DockerHubVersion,
+			EnvVars:             process.EnvVars,
+			ArgsVars:            process.ArgsVars,
+			PortMapping:         process.PortMapping,
+			MountFolders:        process.MountFolders,
+			Runtime:             process.Runtime,
+		}
+		if redact {
+			app.EnvVars = redactVarPairs(app.EnvVars)
+			app.ArgsVars = redactVarPairs(app.ArgsVars)
+		}
+		bundle.Apps = append(bundle.Apps, app)
+	}
+	sort.Slice(bundle.Cameras, func(i, j int) bool { return bundle.Cameras[i].Name < bundle.Cameras[j].Name })
+	sort.Slice(bundle.Apps, func(i, j int) bool { return bundle.Apps[i].Name < bundle.Apps[j].Name })
+	return bundle, nil
+}
+
+// Import - validates every camera and app and applies the valid ones (at most concurrency at the same time).
+// Existing cameras are updated in place when changed, existing apps are skipped. Nothing is applied on dry run.
+func (cm *ConfigTransferManager) Import(bundle *models.ConfigBundle, dryRun bool, concurrency int) *models.ImportReport {
+	if concurrency <= 0 {
+		concurrency = models.DefaultImportConcurrency
+	}
+	if concurrency > models.MaxImportConcurrency {
+		concurrency = models.MaxImportConcurrency
+	}
+	report := &models.ImportReport{
+		DryRun:  dryRun,
+		Total:   len(bundle.Cameras) + len(bundle.Apps),
+		Results: make([]*models.ImportItemResult, len(bundle.Cameras)+len(bundle.Apps)),
+	}
+
+	var imageOnce sync.Once
+	var image *models.ImageUpgrade
+	var imageErr error
+	cameraImage := func() (*models.ImageUpgrade, error) {
+		imageOnce.Do(func() { image, imageErr = cm.cameraImage() })
+		return image, imageErr
+	}
+
+	names := make(map[string]bool)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	apply := func(i int, result *models.ImportItemResult, validErr error, run func() (string, error)) {
+		report.Results[i] = result
+		if validErr != nil {
+			result.Result = models.ImportResultInvalid
+			result.Message = validErr.Error()
+			return
+		}
+		if names[result.Type+"/"+result.Name] {
+			result.Result = models.ImportResultInvalid
+			result.Message = "duplicate name"
+			return
+		}
+		names[result.Type+"/"+result.Name] = true
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res, err := run()
+			result.Result = res
+			if err != nil {
+				result.Result = models.ImportResultFailed
+				result.Message = err.Error()
+			}
+		}()
+	}
+
+	for i, camera := range bundle.Cameras {
+		camera := camera
+		err := validateImportedCamera(camera)
+		apply(i, &models.ImportItemResult{Type: models.ProcessTypeRTSP, Name: camera.Name}, err, func() (string, error) {
+			return cm.importCamera(camera, dryRun, cameraImage)
+		})
+	}
+	for i, app := range bundle.Apps {
+		app := app
+		err := validateImportedApp(app)
+		apply(len(bundle.Cameras)+i, &models.ImportItemResult{Type: models.ProcessTypeApplication, Name: app.Name}, err, func() (string, error) {
+			return cm.importApp(app, dryRun)
+		})
+	}
+	wg.Wait()
+
+	for _, r := range report.Results {
+		if r.Result == models.ImportResultInvalid || r.Result == models.ImportResultFailed {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+	g.Log.Info("configuration import finished", "dry run", dryRun, "succeeded", report.Succeeded, "failed", report.Failed)
+	return report
+}
+
+func (cm *ConfigTransferManager) importCamera(camera *models.StreamProcess, dryRun bool, cameraImage func() (*models.ImageUpgrade, error)) (string, error) {
+	stored, err := cm.processManager.storedProcess(camera.Name)
+	if err == nil {
+		storedEndpoint, err := cm.processManager.withEndpointCredentials(stored)
+		if err != nil {
+			return "", err
+		}
+		importedEndpoint, err := cm.processManager.withEndpointCredentials(camera)
+		if err != nil {
+			return "", err
+		}
+		if storedEndpoint.RTSPEndpoint == importedEndpoint.RTSPEndpoint && stored.RTMPEndpoint == camera.RTMPEndpoint && sameCameraMetadata(stored, camera) {
+			return models.ImportResultUnchanged, nil
+		}
+		if dryRun {
+			return models.ImportResultValid, nil
+		}
+		if _, err := cm.processManager.Update(camera.Name, camera); err != nil {
+			return "", err
+		}
+		cm.publish(camera.Name, models.DeviceOperationUpdate, models.ProcessTypeRTSP)
+		return models.ImportResultUpdated, nil
+	}
+	if err != models.ErrProcessNotFoundDatastore {
+		return "", err
+	}
+	if dryRun {
+		return models.ImportResultValid, nil
+	}
+	image, err := cameraImage()
+	if err != nil {
+		return "", err
+	}
+	process := &models.StreamProcess{
+		Name:         camera.Name,
+		RTSPEndpoint: camera.RTSPEndpoint,
+		RTSPUsername: camera.RTSPUsername,
+		RTSPPassword: camera.RTSPPassword,
+		RTMPEndpoint: camera.RTMPEndpoint,
+		Location:     camera.Location,
+		Group:        camera.Group,
+		Labels:       camera.Labels,
+		Description:  camera.Description,
+		RTMPStreamStatus: &models.RTMPStreamStatus{
+			Storing:   false,
+			Streaming: true,
+		},
+	}
+	if err := cm.processManager.Start(process, image, false); err != nil {
+		return "", err
+	}
+	cm.publish(camera.Name, models.DeviceOperationAdd, models.ProcessTypeRTSP)
+	return models.ImportResultCreated, nil
+}
+
+func (cm *ConfigTransferManager) importApp(app *models.AppProcess, dryRun bool) (string, error) {
+	_, err := cm.appManager.storedApp(app.Name)
+	if err == nil {
+		return models.ImportResultSkipped, nil
+	}
+	if err != models.ErrProcessNotFoundDatastore {
+		return "", err
+	}
+	if dryRun {
+		return models.ImportResultValid, nil
+	}
+	if err := cm.pullAppImage(app); err != nil {
+		return "", err
+	}
+	if _, err := cm.appManager.Install(app); err != nil {
+		return "", err
+	}
+	return models.ImportResultCreated, nil
+}
+
+// pullAppImage pulls the docker image of the app
+func (cm *ConfigTransferManager) pullAppImage(app *models.AppProcess) error {
+	_, err := cm.settingsManager.PullDockerImage(app.DockerHubUser+"/"+app.DockerhubRepository, app.DockerHubVersion)
+	return err
+}
+
+func (cm *ConfigTransferManager) publish(deviceID string, operation string, processType string) {
+	if cm.rdb == nil {
+		return
+	}
+	utils.PublishToRedis(cm.rdb, deviceID, models.MQTTProcessOperation(operation), processType, nil)
+}
+
+// validateImportedCamera requires RTSP endpoint without redacted secrets. Cameras without a name are named by the endpoint (same as single camera API).
+func validateImportedCamera(camera *models.StreamProcess) error {
+	if camera.RTSPEndpoint == "" {
+		return errors.New("rtsp endpoint required")
+	}
+	if _, err := url.Parse(camera.RTSPEndpoint); err != nil {
+		return fmt.Errorf("invalid rtsp endpoint: %v", err)
+	}
+	if camera.RTMPEndpoint != "" {
+		if _, err := url.Parse(camera.RTMPEndpoint); err != nil {
+			return fmt.Errorf("invalid rtmp endpoint: %v", err)
+		}
+	}
+	if strings.Contains(camera.RTSPEndpoint, models.RedactedSecret) || strings.Contains(camera.RTMPEndpoint, models.RedactedSecret) {
+		return errors.New("redacted secret must be replaced")
+	}
+	if camera.Name == "" {
+		camera.Name = fmt.Sprintf("%x", md5.Sum([]byte(camera.RTSPEndpoint)))
+	}
+	if strings.Contains(camera.Name, "/") {
+		return models.ErrInvalidInputParameters
+	}
+	if err := models.ValidateCameraMetadata(camera); err != nil {
+		return fmt.Errorf("invalid labels, group or location: %v", err)
+	}
+	return nil
+}
+
+func validateImportedApp(app *models.AppProcess) error {
+	if err := models.ValidateApp(app); err != nil {
+		return err
+	}
+	if strings.Contains(app.Name, "/") {
+		return models.ErrInvalidInputParameters
+	}
+	for _, p := range append(append([]*models.VarPair{}, app.EnvVars...), app.ArgsVars...) {
+		if p != nil && p.Value == models.RedactedSecret {
+			return errors.New("redacted secret must be replaced: " + p.Name)
+		}
+	}
+	return nil
+}
+
+// MarshalConfigBundle - bundle as JSON or YAML (same field names)
+func MarshalConfigBundle(bundle *models.ConfigBundle, format string) ([]byte, error) {
+	b, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", models.ConfigFormatJSON:
+		return b, nil
+	case models.ConfigFormatYAML:
+		// JSON is valid YAML, re-encoding keeps the JSON field names
+		var v yaml.MapSlice
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(v)
+	}
+	return nil, models.ErrInvalidInputParameters
+}
+
+// ParseConfigBundle - bundle from JSON, YAML or CSV (name, rtsp and rtmp columns with optional header)
+func ParseConfigBundle(data []byte, format string) (*models.ConfigBundle, error) {
+	var bundle models.ConfigBundle
+	switch format {
+	case models.ConfigFormatJSON:
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return nil, err
+		}
+	case models.ConfigFormatYAML:
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(yamlToJSONValue(v))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &bundle); err != nil {
+			return nil, err
+		}
+	case models.ConfigFormatCSV:
+		cameras, err := parseCamerasCSV(data)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Cameras = cameras
+	default:
+		return nil, models.ErrInvalidInputParameters
+	}
+	if bundle.Version > models.ConfigBundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	return &bundle, nil
+}
+
+func parseCamerasCSV(data []byte) ([]*models.StreamProcess, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	columns := map[string]int{"name": 0, "rtsp": 1, "rtmp": 2}
+	cameras := make([]*models.StreamProcess, 0)
+	for row := 0; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "name") {
+			columns = make(map[string]int)
+			for i, h := range record {
+				h = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(h)), "_endpoint")
+				columns[h] = i
+			}
+			continue
+		}
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		cameras = append(cameras, &models.StreamProcess{
+			Name:         column("name"),
+			RTSPEndpoint: column("rtsp"),
+			RTMPEndpoint: column("rtmp"),
+		})
+	}
+	return cameras, nil
+}
+
+// yamlToJSONValue converts YAML maps (interface keys) into JSON compatible maps
+func yamlToJSONValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprintf("%v", k)] = yamlToJSONValue(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = yamlToJSONValue(val)
+		}
+		return t
+	}
+	return v
+}
+
+// redactRTMPKey replaces the stream key (last path element) of the RTMP endpoint
+func redactRTMPKey(endpoint string) string {
+	key, err := utils.ParseRTMPKey(endpoint)
+	if err != nil || key == "" {
+		return endpoint
+	}
+	return strings.TrimSuffix(endpoint, key) + models.RedactedSecret
+}
+
+func redactVarPairs(pairs []*models.VarPair) []*models.VarPair {
+	redacted := make([]*models.VarPair, 0, len(pairs))
+	for _, p := range pairs {
+		value := p.Value
+		lower := strings.ToLower(p.Name)
+		for _, s := range []string{"password", "secret", "token", "key"} {
+			if strings.Contains(lower, s) {
+				value = models.RedactedSecret
+				break
+			}
+		}
+		redacted = append(redacted, &models.VarPair{Name: p.Name, Value: value})
+	}
+	return redacted
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// StreamLogs - streams camera container log lines. In follow mode streaming continues until the context is cancelled.
+func (pm *ProcessManager) StreamLogs(ctx context.Context, deviceID string, query *models.ContainerLogsQuery, found func(line *models.ContainerLogLine) error) error {
+	return streamContainerLogs(ctx, pm.stateCache, deviceID, query, found)
+}
+
+// StreamLogs - streams app container log lines. In follow mode streaming continues until the context is cancelled.
+func (am *AppProcessManager) StreamLogs(ctx context.Context, appName string, query *models.ContainerLogsQuery, found func(line *models.ContainerLogLine) error) error {
+	return streamContainerLogs(ctx, am.stateCache, appName, query, found)
+}
+
+func streamContainerLogs(ctx context.Context, stateCache *ProcessStateCache, name string, query *models.ContainerLogsQuery, found func(line *models.ContainerLogLine) error) error {
+	if !query.Stdout && !query.Stderr {
+		return models.ErrInvalidInputParameters
+	}
+	c, err := stateCache.Get(name)
+	if err != nil {
+		return err
+	}
+	options := types.ContainerLogsOptions{
+		ShowStdout: query.Stdout,
+		ShowStderr: query.Stderr,
+		Follow:     query.Follow,
+		Since:      query.Since,
+		Until:      query.Until,
+		Tail:       query.Tail,
+		Timestamps: true,
+	}
+	if options.Tail == "" {
+		options.Tail = models.DefaultLogTail
+	}
+
+	rc, err := stateCache.containerRuntime.ContainerLogsStream(ctx, c.ID, options)
+	if err != nil {
+		g.Log.Error("failed to stream container logs", name, err)
+		return err
+	}
+	defer rc.Close()
+
+	stdout := &logLineWriter{stream: models.LogStreamStdout, found: found}
+	stderr := &logLineWriter{stream: models.LogStreamStderr, found: found}
+	_, err = stdcopy.StdCopy(stdout, stderr, rc)
+	if ctx.Err() != nil {
+		// client went away or stopped following
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := stdout.flush(); err != nil {
+		return err
+	}
+	return stderr.flush()
+}
+
+// logLineWriter splits the demultiplexed container output into lines
+type logLineWriter struct {
+	stream string
+	found  func(line *models.ContainerLogLine) error
+	buf    []byte
+}
+
+func (lw *logLineWriter) Write(p []byte) (int, error) {
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(lw.buf[:i])
+		lw.buf = lw.buf[i+1:]
+		if err := lw.found(parseLogLine(lw.stream, line)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush reports the last line without a trailing newline
+func (lw *logLineWriter) flush() error {
+	if len(lw.buf) == 0 {
+		return nil
+	}
+	line := string(lw.buf)
+	lw.buf = nil
+	return lw.found(parseLogLine(lw.stream, line))
+}
+
+// parseLogLine separates the timestamp docker prefixes each line with
+func parseLogLine(stream string, line string) *models.ContainerLogLine {
+	line = strings.TrimSuffix(line, "\r")
+	logLine := &models.ContainerLogLine{Stream: stream, Line: line}
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) == 2 {
+		if _, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+			logLine.Timestamp = parts[0]
+			logLine.Line = parts[1]
+		}
+	}
+	return logLine
+}
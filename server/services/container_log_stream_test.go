@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestStreamContainerLogs(t *testing.T) {
+	rt := NewMemoryRuntime()
+	rt.AddImage("chryscloud/chrysedgeproxy:0.0.7")
+	if err := rt.ContainerCreate("frontdoor", &container.Config{Image: "chryscloud/chrysedgeproxy:0.0.7"}, &container.HostConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.ContainerStart("frontdoor"); err != nil {
+		t.Fatal(err)
+	}
+	rt.AppendLogs("frontdoor", "line 1\nline 2\nline 3\n")
+	stateCache := NewProcessStateCache(rt)
+
+	lines := make([]*models.ContainerLogLine, 0)
+	query := &models.ContainerLogsQuery{Tail: "2", Stdout: true, Stderr: true}
+	err := streamContainerLogs(context.Background(), stateCache, "frontdoor", query, func(line *models.ContainerLogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0].Line != "line 2" || lines[1].Line != "line 3" || lines[0].Stream != models.LogStreamStdout {
+		t.Fatalf("expected last 2 stdout lines, got %v", lines)
+	}
+
+	// stdout filtered out
+	query = &models.ContainerLogsQuery{Tail: "all", Stderr: true}
+	err = streamContainerLogs(context.Background(), stateCache, "frontdoor", query, func(line *models.ContainerLogLine) error {
+		t.Fatalf("unexpected line %v", line)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// follow receives new lines until cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan *models.ContainerLogLine, 10)
+	done := make(chan error)
+	go func() {
+		query := &models.ContainerLogsQuery{Follow: true, Tail: "0", Stdout: true}
+		done <- streamContainerLogs(ctx, stateCache, "frontdoor", query, func(line *models.ContainerLogLine) error {
+			received <- line
+			return nil
+		})
+	}()
+	time.Sleep(time.Millisecond * 50)
+	rt.AppendLogs("frontdoor", "line 4\n")
+	select {
+	case line := <-received:
+		if line.Line != "line 4" {
+			t.Fatalf("expected followed line, got %v", line.Line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("followed line not received")
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("log stream not stopped after cancel")
+	}
+
+	if err := streamContainerLogs(context.Background(), stateCache, "backyard", &models.ContainerLogsQuery{Stdout: true}, nil); err != models.ErrProcessNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	line := parseLogLine(models.LogStreamStderr, "2020-11-19T08:11:42.123456789Z failed to connect to RTSP camera\r")
+	if line.Timestamp != "2020-11-19T08:11:42.123456789Z" || line.Line != "failed to connect to RTSP camera" {
+		t.Fatalf("unexpected parsed line %v", line)
+	}
+	line = parseLogLine(models.LogStreamStdout, "no timestamp here")
+	if line.Timestamp != "" || line.Line != "no timestamp here" {
+		t.Fatalf("unexpected parsed line %v", line)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/chryscloud/go-microkit-plugins/docker"
+	microModelDocker "github.com/chryscloud/go-microkit-plugins/models/docker"
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+const (
+	RuntimeDocker = "docker" // docker daemon (local socket or remote host)
+	RuntimeMemory = "memory" // in-memory runtime (no containers are actually run)
+
+	defaultRuntimeHost       = "unix:///var/run/docker.sock"
+	defaultRuntimeAPIVersion = "1.40"
+)
+
+// ContainerRuntime - container operations required to run cameras and apps.
+// Missing containers are reported with errors satisfying client.IsErrNotFound (github.com/docker/docker/client).
+type ContainerRuntime interface {
+	// container lifecycle (containerID is either container ID or container name)
+	ContainerCreate(name string, config *container.Config, hostConfig *container.HostConfig) error
+	ContainerStart(containerID string) error
+	ContainerStop(containerID string, killAfter *time.Duration) error
+	ContainerRestart(containerID string, timeout time.Duration) error
+	ContainerRemove(containerID string) error
+	ContainerPause(containerID string) error
+	ContainerUnpause(containerID string) error
+	ContainerRename(containerID string, newName string) error
+	// ContainerReplace recreates the container with the same configuration and a new image
+	ContainerReplace(containerID string, image string, tag string) error
+	// ContainersPrune removes all stopped containers
+	ContainersPrune() (*types.ContainersPruneReport, error)
+	// CopyToContainer writes the file (readable by the container user only) into the container filesystem,
+	// unlike environment variables the content isn't shown by docker inspect
+	CopyToContainer(containerID string, filePath string, content []byte) error
+
+	// inspection
+	ContainerGet(containerID string) (*types.ContainerJSON, error)
+	ContainersList(all bool) ([]types.Container, error)
+	ContainerLogs(containerID string, tailLines int, since time.Time) (*microModelDocker.DockerLogs, error)
+	// ContainerLogsStream returns stdout and stderr multiplexed in the docker stdcopy format (follow ends when the context is cancelled)
+	ContainerLogsStream(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error)
+	ContainerStats(containerID string) (*microModelDocker.Stats, error)
+	// ContainerExec runs the command inside the running container with an attached TTY
+	ContainerExec(ctx context.Context, containerID string, cmd []string) (ExecSession, error)
+	// Events streams container events until the context is cancelled or an error is received
+	Events(ctx context.Context) (<-chan events.Message, <-chan error)
+
+	// images
+	ImagesList() ([]types.ImageSummary, error)
+	ImagePull(image string, tag string) (string, error)
+
+	// host system
+	SystemWideInfo() (types.Info, types.DiskUsage, error)
+}
+
+// ExecSession - TTY attached to a command running inside a container (stdout and stderr are combined)
+type ExecSession interface {
+	io.ReadWriteCloser
+	Resize(height uint, width uint) error
+}
+
+// NewContainerRuntime - container runtime as configured in conf.yaml (docker on local socket by default)
+func NewContainerRuntime() (ContainerRuntime, error) {
+	conf := g.Conf.Runtime
+	if conf == nil {
+		conf = &g.RuntimeSubconfig{}
+	}
+	switch conf.Type {
+	case "", RuntimeDocker:
+		return newDockerRuntime(conf)
+	case RuntimeMemory:
+		g.Log.Warn("using in-memory container runtime, cameras and apps will not be started")
+		return NewMemoryRuntime(), nil
+	}
+	return nil, errors.New("unsupported container runtime " + conf.Type)
+}
+
+// dockerRuntime - docker daemon shared by all services
+type dockerRuntime struct {
+	cl docker.Docker
+}
+
+func newDockerRuntime(conf *g.RuntimeSubconfig) (*dockerRuntime, error) {
+	host := conf.Host
+	if host == "" {
+		host = defaultRuntimeHost
+	}
+	apiVersion := conf.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultRuntimeAPIVersion
+	}
+	opts := []docker.Option{docker.Log(g.Log), docker.Host(host), docker.APIVersion(apiVersion)}
+
+	if conf.TLSCACert != "" || conf.TLSCert != "" || conf.TLSKey != "" {
+		files := []struct {
+			path   string
+			option func([]byte) docker.Option
+		}{
+			{conf.TLSCACert, docker.CACert},
+			{conf.TLSCert, docker.Cert},
+			{conf.TLSKey, docker.CertKey},
+		}
+		for _, f := range files {
+			if f.path == "" {
+				continue
+			}
+			b, err := ioutil.ReadFile(f.path)
+			if err != nil {
+				g.Log.Error("failed to read docker tls file", f.path, err)
+				return nil, err
+			}
+			opts = append(opts, f.option(b))
+		}
+		return &dockerRuntime{cl: docker.NewTLSClient(opts...)}, nil
+	}
+	if strings.HasPrefix(host, "unix://") {
+		return &dockerRuntime{cl: docker.NewSocketClient(opts...)}, nil
+	}
+	return &dockerRuntime{cl: docker.NewLocalClient(opts...)}, nil
+}
+
+func (dr *dockerRuntime) ContainerCreate(name string, config *container.Config, hostConfig *container.HostConfig) error {
+	_, err := dr.cl.ContainerCreate(name, config, hostConfig, nil)
+	return err
+}
+
+func (dr *dockerRuntime) ContainerStart(containerID string) error {
+	return dr.cl.ContainerStart(containerID)
+}
+
+func (dr *dockerRuntime) ContainerStop(containerID string, killAfter *time.Duration) error {
+	return dr.cl.ContainerStop(containerID, killAfter)
+}
+
+func (dr *dockerRuntime) ContainerRestart(containerID string, timeout time.Duration) error {
+	return dr.cl.ContainerRestart(containerID, timeout)
+}
+
+func (dr *dockerRuntime) ContainerRemove(containerID string) error {
+	return dr.cl.ContainerRemove(containerID)
+}
+
+func (dr *dockerRuntime) ContainerPause(containerID string) error {
+	return dr.cl.GetDockerClient().ContainerPause(context.Background(), containerID)
+}
+
+func (dr *dockerRuntime) ContainerUnpause(containerID string) error {
+	return dr.cl.GetDockerClient().ContainerUnpause(context.Background(), containerID)
+}
+
+func (dr *dockerRuntime) ContainerRename(containerID string, newName string) error {
+	return dr.cl.ContainerRename(containerID, newName)
+}
+
+func (dr *dockerRuntime) ContainerReplace(containerID string, image string, tag string) error {
+	return dr.cl.ContainerReplace(containerID, image, tag)
+}
+
+func (dr *dockerRuntime) CopyToContainer(containerID string, filePath string, content []byte) error {
+	// tar archive with the parent folders (extracted into the container root)
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	filePath = strings.TrimPrefix(path.Clean(filePath), "/")
+	parts := strings.Split(filePath, "/")
+	for i := 1; i < len(parts); i++ {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: strings.Join(parts[:i], "/") + "/", Mode: 0755}); err != nil {
+			return err
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: filePath, Mode: 0400, Size: int64(len(content))}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return dr.cl.GetDockerClient().CopyToContainer(context.Background(), containerID, "/", &buf, types.CopyToContainerOptions{})
+}
+
+func (dr *dockerRuntime) ContainersPrune() (*types.ContainersPruneReport, error) {
+	return dr.cl.ContainersPrune(filters.NewArgs())
+}
+
+func (dr *dockerRuntime) ContainerGet(containerID string) (*types.ContainerJSON, error) {
+	return dr.cl.ContainerGet(containerID)
+}
+
+func (dr *dockerRuntime) ContainersList(all bool) ([]types.Container, error) {
+	return dr.cl.ContainersListWithOptions(types.ContainerListOptions{All: all})
+}
+
+func (dr *dockerRuntime) ContainerLogs(containerID string, tailLines int, since time.Time) (*microModelDocker.DockerLogs, error) {
+	return dr.cl.ContainerLogs(containerID, tailLines, since)
+}
+
+func (dr *dockerRuntime) ContainerLogsStream(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	return dr.cl.GetDockerClient().ContainerLogs(ctx, containerID, options)
+}
+
+func (dr *dockerRuntime) ContainerStats(containerID string) (*microModelDocker.Stats, error) {
+	s, err := dr.cl.ContainerStats(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return dr.cl.CalculateStats(s), nil
+}
+
+func (dr *dockerRuntime) ContainerExec(ctx context.Context, containerID string, cmd []string) (ExecSession, error) {
+	cli := dr.cl.GetDockerClient()
+	resp, err := cli.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return nil, err
+	}
+	hijacked, err := cli.ContainerExecAttach(ctx, resp.ID, types.ExecStartCheck{Tty: true})
+	if err != nil {
+		return nil, err
+	}
+	return &dockerExecSession{cli: cli, execID: resp.ID, hijacked: hijacked}, nil
+}
+
+func (dr *dockerRuntime) Events(ctx context.Context) (<-chan events.Message, <-chan error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("type", events.ContainerEventType)
+	return dr.cl.GetDockerClient().Events(ctx, types.EventsOptions{Filters: filterArgs})
+}
+
+func (dr *dockerRuntime) ImagesList() ([]types.ImageSummary, error) {
+	return dr.cl.ImagesList()
+}
+
+func (dr *dockerRuntime) ImagePull(image string, tag string) (string, error) {
+	return dr.cl.ImagePullDockerHub(image, tag, "", "")
+}
+
+func (dr *dockerRuntime) SystemWideInfo() (types.Info, types.DiskUsage, error) {
+	return dr.cl.SystemWideInfo()
+}
+
+// dockerExecSession - exec attached over the hijacked docker API connection
+type dockerExecSession struct {
+	cli      *client.Client
+	execID   string
+	hijacked types.HijackedResponse
+}
+
+func (es *dockerExecSession) Read(p []byte) (int, error) {
+	return es.hijacked.Reader.Read(p)
+}
+
+func (es *dockerExecSession) Write(p []byte) (int, error) {
+	return es.hijacked.Conn.Write(p)
+}
+
+func (es *dockerExecSession) Close() error {
+	es.hijacked.Close()
+	return nil
+}
+
+func (es *dockerExecSession) Resize(height uint, width uint) error {
+	return es.cli.ContainerExecResize(context.Background(), es.execID, types.ResizeOptions{Height: height, Width: width})
+}
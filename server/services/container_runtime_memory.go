@@ -0,0 +1,734 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	microModelDocker "github.com/chryscloud/go-microkit-plugins/models/docker"
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// MemoryRuntime - in-memory container runtime. Containers are only records with docker-like state transitions and events,
+// which makes camera and app lifecycle testable without docker.
+type MemoryRuntime struct {
+	mux         sync.Mutex
+	containers  map[string]*types.ContainerJSON // by container ID
+	images      map[string]string               // repo:tag -> image ID
+	logs        map[string][]byte               // container ID -> stdout
+	startErrors map[string]error                // container name or ID -> error returned on start
+	pullErrors  map[string]error                // image -> error returned on pull
+	subscribers map[chan events.Message]bool
+	followers   map[string]map[chan []byte]bool // container ID -> log streams in follow mode
+	files       map[string]map[string][]byte    // container ID -> files copied into the container by path
+}
+
+func NewMemoryRuntime() *MemoryRuntime {
+	return &MemoryRuntime{
+		containers:  make(map[string]*types.ContainerJSON),
+		images:      make(map[string]string),
+		logs:        make(map[string][]byte),
+		startErrors: make(map[string]error),
+		pullErrors:  make(map[string]error),
+		files:       make(map[string]map[string][]byte),
+		subscribers: make(map[chan events.Message]bool),
+		followers:   make(map[string]map[chan []byte]bool),
+	}
+}
+
+// AddImage makes images (repo:tag) available locally without pulling them
+func (mr *MemoryRuntime) AddImage(repoTags ...string) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	for _, repoTag := range repoTags {
+		if _, ok := mr.images[repoTag]; !ok {
+			mr.images[repoTag] = "sha256:" + randomID()
+		}
+	}
+}
+
+// AppendLogs appends output to the container logs
+func (mr *MemoryRuntime) AppendLogs(containerID string, output string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	mr.logs[c.ID] = append(mr.logs[c.ID], []byte(output)...)
+	for follower := range mr.followers[c.ID] {
+		select {
+		case follower <- []byte(output):
+		default:
+			g.Log.Warn("memory runtime log follower can't keep up, dropping output", c.ID)
+		}
+	}
+	return nil
+}
+
+// ContainerFile returns the file copied into the container
+func (mr *MemoryRuntime) ContainerFile(containerID string, filePath string) ([]byte, bool) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return nil, false
+	}
+	content, ok := mr.files[c.ID][filePath]
+	return content, ok
+}
+
+// FailImagePull makes pulls of the image (any tag) fail with err (nil clears the failure)
+func (mr *MemoryRuntime) FailImagePull(image string, err error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	if err == nil {
+		delete(mr.pullErrors, image)
+		return
+	}
+	mr.pullErrors[image] = err
+}
+
+// FailStart makes containers created with the name afterwards fail to start with err (nil clears the failure)
+func (mr *MemoryRuntime) FailStart(name string, err error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	name = strings.TrimPrefix(name, "/")
+	if err == nil {
+		delete(mr.startErrors, name)
+		return
+	}
+	mr.startErrors[name] = err
+}
+
+// Kill simulates container exiting on its own (e.g. crashed process)
+func (mr *MemoryRuntime) Kill(containerID string, exitCode int) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	mr.stop(c, exitCode)
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerCreate(name string, config *container.Config, hostConfig *container.HostConfig) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+
+	name = strings.TrimPrefix(name, "/")
+	if _, err := mr.find(name); err == nil {
+		return errdefs.Conflict(fmt.Errorf("Conflict. The container name \"/%s\" is already in use", name))
+	}
+	if config == nil {
+		config = &container.Config{}
+	}
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+	imageID, ok := mr.images[imageRepoTag(config.Image)]
+	if !ok {
+		return errdefs.NotFound(fmt.Errorf("No such image: %s", config.Image))
+	}
+
+	c := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:         randomID(),
+			Created:    time.Now().UTC().Format(time.RFC3339Nano),
+			Name:       "/" + name,
+			Image:      imageID,
+			HostConfig: hostConfig,
+			State: &types.ContainerState{
+				Status:     "created",
+				StartedAt:  "0001-01-01T00:00:00Z",
+				FinishedAt: "0001-01-01T00:00:00Z",
+			},
+		},
+		Config: config,
+	}
+	mr.containers[c.ID] = c
+	if err, ok := mr.startErrors[name]; ok {
+		mr.startErrors[c.ID] = err
+	}
+	mr.publish(c, "create")
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerStart(containerID string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		return nil
+	}
+	if err, ok := mr.startErrors[c.ID]; ok {
+		return err
+	}
+	mr.start(c)
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerStop(containerID string, killAfter *time.Duration) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		mr.stop(c, 0)
+		mr.publish(c, "stop")
+	}
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerRestart(containerID string, timeout time.Duration) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		mr.stop(c, 0)
+	}
+	mr.start(c)
+	c.RestartCount++
+	mr.publish(c, "restart")
+	return nil
+}
+
+// ContainerRemove - force removes the container (running or not)
+func (mr *MemoryRuntime) ContainerRemove(containerID string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if c.State.Running {
+		mr.stop(c, 137)
+	}
+	mr.remove(c)
+	return nil
+}
+
+func (mr *MemoryRuntime) CopyToContainer(containerID string, filePath string, content []byte) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if _, ok := mr.files[c.ID]; !ok {
+		mr.files[c.ID] = make(map[string][]byte)
+	}
+	mr.files[c.ID][filePath] = append([]byte{}, content...)
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerPause(containerID string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if !c.State.Running {
+		return errdefs.Conflict(fmt.Errorf("Container %s is not running", c.ID))
+	}
+	if !c.State.Paused {
+		c.State.Paused = true
+		c.State.Status = "paused"
+		mr.publish(c, "pause")
+	}
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerUnpause(containerID string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	if !c.State.Paused {
+		return errdefs.Conflict(fmt.Errorf("Container %s is not paused", c.ID))
+	}
+	c.State.Paused = false
+	c.State.Status = "running"
+	mr.publish(c, "unpause")
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerRename(containerID string, newName string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	newName = strings.TrimPrefix(newName, "/")
+	if existing, err := mr.find(newName); err == nil && existing.ID != c.ID {
+		return errdefs.Conflict(fmt.Errorf("Conflict. The container name \"/%s\" is already in use", newName))
+	}
+	c.Name = "/" + newName
+	mr.publish(c, "rename")
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainerReplace(containerID string, image string, tag string) error {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return err
+	}
+	imageID, ok := mr.images[image+":"+tag]
+	if !ok {
+		return errdefs.NotFound(fmt.Errorf("No such image: %s:%s", image, tag))
+	}
+	if c.State.Running {
+		mr.stop(c, 0)
+		mr.publish(c, "stop")
+	}
+	mr.remove(c)
+
+	config := *c.Config
+	config.Image = image + ":" + tag
+	replacement := &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:         randomID(),
+			Created:    time.Now().UTC().Format(time.RFC3339Nano),
+			Name:       c.Name,
+			Image:      imageID,
+			HostConfig: c.HostConfig,
+			State:      &types.ContainerState{Status: "created"},
+		},
+		Config: &config,
+	}
+	mr.containers[replacement.ID] = replacement
+	mr.publish(replacement, "create")
+	mr.start(replacement)
+	return nil
+}
+
+func (mr *MemoryRuntime) ContainersPrune() (*types.ContainersPruneReport, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	report := &types.ContainersPruneReport{ContainersDeleted: make([]string, 0)}
+	for _, c := range mr.containers {
+		if !c.State.Running {
+			mr.remove(c)
+			report.ContainersDeleted = append(report.ContainersDeleted, c.ID)
+		}
+	}
+	return report, nil
+}
+
+// ContainerGet returns a copy of the container (state changes don't modify previously returned containers)
+func (mr *MemoryRuntime) ContainerGet(containerID string) (*types.ContainerJSON, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+	base := *c.ContainerJSONBase
+	state := *c.State
+	base.State = &state
+	return &types.ContainerJSON{ContainerJSONBase: &base, Config: c.Config}, nil
+}
+
+func (mr *MemoryRuntime) ContainersList(all bool) ([]types.Container, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	list := make([]types.Container, 0, len(mr.containers))
+	for _, c := range mr.containers {
+		if !all && !c.State.Running {
+			continue
+		}
+		created, _ := time.Parse(time.RFC3339Nano, c.Created)
+		item := types.Container{
+			ID:      c.ID,
+			Names:   []string{c.Name},
+			Image:   c.Config.Image,
+			ImageID: c.Image,
+			Labels:  c.Config.Labels,
+			State:   c.State.Status,
+			Status:  c.State.Status,
+			Created: created.Unix(),
+		}
+		item.HostConfig.NetworkMode = string(c.HostConfig.NetworkMode)
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// ContainerLogs returns the last tailLines lines of output appended with AppendLogs (since is ignored)
+func (mr *MemoryRuntime) ContainerLogs(containerID string, tailLines int, since time.Time) (*microModelDocker.DockerLogs, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return &microModelDocker.DockerLogs{Stdout: tailLogs(mr.logs[c.ID], tailLines), Stderr: []byte{}}, nil
+}
+
+// ContainerLogsStream streams output appended with AppendLogs as stdout (since, until and timestamps are ignored)
+func (mr *MemoryRuntime) ContainerLogsStream(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+	tail := 0
+	if options.Tail != "" && options.Tail != "all" {
+		if tail, err = strconv.Atoi(options.Tail); err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		if tail == 0 {
+			tail = -1
+		}
+	}
+	output := tailLogs(mr.logs[c.ID], tail)
+
+	var updates chan []byte
+	if options.Follow {
+		updates = make(chan []byte, 100)
+		if _, ok := mr.followers[c.ID]; !ok {
+			mr.followers[c.ID] = make(map[chan []byte]bool)
+		}
+		mr.followers[c.ID][updates] = true
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		stdout := stdcopy.NewStdWriter(writer, stdcopy.Stdout)
+		if options.ShowStdout && len(output) > 0 {
+			if _, err := stdout.Write(output); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		if updates == nil {
+			writer.Close()
+			return
+		}
+		for {
+			select {
+			case b, ok := <-updates:
+				if !ok {
+					// container removed
+					writer.Close()
+					return
+				}
+				if options.ShowStdout {
+					if _, err := stdout.Write(b); err != nil {
+						mr.unfollow(c.ID, updates)
+						writer.CloseWithError(err)
+						return
+					}
+				}
+			case <-ctx.Done():
+				mr.unfollow(c.ID, updates)
+				writer.CloseWithError(ctx.Err())
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+func (mr *MemoryRuntime) unfollow(containerID string, updates chan []byte) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	if _, ok := mr.followers[containerID][updates]; ok {
+		delete(mr.followers[containerID], updates)
+		close(updates)
+	}
+}
+
+// tailLogs returns the last tailLines lines of output (all lines if tailLines is 0, none if negative)
+func tailLogs(output []byte, tailLines int) []byte {
+	if tailLines < 0 {
+		return []byte{}
+	}
+	lines := strings.SplitAfter(string(output), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if tailLines > 0 && len(lines) > tailLines {
+		lines = lines[len(lines)-tailLines:]
+	}
+	return []byte(strings.Join(lines, ""))
+}
+
+func (mr *MemoryRuntime) ContainerStats(containerID string) (*microModelDocker.Stats, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+	return &microModelDocker.Stats{
+		Name:   strings.TrimPrefix(c.Name, "/"),
+		ID:     c.ID,
+		Status: c.State.Status,
+	}, nil
+}
+
+// ContainerExec attaches to an echo session (input is written back as output) in the running container
+func (mr *MemoryRuntime) ContainerExec(ctx context.Context, containerID string, cmd []string) (ExecSession, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	c, err := mr.find(containerID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.State.Running || c.State.Paused {
+		return nil, errdefs.Conflict(fmt.Errorf("Container %s is not running", c.ID))
+	}
+	reader, writer := io.Pipe()
+	mr.publish(c, "exec_start: "+strings.Join(cmd, " "))
+	return &MemoryExecSession{reader: reader, writer: writer}, nil
+}
+
+// MemoryExecSession - echo TTY of the in-memory runtime
+type MemoryExecSession struct {
+	reader *io.PipeReader
+	writer *io.PipeWriter
+	mux    sync.Mutex
+	height uint
+	width  uint
+}
+
+func (es *MemoryExecSession) Read(p []byte) (int, error) {
+	return es.reader.Read(p)
+}
+
+func (es *MemoryExecSession) Write(p []byte) (int, error) {
+	return es.writer.Write(p)
+}
+
+func (es *MemoryExecSession) Close() error {
+	es.reader.Close()
+	return es.writer.Close()
+}
+
+func (es *MemoryExecSession) Resize(height uint, width uint) error {
+	es.mux.Lock()
+	defer es.mux.Unlock()
+	es.height, es.width = height, width
+	return nil
+}
+
+// Size returns the last TTY size set with Resize
+func (es *MemoryExecSession) Size() (uint, uint) {
+	es.mux.Lock()
+	defer es.mux.Unlock()
+	return es.height, es.width
+}
+
+// Events streams container events until the context is cancelled (events are dropped when the receiver can't keep up)
+func (mr *MemoryRuntime) Events(ctx context.Context) (<-chan events.Message, <-chan error) {
+	messages := make(chan events.Message, 100)
+	errs := make(chan error, 1)
+
+	mr.mux.Lock()
+	mr.subscribers[messages] = true
+	mr.mux.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		mr.mux.Lock()
+		delete(mr.subscribers, messages)
+		mr.mux.Unlock()
+		errs <- ctx.Err()
+	}()
+	return messages, errs
+}
+
+func (mr *MemoryRuntime) ImagesList() ([]types.ImageSummary, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	byID := make(map[string]*types.ImageSummary)
+	for repoTag, id := range mr.images {
+		im, ok := byID[id]
+		if !ok {
+			im = &types.ImageSummary{ID: id, RepoTags: make([]string, 0)}
+			byID[id] = im
+		}
+		im.RepoTags = append(im.RepoTags, repoTag)
+	}
+	for _, c := range mr.containers {
+		if im, ok := byID[c.Image]; ok {
+			im.Containers++
+		}
+	}
+	images := make([]types.ImageSummary, 0, len(byID))
+	for _, im := range byID {
+		images = append(images, *im)
+	}
+	return images, nil
+}
+
+// ImagePull adds the image to local images (fails only with FailImagePull)
+func (mr *MemoryRuntime) ImagePull(image string, tag string) (string, error) {
+	mr.mux.Lock()
+	err := mr.pullErrors[image]
+	mr.mux.Unlock()
+	if err != nil {
+		return "", err
+	}
+	mr.AddImage(image + ":" + tag)
+	return "Status: Downloaded newer image for " + image + ":" + tag, nil
+}
+
+func (mr *MemoryRuntime) SystemWideInfo() (types.Info, types.DiskUsage, error) {
+	mr.mux.Lock()
+	defer mr.mux.Unlock()
+	info := types.Info{
+		ID:              "memory",
+		Name:            "memory",
+		Architecture:    runtime.GOARCH,
+		OSType:          runtime.GOOS,
+		OperatingSystem: runtime.GOOS,
+		NCPU:            runtime.NumCPU(),
+		ServerVersion:   RuntimeMemory,
+		Containers:      len(mr.containers),
+		Images:          len(mr.images),
+	}
+	for _, c := range mr.containers {
+		if c.State.Running {
+			info.ContainersRunning++
+		} else {
+			info.ContainersStopped++
+		}
+	}
+	return info, types.DiskUsage{}, nil
+}
+
+// find container by ID, ID prefix or name (must be called with the lock held)
+func (mr *MemoryRuntime) find(containerID string) (*types.ContainerJSON, error) {
+	if c, ok := mr.containers[containerID]; ok {
+		return c, nil
+	}
+	name := "/" + strings.TrimPrefix(containerID, "/")
+	for _, c := range mr.containers {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	if len(containerID) >= 12 {
+		for id, c := range mr.containers {
+			if strings.HasPrefix(id, containerID) {
+				return c, nil
+			}
+		}
+	}
+	return nil, errdefs.NotFound(fmt.Errorf("No such container: %s", containerID))
+}
+
+func (mr *MemoryRuntime) start(c *types.ContainerJSON) {
+	c.State.Status = "running"
+	c.State.Running = true
+	c.State.Paused = false
+	c.State.Restarting = false
+	c.State.ExitCode = 0
+	c.State.Pid = 1
+	c.State.StartedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	mr.publish(c, "start")
+}
+
+func (mr *MemoryRuntime) stop(c *types.ContainerJSON, exitCode int) {
+	c.State.Status = "exited"
+	c.State.Running = false
+	c.State.Paused = false
+	c.State.ExitCode = exitCode
+	c.State.Pid = 0
+	c.State.FinishedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	mr.publish(c, "die")
+}
+
+func (mr *MemoryRuntime) remove(c *types.ContainerJSON) {
+	delete(mr.containers, c.ID)
+	delete(mr.logs, c.ID)
+	delete(mr.startErrors, c.ID)
+	delete(mr.files, c.ID)
+	for follower := range mr.followers[c.ID] {
+		close(follower)
+	}
+	delete(mr.followers, c.ID)
+	mr.publish(c, "destroy")
+}
+
+// publish container event to all subscribers (must be called with the lock held)
+func (mr *MemoryRuntime) publish(c *types.ContainerJSON, action string) {
+	now := time.Now()
+	msg := events.Message{
+		Status: action,
+		ID:     c.ID,
+		From:   c.Config.Image,
+		Type:   events.ContainerEventType,
+		Action: action,
+		Actor: events.Actor{
+			ID:         c.ID,
+			Attributes: map[string]string{"name": strings.TrimPrefix(c.Name, "/"), "image": c.Config.Image},
+		},
+		Time:     now.Unix(),
+		TimeNano: now.UnixNano(),
+	}
+	for sub := range mr.subscribers {
+		select {
+		case sub <- msg:
+		default:
+		}
+	}
+}
+
+// image without tag refers to the latest tag
+func imageRepoTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image
+	}
+	return image + ":latest"
+}
+
+func randomID() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,601 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/dgraph-io/badger/v2"
+)
+
+const (
+	defaultCountingTrackTimeout = time.Second * 10
+	unknownObjectType           = "unknown"
+)
+
+// CountingManager - counts line crossings and area occupancy from tracked annotations
+type CountingManager struct {
+	storage      *Storage
+	mux          *sync.Mutex
+	zones        map[string][]*models.CountingZone // zones cache per device
+	tracks       map[string]*countingTrack         // tracked objects per device and tracking id
+	occupancy    map[string]int64                  // current occupancy per device, zone and object type
+	pending      map[string]*models.CountingSample // minute samples not yet stored (merged into the stored ones on flush)
+	flushMux     *sync.Mutex                       // one flush at a time (read-modify-write of stored samples)
+	trackTimeout time.Duration
+	retention    time.Duration // 0 keeps the samples forever
+	ticker       *time.Ticker
+	stop         chan struct{}
+	closeOnce    sync.Once
+}
+
+// countingTrack is the last known state of a tracked object
+type countingTrack struct {
+	deviceName string
+	objectType string
+	position   models.Point
+	lastSeen   time.Time
+	inside     map[string]bool // names of areas the object is currently in
+}
+
+func NewCountingManager(storage *Storage) *CountingManager {
+	cm := &CountingManager{
+		storage:      storage,
+		mux:          &sync.Mutex{},
+		zones:        make(map[string][]*models.CountingZone),
+		tracks:       make(map[string]*countingTrack),
+		occupancy:    make(map[string]int64),
+		pending:      make(map[string]*models.CountingSample),
+		flushMux:     &sync.Mutex{},
+		trackTimeout: defaultCountingTrackTimeout,
+		ticker:       time.NewTicker(time.Second),
+		stop:         make(chan struct{}),
+	}
+	if g.Conf.Counting != nil {
+		if g.Conf.Counting.TrackTimeoutMs > 0 {
+			cm.trackTimeout = time.Duration(g.Conf.Counting.TrackTimeoutMs) * time.Millisecond
+		}
+		if g.Conf.Counting.RetentionDays > 0 {
+			cm.retention = time.Hour * 24 * time.Duration(g.Conf.Counting.RetentionDays)
+		}
+	}
+	err := cm.loadZones()
+	if err != nil {
+		g.Log.Error("failed to load counting zones", err)
+	}
+
+	// check every second for tracked objects that haven't been seen for a while and store the counts
+	go func() {
+		for {
+			select {
+			case t := <-cm.ticker.C:
+				cm.expireTracks(t)
+				cm.flush()
+			case <-cm.stop:
+				return
+			}
+		}
+	}()
+
+	return cm
+}
+
+// Close stops expiring tracked objects and stores the pending counts
+func (cm *CountingManager) Close() {
+	cm.closeOnce.Do(func() {
+		cm.ticker.Stop()
+		close(cm.stop)
+		cm.flush()
+	})
+}
+
+// Process follows the tracking ID trajectory and counts zone crossings
+func (cm *CountingManager) Process(req *pb.AnnotateRequest) {
+	if req.ObjectTrackingId == "" {
+		return
+	}
+	center, ok := utils.AnnotationCenter(req)
+	if !ok {
+		return
+	}
+	position := models.Point{X: center.X, Y: center.Y}
+
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	zones := cm.zones[req.DeviceName]
+	if len(zones) == 0 {
+		return
+	}
+
+	objectType := req.ObjectType
+	if objectType == "" {
+		objectType = unknownObjectType
+	}
+
+	trackKey := req.DeviceName + "/" + req.ObjectTrackingId
+	track, exists := cm.tracks[trackKey]
+	if !exists {
+		track = &countingTrack{
+			deviceName: req.DeviceName,
+			objectType: objectType,
+			inside:     make(map[string]bool),
+		}
+		cm.tracks[trackKey] = track
+	}
+
+	for _, zone := range zones {
+		if !zoneCountsObjectType(zone, track.objectType) {
+			continue
+		}
+		switch zone.Type {
+		case models.CountingZoneTypeLine:
+			if !exists {
+				continue
+			}
+			direction := lineCrossing(track.position, position, *zone.Points[0], *zone.Points[1])
+			if direction > 0 {
+				cm.record(zone, track.objectType, req.StartTimestamp, 1, 0)
+			} else if direction < 0 {
+				cm.record(zone, track.objectType, req.StartTimestamp, 0, 1)
+			}
+		case models.CountingZoneTypeArea:
+			isInside := pointInPolygon(position, zone.Points)
+			if isInside && !track.inside[zone.Name] {
+				track.inside[zone.Name] = true
+				cm.occupancy[occupancyKey(zone.DeviceName, zone.Name, track.objectType)]++
+				cm.record(zone, track.objectType, req.StartTimestamp, 1, 0)
+			} else if !isInside && track.inside[zone.Name] {
+				delete(track.inside, zone.Name)
+				cm.occupancy[occupancyKey(zone.DeviceName, zone.Name, track.objectType)]--
+				cm.record(zone, track.objectType, req.StartTimestamp, 0, 1)
+			}
+		}
+	}
+
+	track.position = position
+	track.lastSeen = time.Now()
+}
+
+// InZone checks if the annotated object is inside of the device's counting area
+func (cm *CountingManager) InZone(req *pb.AnnotateRequest, zoneName string) bool {
+	center, ok := utils.AnnotationCenter(req)
+	if !ok {
+		return false
+	}
+
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	for _, zone := range cm.zones[req.DeviceName] {
+		if zone.Name == zoneName && zone.Type == models.CountingZoneTypeArea {
+			return pointInPolygon(models.Point{X: center.X, Y: center.Y}, zone.Points)
+		}
+	}
+	return false
+}
+
+// PutZone creates or overwrites the counting zone
+func (cm *CountingManager) PutZone(zone *models.CountingZone) (*models.CountingZone, error) {
+	err := models.ValidateCountingZone(zone)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := cm.getZone(zone.DeviceName, zone.Name)
+	if err != nil && err != badger.ErrKeyNotFound {
+		return nil, err
+	}
+	now := time.Now().Unix() * 1000
+	zone.Created = now
+	if existing != nil {
+		zone.Created = existing.Created
+	}
+	zone.Modified = now
+
+	b, err := json.Marshal(zone)
+	if err != nil {
+		g.Log.Error("failed to marshal counting zone", err)
+		return nil, err
+	}
+	err = cm.storage.Put(models.PrefixCountingZone, zone.DeviceName+"/"+zone.Name, b)
+	if err != nil {
+		g.Log.Error("failed to store counting zone", zone.DeviceName, zone.Name, err)
+		return nil, err
+	}
+
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+	cm.resetZoneState(zone.DeviceName, zone.Name)
+	zones := make([]*models.CountingZone, 0)
+	for _, z := range cm.zones[zone.DeviceName] {
+		if z.Name != zone.Name {
+			zones = append(zones, z)
+		}
+	}
+	cm.zones[zone.DeviceName] = append(zones, zone)
+
+	return zone, nil
+}
+
+// ListZones lists all counting zones for the device
+func (cm *CountingManager) ListZones(deviceName string) ([]*models.CountingZone, error) {
+	objects, err := cm.storage.List(models.PrefixCountingZone + deviceName + "/")
+	if err != nil {
+		g.Log.Error("failed to list counting zones", deviceName, err)
+		return nil, err
+	}
+	zones := make([]*models.CountingZone, 0)
+	for _, v := range objects {
+		var zone models.CountingZone
+		err := json.Unmarshal(v, &zone)
+		if err != nil {
+			g.Log.Error("failed to unmarshal counting zone", err)
+			return nil, err
+		}
+		zones = append(zones, &zone)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	return zones, nil
+}
+
+// DeleteZone removes the counting zone (collected counts are kept until they expire)
+func (cm *CountingManager) DeleteZone(deviceName, zoneName string) error {
+	_, err := cm.getZone(deviceName, zoneName)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return models.ErrProcessNotFound
+		}
+		return err
+	}
+	err = cm.storage.Del(models.PrefixCountingZone, deviceName+"/"+zoneName)
+	if err != nil {
+		g.Log.Error("failed to delete counting zone", deviceName, zoneName, err)
+		return err
+	}
+
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+	cm.resetZoneState(deviceName, zoneName)
+	zones := make([]*models.CountingZone, 0)
+	for _, z := range cm.zones[deviceName] {
+		if z.Name != zoneName {
+			zones = append(zones, z)
+		}
+	}
+	cm.zones[deviceName] = zones
+	return nil
+}
+
+// Occupancy returns the current occupancy of all areas of the device
+func (cm *CountingManager) Occupancy(deviceName string) []*models.CountingOccupancy {
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	result := make([]*models.CountingOccupancy, 0)
+	for key, occupancy := range cm.occupancy {
+		splitted := strings.SplitN(key, "/", 3)
+		if len(splitted) != 3 || splitted[0] != deviceName {
+			continue
+		}
+		result = append(result, &models.CountingOccupancy{
+			DeviceName: splitted[0],
+			Zone:       splitted[1],
+			ObjectType: splitted[2],
+			Occupancy:  occupancy,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Zone == result[j].Zone {
+			return result[i].ObjectType < result[j].ObjectType
+		}
+		return result[i].Zone < result[j].Zone
+	})
+	return result
+}
+
+// Counts queries the counting time series and aggregates it into buckets of desired duration
+// objectType is optional (empty returns counts for all object types)
+func (cm *CountingManager) Counts(deviceName, zoneName, objectType string, from, to int64, bucket time.Duration) ([]*models.CountingSample, error) {
+	if bucket < time.Minute {
+		return nil, models.ErrInvalidInputParameters
+	}
+	cm.flush()
+
+	// minute samples are keyed by time, only the requested range is scanned
+	minuteMs := time.Minute.Milliseconds()
+	fromKey := fmt.Sprintf("%013d", from-from%minuteMs)
+	toKey := "~"
+	if to > 0 {
+		toKey = fmt.Sprintf("%013d/~", to)
+	}
+	objects, err := cm.storage.ListRange(models.PrefixCountingBucket+deviceName+"/"+zoneName+"/", fromKey, toKey)
+	if err != nil {
+		g.Log.Error("failed to list counting series", deviceName, zoneName, err)
+		return nil, err
+	}
+
+	bucketMs := bucket.Milliseconds()
+	aggregated := make(map[string]*models.CountingSample)
+	for _, v := range objects {
+		var sample models.CountingSample
+		err := json.Unmarshal(v, &sample)
+		if err != nil {
+			g.Log.Error("failed to unmarshal counting sample", err)
+			return nil, err
+		}
+		if (objectType != "" && sample.ObjectType != objectType) || sample.Timestamp < from || (to > 0 && sample.Timestamp > to) {
+			continue
+		}
+		bucketStart := sample.Timestamp - sample.Timestamp%bucketMs
+		key := fmt.Sprintf("%s/%d", sample.ObjectType, bucketStart)
+		agg, ok := aggregated[key]
+		if !ok {
+			agg = &models.CountingSample{
+				DeviceName: sample.DeviceName,
+				Zone:       sample.Zone,
+				ObjectType: sample.ObjectType,
+				Timestamp:  bucketStart,
+			}
+			aggregated[key] = agg
+		}
+		agg.In += sample.In
+		agg.Out += sample.Out
+		if sample.Occupancy > agg.Occupancy {
+			agg.Occupancy = sample.Occupancy
+		}
+	}
+
+	result := make([]*models.CountingSample, 0, len(aggregated))
+	for _, sample := range aggregated {
+		result = append(result, sample)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Timestamp == result[j].Timestamp {
+			return result[i].ObjectType < result[j].ObjectType
+		}
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result, nil
+}
+
+// CountingBucketDuration converts bucket name (minute, hour, day) or duration (e.g. 15m) to duration
+func CountingBucketDuration(bucket string) (time.Duration, error) {
+	switch bucket {
+	case "", models.CountingBucketMinute:
+		return time.Minute, nil
+	case models.CountingBucketHour:
+		return time.Hour, nil
+	case models.CountingBucketDay:
+		return time.Hour * 24, nil
+	}
+	d, err := time.ParseDuration(bucket)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Minute || d%time.Minute != 0 {
+		return 0, errors.New("bucket must be a multiple of a minute")
+	}
+	return d, nil
+}
+
+// record adds in and out counts to the pending minute sample of the zone (expected to be called under lock)
+func (cm *CountingManager) record(zone *models.CountingZone, objectType string, timestamp int64, in, out int64) {
+	if timestamp <= 0 {
+		timestamp = time.Now().Unix() * 1000
+	}
+	minute := timestamp - timestamp%time.Minute.Milliseconds()
+	key := countingBucketKey(zone.DeviceName, zone.Name, minute, objectType)
+
+	sample, ok := cm.pending[key]
+	if !ok {
+		sample = &models.CountingSample{
+			DeviceName: zone.DeviceName,
+			Zone:       zone.Name,
+			ObjectType: objectType,
+			Timestamp:  minute,
+		}
+		cm.pending[key] = sample
+	}
+	sample.In += in
+	sample.Out += out
+	if zone.Type == models.CountingZoneTypeArea {
+		occupancy := cm.occupancy[occupancyKey(zone.DeviceName, zone.Name, objectType)]
+		if occupancy > sample.Occupancy {
+			sample.Occupancy = occupancy
+		}
+	}
+}
+
+// flush merges the pending samples into the stored ones (datastore is accessed without holding the counting lock)
+func (cm *CountingManager) flush() {
+	cm.flushMux.Lock()
+	defer cm.flushMux.Unlock()
+
+	cm.mux.Lock()
+	pending := cm.pending
+	cm.pending = make(map[string]*models.CountingSample)
+	cm.mux.Unlock()
+
+	for key, sample := range pending {
+		existing, err := cm.storage.Get(models.PrefixCountingBucket, key)
+		if err == nil {
+			var stored models.CountingSample
+			if uErr := json.Unmarshal(existing, &stored); uErr != nil {
+				g.Log.Error("failed to unmarshal counting sample", key, uErr)
+			}
+			sample.In += stored.In
+			sample.Out += stored.Out
+			if stored.Occupancy > sample.Occupancy {
+				sample.Occupancy = stored.Occupancy
+			}
+		} else if err != badger.ErrKeyNotFound {
+			g.Log.Error("failed to read counting sample", key, err)
+			continue
+		}
+		if err := cm.storeSample(key, sample); err != nil {
+			g.Log.Error("failed to store counting sample", key, err)
+		}
+	}
+}
+
+func (cm *CountingManager) storeSample(key string, sample *models.CountingSample) error {
+	b, err := json.Marshal(sample)
+	if err != nil {
+		g.Log.Error("failed to marshal counting sample", err)
+		return err
+	}
+	if cm.retention > 0 {
+		ttl := cm.retention - time.Since(time.Unix(0, sample.Timestamp*int64(time.Millisecond)))
+		if ttl <= 0 {
+			return nil
+		}
+		return cm.storage.PutWithTTL(models.PrefixCountingBucket, key, b, ttl)
+	}
+	return cm.storage.Put(models.PrefixCountingBucket, key, b)
+}
+
+// expireTracks forgets objects not seen for a while and counts them out of the areas they were in
+func (cm *CountingManager) expireTracks(now time.Time) {
+	timeout := cm.trackTimeout
+
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	for key, track := range cm.tracks {
+		if now.Sub(track.lastSeen) < timeout {
+			continue
+		}
+		for _, zone := range cm.zones[track.deviceName] {
+			if track.inside[zone.Name] {
+				cm.occupancy[occupancyKey(zone.DeviceName, zone.Name, track.objectType)]--
+				cm.record(zone, track.objectType, now.Unix()*1000, 0, 1)
+			}
+		}
+		delete(cm.tracks, key)
+	}
+}
+
+// resetZoneState clears occupancy and tracked objects state of the zone (expected to be called under lock)
+func (cm *CountingManager) resetZoneState(deviceName, zoneName string) {
+	for key := range cm.occupancy {
+		if strings.HasPrefix(key, deviceName+"/"+zoneName+"/") {
+			delete(cm.occupancy, key)
+		}
+	}
+	for _, track := range cm.tracks {
+		if track.deviceName == deviceName {
+			delete(track.inside, zoneName)
+		}
+	}
+}
+
+func (cm *CountingManager) getZone(deviceName, zoneName string) (*models.CountingZone, error) {
+	b, err := cm.storage.Get(models.PrefixCountingZone, deviceName+"/"+zoneName)
+	if err != nil {
+		return nil, err
+	}
+	var zone models.CountingZone
+	err = json.Unmarshal(b, &zone)
+	if err != nil {
+		g.Log.Error("failed to unmarshal counting zone", err)
+		return nil, err
+	}
+	return &zone, nil
+}
+
+func (cm *CountingManager) loadZones() error {
+	objects, err := cm.storage.List(models.PrefixCountingZone)
+	if err != nil {
+		return err
+	}
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+	for _, v := range objects {
+		var zone models.CountingZone
+		err := json.Unmarshal(v, &zone)
+		if err != nil {
+			g.Log.Error("failed to unmarshal counting zone", err)
+			continue
+		}
+		cm.zones[zone.DeviceName] = append(cm.zones[zone.DeviceName], &zone)
+	}
+	return nil
+}
+
+func countingBucketKey(deviceName, zoneName string, minute int64, objectType string) string {
+	return fmt.Sprintf("%s/%s/%013d/%s", deviceName, zoneName, minute, objectType)
+}
+
+func occupancyKey(deviceName, zoneName, objectType string) string {
+	return deviceName + "/" + zoneName + "/" + objectType
+}
+
+func zoneCountsObjectType(zone *models.CountingZone, objectType string) bool {
+	if len(zone.ObjectTypes) == 0 {
+		return true
+	}
+	for _, ot := range zone.ObjectTypes {
+		if ot == objectType {
+			return true
+		}
+	}
+	return false
+}
+
+// lineCrossing returns 1 if movement from->to crosses the line a->b from its left to its right side,
+// -1 if crossing from right to left and 0 if there is no crossing
+func lineCrossing(from, to, a, b models.Point) int {
+	side := func(p, s, e models.Point) float64 {
+		return (e.X-s.X)*(p.Y-s.Y) - (e.Y-s.Y)*(p.X-s.X)
+	}
+	sFrom := side(from, a, b)
+	sTo := side(to, a, b)
+	if (sFrom >= 0) == (sTo >= 0) {
+		return 0
+	}
+	// line endpoints must be on opposite sides of the movement
+	if side(a, from, to)*side(b, from, to) > 0 {
+		return 0
+	}
+	if sFrom < 0 {
+		return 1
+	}
+	return -1
+}
+
+// pointInPolygon ray casting test
+func pointInPolygon(p models.Point, polygon []*models.Point) bool {
+	inside := false
+	j := len(polygon) - 1
+	for i := 0; i < len(polygon); i++ {
+		pi := polygon[i]
+		pj := polygon[j]
+		if (pi.Y > p.Y) != (pj.Y > p.Y) && p.X < (pj.X-pi.X)*(p.Y-pi.Y)/(pj.Y-pi.Y)+pi.X {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
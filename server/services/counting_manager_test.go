@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func countingAnnotation(trackingID string, x, y float64, ts int64) *pb.AnnotateRequest {
+	return &pb.AnnotateRequest{
+		DeviceName:       "countingcam",
+		Type:             "moving",
+		ObjectType:       "person",
+		ObjectTrackingId: trackingID,
+		StartTimestamp:   ts,
+		ObjectCoordinate: &pb.Coordinate{X: x, Y: y},
+	}
+}
+
+func TestLineCrossing(t *testing.T) {
+	a := models.Point{X: 0, Y: 10}
+	b := models.Point{X: 100, Y: 10}
+
+	if d := lineCrossing(models.Point{X: 50, Y: 0}, models.Point{X: 50, Y: 20}, a, b); d != 1 {
+		t.Fatalf("expected in crossing, got %v", d)
+	}
+	if d := lineCrossing(models.Point{X: 50, Y: 20}, models.Point{X: 50, Y: 0}, a, b); d != -1 {
+		t.Fatalf("expected out crossing, got %v", d)
+	}
+	if d := lineCrossing(models.Point{X: 150, Y: 0}, models.Point{X: 150, Y: 20}, a, b); d != 0 {
+		t.Fatalf("expected no crossing outside of line segment, got %v", d)
+	}
+}
+
+func TestCountingLineAndArea(t *testing.T) {
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	cm := NewCountingManager(NewStorage(db))
+	defer cm.Close()
+
+	_, err = cm.PutZone(&models.CountingZone{
+		Name:       "door",
+		DeviceName: "countingcam",
+		Type:       models.CountingZoneTypeLine,
+		Points:     []*models.Point{{X: 0, Y: 10}, {X: 100, Y: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cm.PutZone(&models.CountingZone{
+		Name:       "room",
+		DeviceName: "countingcam",
+		Type:       models.CountingZoneTypeArea,
+		Points:     []*models.Point{{X: 0, Y: 10}, {X: 100, Y: 10}, {X: 100, Y: 100}, {X: 0, Y: 100}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Date(2020, 10, 10, 10, 10, 0, 0, time.UTC).Unix() * 1000
+	cm.Process(countingAnnotation("1", 50, 0, ts))
+	cm.Process(countingAnnotation("1", 50, 20, ts+1000))
+	cm.Process(countingAnnotation("2", 20, 0, ts+2000))
+	cm.Process(countingAnnotation("2", 20, 50, ts+61000))
+
+	occupancy := cm.Occupancy("countingcam")
+	if len(occupancy) != 1 || occupancy[0].Occupancy != 2 {
+		t.Fatalf("expected occupancy of 2 in room, got %v", occupancy)
+	}
+
+	counts, err := cm.Counts("countingcam", "door", "", ts, ts+time.Hour.Milliseconds(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0].In != 1 || counts[1].In != 1 {
+		t.Fatalf("expected one in crossing per minute, got %v", counts)
+	}
+
+	later, err := cm.Counts("countingcam", "door", "", ts+time.Minute.Milliseconds(), ts+time.Hour.Milliseconds(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(later) != 1 || later[0].Timestamp != ts+time.Minute.Milliseconds() {
+		t.Fatalf("expected only the second minute within range, got %v", later)
+	}
+
+	hourly, err := cm.Counts("countingcam", "door", "person", ts, ts+time.Hour.Milliseconds(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hourly) != 1 || hourly[0].In != 2 || hourly[0].Out != 0 {
+		t.Fatalf("expected 2 in crossings within an hour, got %v", hourly)
+	}
+
+	rooms, err := cm.Counts("countingcam", "room", "person", ts, ts+time.Hour.Milliseconds(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 1 || rooms[0].Occupancy != 2 {
+		t.Fatalf("expected max occupancy 2, got %v", rooms)
+	}
+}
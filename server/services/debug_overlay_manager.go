@@ -0,0 +1,464 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/go-redis/redis/v7"
+	"github.com/golang/protobuf/proto"
+	"github.com/rs/xid"
+)
+
+const (
+	defaultOverlayToleranceMs = 500
+	overlayDecodeLookaheadMs  = 2000 // in-memory buffer decodes in batches, query a bit past the requested timestamp
+	overlayDecodeTimeout      = time.Second * 15
+	overlayLiveMaxDistanceMs  = 1000 // live frame is used only if this close to the requested timestamp
+	overlayJPEGQuality        = 85
+)
+
+var overlayPalette = []color.RGBA{
+	{R: 255, G: 56, B: 56, A: 255},
+	{R: 72, G: 249, B: 10, A: 255},
+	{R: 0, G: 194, B: 255, A: 255},
+	{R: 255, G: 178, B: 29, A: 255},
+	{R: 207, G: 210, B: 49, A: 255},
+	{R: 146, G: 204, B: 23, A: 255},
+	{R: 255, G: 55, B: 199, A: 255},
+	{R: 132, G: 56, B: 255, A: 255},
+}
+
+// DebugOverlay - frame with annotations drawn on it
+type DebugOverlay struct {
+	Image          []byte // JPEG
+	FrameTimestamp int64
+	Width          int
+	Height         int
+	Annotations    []*pb.AnnotateRequest
+}
+
+// DebugOverlayManager - renders annotations from the local annotation store onto the nearest decoded frame
+type DebugOverlayManager struct {
+	rdb                *redis.Client
+	annotationStore    *AnnotationStore
+	privacyMaskManager *PrivacyMaskManager
+}
+
+func NewDebugOverlayManager(rdb *redis.Client, annotationStore *AnnotationStore, privacyMaskManager *PrivacyMaskManager) *DebugOverlayManager {
+	return &DebugOverlayManager{
+		rdb:                rdb,
+		annotationStore:    annotationStore,
+		privacyMaskManager: privacyMaskManager,
+	}
+}
+
+// Render returns the nearest frame to the timestamp (ms) with annotations within tolerance drawn on it
+func (om *DebugOverlayManager) Render(deviceID string, timestamp, toleranceMs int64) (*DebugOverlay, error) {
+	if deviceID == "" {
+		return nil, models.ErrMissingInputParameters
+	}
+	if timestamp <= 0 {
+		timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	if toleranceMs <= 0 {
+		toleranceMs = defaultOverlayToleranceMs
+	}
+
+	vf, frameTs, err := om.nearestFrame(deviceID, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	om.privacyMaskManager.Apply(deviceID, vf)
+
+	img, err := frameImage(vf)
+	if err != nil {
+		g.Log.Error("failed to convert frame to image", deviceID, err)
+		return nil, err
+	}
+
+	annotations := nearestAnnotations(om.annotationStore.Query(deviceID, frameTs-toleranceMs, frameTs+toleranceMs), frameTs)
+	drawAnnotations(img, annotations)
+
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: overlayJPEGQuality})
+	if err != nil {
+		g.Log.Error("failed to encode debug overlay jpeg", deviceID, err)
+		return nil, err
+	}
+	return &DebugOverlay{
+		Image:          buf.Bytes(),
+		FrameTimestamp: frameTs,
+		Width:          img.Rect.Dx(),
+		Height:         img.Rect.Dy(),
+		Annotations:    annotations,
+	}, nil
+}
+
+// nearestFrame looks for the frame in the live decoded stream first (exact timestamps)
+// and falls back to decoding the in-memory buffer
+func (om *DebugOverlayManager) nearestFrame(deviceID string, timestamp int64) (*pb.VideoFrame, int64, error) {
+	live, err := om.rdb.XRevRangeN(deviceID, "+", "-", 10).Result()
+	if err == nil && len(live) > 0 && redisIDTimestamp(live[len(live)-1].ID) <= timestamp {
+		best := live[0]
+		for _, msg := range live {
+			if absInt64(redisIDTimestamp(msg.ID)-timestamp) < absInt64(redisIDTimestamp(best.ID)-timestamp) {
+				best = msg
+			}
+		}
+		vf, ok := redisFrame(best)
+		if ok && absInt64(redisIDTimestamp(best.ID)-timestamp) <= overlayLiveMaxDistanceMs {
+			return vf, redisIDTimestamp(best.ID), nil
+		}
+	}
+	return om.bufferedFrame(deviceID, timestamp)
+}
+
+// bufferedFrame decodes the in-memory buffer around the timestamp and returns the nearest frame.
+// Decoded frames follow the compressed packets after the preceding key frame in order (one frame per packet),
+// which is used to assign the packet timestamps to decoded frames.
+func (om *DebugOverlayManager) bufferedFrame(deviceID string, timestamp int64) (*pb.VideoFrame, int64, error) {
+	iframes, err := om.rdb.XRange(models.RedisInMemoryIFrameListPrefix+deviceID, "-", "+").Result()
+	if err != nil || len(iframes) == 0 {
+		return nil, 0, models.ErrFrameNotFound
+	}
+	keyframeID := iframes[0].ID
+	for _, f := range iframes {
+		if redisIDTimestamp(f.ID) > timestamp {
+			break
+		}
+		keyframeID = f.ID
+	}
+	to := timestamp + overlayDecodeLookaheadMs
+	packets, err := om.rdb.XRange(models.RedisInMemoryQueue+deviceID, keyframeID, strconv.FormatInt(to, 10)).Result()
+	if err != nil || len(packets) < 2 {
+		return nil, 0, models.ErrFrameNotFound
+	}
+	packetTimes := make([]int64, 0, len(packets)-1)
+	for _, p := range packets[1:] {
+		packetTimes = append(packetTimes, redisIDTimestamp(p.ID))
+	}
+
+	pubsubMsg := &models.PubSubMessage{
+		DeviceID:      deviceID,
+		FromTimestamp: timestamp,
+		ToTimestamp:   to,
+		RequestID:     xid.New().String(),
+	}
+	pubSubMsgBytes, err := json.Marshal(pubsubMsg)
+	if err != nil {
+		return nil, 0, err
+	}
+	streamName := models.RedisInMemoryDecodedImagesPrefix + deviceID + pubsubMsg.RequestID
+	defer om.rdb.Del(streamName)
+	om.rdb.Publish(models.RedisInMemoryBufferChannel, base64.StdEncoding.EncodeToString(pubSubMsgBytes))
+
+	var best *pb.VideoFrame
+	bestTs := int64(0)
+	index := 0
+	lastID := "0-0"
+	started := time.Now()
+	for time.Since(started) < overlayDecodeTimeout {
+		vals, err := om.rdb.XRead(&redis.XReadArgs{Streams: []string{streamName, lastID}, Block: time.Millisecond * 50, Count: 10}).Result()
+		if err != nil {
+			continue
+		}
+		for _, val := range vals {
+			for _, msg := range val.Messages {
+				lastID = msg.ID
+				vf, ok := redisFrame(msg)
+				if !ok {
+					continue
+				}
+				if vf.Data == nil {
+					// end of decoding
+					if best == nil {
+						return nil, 0, models.ErrFrameNotFound
+					}
+					return best, bestTs, nil
+				}
+				frameTs := packetTimes[len(packetTimes)-1]
+				if index < len(packetTimes) {
+					frameTs = packetTimes[index]
+				}
+				index++
+				if best == nil || absInt64(frameTs-timestamp) < absInt64(bestTs-timestamp) {
+					best = vf
+					bestTs = frameTs
+				}
+			}
+		}
+	}
+	if best == nil {
+		return nil, 0, models.ErrFrameNotFound
+	}
+	return best, bestTs, nil
+}
+
+// nearestAnnotations keeps only the annotation closest to the frame timestamp per tracked object
+func nearestAnnotations(annotations []*pb.AnnotateRequest, frameTs int64) []*pb.AnnotateRequest {
+	result := make([]*pb.AnnotateRequest, 0)
+	tracked := make(map[string]int)
+	for _, a := range annotations {
+		if a.ObjectTrackingId == "" {
+			result = append(result, a)
+			continue
+		}
+		key := a.Type + "/" + a.ObjectTrackingId
+		if i, ok := tracked[key]; ok {
+			if absInt64(a.StartTimestamp-frameTs) < absInt64(result[i].StartTimestamp-frameTs) {
+				result[i] = a
+			}
+			continue
+		}
+		tracked[key] = len(result)
+		result = append(result, a)
+	}
+	return result
+}
+
+// drawAnnotations draws bounding boxes, masks, coordinates and labels (object type, tracking id, confidence)
+func drawAnnotations(img *image.RGBA, annotations []*pb.AnnotateRequest) {
+	width := img.Rect.Dx()
+	height := img.Rect.Dy()
+	thickness := 1 + width/640
+	scale := 1 + width/960
+
+	for _, a := range annotations {
+		// annotation coordinates are relative to the annotated image size
+		sx, sy := 1.0, 1.0
+		if a.Width > 0 && a.Height > 0 {
+			sx = float64(width) / float64(a.Width)
+			sy = float64(height) / float64(a.Height)
+		}
+		c := overlayColor(a)
+		labelPos := image.Point{}
+		hasLabelPos := false
+
+		if len(a.Mask) >= 3 {
+			polygon := make([]image.Point, 0, len(a.Mask))
+			for _, p := range a.Mask {
+				if p == nil {
+					break
+				}
+				point, ok := overlayPoint(p.X*sx, p.Y*sy, width, height)
+				if !ok {
+					break
+				}
+				polygon = append(polygon, point)
+			}
+			if len(polygon) == len(a.Mask) {
+				utils.DrawPolygon(img, polygon, c, thickness)
+				labelPos = polygon[0]
+				hasLabelPos = true
+			} else {
+				g.Log.Warn("debug overlay skips invalid mask of annotation", a.DeviceName, a.ObjectTrackingId)
+			}
+		}
+		if bb := a.ObjectBoudingBox; bb != nil && bb.Width > 0 && bb.Height > 0 {
+			min, minOk := overlayPoint(float64(bb.Left)*sx, float64(bb.Top)*sy, width, height)
+			max, maxOk := overlayPoint((float64(bb.Left)+float64(bb.Width))*sx, (float64(bb.Top)+float64(bb.Height))*sy, width, height)
+			if minOk && maxOk {
+				r := image.Rectangle{Min: min, Max: max}
+				utils.DrawRect(img, r, c, thickness)
+				labelPos = r.Min
+				hasLabelPos = true
+			} else {
+				g.Log.Warn("debug overlay skips invalid bounding box of annotation", a.DeviceName, a.ObjectTrackingId)
+			}
+		}
+		if oc := a.ObjectCoordinate; oc != nil {
+			if p, ok := overlayPoint(oc.X*sx, oc.Y*sy, width, height); ok {
+				utils.DrawLine(img, p.X-4*thickness, p.Y, p.X+4*thickness, p.Y, c, thickness)
+				utils.DrawLine(img, p.X, p.Y-4*thickness, p.X, p.Y+4*thickness, c, thickness)
+				if !hasLabelPos {
+					labelPos = p
+					hasLabelPos = true
+				}
+			} else {
+				g.Log.Warn("debug overlay skips invalid coordinate of annotation", a.DeviceName, a.ObjectTrackingId)
+			}
+		}
+		if !hasLabelPos {
+			continue
+		}
+
+		label := overlayLabel(a)
+		_, textHeight := utils.TextSize(label, scale)
+		y := labelPos.Y - textHeight
+		if y < 0 {
+			y = labelPos.Y
+		}
+		utils.DrawText(img, labelPos.X, y, label, color.RGBA{R: 255, G: 255, B: 255, A: 255}, c, scale)
+	}
+}
+
+// overlayPoint converts the scaled annotation coordinate to the image point. Coordinates that are not finite
+// or lie further than one image size outside of the frame are rejected.
+func overlayPoint(x, y float64, width, height int) (image.Point, bool) {
+	if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return image.Point{}, false
+	}
+	if x < -float64(width) || x > float64(2*width) || y < -float64(height) || y > float64(2*height) {
+		return image.Point{}, false
+	}
+	return image.Point{X: int(x), Y: int(y)}, true
+}
+
+func overlayLabel(a *pb.AnnotateRequest) string {
+	parts := make([]string, 0, 3)
+	if a.ObjectType != "" {
+		parts = append(parts, a.ObjectType)
+	} else if len(a.Labels) > 0 && a.Labels[0] != nil {
+		parts = append(parts, a.Labels[0].Label)
+	} else {
+		parts = append(parts, a.Type)
+	}
+	if a.ObjectTrackingId != "" {
+		parts = append(parts, "#"+a.ObjectTrackingId)
+	}
+	if a.Confidence > 0 {
+		parts = append(parts, fmt.Sprintf("%.2f", a.Confidence))
+	}
+	return strings.Join(parts, " ")
+}
+
+// overlayColor stable color per tracked object (or object type)
+func overlayColor(a *pb.AnnotateRequest) color.RGBA {
+	key := a.ObjectTrackingId
+	if key == "" {
+		key = a.ObjectType
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return overlayPalette[h.Sum32()%uint32(len(overlayPalette))]
+}
+
+// frameImage converts raw decoded frame (bgr24 by default, or pix_fmt) to an RGBA image
+func frameImage(vf *pb.VideoFrame) (*image.RGBA, error) {
+	width, height, planes, err := frameLayout(vf)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	data := vf.Data
+
+	if len(planes) == 1 {
+		plane := planes[0]
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				idx := (y*width + x) * plane.channels
+				var c color.RGBA
+				switch plane.channels {
+				case 1:
+					c = color.RGBA{R: data[idx], G: data[idx], B: data[idx], A: 255}
+				case 3:
+					if vf.PixFmt == "rgb24" {
+						c = color.RGBA{R: data[idx], G: data[idx+1], B: data[idx+2], A: 255}
+					} else {
+						c = color.RGBA{R: data[idx+2], G: data[idx+1], B: data[idx], A: 255}
+					}
+				case 4:
+					switch vf.PixFmt {
+					case "rgba":
+						c = color.RGBA{R: data[idx], G: data[idx+1], B: data[idx+2], A: 255}
+					case "argb":
+						c = color.RGBA{R: data[idx+1], G: data[idx+2], B: data[idx+3], A: 255}
+					case "abgr":
+						c = color.RGBA{R: data[idx+3], G: data[idx+2], B: data[idx+1], A: 255}
+					default:
+						c = color.RGBA{R: data[idx+2], G: data[idx+1], B: data[idx], A: 255}
+					}
+				default:
+					return nil, fmt.Errorf("unsupported number of channels %d", plane.channels)
+				}
+				img.SetRGBA(x, y, c)
+			}
+		}
+		return img, nil
+	}
+
+	// planar or semi-planar yuv 4:2:0
+	luma := planes[0]
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			yy := data[luma.offset+y*width+x]
+			var cb, cr byte
+			if len(planes) == 3 {
+				ci := (y/2)*planes[1].width + x/2
+				cb = data[planes[1].offset+ci]
+				cr = data[planes[2].offset+ci]
+			} else {
+				ci := planes[1].offset + ((y/2)*planes[1].width+x/2)*2
+				cb, cr = data[ci], data[ci+1]
+				if vf.PixFmt == "nv21" {
+					cb, cr = cr, cb
+				}
+			}
+			r, gg, b := color.YCbCrToRGB(yy, cb, cr)
+			img.SetRGBA(x, y, color.RGBA{R: r, G: gg, B: b, A: 255})
+		}
+	}
+	return img, nil
+}
+
+func redisFrame(msg redis.XMessage) (*pb.VideoFrame, bool) {
+	val, ok := msg.Values["data"]
+	if !ok {
+		return nil, false
+	}
+	str, ok := val.(string)
+	if !ok {
+		return nil, false
+	}
+	vf := &pb.VideoFrame{}
+	err := proto.Unmarshal([]byte(str), vf)
+	if err != nil {
+		g.Log.Error("failed to unmarshall VideoFrame proto", err)
+		return nil, false
+	}
+	return vf, true
+}
+
+// redisIDTimestamp returns ms timestamp part of redis stream ID
+func redisIDTimestamp(id string) int64 {
+	ts, err := strconv.ParseInt(strings.Split(id, "-")[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return ts
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
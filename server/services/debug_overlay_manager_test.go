@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func TestDebugOverlayDrawing(t *testing.T) {
+	vf := bgrFrame(64, 48)
+	img, err := frameImage(vf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Rect.Dx() != 64 || img.Rect.Dy() != 48 {
+		t.Fatalf("unexpected image size %v", img.Rect)
+	}
+	// bgr24 converted to rgb
+	if c := img.RGBAAt(0, 0); c.R != vf.Data[2] || c.B != vf.Data[0] {
+		t.Fatalf("unexpected pixel color %v", c)
+	}
+
+	annotations := nearestAnnotations([]*pb.AnnotateRequest{
+		{Type: "moving", ObjectType: "person", ObjectTrackingId: "1", StartTimestamp: 900, Confidence: 0.5},
+		{Type: "moving", ObjectType: "person", ObjectTrackingId: "1", StartTimestamp: 1010, Confidence: 0.8,
+			ObjectBoudingBox: &pb.BoudingBox{Left: 16, Top: 16, Width: 16, Height: 16}, Width: 128, Height: 96},
+		{Type: "moving", ObjectType: "car", StartTimestamp: 1000, Mask: []*pb.Coordinate{{X: 2, Y: 40}, {X: 10, Y: 40}, {X: 10, Y: 46}}},
+	}, 1000)
+	if len(annotations) != 2 || annotations[0].Confidence != 0.8 {
+		t.Fatalf("expected nearest annotation per tracked object, got %v", annotations)
+	}
+
+	drawAnnotations(img, annotations)
+	// bounding box scaled from 128x96 annotated image to 64x48 frame
+	if c := img.RGBAAt(8, 16); c != overlayColor(annotations[0]) {
+		t.Fatalf("expected bounding box edge at 8,16, got %v", c)
+	}
+	if c := img.RGBAAt(6, 40); c != overlayColor(annotations[1]) {
+		t.Fatalf("expected mask outline at 6,40, got %v", c)
+	}
+	if overlayLabel(annotations[0]) != "person #1 0.80" {
+		t.Fatalf("unexpected label %v", overlayLabel(annotations[0]))
+	}
+
+	// absurd client coordinates are rejected instead of iterated
+	before := append([]uint8(nil), img.Pix...)
+	drawAnnotations(img, []*pb.AnnotateRequest{
+		{Type: "moving", ObjectType: "car", ObjectCoordinate: &pb.Coordinate{X: 1e300, Y: 1e300}},
+		{Type: "moving", ObjectType: "car", Mask: []*pb.Coordinate{{X: -1e12, Y: 0}, {X: 1e12, Y: 0}, {X: 0, Y: 1e12}}},
+		{Type: "moving", ObjectType: "car", ObjectBoudingBox: &pb.BoudingBox{Left: 2147483000, Top: 0, Width: 2147483000, Height: 10}},
+	})
+	if string(before) != string(img.Pix) {
+		t.Fatal("expected annotations with invalid coordinates to be skipped")
+	}
+}
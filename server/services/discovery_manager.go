@@ -0,0 +1,380 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+const (
+	wsDiscoveryMulticastAddr = "239.255.255.250:3702"
+	onvifRequestTimeout      = 5 * time.Second
+	onvifMaxResponseBytes    = 1 << 20
+
+	onvifScopeName     = "onvif://www.onvif.org/name/"
+	onvifScopeHardware = "onvif://www.onvif.org/hardware/"
+	onvifScopeLocation = "onvif://www.onvif.org/location/"
+)
+
+var errONVIFUnauthorized = errors.New("onvif authentication failed")
+
+// characters not allowed in the suggested camera names
+var cameraNameRegex = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+const wsDiscoveryProbe = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing">
+<s:Header><a:Action s:mustUnderstand="1">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</a:Action><a:MessageID>uuid:%s</a:MessageID><a:ReplyTo><a:Address>http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo><a:To s:mustUnderstand="1">urn:schemas-xmlsoap-org:ws:2005:04:discovery</a:To></s:Header>
+<s:Body><Probe xmlns="http://schemas.xmlsoap.org/ws/2005/04/discovery"><d:Types xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" xmlns:dn="http://www.onvif.org/ver10/network/wsdl">dn:NetworkVideoTransmitter</d:Types></Probe></s:Body>
+</s:Envelope>`
+
+const onvifEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Header>%s</s:Header><s:Body>%s</s:Body></s:Envelope>`
+
+const onvifUsernameToken = `<Security s:mustUnderstand="1" xmlns="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"><UsernameToken><Username>%s</Username><Password Type="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest">%s</Password><Nonce EncodingType="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary">%s</Nonce><Created xmlns="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd">%s</Created></UsernameToken></Security>`
+
+const (
+	onvifGetCapabilities = `<GetCapabilities xmlns="http://www.onvif.org/ver10/device/wsdl"><Category>Media</Category></GetCapabilities>`
+	onvifGetProfiles     = `<GetProfiles xmlns="http://www.onvif.org/ver10/media/wsdl"/>`
+	onvifGetStreamURI    = `<GetStreamUri xmlns="http://www.onvif.org/ver10/media/wsdl"><StreamSetup><Stream xmlns="http://www.onvif.org/ver10/schema">RTP-Unicast</Stream><Transport xmlns="http://www.onvif.org/ver10/schema"><Protocol>RTSP</Protocol></Transport></StreamSetup><ProfileToken>%s</ProfileToken></GetStreamUri>`
+)
+
+type wsProbeMatches struct {
+	Matches []struct {
+		Address string `xml:"EndpointReference>Address"`
+		Scopes  string `xml:"Scopes"`
+		XAddrs  string `xml:"XAddrs"`
+	} `xml:"Body>ProbeMatches>ProbeMatch"`
+}
+
+type onvifCapabilities struct {
+	MediaXAddr string `xml:"Body>GetCapabilitiesResponse>Capabilities>Media>XAddr"`
+}
+
+type onvifProfiles struct {
+	Profiles []struct {
+		Token        string `xml:"token,attr"`
+		Name         string `xml:"Name"`
+		VideoEncoder *struct {
+			Encoding  string  `xml:"Encoding"`
+			Width     int     `xml:"Resolution>Width"`
+			Height    int     `xml:"Resolution>Height"`
+			FrameRate float64 `xml:"RateControl>FrameRateLimit"`
+		} `xml:"VideoEncoderConfiguration"`
+	} `xml:"Body>GetProfilesResponse>Profiles"`
+}
+
+type onvifStreamURI struct {
+	URI string `xml:"Body>GetStreamUriResponse>MediaUri>Uri"`
+}
+
+type onvifFault struct {
+	Subcode string `xml:"Body>Fault>Code>Subcode>Value"`
+	Reason  string `xml:"Body>Fault>Reason>Text"`
+}
+
+// DiscoveryManager - finds ONVIF cameras on the local network (WS-Discovery) and their RTSP streams
+type DiscoveryManager struct {
+	probeAddr  string // WS-Discovery multicast address
+	httpClient *http.Client
+}
+
+func NewDiscoveryManager() *DiscoveryManager {
+	return &DiscoveryManager{
+		probeAddr:  wsDiscoveryMulticastAddr,
+		httpClient: &http.Client{Timeout: onvifRequestTimeout},
+	}
+}
+
+// Scan probes the network for ONVIF cameras. With credentials the media profiles and stream URIs of each camera are queried.
+func (dm *DiscoveryManager) Scan(request *models.DiscoveryScanRequest) ([]*models.DiscoveredCamera, error) {
+	timeout := time.Duration(models.DefaultDiscoveryTimeoutMs) * time.Millisecond
+	if request.TimeoutMs > 0 {
+		if request.TimeoutMs > models.MaxDiscoveryTimeoutMs {
+			return nil, models.ErrInvalidInputParameters
+		}
+		timeout = time.Duration(request.TimeoutMs) * time.Millisecond
+	}
+
+	cameras, err := dm.probe(timeout)
+	if err != nil {
+		return nil, err
+	}
+	if request.Username != "" || request.Password != "" {
+		var wg sync.WaitGroup
+		for _, camera := range cameras {
+			wg.Add(1)
+			go func(camera *models.DiscoveredCamera) {
+				defer wg.Done()
+				streams, err := dm.streams(camera, request.Username, request.Password)
+				if err != nil {
+					g.Log.Warn("failed to query onvif streams", camera.IP, err)
+					camera.Error = err.Error()
+					return
+				}
+				camera.Streams = streams
+			}(camera)
+		}
+		wg.Wait()
+	}
+	g.Log.Info("onvif discovery finished, cameras found:", len(cameras))
+	return cameras, nil
+}
+
+// probe sends the WS-Discovery probe and collects the answers until the timeout
+func (dm *DiscoveryManager) probe(timeout time.Duration) ([]*models.DiscoveredCamera, error) {
+	addr, err := net.ResolveUDPAddr("udp4", dm.probeAddr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		g.Log.Error("failed to open udp socket for onvif discovery", err)
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteTo([]byte(fmt.Sprintf(wsDiscoveryProbe, newUUID())), addr); err != nil {
+		g.Log.Error("failed to send onvif discovery probe", err)
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Now().Add(timeout))
+
+	found := make(map[string]*models.DiscoveredCamera)
+	buf := make([]byte, 64*1024)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				break
+			}
+			return nil, err
+		}
+		var matches wsProbeMatches
+		if err := xml.Unmarshal(buf[:n], &matches); err != nil {
+			continue
+		}
+		responder, ok := from.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		for _, m := range matches.Matches {
+			xaddrs := responderXAddrs(strings.Fields(m.XAddrs), responder.IP)
+			if len(xaddrs) == 0 {
+				g.Log.Warn("onvif discovery answer without service address on the responder", responder.IP.String(), m.XAddrs)
+				continue
+			}
+			address := strings.TrimSpace(m.Address)
+			if address == "" {
+				address = xaddrs[0]
+			}
+			if _, ok := found[address]; ok {
+				continue
+			}
+			camera := &models.DiscoveredCamera{Address: address, XAddrs: xaddrs}
+			if u, err := url.Parse(xaddrs[0]); err == nil {
+				camera.IP = u.Hostname()
+			}
+			for _, scope := range strings.Fields(m.Scopes) {
+				switch {
+				case strings.HasPrefix(scope, onvifScopeName):
+					camera.Name = unescapeScope(scope, onvifScopeName)
+				case strings.HasPrefix(scope, onvifScopeHardware):
+					camera.Hardware = unescapeScope(scope, onvifScopeHardware)
+				case strings.HasPrefix(scope, onvifScopeLocation):
+					camera.Location = unescapeScope(scope, onvifScopeLocation)
+				}
+			}
+			found[address] = camera
+		}
+	}
+
+	cameras := make([]*models.DiscoveredCamera, 0, len(found))
+	for _, camera := range found {
+		cameras = append(cameras, camera)
+	}
+	sort.Slice(cameras, func(i, j int) bool {
+		if cameras[i].IP != cameras[j].IP {
+			return cameras[i].IP < cameras[j].IP
+		}
+		return cameras[i].Address < cameras[j].Address
+	})
+	return cameras, nil
+}
+
+// streams queries media profiles and their RTSP stream URIs (GetCapabilities, GetProfiles, GetStreamUri)
+func (dm *DiscoveryManager) streams(camera *models.DiscoveredCamera, username string, password string) ([]*models.DiscoveredStream, error) {
+	deviceXAddr := camera.XAddrs[0]
+	mediaXAddr := deviceXAddr
+	var capabilities onvifCapabilities
+	if err := dm.call(deviceXAddr, username, password, onvifGetCapabilities, &capabilities); err != nil {
+		if err == errONVIFUnauthorized {
+			return nil, err
+		}
+		// media service on the device service URL
+		g.Log.Warn("onvif capabilities not available", camera.IP, err)
+	} else if capabilities.MediaXAddr != "" {
+		// credentials are only sent to the camera itself
+		if xaddr := strings.TrimSpace(capabilities.MediaXAddr); xaddrOnHost(xaddr, net.ParseIP(camera.IP)) {
+			mediaXAddr = xaddr
+		} else {
+			g.Log.Warn("onvif media service not on the camera, using device service", camera.IP, xaddr)
+		}
+	}
+
+	var profiles onvifProfiles
+	if err := dm.call(mediaXAddr, username, password, onvifGetProfiles, &profiles); err != nil {
+		return nil, err
+	}
+	streams := make([]*models.DiscoveredStream, 0, len(profiles.Profiles))
+	for _, profile := range profiles.Profiles {
+		var streamURI onvifStreamURI
+		body := fmt.Sprintf(onvifGetStreamURI, xmlEscape(profile.Token))
+		if err := dm.call(mediaXAddr, username, password, body, &streamURI); err != nil {
+			g.Log.Warn("failed to get onvif stream uri", camera.IP, profile.Token, err)
+			continue
+		}
+		if streamURI.URI == "" {
+			continue
+		}
+		stream := &models.DiscoveredStream{
+			Name:         suggestedCameraName(camera, profile.Token),
+			RTSPEndpoint: strings.TrimSpace(streamURI.URI),
+			RTSPUsername: username,
+			Profile:      profile.Token,
+			ProfileName:  profile.Name,
+		}
+		if ve := profile.VideoEncoder; ve != nil {
+			stream.Codec = ve.Encoding
+			stream.Width = ve.Width
+			stream.Height = ve.Height
+			stream.FrameRate = ve.FrameRate
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+// responderXAddrs - http(s) service addresses on the host that answered the probe (credentials are sent to them)
+func responderXAddrs(xaddrs []string, responder net.IP) []string {
+	allowed := make([]string, 0, len(xaddrs))
+	for _, xaddr := range xaddrs {
+		if xaddrOnHost(xaddr, responder) {
+			allowed = append(allowed, xaddr)
+		}
+	}
+	return allowed
+}
+
+// xaddrOnHost - true if xaddr is a http(s) URL of the host
+func xaddrOnHost(xaddr string, host net.IP) bool {
+	if host == nil {
+		return false
+	}
+	u, err := url.Parse(xaddr)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.Equal(host)
+}
+
+// call sends the SOAP request authenticated with the WS-Security username token
+func (dm *DiscoveryManager) call(xaddr string, username string, password string, body string, response interface{}) error {
+	header := ""
+	if username != "" || password != "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		created := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+		digest := sha1.Sum(append(append(nonce, created...), password...))
+		header = fmt.Sprintf(onvifUsernameToken, xmlEscape(username), base64.StdEncoding.EncodeToString(digest[:]), base64.StdEncoding.EncodeToString(nonce), created)
+	}
+	envelope := fmt.Sprintf(onvifEnvelope, header, body)
+
+	resp, err := dm.httpClient.Post(xaddr, "application/soap+xml; charset=utf-8", bytes.NewBufferString(envelope))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, onvifMaxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var fault onvifFault
+		xml.Unmarshal(b, &fault)
+		if resp.StatusCode == http.StatusUnauthorized || strings.HasSuffix(fault.Subcode, "NotAuthorized") {
+			return errONVIFUnauthorized
+		}
+		if fault.Reason != "" {
+			return errors.New("onvif fault: " + strings.TrimSpace(fault.Reason))
+		}
+		return fmt.Errorf("onvif request failed: %s", resp.Status)
+	}
+	return xml.Unmarshal(b, response)
+}
+
+// suggestedCameraName - camera name from the device name (or IP) and the profile token
+func suggestedCameraName(camera *models.DiscoveredCamera, profileToken string) string {
+	name := camera.Name
+	if name == "" {
+		name = camera.IP
+	}
+	name = strings.Trim(cameraNameRegex.ReplaceAllString(strings.ToLower(name+"_"+profileToken), "-"), "-_")
+	return name
+}
+
+func unescapeScope(scope string, prefix string) string {
+	value := strings.TrimPrefix(scope, prefix)
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// newUUID - random (version 4) UUID of the WS-Discovery message
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+const fakeONVIFProbeMatches = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+<s:Body><d:ProbeMatches><d:ProbeMatch><a:EndpointReference><a:Address>urn:uuid:fake-camera</a:Address></a:EndpointReference>
+<d:Scopes>onvif://www.onvif.org/type/video_encoder onvif://www.onvif.org/name/Front%%20Door onvif://www.onvif.org/hardware/FC-100</d:Scopes>
+<d:XAddrs>%s/onvif/device_service</d:XAddrs></d:ProbeMatch></d:ProbeMatches></s:Body></s:Envelope>`
+
+const fakeONVIFProfiles = `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:trt="http://www.onvif.org/ver10/media/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema">
+<s:Body><trt:GetProfilesResponse>
+<trt:Profiles token="main"><tt:Name>MainStream</tt:Name><tt:VideoEncoderConfiguration token="enc0"><tt:Encoding>H264</tt:Encoding><tt:Resolution><tt:Width>1920</tt:Width><tt:Height>1080</tt:Height></tt:Resolution><tt:RateControl><tt:FrameRateLimit>25</tt:FrameRateLimit></tt:RateControl></tt:VideoEncoderConfiguration></trt:Profiles>
+<trt:Profiles token="sub"><tt:Name>SubStream</tt:Name><tt:VideoEncoderConfiguration token="enc1"><tt:Encoding>H264</tt:Encoding><tt:Resolution><tt:Width>640</tt:Width><tt:Height>360</tt:Height></tt:Resolution></tt:VideoEncoderConfiguration></trt:Profiles>
+</trt:GetProfilesResponse></s:Body></s:Envelope>`
+
+const fakeONVIFNotAuthorized = `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:ter="http://www.onvif.org/ver10/error">
+<s:Body><s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>ter:NotAuthorized</s:Value></s:Subcode></s:Code><s:Reason><s:Text xml:lang="en">Sender not authorized</s:Text></s:Reason></s:Fault></s:Body></s:Envelope>`
+
+// fakeONVIFCamera answers WS-Discovery probes over UDP and device and media requests over HTTP (WS-Security digest required)
+func fakeONVIFCamera(t *testing.T, username string, password string) string {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		var token struct {
+			Username string `xml:"Header>Security>UsernameToken>Username"`
+			Password string `xml:"Header>Security>UsernameToken>Password"`
+			Nonce    string `xml:"Header>Security>UsernameToken>Nonce"`
+			Created  string `xml:"Header>Security>UsernameToken>Created"`
+		}
+		xml.Unmarshal(b, &token)
+		nonce, _ := base64.StdEncoding.DecodeString(token.Nonce)
+		digest := sha1.Sum([]byte(string(nonce) + token.Created + password))
+		if token.Username != username || token.Password != base64.StdEncoding.EncodeToString(digest[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, fakeONVIFNotAuthorized)
+			return
+		}
+		body := string(b)
+		switch {
+		case strings.Contains(body, "GetCapabilities"):
+			fmt.Fprintf(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body><GetCapabilitiesResponse><Capabilities><Media><XAddr>%s/onvif/media_service</XAddr></Media></Capabilities></GetCapabilitiesResponse></s:Body></s:Envelope>`, server.URL)
+		case strings.Contains(body, "GetProfiles") && r.URL.Path == "/onvif/media_service":
+			fmt.Fprint(w, fakeONVIFProfiles)
+		case strings.Contains(body, "GetStreamUri") && r.URL.Path == "/onvif/media_service":
+			profile := "main"
+			if strings.Contains(body, "<ProfileToken>sub</ProfileToken>") {
+				profile = "sub"
+			}
+			fmt.Fprintf(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"><s:Body><GetStreamUriResponse><MediaUri><Uri>rtsp://%s/%s</Uri></MediaUri></GetStreamUriResponse></s:Body></s:Envelope>`, r.Host, profile)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if strings.Contains(string(buf[:n]), "NetworkVideoTransmitter") {
+				conn.WriteTo([]byte(fmt.Sprintf(fakeONVIFProbeMatches, server.URL)), addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDiscoveryScan(t *testing.T) {
+	dm := NewDiscoveryManager()
+	dm.probeAddr = fakeONVIFCamera(t, "admin", "secret")
+
+	cameras, err := dm.Scan(&models.DiscoveryScanRequest{Username: "admin", Password: "secret", TimeoutMs: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cameras) != 1 {
+		t.Fatalf("expected 1 camera, got %d", len(cameras))
+	}
+	camera := cameras[0]
+	if camera.Address != "urn:uuid:fake-camera" || camera.IP != "127.0.0.1" || camera.Name != "Front Door" || camera.Hardware != "FC-100" || camera.Error != "" {
+		t.Fatalf("unexpected camera %+v", camera)
+	}
+	if len(camera.Streams) != 2 {
+		t.Fatalf("expected 2 streams, got %d", len(camera.Streams))
+	}
+	main := camera.Streams[0]
+	if main.Name != "front-door_main" || !strings.HasSuffix(main.RTSPEndpoint, "/main") || main.RTSPUsername != "admin" || main.Codec != "H264" || main.Width != 1920 || main.Height != 1080 || main.FrameRate != 25 {
+		t.Fatalf("unexpected main stream %+v", main)
+	}
+	if sub := camera.Streams[1]; sub.Profile != "sub" || !strings.HasSuffix(sub.RTSPEndpoint, "/sub") || sub.Width != 640 {
+		t.Fatalf("unexpected sub stream %+v", sub)
+	}
+
+	// wrong credentials: camera found, streams not available
+	cameras, err = dm.Scan(&models.DiscoveryScanRequest{Username: "admin", Password: "wrong", TimeoutMs: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cameras) != 1 || cameras[0].Error != errONVIFUnauthorized.Error() || len(cameras[0].Streams) != 0 {
+		t.Fatalf("expected authentication error, got %+v", cameras[0])
+	}
+
+	// without credentials only the devices are listed
+	start := time.Now()
+	cameras, err = dm.Scan(&models.DiscoveryScanRequest{TimeoutMs: 300})
+	if err != nil || len(cameras) != 1 || cameras[0].Streams != nil || cameras[0].Error != "" {
+		t.Fatalf("unexpected scan without credentials %v %v", cameras, err)
+	}
+	if time.Since(start) < 300*time.Millisecond {
+		t.Fatal("expected to wait for discovery responses until the timeout")
+	}
+
+	if _, err := dm.Scan(&models.DiscoveryScanRequest{TimeoutMs: models.MaxDiscoveryTimeoutMs + 1}); err != models.ErrInvalidInputParameters {
+		t.Fatalf("expected invalid timeout, got %v", err)
+	}
+}
+
+func TestDiscoveryResponderXAddrs(t *testing.T) {
+	responder := net.IPv4(192, 168, 1, 20)
+	xaddrs := responderXAddrs([]string{
+		"http://192.168.1.20/onvif/device_service",
+		"https://192.168.1.20:8443/onvif/device_service",
+		"http://192.168.1.21/onvif/device_service",
+		"http://169.254.169.254/latest/meta-data",
+		"http://camera.local/onvif/device_service",
+		"file:///etc/passwd",
+		"gopher://192.168.1.20/",
+	}, responder)
+	if len(xaddrs) != 2 || xaddrs[0] != "http://192.168.1.20/onvif/device_service" || xaddrs[1] != "https://192.168.1.20:8443/onvif/device_service" {
+		t.Fatalf("expected only http(s) addresses on the responder, got %v", xaddrs)
+	}
+	if xaddrOnHost("http://192.168.1.20/onvif/media_service", nil) {
+		t.Fatal("expected no address accepted without a known host")
+	}
+}
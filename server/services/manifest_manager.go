@@ -0,0 +1,439 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/go-redis/redis/v7"
+)
+
+const (
+	defaultManifestInterval  = time.Minute
+	maxManifestManualChanges = 100
+)
+
+// ManifestManager - reconciles stored cameras and apps to the declarative site manifest (YAML file in the data directory)
+type ManifestManager struct {
+	storage        *Storage
+	processManager *ProcessManager
+	configTransfer *ConfigTransferManager
+	rdb            *redis.Client
+	mux            sync.Mutex
+	status         *models.ManifestStatus
+	manualChanges  []*models.ManifestManualChange
+}
+
+func NewManifestManager(storage *Storage, processManager *ProcessManager, configTransfer *ConfigTransferManager, rdb *redis.Client) *ManifestManager {
+	return &ManifestManager{
+		storage:        storage,
+		processManager: processManager,
+		configTransfer: configTransfer,
+		rdb:            rdb,
+		manualChanges:  make([]*models.ManifestManualChange, 0),
+	}
+}
+
+// Enabled - manifest mode is active only when enabled in conf.yaml
+func (mm *ManifestManager) Enabled() bool {
+	return g.Conf.Manifest != nil && g.Conf.Manifest.Enabled
+}
+
+// Start checks the manifest on boot and then periodically. With self heal the drift is reconciled, otherwise only reported.
+func (mm *ManifestManager) Start() {
+	if !mm.Enabled() {
+		return
+	}
+	interval := defaultManifestInterval
+	if g.Conf.Manifest.IntervalMs > 0 {
+		interval = time.Duration(g.Conf.Manifest.IntervalMs) * time.Millisecond
+	}
+	go func() {
+		for {
+			var err error
+			if g.Conf.Manifest.SelfHeal {
+				_, err = mm.Reconcile()
+			} else {
+				_, err = mm.Status()
+			}
+			if err != nil {
+				g.Log.Error("site manifest check failed", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// Status - current drift between the manifest and the stored cameras and apps (nothing is applied)
+func (mm *ManifestManager) Status() (*models.ManifestStatus, error) {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+	return mm.check(false)
+}
+
+// Reconcile applies the manifest: missing cameras and apps are created, changed cameras updated, changed apps
+// reinstalled and (when pruning) cameras and apps not in the manifest removed
+func (mm *ManifestManager) Reconcile() (*models.ManifestStatus, error) {
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+	if !mm.Enabled() {
+		return nil, models.ErrForbidden
+	}
+	return mm.check(true)
+}
+
+// ManualChange is called before a camera or app is changed outside of the manifest. Returns models.ErrManifestManaged
+// with the reject policy, with the flag policy the change is allowed and listed in the manifest status.
+func (mm *ManifestManager) ManualChange(processType string, name string, action string, actor string) error {
+	if !mm.Enabled() {
+		return nil
+	}
+	if g.Conf.Manifest.Policy != models.ManifestPolicyFlag {
+		g.Log.Warn("manual change rejected in manifest mode", action, name, actor)
+		return models.ErrManifestManaged
+	}
+	mm.mux.Lock()
+	defer mm.mux.Unlock()
+	g.Log.Warn("manual change flagged in manifest mode", action, name, actor)
+	mm.manualChanges = append(mm.manualChanges, &models.ManifestManualChange{
+		Created: time.Now().Unix() * 1000,
+		Actor:   actor,
+		Action:  action,
+		Type:    processType,
+		Name:    name,
+	})
+	if len(mm.manualChanges) > maxManifestManualChanges {
+		mm.manualChanges = mm.manualChanges[len(mm.manualChanges)-maxManifestManualChanges:]
+	}
+	return nil
+}
+
+func (mm *ManifestManager) check(apply bool) (*models.ManifestStatus, error) {
+	status := &models.ManifestStatus{
+		Enabled: mm.Enabled(),
+		Drift:   make([]*models.ManifestDrift, 0),
+	}
+	if !status.Enabled {
+		return status, nil
+	}
+	status.Path = g.Conf.Manifest.Path
+	status.Policy = g.Conf.Manifest.Policy
+	if status.Policy == "" {
+		status.Policy = models.ManifestPolicyReject
+	}
+	status.ManualChanges = mm.manualChanges
+	if mm.status != nil {
+		status.LastReconciled = mm.status.LastReconciled
+		status.Results = mm.status.Results
+	}
+	mm.status = status
+
+	data, err := ioutil.ReadFile(status.Path)
+	if err != nil {
+		g.Log.Error("failed to read site manifest", status.Path, err)
+		status.Error = err.Error()
+		return status, err
+	}
+	manifest, err := ParseConfigBundle(data, models.ConfigFormatYAML)
+	if err != nil {
+		g.Log.Error("failed to parse site manifest", status.Path, err)
+		status.Error = err.Error()
+		return status, err
+	}
+	sum := sha256.Sum256(data)
+	status.Checksum = hex.EncodeToString(sum[:])
+	status.Loaded = time.Now().Unix() * 1000
+
+	drift, err := mm.drift(manifest)
+	if err != nil {
+		return status, err
+	}
+	status.Drift = drift
+	if !apply || len(drift) == 0 {
+		return status, nil
+	}
+
+	results, err := mm.apply(manifest, drift)
+	if err != nil {
+		return status, err
+	}
+	status.Results = results
+	status.LastReconciled = time.Now().Unix() * 1000
+	// flagged changes are reverted by now
+	mm.manualChanges = make([]*models.ManifestManualChange, 0)
+	status.ManualChanges = nil
+	if status.Drift, err = mm.drift(manifest); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// drift compares the manifest with the stored cameras and apps
+func (mm *ManifestManager) drift(manifest *models.ConfigBundle) ([]*models.ManifestDrift, error) {
+	drift := make([]*models.ManifestDrift, 0)
+
+	cameras, err := mm.storedCameras()
+	if err != nil {
+		return nil, err
+	}
+	declaredCameras := make(map[string]bool)
+	for _, camera := range manifest.Cameras {
+		if validateImportedCamera(camera) != nil {
+			// reported as invalid when applied
+			continue
+		}
+		declaredCameras[camera.Name] = true
+		stored, ok := cameras[camera.Name]
+		if !ok {
+			drift = append(drift, &models.ManifestDrift{Type: models.ProcessTypeRTSP, Name: camera.Name, Kind: models.DriftMissing})
+			continue
+		}
+		// endpoints are compared including the stored credentials
+		stored, err := mm.processManager.withEndpointCredentials(stored)
+		if err != nil {
+			return nil, err
+		}
+		declared, err := mm.processManager.withEndpointCredentials(camera)
+		if err != nil {
+			return nil, err
+		}
+		if fields := cameraDriftFields(stored, declared); len(fields) > 0 {
+			drift = append(drift, &models.ManifestDrift{Type: models.ProcessTypeRTSP, Name: camera.Name, Kind: models.DriftChanged, Fields: fields})
+		}
+	}
+
+	apps, err := mm.storedApps()
+	if err != nil {
+		return nil, err
+	}
+	declaredApps := make(map[string]bool)
+	for _, app := range manifest.Apps {
+		if validateImportedApp(app) != nil {
+			continue
+		}
+		declaredApps[app.Name] = true
+		stored, ok := apps[app.Name]
+		if !ok {
+			drift = append(drift, &models.ManifestDrift{Type: models.ProcessTypeApplication, Name: app.Name, Kind: models.DriftMissing})
+			continue
+		}
+		if fields := appDriftFields(stored, app); len(fields) > 0 {
+			drift = append(drift, &models.ManifestDrift{Type: models.ProcessTypeApplication, Name: app.Name, Kind: models.DriftChanged, Fields: fields})
+		}
+	}
+
+	for name := range cameras {
+		if !declaredCameras[name] {
+			drift = append(drift, &models.ManifestDrift{Type: models.ProcessTypeRTSP, Name: name, Kind: models.DriftUnmanaged})
+		}
+	}
+	for name := range apps {
+		if !declaredApps[name] {
+			drift = append(drift, &models.ManifestDrift{Type: models.ProcessTypeApplication, Name: name, Kind: models.DriftUnmanaged})
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Type != drift[j].Type {
+			return drift[i].Type > drift[j].Type // cameras first
+		}
+		return drift[i].Name < drift[j].Name
+	})
+	return drift, nil
+}
+
+// apply removes changed apps (reinstalled from the manifest once their image is pulled) and unmanaged items when pruning,
+// then imports the missing and changed items
+func (mm *ManifestManager) apply(manifest *models.ConfigBundle, drift []*models.ManifestDrift) ([]*models.ImportItemResult, error) {
+	results := make([]*models.ImportItemResult, 0)
+	pending := &models.ConfigBundle{Version: models.ConfigBundleVersion}
+	camerasByName := make(map[string]*models.StreamProcess)
+	for _, camera := range manifest.Cameras {
+		camerasByName[camera.Name] = camera
+	}
+	appsByName := make(map[string]*models.AppProcess)
+	for _, app := range manifest.Apps {
+		appsByName[app.Name] = app
+	}
+	// invalid manifest items are reported with the results
+	for _, camera := range manifest.Cameras {
+		if err := validateImportedCamera(camera); err != nil {
+			pending.Cameras = append(pending.Cameras, camera)
+		}
+	}
+	for _, app := range manifest.Apps {
+		if err := validateImportedApp(app); err != nil {
+			pending.Apps = append(pending.Apps, app)
+		}
+	}
+
+	for _, d := range drift {
+		prefix := models.PrefixRTSPProcess
+		if d.Type == models.ProcessTypeApplication {
+			prefix = models.PrefixAppProcess
+		}
+		switch {
+		case d.Kind == models.DriftUnmanaged:
+			if !g.Conf.Manifest.Prune {
+				continue
+			}
+			result := &models.ImportItemResult{Type: d.Type, Name: d.Name, Result: models.ImportResultRemoved}
+			if err := mm.processManager.Stop(d.Name, prefix); err != nil {
+				result.Result = models.ImportResultFailed
+				result.Message = err.Error()
+			} else {
+				mm.publish(d.Name, models.DeviceOperationRemove, d.Type)
+			}
+			results = append(results, result)
+		case d.Type == models.ProcessTypeRTSP:
+			pending.Cameras = append(pending.Cameras, camerasByName[d.Name])
+		case d.Kind == models.DriftChanged:
+			// apps are reinstalled with the manifest configuration, the running app is kept when the new image isn't available
+			app := appsByName[d.Name]
+			if validateImportedApp(app) != nil {
+				// reported with invalid manifest items
+				continue
+			}
+			if err := mm.configTransfer.pullAppImage(app); err != nil {
+				results = append(results, &models.ImportItemResult{Type: d.Type, Name: d.Name, Result: models.ImportResultFailed, Message: err.Error()})
+				continue
+			}
+			if err := mm.processManager.Stop(d.Name, prefix); err != nil {
+				results = append(results, &models.ImportItemResult{Type: d.Type, Name: d.Name, Result: models.ImportResultFailed, Message: err.Error()})
+				continue
+			}
+			pending.Apps = append(pending.Apps, appsByName[d.Name])
+		default:
+			pending.Apps = append(pending.Apps, appsByName[d.Name])
+		}
+	}
+
+	report := mm.configTransfer.Import(pending, false, 0)
+	results = append(results, report.Results...)
+	for _, r := range results {
+		if r.Result == models.ImportResultFailed || r.Result == models.ImportResultInvalid {
+			g.Log.Warn("site manifest item not applied", r.Type, r.Name, r.Result, r.Message)
+		}
+	}
+	g.Log.Info("site manifest reconciled", "drift", len(drift), "applied", report.Succeeded, "failed", report.Failed)
+	return results, nil
+}
+
+func (mm *ManifestManager) publish(deviceID string, operation string, processType string) {
+	if mm.rdb == nil {
+		return
+	}
+	utils.PublishToRedis(mm.rdb, deviceID, models.MQTTProcessOperation(operation), processType, nil)
+}
+
+func (mm *ManifestManager) storedCameras() (map[string]*models.StreamProcess, error) {
+	objects, err := mm.storage.List(models.PrefixRTSPProcess)
+	if err != nil {
+		g.Log.Error("failed to list cameras", err)
+		return nil, err
+	}
+	cameras := make(map[string]*models.StreamProcess)
+	for _, v := range objects {
+		var process models.StreamProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("failed to unmarshal camera", err)
+			return nil, err
+		}
+		cameras[process.Name] = &process
+	}
+	return cameras, nil
+}
+
+func (mm *ManifestManager) storedApps() (map[string]*models.AppProcess, error) {
+	objects, err := mm.storage.List(models.PrefixAppProcess)
+	if err != nil {
+		g.Log.Error("failed to list apps", err)
+		return nil, err
+	}
+	apps := make(map[string]*models.AppProcess)
+	for _, v := range objects {
+		var app models.AppProcess
+		if err := json.Unmarshal(v, &app); err != nil {
+			g.Log.Error("failed to unmarshal app", err)
+			return nil, err
+		}
+		apps[app.Name] = &app
+	}
+	return apps, nil
+}
+
+func cameraDriftFields(stored *models.StreamProcess, declared *models.StreamProcess) []string {
+	fields := make([]string, 0)
+	if stored.RTSPEndpoint != declared.RTSPEndpoint {
+		fields = append(fields, "rtsp_endpoint")
+	}
+	if stored.RTMPEndpoint != declared.RTMPEndpoint {
+		fields = append(fields, "rtmp_endpoint")
+	}
+	if !reflect.DeepEqual(stored.Location, declared.Location) {
+		fields = append(fields, "location")
+	}
+	if stored.Group != declared.Group {
+		fields = append(fields, "group")
+	}
+	if !sameLabels(stored.Labels, declared.Labels) {
+		fields = append(fields, "labels")
+	}
+	if stored.Description != declared.Description {
+		fields = append(fields, "description")
+	}
+	return fields
+}
+
+func appDriftFields(stored *models.AppProcess, declared *models.AppProcess) []string {
+	fields := make([]string, 0)
+	if stored.DockerHubUser != declared.DockerHubUser || stored.DockerhubRepository != declared.DockerhubRepository || stored.DockerHubVersion != declared.DockerHubVersion {
+		fields = append(fields, "image")
+	}
+	if stored.Runtime != declared.Runtime {
+		fields = append(fields, "runtime")
+	}
+	if !sameDriftList(stored.EnvVars, declared.EnvVars) {
+		fields = append(fields, "env_vars")
+	}
+	if !sameDriftList(stored.ArgsVars, declared.ArgsVars) {
+		fields = append(fields, "arguments")
+	}
+	if !sameDriftList(stored.MountFolders, declared.MountFolders) {
+		fields = append(fields, "mount")
+	}
+	if !sameDriftList(stored.PortMapping, declared.PortMapping) {
+		fields = append(fields, "port_mappings")
+	}
+	return fields
+}
+
+// sameDriftList treats missing and empty lists as equal
+func sameDriftList(a interface{}, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Len() == 0 && vb.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+func TestManifestReconcile(t *testing.T) {
+	defer setupMemoryRuntimeConf()()
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manifestPath := filepath.Join(dir, models.DefaultManifestFile)
+	manifest := `cameras:
+- name: frontdoor
+  rtsp_endpoint: rtsp://10.0.0.2/live
+- name: backdoor
+  rtsp_endpoint: rtsp://10.0.0.3/live
+`
+	if err := ioutil.WriteFile(manifestPath, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	g.Conf.Manifest = &g.ManifestSubconfig{Enabled: true, Path: manifestPath, Policy: models.ManifestPolicyReject, Prune: true}
+	defer func() { g.Conf.Manifest = nil }()
+
+	cameraImage := models.CameraTypeToImageTag["rtsp"]
+	rt := NewMemoryRuntime()
+	rt.AddImage(cameraImage + ":0.0.7")
+	stateCache := NewProcessStateCache(rt)
+	pm := NewProcessManager(storage, nil, rt, stateCache, testSecretManager(t))
+	pm.probeRTSP = okRTSPProbe
+	am := NewAppManager(storage, nil, rt, stateCache)
+	cm := NewConfigTransferManager(storage, pm, am, NewSettingsManager(storage, rt, testSecretManager(t)), nil)
+	cm.cameraImage = func() (*models.ImageUpgrade, error) {
+		return &models.ImageUpgrade{HasImage: true, Name: cameraImage, CurrentVersion: "0.0.7"}, nil
+	}
+	mm := NewManifestManager(storage, pm, cm, nil)
+
+	// manual camera on the edge before manifest mode
+	image, _ := cm.cameraImage()
+	if err := pm.Start(&models.StreamProcess{Name: "manual", RTSPEndpoint: "rtsp://10.0.0.9/live"}, image, true); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := mm.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Drift) != 3 || status.Drift[0].Name != "backdoor" || status.Drift[0].Kind != models.DriftMissing || status.Drift[2].Kind != models.DriftUnmanaged {
+		t.Fatalf("unexpected drift %v", status.Drift)
+	}
+	if processes, _ := storage.List(models.PrefixRTSPProcess); len(processes) != 1 {
+		t.Fatal("expected nothing applied by status")
+	}
+
+	status, err = mm.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Drift) != 0 || len(status.Results) != 3 || status.Results[0].Result != models.ImportResultRemoved {
+		t.Fatalf("unexpected reconciliation %v %v", status.Drift, status.Results)
+	}
+	if _, err := pm.storedProcess("manual"); err == nil {
+		t.Fatal("expected unmanaged camera pruned")
+	}
+
+	// changed endpoint is reported and reverted
+	if err := storage.Put(models.PrefixRTSPProcess, "frontdoor", mustStoredCamera(t, pm, "frontdoor", "rtsp://10.0.0.4/live")); err != nil {
+		t.Fatal(err)
+	}
+	status, _ = mm.Status()
+	if len(status.Drift) != 1 || status.Drift[0].Kind != models.DriftChanged || status.Drift[0].Fields[0] != "rtsp_endpoint" {
+		t.Fatalf("expected changed camera, got %v", status.Drift)
+	}
+	if _, err := mm.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := pm.storedProcess("frontdoor"); stored.RTSPEndpoint != "rtsp://10.0.0.2/live" {
+		t.Fatalf("expected reverted endpoint, got %v", stored.RTSPEndpoint)
+	}
+
+	// manual changes rejected or flagged
+	if err := mm.ManualChange(models.ProcessTypeRTSP, "frontdoor", "DELETE process/:name", "admin"); err != models.ErrManifestManaged {
+		t.Fatalf("expected rejected change, got %v", err)
+	}
+	g.Conf.Manifest.Policy = models.ManifestPolicyFlag
+	if err := mm.ManualChange(models.ProcessTypeRTSP, "frontdoor", "DELETE process/:name", "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ = mm.Status(); len(status.ManualChanges) != 1 || status.ManualChanges[0].Actor != "admin" {
+		t.Fatalf("expected flagged change, got %v", status.ManualChanges)
+	}
+}
+
+func TestManifestAppImagePull(t *testing.T) {
+	defer setupMemoryRuntimeConf()()
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manifestPath := filepath.Join(dir, models.DefaultManifestFile)
+	writeManifest := func(version string) {
+		manifest := "apps:\n- name: detector\n  docker_user: chryscloud\n  docker_repository: detector\n  docker_version: \"" + version + "\"\n"
+		if err := ioutil.WriteFile(manifestPath, []byte(manifest), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Conf.Manifest = &g.ManifestSubconfig{Enabled: true, Path: manifestPath, Policy: models.ManifestPolicyReject}
+	defer func() { g.Conf.Manifest = nil }()
+
+	rt := NewMemoryRuntime()
+	stateCache := NewProcessStateCache(rt)
+	pm := NewProcessManager(storage, nil, rt, stateCache, testSecretManager(t))
+	am := NewAppManager(storage, nil, rt, stateCache)
+	cm := NewConfigTransferManager(storage, pm, am, NewSettingsManager(storage, rt, testSecretManager(t)), nil)
+	mm := NewManifestManager(storage, pm, cm, nil)
+
+	writeManifest("1.0")
+	if _, err := mm.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	if app, err := am.storedApp("detector"); err != nil || app.DockerHubVersion != "1.0" {
+		t.Fatalf("expected installed app, got %v %v", app, err)
+	}
+
+	// changed app is kept when the new image can't be pulled
+	writeManifest("1.1")
+	rt.FailImagePull("chryscloud/detector", errors.New("network unreachable"))
+	status, err := mm.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Results) != 1 || status.Results[0].Result != models.ImportResultFailed {
+		t.Fatalf("expected failed app reinstall, got %v", status.Results)
+	}
+	if app, err := am.storedApp("detector"); err != nil || app.DockerHubVersion != "1.0" {
+		t.Fatalf("expected running app kept, got %v %v", app, err)
+	}
+
+	rt.FailImagePull("chryscloud/detector", nil)
+	if _, err := mm.Reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	if app, err := am.storedApp("detector"); err != nil || app.DockerHubVersion != "1.1" {
+		t.Fatalf("expected reinstalled app, got %v %v", app, err)
+	}
+}
+
+func mustStoredCamera(t *testing.T, pm *ProcessManager, name string, rtspEndpoint string) []byte {
+	stored, err := pm.storedProcess(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.RTSPEndpoint = rtspEndpoint
+	b, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
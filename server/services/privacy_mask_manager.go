@@ -0,0 +1,431 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// bytes per pixel of packed (interleaved) pixel formats
+var packedPixelFormats = map[string]int{
+	"gray":  1,
+	"gray8": 1,
+	"rgb24": 3,
+	"bgr24": 3,
+	"rgba":  4,
+	"bgra":  4,
+	"argb":  4,
+	"abgr":  4,
+}
+
+// PrivacyMaskManager - versioned privacy masks applied to decoded frames before they leave the edge
+type PrivacyMaskManager struct {
+	storage *Storage
+	mux     sync.RWMutex
+	masks   map[string]*models.PrivacyMask // current mask per device
+	bitmaps map[string]*maskBitmap         // rasterized current mask per device
+}
+
+// maskBitmap is a rasterized mask for a specific frame size
+type maskBitmap struct {
+	version int64
+	width   int
+	height  int
+	pixels  []bool
+}
+
+// framePlane describes a single image plane within the raw frame buffer
+type framePlane struct {
+	offset    int
+	width     int
+	height    int
+	channels  int    // interleaved bytes per pixel
+	subsample int    // 1 = full resolution, 2 = half resolution (chroma)
+	black     []byte // black value per channel
+}
+
+func NewPrivacyMaskManager(storage *Storage) *PrivacyMaskManager {
+	pm := &PrivacyMaskManager{
+		storage: storage,
+		masks:   make(map[string]*models.PrivacyMask),
+		bitmaps: make(map[string]*maskBitmap),
+	}
+	err := pm.loadMasks()
+	if err != nil {
+		g.Log.Error("failed to load privacy masks", err)
+	}
+	return pm
+}
+
+// Put stores a new version of the device privacy mask
+func (pm *PrivacyMaskManager) Put(mask *models.PrivacyMask) (*models.PrivacyMask, error) {
+	err := models.ValidatePrivacyMask(mask)
+	if err != nil {
+		return nil, err
+	}
+	if mask.Mode == "" {
+		mask.Mode = models.PrivacyMaskModeBlack
+	}
+	if mask.Mode == models.PrivacyMaskModePixelate && mask.PixelSize == 0 {
+		mask.PixelSize = models.DefaultPrivacyMaskPixelSize
+	}
+
+	versions, err := pm.Versions(mask.DeviceName)
+	if err != nil {
+		return nil, err
+	}
+	mask.Version = 1
+	if len(versions) > 0 {
+		mask.Version = versions[len(versions)-1].Version + 1
+	}
+	mask.Created = time.Now().Unix() * 1000
+
+	b, err := json.Marshal(mask)
+	if err != nil {
+		g.Log.Error("failed to marshal privacy mask", err)
+		return nil, err
+	}
+	err = pm.storage.Put(models.PrefixPrivacyMaskVersion, versionKey(mask.DeviceName, mask.Version), b)
+	if err != nil {
+		g.Log.Error("failed to store privacy mask version", mask.DeviceName, err)
+		return nil, err
+	}
+	err = pm.storage.Put(models.PrefixPrivacyMask, mask.DeviceName, b)
+	if err != nil {
+		g.Log.Error("failed to store privacy mask", mask.DeviceName, err)
+		return nil, err
+	}
+
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+	pm.masks[mask.DeviceName] = mask
+	delete(pm.bitmaps, mask.DeviceName)
+	return mask, nil
+}
+
+// Get returns the current privacy mask of the device
+func (pm *PrivacyMaskManager) Get(deviceName string) (*models.PrivacyMask, error) {
+	pm.mux.RLock()
+	defer pm.mux.RUnlock()
+	mask, ok := pm.masks[deviceName]
+	if !ok {
+		return nil, models.ErrProcessNotFound
+	}
+	return mask, nil
+}
+
+// Versions lists all stored mask versions of the device (oldest first)
+func (pm *PrivacyMaskManager) Versions(deviceName string) ([]*models.PrivacyMask, error) {
+	objects, err := pm.storage.List(models.PrefixPrivacyMaskVersion + deviceName + "/")
+	if err != nil {
+		g.Log.Error("failed to list privacy mask versions", deviceName, err)
+		return nil, err
+	}
+	versions := make([]*models.PrivacyMask, 0)
+	for _, v := range objects {
+		var mask models.PrivacyMask
+		err := json.Unmarshal(v, &mask)
+		if err != nil {
+			g.Log.Error("failed to unmarshal privacy mask version", err)
+			continue
+		}
+		versions = append(versions, &mask)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// Restore makes an older version the current mask (stored as a new version)
+func (pm *PrivacyMaskManager) Restore(deviceName string, version int64) (*models.PrivacyMask, error) {
+	b, err := pm.storage.Get(models.PrefixPrivacyMaskVersion, versionKey(deviceName, version))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, models.ErrProcessNotFound
+		}
+		return nil, err
+	}
+	var mask models.PrivacyMask
+	err = json.Unmarshal(b, &mask)
+	if err != nil {
+		g.Log.Error("failed to unmarshal privacy mask version", err)
+		return nil, err
+	}
+	return pm.Put(&mask)
+}
+
+// Delete removes the current mask of the device (versions are kept)
+func (pm *PrivacyMaskManager) Delete(deviceName string) error {
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+	if _, ok := pm.masks[deviceName]; !ok {
+		return models.ErrProcessNotFound
+	}
+	err := pm.storage.Del(models.PrefixPrivacyMask, deviceName)
+	if err != nil {
+		g.Log.Error("failed to delete privacy mask", deviceName, err)
+		return err
+	}
+	delete(pm.masks, deviceName)
+	delete(pm.bitmaps, deviceName)
+	return nil
+}
+
+// Apply blacks out or pixelates masked regions of the decoded frame in place.
+// The raw buffer is interpreted from the frame shape (height, width, channels) or pix_fmt.
+// Frames with unknown layout of masked devices are blacked out completely.
+func (pm *PrivacyMaskManager) Apply(deviceName string, vf *pb.VideoFrame) {
+	if vf == nil || len(vf.Data) == 0 {
+		return
+	}
+	pm.mux.RLock()
+	mask, ok := pm.masks[deviceName]
+	pm.mux.RUnlock()
+	if !ok || len(mask.Regions) == 0 {
+		return
+	}
+
+	width, height, planes, err := frameLayout(vf)
+	if err != nil {
+		g.Log.Warn("unknown frame layout, blacking out the whole frame of masked device", deviceName, err)
+		for i := range vf.Data {
+			vf.Data[i] = 0
+		}
+		return
+	}
+
+	bitmap := pm.bitmap(deviceName, mask, width, height)
+	for _, plane := range planes {
+		if mask.Mode == models.PrivacyMaskModePixelate {
+			pixelatePlane(vf.Data, plane, bitmap, mask.PixelSize)
+		} else {
+			blackoutPlane(vf.Data, plane, bitmap)
+		}
+	}
+}
+
+// bitmap returns (cached) rasterized mask for the frame size
+func (pm *PrivacyMaskManager) bitmap(deviceName string, mask *models.PrivacyMask, width, height int) *maskBitmap {
+	pm.mux.RLock()
+	cached, ok := pm.bitmaps[deviceName]
+	pm.mux.RUnlock()
+	if ok && cached.version == mask.Version && cached.width == width && cached.height == height {
+		return cached
+	}
+
+	scaleX, scaleY := 1.0, 1.0
+	if mask.Width > 0 && mask.Height > 0 {
+		scaleX = float64(width) / float64(mask.Width)
+		scaleY = float64(height) / float64(mask.Height)
+	}
+	bm := &maskBitmap{
+		version: mask.Version,
+		width:   width,
+		height:  height,
+		pixels:  make([]bool, width*height),
+	}
+	for _, region := range mask.Regions {
+		polygon := make([]*models.Point, 0, len(region))
+		minX, minY, maxX, maxY := float64(width), float64(height), 0.0, 0.0
+		for _, p := range region {
+			sp := &models.Point{X: p.X * scaleX, Y: p.Y * scaleY}
+			polygon = append(polygon, sp)
+			if sp.X < minX {
+				minX = sp.X
+			}
+			if sp.Y < minY {
+				minY = sp.Y
+			}
+			if sp.X > maxX {
+				maxX = sp.X
+			}
+			if sp.Y > maxY {
+				maxY = sp.Y
+			}
+		}
+		for y := clampInt(int(minY), 0, height); y < clampInt(int(maxY)+1, 0, height); y++ {
+			for x := clampInt(int(minX), 0, width); x < clampInt(int(maxX)+1, 0, width); x++ {
+				if pointInPolygon(models.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5}, polygon) {
+					bm.pixels[y*width+x] = true
+				}
+			}
+		}
+	}
+
+	pm.mux.Lock()
+	pm.bitmaps[deviceName] = bm
+	pm.mux.Unlock()
+	return bm
+}
+
+func (pm *PrivacyMaskManager) loadMasks() error {
+	objects, err := pm.storage.List(models.PrefixPrivacyMask)
+	if err != nil {
+		return err
+	}
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+	for _, v := range objects {
+		var mask models.PrivacyMask
+		err := json.Unmarshal(v, &mask)
+		if err != nil {
+			g.Log.Error("failed to unmarshal privacy mask", err)
+			continue
+		}
+		pm.masks[mask.DeviceName] = &mask
+	}
+	return nil
+}
+
+// frameLayout resolves frame size and image planes of the raw frame buffer
+func frameLayout(vf *pb.VideoFrame) (int, int, []*framePlane, error) {
+	size := len(vf.Data)
+
+	// decoded frames are numpy arrays with shape (height, width, channels)
+	if vf.Shape != nil && len(vf.Shape.Dim) >= 2 {
+		height := int(vf.Shape.Dim[0].Size)
+		width := int(vf.Shape.Dim[1].Size)
+		channels := 1
+		if len(vf.Shape.Dim) >= 3 {
+			channels = int(vf.Shape.Dim[2].Size)
+		}
+		if width > 0 && height > 0 && channels > 0 && width*height*channels == size {
+			return width, height, []*framePlane{{width: width, height: height, channels: channels, subsample: 1, black: make([]byte, channels)}}, nil
+		}
+	}
+
+	width := int(vf.Width)
+	height := int(vf.Height)
+	if width <= 0 || height <= 0 {
+		return 0, 0, nil, fmt.Errorf("missing frame size")
+	}
+	if bpp, ok := packedPixelFormats[vf.PixFmt]; ok && width*height*bpp == size {
+		return width, height, []*framePlane{{width: width, height: height, channels: bpp, subsample: 1, black: make([]byte, bpp)}}, nil
+	}
+
+	lumaBlack := byte(16)
+	switch vf.PixFmt {
+	case "yuvj420p":
+		lumaBlack = 0
+		fallthrough
+	case "yuv420p":
+		cw, ch := (width+1)/2, (height+1)/2
+		if width*height+2*cw*ch != size {
+			break
+		}
+		return width, height, []*framePlane{
+			{offset: 0, width: width, height: height, channels: 1, subsample: 1, black: []byte{lumaBlack}},
+			{offset: width * height, width: cw, height: ch, channels: 1, subsample: 2, black: []byte{128}},
+			{offset: width*height + cw*ch, width: cw, height: ch, channels: 1, subsample: 2, black: []byte{128}},
+		}, nil
+	case "nv12", "nv21":
+		cw, ch := (width+1)/2, (height+1)/2
+		if width*height+2*cw*ch != size {
+			break
+		}
+		return width, height, []*framePlane{
+			{offset: 0, width: width, height: height, channels: 1, subsample: 1, black: []byte{lumaBlack}},
+			{offset: width * height, width: cw, height: ch, channels: 2, subsample: 2, black: []byte{128, 128}},
+		}, nil
+	}
+	return 0, 0, nil, fmt.Errorf("unsupported pixel format %q with %d bytes for %dx%d frame", vf.PixFmt, size, width, height)
+}
+
+func blackoutPlane(data []byte, plane *framePlane, bitmap *maskBitmap) {
+	for y := 0; y < plane.height; y++ {
+		for x := 0; x < plane.width; x++ {
+			if !bitmap.masked(x*plane.subsample, y*plane.subsample) {
+				continue
+			}
+			idx := plane.offset + (y*plane.width+x)*plane.channels
+			copy(data[idx:idx+plane.channels], plane.black)
+		}
+	}
+}
+
+// pixelatePlane replaces masked pixels within each block with the average of the masked pixels in that block
+func pixelatePlane(data []byte, plane *framePlane, bitmap *maskBitmap, pixelSize int) {
+	block := pixelSize / plane.subsample
+	if block < 1 {
+		block = 1
+	}
+	sums := make([]int, plane.channels)
+	for by := 0; by < plane.height; by += block {
+		for bx := 0; bx < plane.width; bx += block {
+			for c := range sums {
+				sums[c] = 0
+			}
+			count := 0
+			for y := by; y < by+block && y < plane.height; y++ {
+				for x := bx; x < bx+block && x < plane.width; x++ {
+					if !bitmap.masked(x*plane.subsample, y*plane.subsample) {
+						continue
+					}
+					idx := plane.offset + (y*plane.width+x)*plane.channels
+					for c := 0; c < plane.channels; c++ {
+						sums[c] += int(data[idx+c])
+					}
+					count++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+			for y := by; y < by+block && y < plane.height; y++ {
+				for x := bx; x < bx+block && x < plane.width; x++ {
+					if !bitmap.masked(x*plane.subsample, y*plane.subsample) {
+						continue
+					}
+					idx := plane.offset + (y*plane.width+x)*plane.channels
+					for c := 0; c < plane.channels; c++ {
+						data[idx+c] = byte(sums[c] / count)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (bm *maskBitmap) masked(x, y int) bool {
+	if x >= bm.width {
+		x = bm.width - 1
+	}
+	if y >= bm.height {
+		y = bm.height - 1
+	}
+	return bm.pixels[y*bm.width+x]
+}
+
+func versionKey(deviceName string, version int64) string {
+	return fmt.Sprintf("%s/%010d", deviceName, version)
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func bgrFrame(width, height int) *pb.VideoFrame {
+	data := make([]byte, width*height*3)
+	for i := range data {
+		data[i] = byte(100 + i%50)
+	}
+	return &pb.VideoFrame{
+		Width:  int64(width),
+		Height: int64(height),
+		Data:   data,
+		Shape: &pb.ShapeProto{Dim: []*pb.ShapeProto_Dim{
+			{Size: int64(height), Name: "0"},
+			{Size: int64(width), Name: "1"},
+			{Size: 3, Name: "2"},
+		}},
+	}
+}
+
+func TestPrivacyMaskBlackout(t *testing.T) {
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	pm := NewPrivacyMaskManager(NewStorage(db))
+
+	_, err = pm.Put(&models.PrivacyMask{
+		DeviceName: "maskcam",
+		Regions:    [][]*models.Point{{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vf := bgrFrame(20, 20)
+	pm.Apply("maskcam", vf)
+	if vf.Data[(5*20+5)*3] != 0 || vf.Data[(5*20+5)*3+2] != 0 {
+		t.Fatal("expected masked pixel to be black")
+	}
+	if vf.Data[(15*20+15)*3] == 0 {
+		t.Fatal("expected pixel outside of mask to be untouched")
+	}
+
+	// reference resolution scaled to half size frame
+	_, err = pm.Put(&models.PrivacyMask{
+		DeviceName: "maskcam",
+		Width:      40,
+		Height:     40,
+		Regions:    [][]*models.Point{{{X: 20, Y: 20}, {X: 40, Y: 20}, {X: 40, Y: 40}, {X: 20, Y: 40}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vf = bgrFrame(20, 20)
+	pm.Apply("maskcam", vf)
+	if vf.Data[(5*20+5)*3] == 0 || vf.Data[(15*20+15)*3] != 0 {
+		t.Fatal("expected scaled mask to cover only bottom right quarter")
+	}
+
+	versions, err := pm.Versions("maskcam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[1].Version != 2 {
+		t.Fatalf("expected 2 mask versions, got %v", len(versions))
+	}
+	restored, err := pm.Restore("maskcam", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Version != 3 || restored.Width != 0 {
+		t.Fatalf("expected version 1 restored as version 3, got %v", restored.Version)
+	}
+
+	// unknown layout is blacked out completely
+	vf = &pb.VideoFrame{Width: 20, Height: 20, PixFmt: "unknown", Data: []byte{1, 2, 3}}
+	pm.Apply("maskcam", vf)
+	if vf.Data[0] != 0 || vf.Data[2] != 0 {
+		t.Fatal("expected frame with unknown layout to be blacked out")
+	}
+
+	// unmasked device isn't modified
+	vf = bgrFrame(20, 20)
+	pm.Apply("othercam", vf)
+	if vf.Data[0] == 0 {
+		t.Fatal("expected frame of unmasked device to be untouched")
+	}
+}
+
+func TestPrivacyMaskPixelateYUV(t *testing.T) {
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	pm := NewPrivacyMaskManager(NewStorage(db))
+
+	_, err = pm.Put(&models.PrivacyMask{
+		DeviceName: "yuvcam",
+		Mode:       models.PrivacyMaskModePixelate,
+		PixelSize:  4,
+		Regions:    [][]*models.Point{{{X: 0, Y: 0}, {X: 8, Y: 0}, {X: 8, Y: 8}, {X: 0, Y: 8}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	width, height := 8, 8
+	data := make([]byte, width*height*3/2)
+	for i := 0; i < width*height; i++ {
+		data[i] = byte(i)
+	}
+	vf := &pb.VideoFrame{Width: int64(width), Height: int64(height), PixFmt: "yuv420p", Data: data}
+	pm.Apply("yuvcam", vf)
+
+	// every 4x4 block of luma has the same value
+	for by := 0; by < height; by += 4 {
+		for bx := 0; bx < width; bx += 4 {
+			first := vf.Data[by*width+bx]
+			for y := by; y < by+4; y++ {
+				for x := bx; x < bx+4; x++ {
+					if vf.Data[y*width+x] != first {
+						t.Fatalf("expected pixelated block at %v,%v", bx, by)
+					}
+				}
+			}
+		}
+	}
+}
@@ -21,14 +21,14 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/chryscloud/go-microkit-plugins/docker"
+	microModelDocker "github.com/chryscloud/go-microkit-plugins/models/docker"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/models"
 	"github.com/dgraph-io/badger/v2"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
 	dockerErrors "github.com/docker/docker/client"
 	"github.com/go-redis/redis/v7"
@@ -46,20 +46,31 @@ var (
 
 // ProcessManager - start, stop of docker containers
 type ProcessManager struct {
-	storage *Storage
-	rdb     *redis.Client
+	storage          *Storage
+	rdb              *redis.Client
+	containerRuntime ContainerRuntime
+	stateCache       *ProcessStateCache
+	secrets          *SecretManager
+	streamHealth     sync.Map // deviceID -> stream health reported by the stream watchdog
+	reconcileStatus  sync.Map // deviceID -> result of the last reconciliation
+	probeRTSP        func(endpoint string, username string, password string, timeout time.Duration) *models.RTSPProbeResult
 }
 
-func NewProcessManager(storage *Storage, rdb *redis.Client) *ProcessManager {
+func NewProcessManager(storage *Storage, rdb *redis.Client, containerRuntime ContainerRuntime, stateCache *ProcessStateCache, secrets *SecretManager) *ProcessManager {
 	return &ProcessManager{
-		storage: storage,
-		rdb:     rdb,
+		storage:          storage,
+		rdb:              rdb,
+		containerRuntime: containerRuntime,
+		stateCache:       stateCache,
+		secrets:          secrets,
+		probeRTSP:        ProbeRTSP,
 	}
 }
 
 // Start - starts the docker container with rtsp, device_id and possibly rtmp environment variables.
-// Restarts always when something goes wrong within the streaming process
-func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *models.ImageUpgrade) error {
+// Restarts always when something goes wrong within the streaming process.
+// RTSP endpoint is probed first (models.RTSPProbeError if the stream can't be consumed) unless skipProbe is set.
+func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *models.ImageUpgrade, skipProbe bool) error {
 
 	// detect architecture
 	arch := runtime.GOARCH
@@ -71,6 +82,13 @@ func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *mod
 	if process.Name == "" || process.RTSPEndpoint == "" {
 		return errors.New("required parameters missing")
 	}
+	splitCredentials(process)
+
+	if !skipProbe {
+		if err := pm.validateStream(process); err != nil {
+			return err
+		}
+	}
 
 	if !imageUpgrade.HasImage && !imageUpgrade.HasUpgrade {
 		return errors.New("no camera container found. Please refer to documentation on how to pull a docker image manually")
@@ -129,16 +147,57 @@ func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *mod
 		}
 	}
 
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
+	cl := pm.containerRuntime
 
-	fl := filters.NewArgs()
-	pruneReport, pruneErr := cl.ContainersPrune(fl)
+	pruneReport, pruneErr := cl.ContainersPrune()
 	if pruneErr != nil {
 		g.Log.Error("container prunning fialed", pruneErr)
 		return pruneErr
 	}
 	g.Log.Info("prune successfull. Report and space reclaimed:", pruneReport.ContainersDeleted, pruneReport.SpaceReclaimed)
 
+	password, err := pm.containerPassword(process)
+	if err != nil {
+		return err
+	}
+	if err := pm.createContainer(process, password); err != nil {
+		return err
+	}
+
+	err = cl.ContainerStart(process.Name)
+	if err != nil {
+		g.Log.Error("failed to start container", process.Name, err)
+		return err
+	}
+
+	process.Status = "running"
+	process.Created = time.Now().Unix() * 1000
+
+	if err := pm.startRTMPProxy(process); err != nil {
+		return err
+	}
+
+	if err := pm.storeCredentials(process); err != nil {
+		return err
+	}
+
+	obj, err := json.Marshal(process)
+	if err != nil {
+		g.Log.Error("failed to marshal process json", err)
+		return err
+	}
+
+	err = pm.storage.Put(models.PrefixRTSPProcess, process.Name, obj)
+	if err != nil {
+		g.Log.Error("failed to store process into datastore", err)
+		return err
+	}
+
+	return nil
+}
+
+// createContainer creates the camera container from the process configuration (doesn't start it)
+func (pm *ProcessManager) createContainer(process *models.StreamProcess, password string) error {
 	hostConfig := &container.HostConfig{
 		// PortBindings: mappingPorts,
 		LogConfig: container.LogConfig{
@@ -165,7 +224,25 @@ func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *mod
 		hostConfig.Mounts = mounts
 	}
 
-	envVars := []string{"rtsp_endpoint=" + process.RTSPEndpoint, "device_id=" + process.Name, "in_memory_buffer=" + strconv.Itoa(g.Conf.Buffer.InMemory)}
+	// credentials are kept out of the stored endpoint and only injected into the container
+	rtspEndpoint := process.RTSPEndpoint
+	credentialVars := make([]string, 0)
+	passwordFile := false
+	if credentialsOutsideEndpoint(process.ImageTag) {
+		if process.RTSPUsername != "" {
+			credentialVars = append(credentialVars, "rtsp_username="+process.RTSPUsername)
+		}
+		if password != "" {
+			credentialVars = append(credentialVars, "rtsp_password_file="+rtspPasswordFile)
+			passwordFile = true
+		}
+	} else {
+		// older camera images only read the credentials from the endpoint
+		rtspEndpoint = withCredentials(process.RTSPEndpoint, process.RTSPUsername, password)
+	}
+
+	envVars := []string{"rtsp_endpoint=" + rtspEndpoint, "device_id=" + process.Name, "in_memory_buffer=" + strconv.Itoa(g.Conf.Buffer.InMemory)}
+	envVars = append(envVars, credentialVars...)
 	if process.RTMPEndpoint != "" {
 		envVars = append(envVars, "rtmp_endpoint="+process.RTMPEndpoint)
 	}
@@ -186,26 +263,38 @@ func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *mod
 
 	envVars = append(envVars, "PYTHONUNBUFFERED=0") // for output to console
 
-	_, ccErr := cl.ContainerCreate(strings.ToLower(process.Name), &container.Config{
-		Image: process.ImageTag,
-		Env:   envVars,
-	}, hostConfig, nil)
+	labels := map[string]string{models.ContainerLabelManaged: models.ProcessTypeRTSP, models.ContainerLabelName: process.Name}
+	if process.Group != "" {
+		labels[models.ContainerLabelGroup] = process.Group
+	}
+	for k, v := range process.Labels {
+		labels[models.ContainerLabelPrefix+k] = v
+	}
+
+	ccErr := pm.containerRuntime.ContainerCreate(strings.ToLower(process.Name), &container.Config{
+		Image:  process.ImageTag,
+		Env:    envVars,
+		Labels: labels,
+	}, hostConfig)
 
 	if ccErr != nil {
 		g.Log.Error("failed to create container ", process.Name, ccErr)
 		return ccErr
 	}
-
-	err = cl.ContainerStart(process.Name)
-	if err != nil {
-		g.Log.Error("failed to start container", process.Name, err)
-		return err
+	if passwordFile {
+		if err := pm.containerRuntime.CopyToContainer(strings.ToLower(process.Name), rtspPasswordFile, []byte(password)); err != nil {
+			g.Log.Error("failed to copy rtsp password into container", process.Name, err)
+			if rErr := pm.containerRuntime.ContainerRemove(strings.ToLower(process.Name)); rErr != nil {
+				g.Log.Error("failed to remove container", process.Name, rErr)
+			}
+			return err
+		}
 	}
+	return nil
+}
 
-	process.Status = "running"
-	process.Created = time.Now().Unix() * 1000
-
-	// set default value in redis if RTMP streaming enabled
+// startRTMPProxy sets the default proxy values in redis if RTMP streaming enabled
+func (pm *ProcessManager) startRTMPProxy(process *models.StreamProcess) error {
 	if process.RTMPEndpoint != "" {
 		valMap := make(map[string]interface{}, 0)
 		valMap[models.RedisLastAccessQueryTimeKey] = time.Now().Unix() * 1000
@@ -221,42 +310,40 @@ func (pm *ProcessManager) Start(process *models.StreamProcess, imageUpgrade *mod
 		}
 		process.RTMPStreamStatus.Streaming = true
 	}
-
-	obj, err := json.Marshal(process)
-	if err != nil {
-		g.Log.Error("failed to marshal process json", err)
-		return err
-	}
-
-	err = pm.storage.Put(models.PrefixRTSPProcess, process.Name, obj)
-	if err != nil {
-		g.Log.Error("failed to store process into datastore", err)
-		return err
-	}
-
 	return nil
 }
 
 // Stop - stops the docker container by the name of deviceID and removed from local datastore
+// (record is removed also when the container is already gone)
 // databasePrefix = models.PrefixRTSPProcess or models.PrefixAppProcess
 func (pm *ProcessManager) Stop(deviceID string, databasePrefix string) error {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
+	cl := pm.containerRuntime
 
 	container, err := cl.ContainerGet(deviceID)
 	if err != nil {
-		if dockerErrors.IsErrNotFound(err) {
-			g.Log.Info("container not found to be stopeed", err)
-			return models.ErrProcessNotFound
+		if !dockerErrors.IsErrNotFound(err) {
+			g.Log.Error("failed to get container to be stopped", deviceID, err)
+			return err
 		}
-	}
-
-	// waits up to 10 minutes to stop the container, otherwise kills after 30 seconds
-	killAfer := time.Second * 5
-	err = cl.ContainerStop(container.ID, &killAfer)
-	if err != nil {
-		if dockerErrors.IsErrNotFound(err) {
-			g.Log.Warn("container doesn't exist. probably stopped before", err)
-			return nil
+		if _, err := pm.storage.Get(databasePrefix, deviceID); err != nil {
+			if err == badger.ErrKeyNotFound {
+				g.Log.Info("container not found to be stopeed", deviceID)
+				return models.ErrProcessNotFound
+			}
+			g.Log.Error("failed to get process to be stopped", deviceID, err)
+			return err
+		}
+		g.Log.Warn("container doesn't exist, removing the missing process", deviceID)
+	} else {
+		// waits up to 10 minutes to stop the container, otherwise kills after 30 seconds
+		killAfer := time.Second * 5
+		err = cl.ContainerStop(container.ID, &killAfer)
+		if err != nil {
+			if dockerErrors.IsErrNotFound(err) {
+				g.Log.Warn("container doesn't exist. probably stopped before", err)
+			} else {
+				g.Log.Error("failed to stop container", deviceID, err)
+			}
 		}
 	}
 
@@ -265,20 +352,25 @@ func (pm *ProcessManager) Stop(deviceID string, databasePrefix string) error {
 		g.Log.Error("Failed to delete rtsp proces", err)
 		return err
 	}
+	if databasePrefix == models.PrefixRTSPProcess {
+		if err := pm.removeCredentials(deviceID); err != nil {
+			return err
+		}
+	}
 
-	fl := filters.NewArgs()
-	pruneReport, pruneErr := cl.ContainersPrune(fl)
+	pruneReport, pruneErr := cl.ContainersPrune()
 	if pruneErr != nil {
 		g.Log.Error("container prunning fialed", pruneErr)
 		return pruneErr
 	}
 	g.Log.Info("prune successfull. Report and space reclaimed:", pruneReport.ContainersDeleted, pruneReport.SpaceReclaimed)
+	pm.stateCache.remove(deviceID)
 
 	return nil
 }
 
-// ListStream - GRPC method for list all streams (doesn't alter the actual processes)
-func (pm *ProcessManager) ListStream(ctx context.Context, found func(process *models.StreamProcess) error) error {
+// ListStream - GRPC method for list all streams matching the filter (doesn't alter the actual processes)
+func (pm *ProcessManager) ListStream(ctx context.Context, filter *models.ProcessFilter, found func(process *models.StreamProcess) error) error {
 	objects, err := pm.storage.List(models.PrefixRTSPProcess)
 	if err != nil {
 		g.Log.Error("failed to list devices", err)
@@ -292,6 +384,9 @@ func (pm *ProcessManager) ListStream(ctx context.Context, found func(process *mo
 			g.Log.Error("failed to unamrshal object", err)
 			return err
 		}
+		if !filter.Matches(&process) {
+			continue
+		}
 		processes = append(processes, &process)
 	}
 	// clean up and update the list
@@ -322,7 +417,7 @@ func (pm *ProcessManager) ListStream(ctx context.Context, found func(process *mo
 	return nil
 }
 
-// List - listing all of the process in any status (also augments the list based on current processes)
+// List - listing all of the process in any status (also augments the list based on current processes). Processes without container are listed as missing
 func (pm *ProcessManager) List() ([]*models.StreamProcess, error) {
 	objects, err := pm.storage.List(models.PrefixRTSPProcess)
 	if err != nil {
@@ -340,16 +435,16 @@ func (pm *ProcessManager) List() ([]*models.StreamProcess, error) {
 		processes = append(processes, &process)
 	}
 
-	deleteProcesses := make([]*models.StreamProcess, 0)
 	cleanProcesses := make([]*models.StreamProcess, 0)
-	// clean up and update the list
+	// update the list (datastore is the desired state, missing containers are recreated by the reconciler)
 	for _, proc := range processes {
 		info, err := pm.Info(proc.Name)
 		if err != nil {
-			g.Log.Warn("failed to load process", err)
 			if err == models.ErrProcessNotFound {
-				// remove from the list and datastore
-				deleteProcesses = append(deleteProcesses, proc)
+				g.Log.Warn("container missing for process", proc.Name)
+				proc.Status = models.ProcessStatusMissing
+				proc.State = nil
+				cleanProcesses = append(cleanProcesses, proc)
 				continue
 			}
 			g.Log.Error("failed to get process info", err)
@@ -357,35 +452,13 @@ func (pm *ProcessManager) List() ([]*models.StreamProcess, error) {
 		}
 		cleanProcesses = append(cleanProcesses, info)
 	}
-	if len(deleteProcesses) > 0 {
-		for _, proc := range deleteProcesses {
-			err := pm.storage.Del(models.PrefixRTSPProcess, proc.Name)
-			if err != nil {
-				g.Log.Error("failed to delete process with name", proc.Name, err)
-				return nil, err
-			}
-		}
-	}
 	return cleanProcesses, nil
 }
 
-// Info - return information on the streaming docker container (it also updates the process status)
+// Info - return information on the streaming docker container (container state is served from the state cache)
 func (pm *ProcessManager) Info(deviceID string) (*models.StreamProcess, error) {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
-	container, err := cl.ContainerGet(deviceID)
-	if err != nil {
-		if dockerErrors.IsErrNotFound(err) {
-			g.Log.Info("container not found to be stopeed", err)
-			return nil, models.ErrProcessNotFound
-		}
-		g.Log.Error("failed to retrieve container", err)
-		return nil, err
-	}
-
-	// max 100 lines of logs
-	logs, err := cl.ContainerLogs(container.ID, 100, time.Unix(0, 0))
+	container, err := pm.stateCache.Get(deviceID)
 	if err != nil {
-		g.Log.Error("failed to retrieve container logs", err)
 		return nil, err
 	}
 
@@ -400,35 +473,72 @@ func (pm *ProcessManager) Info(deviceID string) (*models.StreamProcess, error) {
 		return nil, err
 	}
 	status.ContainerID = container.ID
-	if container != nil {
+	if container.State != nil {
 		status.State = container.State
 		status.Status = container.State.Status
+		if status.Paused && container.State.Running {
+			status.Status = models.ProcessStatusPaused
+		}
 	} else {
 		status.Status = "unknown"
 	}
-	status.Logs = logs
-	status.Modified = time.Now().Unix() * 1000
+	status.StreamHealth = pm.StreamHealth(deviceID)
+	status.LastReconciled = pm.ReconcileStatus(deviceID)
 
-	b, err := json.Marshal(&status)
-	if err != nil {
-		g.Log.Error("failed to marshal process", err)
-		return nil, err
+	return &status, nil
+}
+
+// Logs - last 100 lines of the streaming docker container logs
+func (pm *ProcessManager) Logs(deviceID string) (*microModelDocker.DockerLogs, error) {
+	return containerLogs(pm.stateCache, deviceID)
+}
+
+// SetStreamHealth - stream health of the device as detected by the stream watchdog (empty if unknown)
+func (pm *ProcessManager) SetStreamHealth(deviceID string, health string) {
+	if health == "" {
+		pm.streamHealth.Delete(deviceID)
+		return
 	}
-	err = pm.storage.Put(models.PrefixRTSPProcess, status.Name, b)
-	if err != nil {
-		g.Log.Error("failed to store process after info", err)
-		return nil, err
+	pm.streamHealth.Store(deviceID, health)
+}
+
+// StreamHealth - last reported stream health of the device
+func (pm *ProcessManager) StreamHealth(deviceID string) string {
+	if h, ok := pm.streamHealth.Load(deviceID); ok {
+		return h.(string)
 	}
+	return ""
+}
 
-	return &status, nil
+// SetReconcileStatus - result of the last reconciliation of the device
+func (pm *ProcessManager) SetReconcileStatus(deviceID string, status *models.ReconcileStatus) {
+	pm.reconcileStatus.Store(deviceID, status)
+}
+
+// ReconcileStatus - result of the last reconciliation of the device (nil if not reconciled yet)
+func (pm *ProcessManager) ReconcileStatus(deviceID string) *models.ReconcileStatus {
+	if s, ok := pm.reconcileStatus.Load(deviceID); ok {
+		return s.(*models.ReconcileStatus)
+	}
+	return nil
 }
 
 // UpdateProcessInfo - start and stop information propagated into redis and state stored into datastore
 func (pm *ProcessManager) UpdateProcessInfo(stream *models.StreamProcess) (*models.StreamProcess, error) {
 
+	if err := pm.storeCredentials(stream); err != nil {
+		return nil, err
+	}
 	stream.Modified = time.Now().Unix() * 1000 // miliseconds
 
-	b, err := json.Marshal(stream)
+	// runtime state is served from the state cache and not stored
+	stored := *stream
+	stored.State = nil
+	stored.Logs = nil
+	stored.StreamHealth = ""
+	stored.LastReconciled = nil
+
+	b, err := json.Marshal(&stored)
 	if err != nil {
 		g.Log.Error("failed to marshal process", err)
 		return nil, err
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// Restart - restarts the camera container. Paused camera is resumed.
+func (pm *ProcessManager) Restart(deviceID string) (*models.StreamProcess, error) {
+	process, err := pm.storedProcess(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := pm.stateCache.Get(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if process.Paused {
+		if err := pm.setPaused(process, false); err != nil {
+			return nil, err
+		}
+	}
+	err = pm.containerRuntime.ContainerRestart(c.ID, time.Second*10)
+	if err != nil {
+		g.Log.Error("failed to restart container", deviceID, err)
+		return nil, err
+	}
+	pm.stateCache.Refresh(deviceID)
+	return pm.Info(deviceID)
+}
+
+// Pause - camera stops producing frames (the python process reads the paused flag from redis). Container and datastore record are kept.
+func (pm *ProcessManager) Pause(deviceID string) (*models.StreamProcess, error) {
+	process, err := pm.storedProcess(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pm.stateCache.Get(deviceID); err != nil {
+		return nil, err
+	}
+	if !process.Paused {
+		if err := pm.setPaused(process, true); err != nil {
+			return nil, err
+		}
+	}
+	return pm.Info(deviceID)
+}
+
+// Resume - paused camera continues producing frames
+func (pm *ProcessManager) Resume(deviceID string) (*models.StreamProcess, error) {
+	process, err := pm.storedProcess(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pm.stateCache.Get(deviceID); err != nil {
+		return nil, err
+	}
+	if process.Paused {
+		if err := pm.setPaused(process, false); err != nil {
+			return nil, err
+		}
+	}
+	return pm.Info(deviceID)
+}
+
+// setPaused stores the paused flag into redis (read by the camera container) and into the datastore
+func (pm *ProcessManager) setPaused(process *models.StreamProcess, paused bool) error {
+	rErr := pm.rdb.HSet(models.RedisLastAccessPrefix+process.Name, models.RedisPausedKey, paused).Err()
+	if rErr != nil {
+		g.Log.Error("failed to store paused value to redis", process.Name, rErr)
+		return rErr
+	}
+	process.Paused = paused
+	_, err := pm.UpdateProcessInfo(process)
+	return err
+}
+
+// storedProcess - camera as stored in the datastore
+func (pm *ProcessManager) storedProcess(deviceID string) (*models.StreamProcess, error) {
+	b, err := pm.storage.Get(models.PrefixRTSPProcess, deviceID)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, models.ErrProcessNotFoundDatastore
+		}
+		g.Log.Error("failed to retrieve process from datastore", deviceID, err)
+		return nil, err
+	}
+	var process models.StreamProcess
+	err = json.Unmarshal(b, &process)
+	if err != nil {
+		g.Log.Error("failed to unmarshal stored process", err)
+		return nil, err
+	}
+	return &process, nil
+}
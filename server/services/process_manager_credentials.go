@@ -0,0 +1,175 @@
+package services
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	// first camera image reading the username and password file outside of the RTSP endpoint
+	// (older images get the credentials in the rtsp_endpoint environment variable)
+	credentialsImageVersion = "0.0.9"
+	// password file copied into the camera container before start (not visible with docker inspect)
+	rtspPasswordFile = "/run/secrets/rtsp_password"
+)
+
+// credentialsOutsideEndpoint - camera image reads the RTSP username and password file (image tag repository:version[-arch])
+func credentialsOutsideEndpoint(imageTag string) bool {
+	i := strings.LastIndex(imageTag, ":")
+	if i < 0 {
+		return false
+	}
+	v, err := version.NewVersion(strings.SplitN(imageTag[i+1:], "-", 2)[0])
+	if err != nil {
+		return false
+	}
+	return !v.LessThan(version.Must(version.NewVersion(credentialsImageVersion)))
+}
+
+// splitCredentials moves the user info of the RTSP endpoint into the username and password fields.
+// Username and password given separately take precedence.
+func splitCredentials(process *models.StreamProcess) {
+	u, err := url.Parse(process.RTSPEndpoint)
+	if err != nil || u.User == nil {
+		return
+	}
+	if process.RTSPUsername == "" {
+		process.RTSPUsername = u.User.Username()
+	}
+	if password, ok := u.User.Password(); ok && (process.RTSPPassword == "" || process.RTSPPassword == models.RedactedSecret) {
+		process.RTSPPassword = password
+	}
+	u.User = nil
+	process.RTSPEndpoint = u.String()
+}
+
+// withCredentials returns the RTSP endpoint with the username and password as user info
+func withCredentials(endpoint string, username string, password string) string {
+	if username == "" && password == "" {
+		return endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	if password == "" {
+		u.User = url.User(username)
+	} else {
+		u.User = url.UserPassword(username, password)
+	}
+	return u.String()
+}
+
+// hasNewPassword - password given in the request (redacted value means the stored one is kept)
+func hasNewPassword(process *models.StreamProcess) bool {
+	return process.RTSPPassword != "" && process.RTSPPassword != models.RedactedSecret
+}
+
+// storeCredentials stores the new RTSP password encrypted and separately from the camera record.
+// The password is cleared from the process, without a new password the stored one is kept.
+func (pm *ProcessManager) storeCredentials(process *models.StreamProcess) error {
+	splitCredentials(process)
+	password := process.RTSPPassword
+	process.RTSPPassword = ""
+	if password == "" || password == models.RedactedSecret {
+		return nil
+	}
+	encrypted, err := pm.secrets.Encrypt([]byte(password))
+	if err != nil {
+		g.Log.Error("failed to encrypt rtsp password", process.Name, err)
+		return err
+	}
+	if err := pm.storage.Put(models.PrefixRTSPCredentials, process.Name, []byte(encrypted)); err != nil {
+		g.Log.Error("failed to store rtsp credentials", process.Name, err)
+		return err
+	}
+	return nil
+}
+
+// removeCredentials removes the stored RTSP password of the camera
+func (pm *ProcessManager) removeCredentials(deviceID string) error {
+	err := pm.storage.Del(models.PrefixRTSPCredentials, deviceID)
+	if err != nil && err != badger.ErrKeyNotFound {
+		g.Log.Error("failed to remove rtsp credentials", deviceID, err)
+		return err
+	}
+	return nil
+}
+
+// rtspPassword - decrypted RTSP password of the camera (empty if none)
+func (pm *ProcessManager) rtspPassword(deviceID string) (string, error) {
+	b, err := pm.storage.Get(models.PrefixRTSPCredentials, deviceID)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return "", nil
+		}
+		g.Log.Error("failed to retrieve rtsp credentials", deviceID, err)
+		return "", err
+	}
+	password, err := pm.secrets.Decrypt(string(b))
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// containerPassword - password passed to the container (new one from the request or the stored one)
+func (pm *ProcessManager) containerPassword(process *models.StreamProcess) (string, error) {
+	if hasNewPassword(process) {
+		return process.RTSPPassword, nil
+	}
+	return pm.rtspPassword(process.Name)
+}
+
+// rtspEndpointWithCredentials - complete RTSP endpoint with the stored username and password (never log it)
+func (pm *ProcessManager) rtspEndpointWithCredentials(process *models.StreamProcess) (string, error) {
+	password, err := pm.containerPassword(process)
+	if err != nil {
+		return "", err
+	}
+	return withCredentials(process.RTSPEndpoint, process.RTSPUsername, password), nil
+}
+
+// EncryptStoredCredentials moves RTSP credentials of cameras stored before encryption out of the endpoints
+func (pm *ProcessManager) EncryptStoredCredentials() error {
+	objects, err := pm.storage.List(models.PrefixRTSPProcess)
+	if err != nil {
+		g.Log.Error("failed to list devices", err)
+		return err
+	}
+	for _, v := range objects {
+		var process models.StreamProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("failed to unmarshal object", err)
+			return err
+		}
+		if u, err := url.Parse(process.RTSPEndpoint); err != nil || u.User == nil {
+			continue
+		}
+		if _, err := pm.UpdateProcessInfo(&process); err != nil {
+			return err
+		}
+		g.Log.Info("rtsp credentials encrypted", process.Name)
+	}
+	return nil
+}
+
+// withEndpointCredentials returns a copy of the camera with the credentials in the RTSP endpoint (compared with imported and declared cameras)
+func (pm *ProcessManager) withEndpointCredentials(process *models.StreamProcess) (*models.StreamProcess, error) {
+	p := *process
+	splitCredentials(&p)
+	endpoint, err := pm.rtspEndpointWithCredentials(&p)
+	if err != nil {
+		return nil, err
+	}
+	p.RTSPEndpoint = endpoint
+	p.RTSPUsername = ""
+	p.RTSPPassword = ""
+	return &p, nil
+}
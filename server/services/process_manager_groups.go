@@ -0,0 +1,107 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+// ListGroup - cameras matching the group and label filter (see List)
+func (pm *ProcessManager) ListGroup(filter *models.ProcessFilter) ([]*models.StreamProcess, error) {
+	processes, err := pm.List()
+	if err != nil {
+		return nil, err
+	}
+	matching := make([]*models.StreamProcess, 0)
+	for _, process := range processes {
+		if filter.Matches(process) {
+			matching = append(matching, process)
+		}
+	}
+	return matching, nil
+}
+
+// RestartGroup - restarts all cameras matching the filter. Failures are reported per camera.
+func (pm *ProcessManager) RestartGroup(filter *models.ProcessFilter) ([]*models.GroupOperationResult, error) {
+	return pm.groupOperation(filter, func(process *models.StreamProcess) error {
+		_, err := pm.Restart(process.Name)
+		return err
+	})
+}
+
+// ProxyGroup - turns RTMP passthrough on or off for all cameras matching the filter. Failures are reported per camera.
+func (pm *ProcessManager) ProxyGroup(filter *models.ProcessFilter, passthrough bool) ([]*models.GroupOperationResult, error) {
+	return pm.groupOperation(filter, func(process *models.StreamProcess) error {
+		_, err := pm.SetProxy(process.Name, passthrough)
+		return err
+	})
+}
+
+// SetProxy - turns RTMP passthrough on or off (read by the camera container from redis)
+func (pm *ProcessManager) SetProxy(deviceID string, passthrough bool) (*models.StreamProcess, error) {
+	info, err := pm.Info(deviceID)
+	if err != nil {
+		g.Log.Error("failed to get deviceID info", err)
+		return nil, err
+	}
+	if info.RTMPEndpoint == "" && passthrough {
+		return nil, models.ErrNoRTMPEndpoint
+	}
+
+	valMap := make(map[string]interface{}, 0)
+	valMap[models.RedisLastAccessQueryTimeKey] = time.Now().Unix() * 1000
+	valMap[models.RedisProxyRTMPKey] = passthrough
+
+	rErr := pm.rdb.HSet(models.RedisLastAccessPrefix+deviceID, valMap).Err()
+	if rErr != nil {
+		g.Log.Error("failed to store startproxy value map to redis", rErr)
+		return nil, rErr
+	}
+	if info.RTMPStreamStatus == nil {
+		info.RTMPStreamStatus = &models.RTMPStreamStatus{}
+	}
+	info.RTMPStreamStatus.Streaming = passthrough
+
+	_, sErr := pm.UpdateProcessInfo(info)
+	if sErr != nil {
+		g.Log.Error("failed to update stream info", deviceID, sErr)
+		return nil, sErr
+	}
+	return info, nil
+}
+
+// groupOperation performs the operation on stored cameras matching the filter, sorted by name
+func (pm *ProcessManager) groupOperation(filter *models.ProcessFilter, operation func(process *models.StreamProcess) error) ([]*models.GroupOperationResult, error) {
+	objects, err := pm.storage.List(models.PrefixRTSPProcess)
+	if err != nil {
+		g.Log.Error("failed to list devices", err)
+		return nil, err
+	}
+	processes := make([]*models.StreamProcess, 0)
+	for _, v := range objects {
+		var process models.StreamProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("failed to unmarshal object", err)
+			return nil, err
+		}
+		if filter.Matches(&process) {
+			processes = append(processes, &process)
+		}
+	}
+	sort.Slice(processes, func(i, j int) bool { return processes[i].Name < processes[j].Name })
+
+	results := make([]*models.GroupOperationResult, 0, len(processes))
+	for _, process := range processes {
+		result := &models.GroupOperationResult{Name: process.Name, Success: true}
+		if err := operation(process); err != nil {
+			g.Log.Warn("group operation failed for camera", process.Name, err)
+			result.Success = false
+			result.Message = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+// time limit for each RTSP request of the probe
+const rtspProbeTimeout = 5 * time.Second
+
+// Probe checks if the camera stream can be consumed before the camera is created.
+// Without a password in the request the stored one of the camera (if any) is used, but only for the same endpoint.
+func (pm *ProcessManager) Probe(process *models.StreamProcess) (*models.RTSPProbeResult, error) {
+	p := *process
+	splitCredentials(&p)
+	password, err := pm.probePassword(&p)
+	if err != nil {
+		return nil, err
+	}
+	result := pm.probeRTSP(p.RTSPEndpoint, p.RTSPUsername, password, rtspProbeTimeout)
+	if !result.OK() {
+		g.Log.Info("rtsp probe failed", process.Name, result.Endpoint, result.Status, result.Message)
+	}
+	return result, nil
+}
+
+// validateStream probes the RTSP endpoint, unreachable streams only end up in container restart loops
+func (pm *ProcessManager) validateStream(process *models.StreamProcess) error {
+	result, err := pm.Probe(process)
+	if err != nil {
+		return err
+	}
+	if !result.OK() {
+		return &models.RTSPProbeError{Result: result}
+	}
+	return nil
+}
+
+// probePassword - password from the request or the stored one if the camera of the same name has the same scheme, host and port
+// (stored password is never sent to a different host)
+func (pm *ProcessManager) probePassword(process *models.StreamProcess) (string, error) {
+	if hasNewPassword(process) {
+		return process.RTSPPassword, nil
+	}
+	stored, err := pm.storedProcess(process.Name)
+	if err != nil {
+		if err == models.ErrProcessNotFoundDatastore {
+			return "", nil
+		}
+		return "", err
+	}
+	if !sameEndpointHost(stored.RTSPEndpoint, process.RTSPEndpoint) {
+		return "", nil
+	}
+	return pm.rtspPassword(process.Name)
+}
+
+// default ports of the RTSP schemes
+var rtspDefaultPorts = map[string]string{"rtsp": "554", "rtsps": "322"}
+
+// sameEndpointHost checks if both endpoints have the same scheme, host and port (default port if omitted)
+func sameEndpointHost(a string, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		return rtspDefaultPorts[strings.ToLower(u.Scheme)]
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) && strings.EqualFold(ua.Hostname(), ub.Hostname()) && port(ua) == port(ub)
+}
@@ -3,14 +3,13 @@ package services
 import (
 	"strings"
 
-	"github.com/chryscloud/go-microkit-plugins/docker"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/models"
 )
 
 // StatsAllProcesses created a statistics object or all running containers (related to edge)
 func (pm *ProcessManager) StatsAllProcesses(sett *models.Settings) (*models.AllStreamProcessStats, error) {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
+	cl := pm.containerRuntime
 
 	systemInfo, diskUsage, err := cl.SystemWideInfo()
 
@@ -63,11 +62,10 @@ func (pm *ProcessManager) StatsAllProcesses(sett *models.Settings) (*models.AllS
 			continue
 		}
 
-		s, err := cl.ContainerStats(c.ID)
+		calculated, err := cl.ContainerStats(c.ID)
 		if err != nil {
 			return nil, err
 		}
-		calculated := cl.CalculateStats(s)
 		calculated.Status = c.State.Status
 		restartCount := 0
 		if c.State.ExitCode > 0 {
@@ -83,6 +81,7 @@ func (pm *ProcessManager) StatsAllProcesses(sett *models.Settings) (*models.AllS
 			NetworkTx:   int64(calculated.NetworkTx),
 			NumRestarts: restartCount,
 			Status:      c.State.Status,
+			Health:      process.StreamHealth,
 		}
 		stats.ContainersStats = append(stats.ContainersStats, procStats)
 	}
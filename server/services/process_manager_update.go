@@ -0,0 +1,186 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	dockerErrors "github.com/docker/docker/client"
+)
+
+// suffix of the previous camera container kept until the updated one is started
+const previousContainerSuffix = "_previous"
+
+// Update - updates the camera in place. Name, image, created time and storage flags are kept from the stored process.
+// Changed stream endpoints recreate the container with the same name. If the new container fails to start
+// the previous container is restored and models.ErrProcessUpdateFailed returned.
+func (pm *ProcessManager) Update(deviceID string, update *models.StreamProcess) (*models.StreamProcess, error) {
+	stored, err := pm.storedProcess(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *stored
+	updated.RTSPEndpoint = update.RTSPEndpoint
+	updated.RTSPUsername = update.RTSPUsername
+	updated.RTSPPassword = update.RTSPPassword
+	splitCredentials(&updated)
+	updated.RTMPEndpoint = update.RTMPEndpoint
+	updated.Location = update.Location
+	updated.Group = update.Group
+	updated.Labels = update.Labels
+	updated.Description = update.Description
+
+	password, credentialsChanged, err := pm.updatedPassword(stored, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if requiresNewContainer(stored, &updated) || credentialsChanged {
+		if err := pm.recreateContainer(&updated, password); err != nil {
+			return nil, err
+		}
+		if updated.RTMPEndpoint != stored.RTMPEndpoint {
+			if err := pm.updateRTMPProxy(&updated); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if password == "" {
+		if err := pm.removeCredentials(deviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = pm.UpdateProcessInfo(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return pm.Info(deviceID)
+}
+
+// updatedPassword returns the RTSP password passed to the container and if the credentials changed.
+// Without a new password the stored one is kept, without a username it's removed as well.
+func (pm *ProcessManager) updatedPassword(stored *models.StreamProcess, updated *models.StreamProcess) (string, bool, error) {
+	storedPassword, err := pm.rtspPassword(stored.Name)
+	if err != nil {
+		return "", false, err
+	}
+	password := storedPassword
+	switch {
+	case hasNewPassword(updated):
+		password = updated.RTSPPassword
+	case updated.RTSPUsername == "":
+		password = ""
+	}
+	return password, stored.RTSPUsername != updated.RTSPUsername || storedPassword != password, nil
+}
+
+// requiresNewContainer checks if the changed fields are passed to the container (environment variables and docker labels)
+func requiresNewContainer(stored *models.StreamProcess, updated *models.StreamProcess) bool {
+	return stored.RTSPEndpoint != updated.RTSPEndpoint || stored.RTMPEndpoint != updated.RTMPEndpoint ||
+		stored.Group != updated.Group || !sameLabels(stored.Labels, updated.Labels)
+}
+
+// sameCameraMetadata compares the descriptive fields (group, labels, description and location)
+func sameCameraMetadata(a *models.StreamProcess, b *models.StreamProcess) bool {
+	return a.Group == b.Group && a.Description == b.Description && sameLabels(a.Labels, b.Labels) && reflect.DeepEqual(a.Location, b.Location)
+}
+
+// sameLabels treats missing and empty labels as equal
+func sameLabels(a map[string]string, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// recreateContainer replaces the camera container with a new one from the process configuration. The previous
+// container is renamed and stopped until the new one is started, it's restored in case of a failure.
+func (pm *ProcessManager) recreateContainer(process *models.StreamProcess, password string) error {
+	cl := pm.containerRuntime
+	containerName := strings.ToLower(process.Name)
+
+	previous, err := cl.ContainerGet(containerName)
+	if err != nil {
+		if !dockerErrors.IsErrNotFound(err) {
+			g.Log.Error("failed to retrieve container", process.Name, err)
+			return err
+		}
+		// container missing, nothing to restore
+		previous = nil
+	}
+
+	if previous != nil {
+		killAfter := time.Second * 5
+		if err := cl.ContainerStop(previous.ID, &killAfter); err != nil {
+			g.Log.Error("failed to stop container before update", process.Name, err)
+			return err
+		}
+		if err := cl.ContainerRename(previous.ID, containerName+previousContainerSuffix); err != nil {
+			g.Log.Error("failed to rename container before update", process.Name, err)
+			if sErr := cl.ContainerStart(previous.ID); sErr != nil {
+				g.Log.Error("failed to start previous container", process.Name, sErr)
+			}
+			return err
+		}
+	}
+
+	startErr := pm.createContainer(process, password)
+	if startErr == nil {
+		startErr = cl.ContainerStart(containerName)
+		if startErr != nil {
+			g.Log.Error("failed to start updated container", process.Name, startErr)
+			if rErr := cl.ContainerRemove(containerName); rErr != nil {
+				g.Log.Error("failed to remove updated container", process.Name, rErr)
+			}
+		}
+	}
+
+	if startErr != nil {
+		if previous == nil {
+			return startErr
+		}
+		// rollback to the previous container
+		if err := cl.ContainerRename(previous.ID, containerName); err != nil {
+			g.Log.Error("failed to rename previous container back", process.Name, err)
+			return err
+		}
+		if err := cl.ContainerStart(previous.ID); err != nil {
+			g.Log.Error("failed to start previous container", process.Name, err)
+			return err
+		}
+		pm.stateCache.Refresh(containerName)
+		g.Log.Warn("camera update failed, previous container restored", process.Name, startErr)
+		return models.ErrProcessUpdateFailed
+	}
+
+	if previous != nil {
+		if err := cl.ContainerRemove(previous.ID); err != nil {
+			g.Log.Warn("failed to remove previous container after update", process.Name, err)
+		}
+	}
+	if c, err := pm.stateCache.Refresh(containerName); err == nil {
+		process.ContainerID = c.ID
+	}
+	return nil
+}
+
+// updateRTMPProxy starts proxying to the new RTMP endpoint or stops proxying if it was removed
+func (pm *ProcessManager) updateRTMPProxy(process *models.StreamProcess) error {
+	if process.RTMPEndpoint != "" {
+		return pm.startRTMPProxy(process)
+	}
+	rErr := pm.rdb.HSet(models.RedisLastAccessPrefix+process.Name, models.RedisProxyRTMPKey, false).Err()
+	if rErr != nil {
+		g.Log.Error("failed to store stopproxy value to redis", rErr)
+		return rErr
+	}
+	if process.RTMPStreamStatus != nil {
+		process.RTMPStreamStatus.Streaming = false
+	}
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/chryscloud/go-microkit-plugins/docker"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/models"
 	"github.com/dgraph-io/badger/v2"
@@ -61,10 +60,10 @@ func (pm *ProcessManager) FindUpgrades(imageUpgrade *models.ImageUpgrade) ([]*mo
 }
 
 func (pm *ProcessManager) UpgradeRunningContainer(process *models.StreamProcess, newImage string) (*models.StreamProcess, error) {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
+	cl := pm.containerRuntime
 
 	// find container
-	containers, err := cl.ContainersList()
+	containers, err := cl.ContainersList(false)
 	if err != nil {
 		g.Log.Error("failed to list running containers", err)
 		return nil, err
@@ -129,13 +128,19 @@ func (pm *ProcessManager) UpgradeRunningContainer(process *models.StreamProcess,
 	splitted := strings.Split(newImage, ":")
 	newVersion := splitted[1]
 	newBaseImage := splitted[0]
-	rErr := cl.ContainerReplace(runningContainer.ID, newBaseImage, newVersion)
+
+	// container is recreated from the camera record (credentials are passed in the format of the new image)
+	password, err := pm.rtspPassword(existingProcess.Name)
+	if err != nil {
+		return nil, err
+	}
+	existingProcess.ImageTag = newBaseImage + ":" + newVersion
+	rErr := pm.recreateContainer(&existingProcess, password)
 	if rErr != nil {
 		g.Log.Error("failed to replace running container", runningContainer.Names, runningContainer.ID, rErr)
 		return nil, rErr
 	}
 
-	existingProcess.ImageTag = newBaseImage + ":" + newVersion
 	existingProcess.Modified = time.Now().Unix() * 1000
 
 	newExistingProcessBytes, err := json.Marshal(existingProcess)
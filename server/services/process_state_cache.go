@@ -0,0 +1,224 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	microModelDocker "github.com/chryscloud/go-microkit-plugins/models/docker"
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+const (
+	processStateResyncInterval = time.Minute     // full resync with docker (in case of missed events)
+	processStateRetryInterval  = time.Second * 5 // reconnect to docker events after failure
+)
+
+// ProcessStateCache - container state kept in memory, updated from the docker events stream and resynced periodically
+type ProcessStateCache struct {
+	mux              sync.RWMutex
+	containerRuntime ContainerRuntime
+	containers       map[string]*processStateEntry // by container name
+	resyncStarted    time.Time                     // start of the running resync (zero if none)
+}
+
+type processStateEntry struct {
+	container *types.ContainerJSON // nil if removed while resync is running
+	updated   time.Time            // when the entry was last updated from docker
+}
+
+func NewProcessStateCache(containerRuntime ContainerRuntime) *ProcessStateCache {
+	return &ProcessStateCache{
+		containerRuntime: containerRuntime,
+		containers:       make(map[string]*processStateEntry),
+	}
+}
+
+// Start listening to docker events and resync periodically
+func (pc *ProcessStateCache) Start() {
+	go func() {
+		for {
+			pc.resync()
+			time.Sleep(processStateResyncInterval)
+		}
+	}()
+	go func() {
+		for {
+			pc.listen()
+			time.Sleep(processStateRetryInterval)
+		}
+	}()
+}
+
+// Get returns the cached container (models.ErrProcessNotFound if container doesn't exist)
+func (pc *ProcessStateCache) Get(name string) (*types.ContainerJSON, error) {
+	pc.mux.RLock()
+	e, ok := pc.containers[containerKey(name)]
+	pc.mux.RUnlock()
+	if ok && e.container != nil {
+		return e.container, nil
+	}
+	// not seen yet (e.g. just created and event not received yet)
+	return pc.Refresh(name)
+}
+
+// Refresh reloads a single container from docker
+func (pc *ProcessStateCache) Refresh(name string) (*types.ContainerJSON, error) {
+	c, err := pc.containerRuntime.ContainerGet(name)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			pc.remove(name)
+			return nil, models.ErrProcessNotFound
+		}
+		g.Log.Error("failed to retrieve container", name, err)
+		return nil, err
+	}
+	pc.put(c)
+	return c, nil
+}
+
+func (pc *ProcessStateCache) put(c *types.ContainerJSON) {
+	pc.mux.Lock()
+	defer pc.mux.Unlock()
+	pc.containers[containerKey(c.Name)] = &processStateEntry{container: c, updated: time.Now()}
+}
+
+func (pc *ProcessStateCache) remove(name string) {
+	pc.mux.Lock()
+	defer pc.mux.Unlock()
+	if pc.resyncStarted.IsZero() {
+		delete(pc.containers, containerKey(name))
+		return
+	}
+	// keep the removal until the resync is finished, the resync snapshot might still contain the container
+	pc.containers[containerKey(name)] = &processStateEntry{updated: time.Now()}
+}
+
+func (pc *ProcessStateCache) resync() {
+	started := pc.startResync()
+	fetched := make(map[string]*types.ContainerJSON)
+	defer func() { pc.finishResync(started, fetched) }()
+
+	list, err := pc.containerRuntime.ContainersList(true)
+	if err != nil {
+		g.Log.Error("failed to list containers for process state resync", err)
+		fetched = nil
+		return
+	}
+	for _, item := range list {
+		c, err := pc.containerRuntime.ContainerGet(item.ID)
+		if err != nil {
+			if !client.IsErrNotFound(err) {
+				g.Log.Error("failed to retrieve container for process state resync", item.ID, err)
+			}
+			continue
+		}
+		fetched[containerKey(c.Name)] = c
+	}
+}
+
+func (pc *ProcessStateCache) startResync() time.Time {
+	pc.mux.Lock()
+	defer pc.mux.Unlock()
+	pc.resyncStarted = time.Now()
+	return pc.resyncStarted
+}
+
+// finishResync merges the resync snapshot per container. Entries updated by events after the resync started
+// are newer than the snapshot and kept. Nil snapshot (failed resync) only drops the removed entries.
+func (pc *ProcessStateCache) finishResync(started time.Time, fetched map[string]*types.ContainerJSON) {
+	pc.mux.Lock()
+	defer pc.mux.Unlock()
+	pc.resyncStarted = time.Time{}
+
+	if fetched != nil {
+		for key, c := range fetched {
+			if e, ok := pc.containers[key]; ok && !e.updated.Before(started) {
+				continue
+			}
+			pc.containers[key] = &processStateEntry{container: c, updated: started}
+		}
+	}
+	for key, e := range pc.containers {
+		if e.container == nil {
+			delete(pc.containers, key)
+			continue
+		}
+		if fetched == nil {
+			continue
+		}
+		// missing in the snapshot and not updated since the resync started
+		if _, ok := fetched[key]; !ok && e.updated.Before(started) {
+			delete(pc.containers, key)
+		}
+	}
+}
+
+// listen updates cached containers on docker events until the event stream fails
+func (pc *ProcessStateCache) listen() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	messages, errs := pc.containerRuntime.Events(ctx)
+
+	for {
+		select {
+		case err := <-errs:
+			if err != nil && err != io.EOF {
+				g.Log.Error("docker event listener for process state cache failed", err)
+			}
+			return
+		case e := <-messages:
+			name, ok := e.Actor.Attributes["name"]
+			if !ok {
+				continue
+			}
+			if e.Action == "destroy" {
+				pc.remove(name)
+				continue
+			}
+			// exec events don't change the container state
+			if strings.HasPrefix(e.Action, "exec_") {
+				continue
+			}
+			pc.Refresh(name)
+		}
+	}
+}
+
+// containerLogs - last 100 lines of container logs (only fetched on request, never cached)
+func containerLogs(stateCache *ProcessStateCache, name string) (*microModelDocker.DockerLogs, error) {
+	c, err := stateCache.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := stateCache.containerRuntime.ContainerLogs(c.ID, 100, time.Unix(0, 0))
+	if err != nil {
+		g.Log.Error("failed to retrieve container logs", name, err)
+		return nil, err
+	}
+	return logs, nil
+}
+
+// containers of cameras are created with lower case names
+func containerKey(name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, "/"))
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestProcessStateCache(t *testing.T) {
+	cache := NewProcessStateCache(NewMemoryRuntime())
+	cache.put(&types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    "abc",
+			Name:  "/Camera1",
+			State: &types.ContainerState{Status: "running", Running: true},
+		},
+	})
+
+	c, err := cache.Get("camera1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != "abc" || c.State.Status != "running" {
+		t.Fatalf("unexpected cached container %v", c.ContainerJSONBase)
+	}
+	if _, err := cache.Get("/camera1"); err != nil {
+		t.Fatal(err)
+	}
+
+	cache.remove("/Camera1")
+	cache.mux.RLock()
+	defer cache.mux.RUnlock()
+	if len(cache.containers) != 0 {
+		t.Fatalf("expected empty cache, got %d containers", len(cache.containers))
+	}
+}
+
+func TestProcessStateCacheEvents(t *testing.T) {
+	rt := NewMemoryRuntime()
+	rt.AddImage("someone/tool:1")
+	cache := NewProcessStateCache(rt)
+	go cache.listen()
+
+	waitFor := func(description string, condition func() bool) {
+		deadline := time.Now().Add(time.Second * 5)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout waiting for %v", description)
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+	}
+	cached := func() *types.ContainerJSON {
+		cache.mux.RLock()
+		defer cache.mux.RUnlock()
+		if e, ok := cache.containers["tool"]; ok {
+			return e.container
+		}
+		return nil
+	}
+	waitFor("event subscription", func() bool {
+		rt.mux.Lock()
+		defer rt.mux.Unlock()
+		return len(rt.subscribers) > 0
+	})
+
+	if err := rt.ContainerCreate("tool", &container.Config{Image: "someone/tool:1"}, &container.HostConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.ContainerStart("tool"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("running container", func() bool {
+		c := cached()
+		return c != nil && c.State.Running
+	})
+	if err := rt.ContainerStop("tool", nil); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("stopped container", func() bool {
+		c := cached()
+		return c != nil && !c.State.Running
+	})
+	if err := rt.ContainerRemove("tool"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("removed container", func() bool { return cached() == nil })
+}
+
+func TestProcessStateCacheResync(t *testing.T) {
+	cache := NewProcessStateCache(NewMemoryRuntime())
+	state := func(name string, status string) *types.ContainerJSON {
+		return &types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: name, Name: "/" + name, State: &types.ContainerState{Status: status}}}
+	}
+	cache.put(state("stale", "running"))
+	cache.put(state("camera1", "running"))
+
+	started := cache.startResync()
+	// events received while the resync snapshot is being built
+	cache.put(state("camera1", "exited"))
+	cache.put(state("camera2", "running"))
+	cache.remove("camera3")
+	snapshot := map[string]*types.ContainerJSON{
+		"camera1": state("camera1", "running"),
+		"camera3": state("camera3", "running"),
+		"camera4": state("camera4", "running"),
+	}
+	cache.finishResync(started, snapshot)
+
+	expected := map[string]string{"camera1": "exited", "camera2": "running", "camera4": "running"}
+	cache.mux.RLock()
+	defer cache.mux.RUnlock()
+	if len(cache.containers) != len(expected) {
+		t.Fatalf("expected %v cached containers, got %v", len(expected), len(cache.containers))
+	}
+	for name, status := range expected {
+		e, ok := cache.containers[name]
+		if !ok || e.container == nil || e.container.State.Status != status {
+			t.Fatalf("expected %v %v after resync, got %v", name, status, e)
+		}
+	}
+}
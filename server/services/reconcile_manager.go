@@ -0,0 +1,456 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/go-redis/redis/v7"
+)
+
+const (
+	defaultReconcileInterval = time.Minute
+	// containers younger than this are skipped (datastore record might not be written yet)
+	reconcileContainerGracePeriod = time.Minute * 2
+	// docker network of cameras and apps
+	reconcileNetwork = "chrysnet"
+	// docker compose label with the service name of the container
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// default running components of the edge proxy (container or docker compose service names)
+var systemContainers = map[string]bool{
+	"chrysedgeportal": true,
+	"chrysedgeserver": true,
+	"redis":           true,
+}
+
+// ReconcileManager - treats the datastore as desired state and brings docker containers in line with it
+type ReconcileManager struct {
+	storage         *Storage
+	processManager  *ProcessManager
+	appManager      *AppProcessManager
+	settingsManager *SettingsManager
+	rdb             *redis.Client
+	mux             sync.Mutex
+}
+
+// reconcileContainer - subset of docker container info required for reconciliation
+type reconcileContainer struct {
+	ID      string
+	Name    string
+	Image   string
+	Labels  map[string]string
+	Network string
+	Created time.Time
+	Env     []string
+}
+
+// reconcileDesired - datastore records by process type
+type reconcileDesired struct {
+	cameras map[string]*models.StreamProcess
+	apps    map[string]*models.AppProcess
+}
+
+func NewReconcileManager(storage *Storage, processManager *ProcessManager, appManager *AppProcessManager, settingsManager *SettingsManager, rdb *redis.Client) *ReconcileManager {
+	return &ReconcileManager{
+		storage:         storage,
+		processManager:  processManager,
+		appManager:      appManager,
+		settingsManager: settingsManager,
+		rdb:             rdb,
+	}
+}
+
+// Start reconciles on boot and periodically after that
+func (rm *ReconcileManager) Start() {
+	interval := defaultReconcileInterval
+	if g.Conf.Reconcile != nil && g.Conf.Reconcile.IntervalMs > 0 {
+		interval = time.Duration(g.Conf.Reconcile.IntervalMs) * time.Millisecond
+	}
+	go func() {
+		for {
+			if _, err := rm.Reconcile(false); err != nil {
+				g.Log.Error("reconciliation failed", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// Reconcile compares datastore records with docker containers. On dry run only the report of required actions is returned.
+func (rm *ReconcileManager) Reconcile(dryRun bool) (*models.ReconcileReport, error) {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+
+	report := &models.ReconcileReport{
+		DryRun:  dryRun,
+		Started: time.Now().Unix() * 1000,
+		Actions: make([]*models.ReconcileAction, 0),
+	}
+
+	desired, err := rm.desiredState()
+	if err != nil {
+		return nil, err
+	}
+
+	cl := rm.processManager.containerRuntime
+	list, err := cl.ContainersList(true)
+	if err != nil {
+		g.Log.Error("failed to list containers for reconciliation", err)
+		return nil, err
+	}
+	containers := make([]*reconcileContainer, 0, len(list))
+	for _, c := range list {
+		rc := &reconcileContainer{
+			ID:      c.ID,
+			Image:   c.Image,
+			Labels:  c.Labels,
+			Network: c.HostConfig.NetworkMode,
+			Created: time.Unix(c.Created, 0),
+		}
+		if len(c.Names) > 0 {
+			rc.Name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		// environment is only required to adopt camera containers
+		if _, managed := c.Labels[models.ContainerLabelManaged]; !managed && isCameraImage(c.Image) {
+			if info, iErr := cl.ContainerGet(c.ID); iErr == nil && info.Config != nil {
+				rc.Env = info.Config.Env
+			}
+		}
+		containers = append(containers, rc)
+	}
+
+	adopt, removeOrphans := true, true
+	if g.Conf.Reconcile != nil {
+		adopt = g.Conf.Reconcile.Adopt
+		removeOrphans = g.Conf.Reconcile.RemoveOrphans
+	}
+	actions, inSync := planReconcile(desired, containers, adopt, removeOrphans, time.Now())
+	report.InSync = len(inSync)
+	report.Actions = actions
+
+	if !dryRun {
+		now := time.Now().Unix() * 1000
+		for _, action := range actions {
+			rm.apply(cl, desired, containers, action)
+			if action.Action == models.ReconcileActionRecreate || action.Action == models.ReconcileActionAdopt {
+				status := &models.ReconcileStatus{Reconciled: now, Status: models.ReconcileStatusRecreated}
+				if action.Action == models.ReconcileActionAdopt {
+					status.Status = models.ReconcileStatusAdopted
+				}
+				if action.Error != "" {
+					status.Status = models.ReconcileStatusFailed
+					status.Error = action.Error
+				}
+				rm.setReconcileStatus(action.ProcessType, action.Name, status)
+			}
+		}
+		for _, key := range inSync {
+			rm.setReconcileStatus(key.processType, key.name, &models.ReconcileStatus{Reconciled: now, Status: models.ReconcileStatusInSync})
+		}
+	}
+
+	report.Finished = time.Now().Unix() * 1000
+	return report, nil
+}
+
+func (rm *ReconcileManager) desiredState() (*reconcileDesired, error) {
+	desired := &reconcileDesired{
+		cameras: make(map[string]*models.StreamProcess),
+		apps:    make(map[string]*models.AppProcess),
+	}
+	cameras, err := rm.storage.List(models.PrefixRTSPProcess)
+	if err != nil {
+		g.Log.Error("failed to list cameras for reconciliation", err)
+		return nil, err
+	}
+	for _, v := range cameras {
+		var process models.StreamProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("failed to unmarshal stored process", err)
+			continue
+		}
+		desired.cameras[strings.ToLower(process.Name)] = &process
+	}
+	apps, err := rm.storage.List(models.PrefixAppProcess)
+	if err != nil {
+		g.Log.Error("failed to list apps for reconciliation", err)
+		return nil, err
+	}
+	for _, v := range apps {
+		var app models.AppProcess
+		if err := json.Unmarshal(v, &app); err != nil {
+			g.Log.Error("failed to unmarshal stored app", err)
+			continue
+		}
+		desired.apps[strings.ToLower(app.Name)] = &app
+	}
+	return desired, nil
+}
+
+type reconcileKey struct {
+	processType string
+	name        string
+}
+
+// planReconcile returns actions required to bring containers to the desired state and processes already in sync
+func planReconcile(desired *reconcileDesired, containers []*reconcileContainer, adopt bool, removeOrphans bool, now time.Time) ([]*models.ReconcileAction, []reconcileKey) {
+	actions := make([]*models.ReconcileAction, 0)
+	inSync := make([]reconcileKey, 0)
+
+	byName := make(map[string]*reconcileContainer)
+	for _, c := range containers {
+		byName[strings.ToLower(c.Name)] = c
+	}
+
+	for name, camera := range desired.cameras {
+		if _, ok := byName[name]; ok {
+			inSync = append(inSync, reconcileKey{models.ProcessTypeRTSP, camera.Name})
+			continue
+		}
+		actions = append(actions, &models.ReconcileAction{
+			Name:        camera.Name,
+			ProcessType: models.ProcessTypeRTSP,
+			Action:      models.ReconcileActionRecreate,
+			Reason:      "camera container missing",
+		})
+	}
+	for name, app := range desired.apps {
+		if _, ok := byName[name]; ok {
+			inSync = append(inSync, reconcileKey{models.ProcessTypeApplication, app.Name})
+			continue
+		}
+		actions = append(actions, &models.ReconcileAction{
+			Name:        app.Name,
+			ProcessType: models.ProcessTypeApplication,
+			Action:      models.ReconcileActionRecreate,
+			Reason:      "app container missing",
+		})
+	}
+
+	for _, c := range containers {
+		name := strings.ToLower(c.Name)
+		if _, ok := desired.cameras[name]; ok {
+			continue
+		}
+		if _, ok := desired.apps[name]; ok {
+			continue
+		}
+		if isSystemContainer(c) || now.Sub(c.Created) < reconcileContainerGracePeriod {
+			continue
+		}
+
+		if processType, managed := c.Labels[models.ContainerLabelManaged]; managed {
+			action := &models.ReconcileAction{
+				Name:        c.Name,
+				ProcessType: processType,
+				ContainerID: c.ID,
+				Action:      models.ReconcileActionRemove,
+				Reason:      "orphaned container without datastore record",
+			}
+			if !removeOrphans {
+				action.Action = models.ReconcileActionFlag
+			}
+			actions = append(actions, action)
+			continue
+		}
+
+		if isCameraImage(c.Image) {
+			if _, ok := cameraFromContainer(c); ok && adopt {
+				actions = append(actions, &models.ReconcileAction{
+					Name:        c.Name,
+					ProcessType: models.ProcessTypeRTSP,
+					ContainerID: c.ID,
+					Action:      models.ReconcileActionAdopt,
+					Reason:      "unmanaged camera container",
+				})
+				continue
+			}
+		} else if c.Network != reconcileNetwork {
+			// not related to the edge proxy
+			continue
+		}
+		actions = append(actions, &models.ReconcileAction{
+			Name:        c.Name,
+			ContainerID: c.ID,
+			Action:      models.ReconcileActionFlag,
+			Reason:      "unmanaged container",
+		})
+	}
+	return actions, inSync
+}
+
+// apply performs the action (errors are reported on the action)
+func (rm *ReconcileManager) apply(cl ContainerRuntime, desired *reconcileDesired, containers []*reconcileContainer, action *models.ReconcileAction) {
+	var err error
+	switch action.Action {
+	case models.ReconcileActionRecreate:
+		if action.ProcessType == models.ProcessTypeRTSP {
+			err = rm.recreateCamera(desired.cameras[strings.ToLower(action.Name)])
+		} else {
+			err = rm.recreateApp(desired.apps[strings.ToLower(action.Name)])
+		}
+	case models.ReconcileActionAdopt:
+		for _, c := range containers {
+			if c.ID == action.ContainerID {
+				err = rm.adoptCamera(c)
+				break
+			}
+		}
+	case models.ReconcileActionRemove:
+		killAfter := time.Second * 5
+		if sErr := cl.ContainerStop(action.ContainerID, &killAfter); sErr != nil {
+			g.Log.Warn("failed to stop orphaned container", action.Name, sErr)
+		}
+		err = cl.ContainerRemove(action.ContainerID)
+	case models.ReconcileActionFlag:
+		g.Log.Warn("container not managed by the edge proxy", action.Name, action.ContainerID)
+	}
+	if err != nil {
+		g.Log.Error("reconcile action failed", action.Action, action.Name, err)
+		action.Error = err.Error()
+	} else if action.Action != models.ReconcileActionFlag {
+		g.Log.Info("reconcile action performed", action.Action, action.Name)
+	}
+}
+
+func (rm *ReconcileManager) recreateCamera(process *models.StreamProcess) error {
+	// make sure the record wasn't removed in the meantime
+	if _, err := rm.storage.Get(models.PrefixRTSPProcess, process.Name); err != nil {
+		return models.ErrProcessNotFoundDatastore
+	}
+	imageUpgrade, err := rm.settingsManager.ListDockerImages(models.CameraTypeToImageTag["rtsp"])
+	if err != nil {
+		return err
+	}
+	created := process.Created
+	process.ContainerID = ""
+	process.State = nil
+	process.Logs = nil
+	// camera was validated when created, the stream might just be temporarily down
+	if err := rm.processManager.Start(process, imageUpgrade, true); err != nil {
+		return err
+	}
+	if process.Paused {
+		if err := rm.processManager.setPaused(process, true); err != nil {
+			return err
+		}
+	}
+	if created > 0 {
+		process.Created = created
+		if _, err := rm.processManager.UpdateProcessInfo(process); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rm *ReconcileManager) recreateApp(app *models.AppProcess) error {
+	if _, err := rm.storage.Get(models.PrefixAppProcess, app.Name); err != nil {
+		return models.ErrProcessNotFoundDatastore
+	}
+	created := app.Created
+	app.ContainerID = ""
+	app.State = nil
+	app.Logs = nil
+	installed, err := rm.appManager.Install(app)
+	if err != nil {
+		return err
+	}
+	if installed.Paused {
+		if err := rm.appManager.containerRuntime.ContainerPause(installed.Name); err != nil {
+			return err
+		}
+	}
+	if created > 0 {
+		installed.Created = created
+		b, err := json.Marshal(installed)
+		if err != nil {
+			return err
+		}
+		return rm.storage.Put(models.PrefixAppProcess, installed.Name, b)
+	}
+	return nil
+}
+
+func (rm *ReconcileManager) adoptCamera(c *reconcileContainer) error {
+	process, ok := cameraFromContainer(c)
+	if !ok {
+		return models.ErrInvalidInputParameters
+	}
+	process.ContainerID = c.ID
+	process.ImageTag = c.Image
+	process.Created = c.Created.Unix() * 1000
+	if process.RTMPEndpoint != "" {
+		process.RTMPStreamStatus = &models.RTMPStreamStatus{Streaming: true}
+	}
+	if _, err := rm.processManager.UpdateProcessInfo(process); err != nil {
+		return err
+	}
+	utils.PublishToRedis(rm.rdb, process.Name, models.MQTTProcessOperation(models.DeviceOperationAdd), models.ProcessTypeRTSP, nil)
+	return nil
+}
+
+// setReconcileStatus keeps the reconcile status in memory (datastore records are only written on configuration changes)
+func (rm *ReconcileManager) setReconcileStatus(processType string, name string, status *models.ReconcileStatus) {
+	if processType == models.ProcessTypeApplication {
+		rm.appManager.SetReconcileStatus(name, status)
+		return
+	}
+	rm.processManager.SetReconcileStatus(name, status)
+}
+
+// cameraFromContainer reconstructs the camera record from container environment variables
+func cameraFromContainer(c *reconcileContainer) (*models.StreamProcess, bool) {
+	process := &models.StreamProcess{}
+	for _, env := range c.Env {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "device_id":
+			process.Name = kv[1]
+		case "rtsp_endpoint":
+			process.RTSPEndpoint = kv[1]
+		case "rtmp_endpoint":
+			process.RTMPEndpoint = kv[1]
+		case "rtsp_username":
+			process.RTSPUsername = kv[1]
+		case "rtsp_password":
+			process.RTSPPassword = kv[1]
+		}
+	}
+	if process.Name == "" || process.RTSPEndpoint == "" || !strings.EqualFold(process.Name, c.Name) {
+		return nil, false
+	}
+	return process, true
+}
+
+func isCameraImage(image string) bool {
+	return strings.HasPrefix(image, models.CameraTypeToImageTag["rtsp"]+":")
+}
+
+// isSystemContainer - default running components of the edge proxy (by exact name or docker compose service)
+func isSystemContainer(c *reconcileContainer) bool {
+	return systemContainers[strings.ToLower(c.Name)] || systemContainers[c.Labels[composeServiceLabel]]
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestReconcilePlan(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour)
+
+	desired := &reconcileDesired{
+		cameras: map[string]*models.StreamProcess{
+			"frontdoor": {Name: "frontdoor", RTSPEndpoint: "rtsp://frontdoor"},
+			"backyard":  {Name: "backyard", RTSPEndpoint: "rtsp://backyard"},
+		},
+		apps: map[string]*models.AppProcess{
+			"detector": {Name: "detector"},
+		},
+	}
+	containers := []*reconcileContainer{
+		{ID: "1", Name: "frontdoor", Image: "chryscloud/chrysedgeproxy:0.0.7", Created: old},
+		{ID: "2", Name: "garage", Image: "chryscloud/chrysedgeproxy:0.0.7", Created: old, Env: []string{"device_id=garage", "rtsp_endpoint=rtsp://garage"}},
+		{ID: "3", Name: "oldapp", Image: "someone/app:1", Network: "chrysnet", Created: old, Labels: map[string]string{models.ContainerLabelManaged: models.ProcessTypeApplication}},
+		{ID: "4", Name: "newapp", Image: "someone/app:1", Network: "chrysnet", Created: now, Labels: map[string]string{models.ContainerLabelManaged: models.ProcessTypeApplication}},
+		{ID: "5", Name: "manual", Image: "someone/tool:1", Network: "chrysnet", Created: old},
+		{ID: "6", Name: "unrelated", Image: "postgres:12", Network: "bridge", Created: old},
+		{ID: "7", Name: "chrysedgeserver", Image: "chryscloud/chrysedgeserver:0.0.7", Network: "chrysnet", Created: old},
+		{ID: "8", Name: "edge_redis_1", Image: "redis:alpine", Network: "chrysnet", Created: old, Labels: map[string]string{composeServiceLabel: "redis"}},
+		{ID: "9", Name: "myredis-cache", Image: "redis:alpine", Network: "chrysnet", Created: old},
+	}
+
+	actions, inSync := planReconcile(desired, containers, true, true, now)
+	if len(inSync) != 1 || inSync[0].name != "frontdoor" {
+		t.Fatalf("expected frontdoor in sync, got %v", inSync)
+	}
+	expected := map[string]string{
+		"backyard": models.ReconcileActionRecreate,
+		"detector": models.ReconcileActionRecreate,
+		"garage":   models.ReconcileActionAdopt,
+		"oldapp":   models.ReconcileActionRemove,
+		"manual":   models.ReconcileActionFlag,
+		// only exact system container names are skipped
+		"myredis-cache": models.ReconcileActionFlag,
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("expected %v actions, got %v", len(expected), len(actions))
+	}
+	for _, a := range actions {
+		if expected[a.Name] != a.Action {
+			t.Fatalf("expected %v for %v, got %v", expected[a.Name], a.Name, a.Action)
+		}
+	}
+
+	// adoption and orphan removal disabled
+	actions, _ = planReconcile(desired, containers, false, false, now)
+	for _, a := range actions {
+		if a.Action == models.ReconcileActionAdopt || a.Action == models.ReconcileActionRemove {
+			t.Fatalf("expected no adopt or remove actions, got %v for %v", a.Action, a.Name)
+		}
+	}
+
+	process, ok := cameraFromContainer(containers[1])
+	if !ok || process.Name != "garage" || process.RTSPEndpoint != "rtsp://garage" {
+		t.Fatalf("expected camera reconstructed from container env, got %v", process)
+	}
+}
+
+func TestReconcileMemoryRuntime(t *testing.T) {
+	defer setupMemoryRuntimeConf()()
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	rt := NewMemoryRuntime()
+	rt.AddImage("someone/detector:1", "someone/tool:1")
+	stateCache := NewProcessStateCache(rt)
+	pm := NewProcessManager(storage, nil, rt, stateCache, testSecretManager(t))
+	am := NewAppManager(storage, nil, rt, stateCache)
+	rm := NewReconcileManager(storage, pm, am, NewSettingsManager(storage, rt, testSecretManager(t)), nil)
+
+	// app record without container
+	app := &models.AppProcess{Name: "detector", DockerHubUser: "someone", DockerhubRepository: "detector", DockerHubVersion: "1"}
+	b, _ := json.Marshal(app)
+	if err := storage.Put(models.PrefixAppProcess, app.Name, b); err != nil {
+		t.Fatal(err)
+	}
+	// managed container without datastore record (created before the grace period)
+	err = rt.ContainerCreate("oldtool", &container.Config{
+		Image:  "someone/tool:1",
+		Labels: map[string]string{models.ContainerLabelManaged: models.ProcessTypeApplication},
+	}, &container.HostConfig{NetworkMode: container.NetworkMode(reconcileNetwork)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.ContainerStart("oldtool"); err != nil {
+		t.Fatal(err)
+	}
+	orphan, _ := rt.ContainerGet("oldtool")
+	rt.containers[orphan.ID].Created = time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+
+	report, err := rm.Reconcile(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(report.Actions))
+	}
+	if _, err := rt.ContainerGet("oldtool"); err != nil {
+		t.Fatal("dry run must not remove containers")
+	}
+
+	report, err = rm.Reconcile(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range report.Actions {
+		if a.Error != "" {
+			t.Fatalf("reconcile action %v for %v failed: %v", a.Action, a.Name, a.Error)
+		}
+	}
+	if _, err := rt.ContainerGet("oldtool"); err == nil {
+		t.Fatal("expected orphaned container to be removed")
+	}
+	info, err := am.Info("detector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != models.ProcessStatusRunning {
+		t.Fatalf("expected recreated app running, got %v", info.Status)
+	}
+	if info.LastReconciled == nil || info.LastReconciled.Status != models.ReconcileStatusRecreated {
+		t.Fatalf("expected recreated reconcile status, got %v", info.LastReconciled)
+	}
+	stored, err := storage.Get(models.PrefixAppProcess, "detector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var storedApp models.AppProcess
+	if err := json.Unmarshal(stored, &storedApp); err != nil {
+		t.Fatal(err)
+	}
+	if storedApp.LastReconciled != nil {
+		t.Fatal("reconcile status must not be stored on the app record")
+	}
+
+	// everything is in sync now
+	report, err = rm.Reconcile(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Actions) != 0 || report.InSync != 1 {
+		t.Fatalf("expected nothing to reconcile, got %d actions and %d in sync", len(report.Actions), report.InSync)
+	}
+}
@@ -0,0 +1,432 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+)
+
+const (
+	rtspDefaultPort   = "554"
+	rtspsDefaultPort  = "322"
+	rtspMaxBodyLength = 1 << 16
+	rtspUserAgent     = "chrysalis-edge-proxy"
+)
+
+var errRTSPUnauthorized = errors.New("authentication failed")
+
+// static RTP payload types (RFC 3551)
+var rtpStaticPayloadTypes = map[int]string{0: "PCMU", 3: "GSM", 8: "PCMA", 9: "G722", 10: "L16", 11: "L16", 14: "MPA", 26: "JPEG", 32: "MPV", 33: "MP2T"}
+
+type rtspResponse struct {
+	statusCode int
+	status     string
+	header     textproto.MIMEHeader
+	body       []byte
+}
+
+// rtspClient - minimal RTSP client over TCP, only what's needed to probe the endpoint
+type rtspClient struct {
+	conn          net.Conn
+	reader        *bufio.Reader
+	timeout       time.Duration
+	cseq          int
+	username      string
+	password      string
+	authorization func(method string, uri string) string
+	auth          string
+	session       string
+}
+
+// ProbeRTSP connects to the RTSP endpoint and checks if the stream can be described and set up (OPTIONS, DESCRIBE, SETUP, TEARDOWN).
+// Credentials in the endpoint are used when username and password are empty.
+func ProbeRTSP(endpoint string, username string, password string, timeout time.Duration) *models.RTSPProbeResult {
+	start := time.Now()
+	result := &models.RTSPProbeResult{Endpoint: utils.RedactURL(endpoint), Auth: models.ProbeAuthNone}
+	defer func() {
+		result.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "rtsp" && u.Scheme != "rtsps") || u.Host == "" {
+		result.Status = models.ProbeStatusFailed
+		result.Message = "invalid rtsp endpoint"
+		return result
+	}
+	if u.User != nil {
+		if username == "" && password == "" {
+			username = u.User.Username()
+			password, _ = u.User.Password()
+		}
+		u.User = nil
+	}
+	requestURL := u.String()
+
+	client, err := dialRTSP(u, timeout)
+	if err != nil {
+		result.Status = models.ProbeStatusUnreachable
+		result.Message = err.Error()
+		return result
+	}
+	defer client.close()
+	client.username = username
+	client.password = password
+
+	options, err := client.request("OPTIONS", requestURL, nil)
+	if err != nil && err != errRTSPUnauthorized {
+		result.Status = models.ProbeStatusUnreachable
+		result.Message = err.Error()
+		return result
+	}
+	result.Reachable = true
+	if options != nil {
+		result.Server = options.header.Get("Server")
+		for _, method := range strings.Split(options.header.Get("Public"), ",") {
+			if method = strings.TrimSpace(method); method != "" {
+				result.Methods = append(result.Methods, method)
+			}
+		}
+	}
+
+	describe, err := client.request("DESCRIBE", requestURL, map[string]string{"Accept": "application/sdp"})
+	result.Auth = client.auth
+	if describe != nil && result.Server == "" {
+		result.Server = describe.header.Get("Server")
+	}
+	if err != nil {
+		result.Status = models.ProbeStatusUnreachable
+		if err == errRTSPUnauthorized {
+			result.Status = models.ProbeStatusUnauthorized
+		}
+		result.Message = err.Error()
+		return result
+	}
+	if describe.statusCode != 200 {
+		result.Status = models.ProbeStatusFailed
+		result.Message = "describe failed: " + describe.status
+		return result
+	}
+	result.SDP = string(describe.body)
+
+	base := requestURL
+	if contentBase := describe.header.Get("Content-Base"); contentBase != "" {
+		base = contentBase
+	} else if contentLocation := describe.header.Get("Content-Location"); contentLocation != "" {
+		base = contentLocation
+	}
+	result.Tracks = parseSDPTracks(result.SDP, base)
+	if len(result.Tracks) == 0 {
+		result.Status = models.ProbeStatusFailed
+		result.Message = "no media tracks in the session description"
+		return result
+	}
+
+	// video track is the one consumed by the camera container
+	track := result.Tracks[0]
+	for _, t := range result.Tracks {
+		if t.Media == "video" {
+			track = t
+			break
+		}
+	}
+	setup, err := client.request("SETUP", track.Control, map[string]string{"Transport": "RTP/AVP/TCP;unicast;interleaved=0-1"})
+	if err != nil {
+		result.Status = models.ProbeStatusFailed
+		result.Message = "setup failed: " + err.Error()
+		return result
+	}
+	if setup.statusCode != 200 {
+		result.Status = models.ProbeStatusFailed
+		result.Message = "setup failed: " + setup.status
+		return result
+	}
+	result.Setup = true
+	client.session = strings.TrimSpace(strings.Split(setup.header.Get("Session"), ";")[0])
+	// stream isn't played, teardown errors don't matter
+	client.request("TEARDOWN", requestURL, nil)
+
+	result.Status = models.ProbeStatusOK
+	return result
+}
+
+func dialRTSP(u *url.URL, timeout time.Duration) (*rtspClient, error) {
+	host := u.Host
+	if u.Port() == "" {
+		port := rtspDefaultPort
+		if u.Scheme == "rtsps" {
+			port = rtspsDefaultPort
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if u.Scheme == "rtsps" {
+		// cameras use self-signed certificates, the probe only checks the stream
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{InsecureSkipVerify: true})
+	} else {
+		conn, err = dialer.Dial("tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rtspClient{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+func (rc *rtspClient) close() {
+	rc.conn.Close()
+}
+
+// request sends the RTSP request and reads the response. Unauthorized request is repeated once with the credentials.
+func (rc *rtspClient) request(method string, uri string, header map[string]string) (*rtspResponse, error) {
+	resp, err := rc.send(method, uri, header)
+	if err != nil {
+		return nil, err
+	}
+	if resp.statusCode != 401 {
+		return resp, nil
+	}
+	if rc.authorization != nil || (rc.username == "" && rc.password == "") {
+		return resp, errRTSPUnauthorized
+	}
+	if err := rc.authenticate(resp.header.Values("WWW-Authenticate")); err != nil {
+		return resp, err
+	}
+	resp, err = rc.send(method, uri, header)
+	if err != nil {
+		return nil, err
+	}
+	if resp.statusCode == 401 {
+		return resp, errRTSPUnauthorized
+	}
+	return resp, nil
+}
+
+func (rc *rtspClient) send(method string, uri string, header map[string]string) (*rtspResponse, error) {
+	rc.cseq++
+	var req strings.Builder
+	fmt.Fprintf(&req, "%s %s RTSP/1.0\r\nCSeq: %d\r\nUser-Agent: %s\r\n", method, uri, rc.cseq, rtspUserAgent)
+	if rc.authorization != nil {
+		fmt.Fprintf(&req, "Authorization: %s\r\n", rc.authorization(method, uri))
+	}
+	if rc.session != "" {
+		fmt.Fprintf(&req, "Session: %s\r\n", rc.session)
+	}
+	for k, v := range header {
+		fmt.Fprintf(&req, "%s: %s\r\n", k, v)
+	}
+	req.WriteString("\r\n")
+
+	rc.conn.SetDeadline(time.Now().Add(rc.timeout))
+	if _, err := rc.conn.Write([]byte(req.String())); err != nil {
+		return nil, err
+	}
+	return rc.readResponse()
+}
+
+func (rc *rtspClient) readResponse() (*rtspResponse, error) {
+	tp := textproto.NewReader(rc.reader)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(line, " ", 3)
+	if len(parts) < 2 || !strings.HasPrefix(parts[0], "RTSP/") {
+		return nil, errors.New("not an rtsp response")
+	}
+	statusCode, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, errors.New("invalid rtsp status code")
+	}
+	resp := &rtspResponse{statusCode: statusCode, status: strings.Join(parts[1:], " ")}
+	resp.header, err = tp.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if length := resp.header.Get("Content-Length"); length != "" {
+		n, err := strconv.Atoi(length)
+		if err != nil || n < 0 || n > rtspMaxBodyLength {
+			return nil, errors.New("invalid rtsp content length")
+		}
+		resp.body = make([]byte, n)
+		if _, err := io.ReadFull(rc.reader, resp.body); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// authenticate prepares the Authorization header from the challenge (digest preferred over basic)
+func (rc *rtspClient) authenticate(challenges []string) error {
+	for _, challenge := range challenges {
+		if strings.HasPrefix(strings.ToLower(challenge), "digest ") {
+			params := parseAuthParams(challenge[len("digest "):])
+			realm, nonce, opaque := params["realm"], params["nonce"], params["opaque"]
+			qop := ""
+			for _, q := range strings.Split(params["qop"], ",") {
+				if strings.TrimSpace(q) == "auth" {
+					qop = "auth"
+				}
+			}
+			nc := 0
+			rc.auth = models.ProbeAuthDigest
+			rc.authorization = func(method string, uri string) string {
+				ha1 := md5Hex(rc.username + ":" + realm + ":" + rc.password)
+				ha2 := md5Hex(method + ":" + uri)
+				header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s"`, rc.username, realm, nonce, uri)
+				if qop != "" {
+					nc++
+					cnonce := randomHex(8)
+					response := md5Hex(fmt.Sprintf("%s:%s:%08x:%s:%s:%s", ha1, nonce, nc, cnonce, qop, ha2))
+					header += fmt.Sprintf(`, qop=%s, nc=%08x, cnonce="%s", response="%s"`, qop, nc, cnonce, response)
+				} else {
+					header += fmt.Sprintf(`, response="%s"`, md5Hex(ha1+":"+nonce+":"+ha2))
+				}
+				if opaque != "" {
+					header += fmt.Sprintf(`, opaque="%s"`, opaque)
+				}
+				return header
+			}
+			return nil
+		}
+	}
+	for _, challenge := range challenges {
+		if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+			rc.auth = models.ProbeAuthBasic
+			credentials := base64.StdEncoding.EncodeToString([]byte(rc.username + ":" + rc.password))
+			rc.authorization = func(method string, uri string) string {
+				return "Basic " + credentials
+			}
+			return nil
+		}
+	}
+	return errors.New("unsupported rtsp authentication")
+}
+
+// parseAuthParams parses key="value" pairs of the WWW-Authenticate challenge
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else {
+			end := strings.Index(s, ",")
+			if end < 0 {
+				value, s = s, ""
+			} else {
+				value, s = s[:end], s[end:]
+			}
+		}
+		params[key] = strings.TrimSpace(value)
+	}
+	return params
+}
+
+// parseSDPTracks lists media tracks of the session description with control URLs resolved against the base URL
+func parseSDPTracks(sdp string, base string) []*models.RTSPTrack {
+	tracks := make([]*models.RTSPTrack, 0)
+	var track *models.RTSPTrack
+	for _, line := range strings.Split(sdp, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "m="):
+			fields := strings.Fields(line[2:])
+			if len(fields) < 4 {
+				track = nil
+				continue
+			}
+			pt, _ := strconv.Atoi(fields[3])
+			track = &models.RTSPTrack{Media: fields[0], PayloadType: pt, Codec: rtpStaticPayloadTypes[pt], Control: base}
+			tracks = append(tracks, track)
+		case track == nil:
+			continue
+		case strings.HasPrefix(line, "a=rtpmap:"):
+			fields := strings.Fields(line[len("a=rtpmap:"):])
+			if len(fields) < 2 || fields[0] != strconv.Itoa(track.PayloadType) {
+				continue
+			}
+			encoding := strings.Split(fields[1], "/")
+			track.Codec = encoding[0]
+			if len(encoding) > 1 {
+				track.ClockRate, _ = strconv.Atoi(encoding[1])
+			}
+			if len(encoding) > 2 {
+				track.Channels, _ = strconv.Atoi(encoding[2])
+			}
+		case strings.HasPrefix(line, "a=fmtp:"):
+			fields := strings.SplitN(line[len("a=fmtp:"):], " ", 2)
+			if len(fields) == 2 && fields[0] == strconv.Itoa(track.PayloadType) {
+				track.Fmtp = fields[1]
+			}
+		case strings.HasPrefix(line, "a=control:"):
+			track.Control = resolveControl(base, line[len("a=control:"):])
+		}
+	}
+	return tracks
+}
+
+func resolveControl(base string, control string) string {
+	if control == "*" || control == "" {
+		return base
+	}
+	if strings.HasPrefix(control, "rtsp://") || strings.HasPrefix(control, "rtsps://") {
+		return control
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base + control
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+const fakeRTSPSDP = "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=Fake\r\nt=0 0\r\na=control:*\r\n" +
+	"m=video 0 RTP/AVP 96\r\na=rtpmap:96 H264/90000\r\na=fmtp:96 packetization-mode=1\r\na=control:trackID=1\r\n" +
+	"m=audio 0 RTP/AVP 8\r\na=control:trackID=2\r\n"
+
+// fakeRTSPServer answers RTSP requests of the probe, DESCRIBE requires digest authentication
+func fakeRTSPServer(t *testing.T, username string, password string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRTSP(conn, username, password)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func serveFakeRTSP(conn net.Conn, username string, password string) {
+	defer conn.Close()
+	tp := textproto.NewReader(bufio.NewReader(conn))
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		header, err := tp.ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		parts := strings.Fields(line)
+		method, uri := parts[0], parts[1]
+		reply := func(status string, headers string, body string) {
+			fmt.Fprintf(conn, "RTSP/1.0 %s\r\nCSeq: %s\r\nServer: FakeCam\r\n%sContent-Length: %d\r\n\r\n%s", status, header.Get("CSeq"), headers, len(body), body)
+		}
+		if method == "OPTIONS" {
+			reply("200 OK", "Public: OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN\r\n", "")
+			continue
+		}
+		params := parseAuthParams(strings.TrimPrefix(header.Get("Authorization"), "Digest "))
+		expected := md5Hex(md5Hex(username+":fake:"+password) + ":nonce1:" + md5Hex(method+":"+uri))
+		if params["response"] != expected {
+			reply("401 Unauthorized", "WWW-Authenticate: Basic realm=\"fake\"\r\nWWW-Authenticate: Digest realm=\"fake\", nonce=\"nonce1\"\r\n", "")
+			continue
+		}
+		switch method {
+		case "DESCRIBE":
+			reply("200 OK", "Content-Type: application/sdp\r\nContent-Base: "+uri+"/\r\n", fakeRTSPSDP)
+		case "SETUP":
+			if !strings.HasSuffix(uri, "/trackID=1") {
+				reply("454 Session Not Found", "", "")
+				continue
+			}
+			reply("200 OK", "Session: 12345;timeout=60\r\nTransport: "+header.Get("Transport")+"\r\n", "")
+		default:
+			reply("200 OK", "", "")
+		}
+	}
+}
+
+func TestProbeRTSP(t *testing.T) {
+	addr := fakeRTSPServer(t, "admin", "secret")
+
+	result := ProbeRTSP("rtsp://admin:secret@"+addr+"/live", "", "", time.Second)
+	if !result.OK() || result.Auth != models.ProbeAuthDigest || result.Server != "FakeCam" || !result.Setup || len(result.Methods) != 5 {
+		t.Fatalf("unexpected probe result %+v", result)
+	}
+	if strings.Contains(result.Endpoint, "secret") {
+		t.Fatalf("expected redacted endpoint, got %v", result.Endpoint)
+	}
+	if len(result.Tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %v", len(result.Tracks))
+	}
+	video, audio := result.Tracks[0], result.Tracks[1]
+	if video.Media != "video" || video.Codec != "H264" || video.ClockRate != 90000 || video.Fmtp != "packetization-mode=1" || video.Control != "rtsp://"+addr+"/live/trackID=1" {
+		t.Fatalf("unexpected video track %+v", video)
+	}
+	if audio.Media != "audio" || audio.Codec != "PCMA" {
+		t.Fatalf("unexpected audio track %+v", audio)
+	}
+
+	if result := ProbeRTSP("rtsp://"+addr+"/live", "admin", "wrong", time.Second); result.Status != models.ProbeStatusUnauthorized || !result.Reachable {
+		t.Fatalf("expected unauthorized, got %+v", result)
+	}
+	if result := ProbeRTSP("rtsp://"+addr+"/live", "", "", time.Second); result.Status != models.ProbeStatusUnauthorized {
+		t.Fatalf("expected unauthorized without credentials, got %+v", result)
+	}
+
+	// nothing listening
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed := l.Addr().String()
+	l.Close()
+	if result := ProbeRTSP("rtsp://"+closed+"/live", "", "", time.Second); result.Status != models.ProbeStatusUnreachable || result.Reachable {
+		t.Fatalf("expected unreachable, got %+v", result)
+	}
+	if result := ProbeRTSP("http://"+addr+"/live", "", "", time.Second); result.Status != models.ProbeStatusFailed {
+		t.Fatalf("expected invalid endpoint, got %+v", result)
+	}
+}
+
+// okRTSPProbe accepts every stream (cameras in tests have no real endpoints)
+func okRTSPProbe(endpoint string, username string, password string, timeout time.Duration) *models.RTSPProbeResult {
+	return &models.RTSPProbeResult{Endpoint: endpoint, Status: models.ProbeStatusOK, Reachable: true, Setup: true}
+}
+
+func TestProcessStartProbe(t *testing.T) {
+	defer setupMemoryRuntimeConf()()
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	addr := fakeRTSPServer(t, "admin", "secret")
+	cameraImage := models.CameraTypeToImageTag["rtsp"]
+	rt := NewMemoryRuntime()
+	rt.AddImage(cameraImage + ":0.0.7")
+	pm := NewProcessManager(storage, nil, rt, NewProcessStateCache(rt), testSecretManager(t))
+	imageUpgrade := &models.ImageUpgrade{HasImage: true, Name: cameraImage, CurrentVersion: "0.0.7"}
+
+	err = pm.Start(&models.StreamProcess{Name: "wrongpass", RTSPEndpoint: "rtsp://admin:wrong@" + addr + "/live"}, imageUpgrade, false)
+	probeErr, ok := err.(*models.RTSPProbeError)
+	if !ok || probeErr.Result.Status != models.ProbeStatusUnauthorized {
+		t.Fatalf("expected unauthorized probe error, got %v", err)
+	}
+	if _, err := rt.ContainerGet("wrongpass"); err == nil {
+		t.Fatal("camera container must not be created when the probe fails")
+	}
+	if _, err := pm.storedProcess("wrongpass"); err == nil {
+		t.Fatal("camera must not be stored when the probe fails")
+	}
+
+	if err := pm.Start(&models.StreamProcess{Name: "offline", RTSPEndpoint: "rtsp://admin:wrong@" + addr + "/live"}, imageUpgrade, true); err != nil {
+		t.Fatalf("expected probe to be skipped, got %v", err)
+	}
+	if err := pm.Start(&models.StreamProcess{Name: "frontdoor", RTSPEndpoint: "rtsp://" + addr + "/live", RTSPUsername: "admin", RTSPPassword: "secret"}, imageUpgrade, false); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,729 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/go-redis/redis/v7"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	ruleListenerMinBackoff = time.Second
+	ruleListenerMaxBackoff = time.Minute
+	ruleActionWorkers      = 4    // rule actions performed concurrently
+	ruleActionQueueSize    = 1000 // firings waiting for a worker (further firings are dropped)
+)
+
+// docker container actions mapped to process states for process_state rules
+var ruleProcessActionToState = map[string]string{
+	"start":   models.ProcessStatusRunning,
+	"unpause": models.ProcessStatusRunning,
+	"restart": models.ProcessStatusRestarting,
+	"pause":   models.ProcessStatusPaused,
+	"die":     models.ProcessStatusExited,
+	"oom":     models.ProcessStatusDead,
+	"destroy": models.ProcessStatusRemoving,
+}
+
+// RuleManager - evaluates declarative event rules on annotations and process state changes
+type RuleManager struct {
+	storage          *Storage
+	rdb              *redis.Client
+	countingManager  *CountingManager
+	containerRuntime ContainerRuntime
+	restClient       *resty.Client
+	mux              sync.Mutex
+	rules            map[string]*models.Rule      // rules cache
+	stats            map[string]*models.RuleStats // hit counters per rule
+	dirty            map[string]bool              // hit counters not yet persisted
+	lastFired        map[string]time.Time         // last firing per rule and device (cooldowns)
+	firings          chan ruleFiring              // rule actions waiting for a worker
+	ticker           *time.Ticker
+	stop             chan struct{}
+	stopOnce         sync.Once
+}
+
+// ruleFiring rule actions to be performed for the event
+type ruleFiring struct {
+	rule  *models.Rule
+	event *models.RuleEvent
+}
+
+// RuleTestResult is the result of evaluating a single rule against a sample
+type RuleTestResult struct {
+	Name       string `json:"name"`
+	Matched    bool   `json:"matched"`     // condition matched
+	InCooldown bool   `json:"in_cooldown"` // rule would be suppressed by cooldown
+}
+
+func NewRuleManager(storage *Storage, rdb *redis.Client, countingManager *CountingManager, containerRuntime ContainerRuntime) *RuleManager {
+	rm := &RuleManager{
+		storage:          storage,
+		rdb:              rdb,
+		countingManager:  countingManager,
+		containerRuntime: containerRuntime,
+		restClient:       resty.New().SetTimeout(time.Second * 10),
+		rules:            make(map[string]*models.Rule),
+		stats:            make(map[string]*models.RuleStats),
+		dirty:            make(map[string]bool),
+		lastFired:        make(map[string]time.Time),
+		firings:          make(chan ruleFiring, ruleActionQueueSize),
+		ticker:           time.NewTicker(time.Second * 10),
+		stop:             make(chan struct{}),
+	}
+	err := rm.loadRules()
+	if err != nil {
+		g.Log.Error("failed to load event rules", err)
+	}
+	err = rm.schedulePendingClips()
+	if err != nil {
+		g.Log.Error("failed to schedule pending event rule clips", err)
+	}
+
+	// persist hit counters every 10 seconds
+	go func() {
+		for {
+			select {
+			case <-rm.ticker.C:
+				rm.persistStats()
+			case <-rm.stop:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < ruleActionWorkers; i++ {
+		go rm.actionWorker(rm.firings)
+	}
+
+	return rm
+}
+
+// Stop stops performing rule actions and stores the hit counters (on shutdown)
+func (rm *RuleManager) Stop() {
+	rm.stopOnce.Do(func() {
+		rm.ticker.Stop()
+		close(rm.stop)
+		rm.persistStats()
+	})
+}
+
+// Put creates or overwrites the rule (hit counters are kept)
+func (rm *RuleManager) Put(rule *models.Rule) (*models.Rule, error) {
+	err := models.ValidateRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix() * 1000
+	rule.Created = now
+	if existing, err := rm.Get(rule.Name); err == nil {
+		rule.Created = existing.Created
+	}
+	rule.Modified = now
+	rule.Stats = nil
+
+	b, err := json.Marshal(rule)
+	if err != nil {
+		g.Log.Error("failed to marshal rule", err)
+		return nil, err
+	}
+	err = rm.storage.Put(models.PrefixRule, rule.Name, b)
+	if err != nil {
+		g.Log.Error("failed to store rule", rule.Name, err)
+		return nil, err
+	}
+
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	rm.rules[rule.Name] = rule
+	if _, ok := rm.stats[rule.Name]; !ok {
+		rm.stats[rule.Name] = &models.RuleStats{Name: rule.Name}
+	}
+	return rm.withStats(rule), nil
+}
+
+// Get returns the rule with its hit counters
+func (rm *RuleManager) Get(name string) (*models.Rule, error) {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	rule, ok := rm.rules[name]
+	if !ok {
+		return nil, models.ErrProcessNotFound
+	}
+	return rm.withStats(rule), nil
+}
+
+// List returns all rules with their hit counters
+func (rm *RuleManager) List() []*models.Rule {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	rules := make([]*models.Rule, 0, len(rm.rules))
+	for _, rule := range rm.rules {
+		rules = append(rules, rm.withStats(rule))
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules
+}
+
+// Delete removes the rule and its hit counters
+func (rm *RuleManager) Delete(name string) error {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	if _, ok := rm.rules[name]; !ok {
+		return models.ErrProcessNotFound
+	}
+	err := rm.storage.Del(models.PrefixRule, name)
+	if err != nil {
+		g.Log.Error("failed to delete rule", name, err)
+		return err
+	}
+	delErr := rm.storage.Del(models.PrefixRuleStats, name)
+	if delErr != nil && delErr != badger.ErrKeyNotFound {
+		g.Log.Warn("failed to delete rule stats", name, delErr)
+	}
+	delete(rm.rules, name)
+	delete(rm.stats, name)
+	delete(rm.dirty, name)
+	for key := range rm.lastFired {
+		if strings.HasPrefix(key, name+"/") {
+			delete(rm.lastFired, key)
+		}
+	}
+	return nil
+}
+
+// EvaluateAnnotation fires annotation rules matching the annotation
+func (rm *RuleManager) EvaluateAnnotation(req *pb.AnnotateRequest) {
+	rm.evaluate(models.RuleTriggerAnnotation, req.DeviceName, "", req, req.StartTimestamp)
+}
+
+// EvaluateProcessState fires process state rules matching the device state change
+func (rm *RuleManager) EvaluateProcessState(deviceName, state string) {
+	rm.evaluate(models.RuleTriggerProcessState, deviceName, state, nil, time.Now().Unix()*1000)
+}
+
+// Test evaluates rules against the sample annotation or process state without performing any actions.
+// If rule is given it's evaluated instead of the stored rules.
+func (rm *RuleManager) Test(rule *models.Rule, req *pb.AnnotateRequest, deviceName, state string) ([]*RuleTestResult, error) {
+	rules := make([]*models.Rule, 0)
+	if rule != nil {
+		err := models.ValidateRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	} else {
+		rules = rm.List()
+	}
+
+	trigger := models.RuleTriggerProcessState
+	timestamp := time.Now().Unix() * 1000
+	if req != nil {
+		trigger = models.RuleTriggerAnnotation
+		deviceName = req.DeviceName
+		if req.StartTimestamp > 0 {
+			timestamp = req.StartTimestamp
+		}
+	}
+	if deviceName == "" {
+		return nil, models.ErrMissingInputParameters
+	}
+
+	results := make([]*RuleTestResult, 0)
+	for _, r := range rules {
+		if r.Trigger != trigger {
+			continue
+		}
+		result := &RuleTestResult{
+			Name:    r.Name,
+			Matched: rm.matches(r, deviceName, state, req, timestamp),
+		}
+		if result.Matched {
+			rm.mux.Lock()
+			last, ok := rm.lastFired[r.Name+"/"+deviceName]
+			rm.mux.Unlock()
+			result.InCooldown = ok && time.Since(last) < time.Duration(r.CooldownSeconds)*time.Second
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// StartProcessListener listens to docker container events and evaluates process state rules
+// (resubscribes with backoff when the event stream fails)
+func (rm *RuleManager) StartProcessListener() {
+	go func() {
+		backoff := ruleListenerMinBackoff
+		for {
+			if rm.listenProcessEvents() {
+				backoff = ruleListenerMinBackoff
+			}
+			g.Log.Warn("resubscribing to docker events for event rules in", backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > ruleListenerMaxBackoff {
+				backoff = ruleListenerMaxBackoff
+			}
+		}
+	}()
+}
+
+// listenProcessEvents evaluates process state rules until the event stream fails. Returns true if any event was received.
+func (rm *RuleManager) listenProcessEvents() bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	messages, errs := rm.containerRuntime.Events(ctx)
+
+	received := false
+	for {
+		select {
+		case err := <-errs:
+			if err != nil && err != io.EOF {
+				g.Log.Error("docker event listener for event rules failed", err)
+			}
+			return received
+		case e := <-messages:
+			received = true
+			state, ok := ruleProcessActionToState[e.Action]
+			if !ok {
+				continue
+			}
+			if name, ok := e.Actor.Attributes["name"]; ok {
+				rm.EvaluateProcessState(name, state)
+			}
+		}
+	}
+}
+
+func (rm *RuleManager) evaluate(trigger, deviceName, state string, req *pb.AnnotateRequest, timestamp int64) {
+	rm.mux.Lock()
+	candidates := make([]*models.Rule, 0)
+	for _, rule := range rm.rules {
+		if rule.Enabled && rule.Trigger == trigger {
+			candidates = append(candidates, rule)
+		}
+	}
+	rm.mux.Unlock()
+
+	for _, rule := range candidates {
+		if !rm.matches(rule, deviceName, state, req, timestamp) {
+			continue
+		}
+		if !rm.hit(rule, deviceName) {
+			continue
+		}
+		event := &models.RuleEvent{
+			Rule:       rule.Name,
+			Trigger:    trigger,
+			DeviceName: deviceName,
+			Timestamp:  timestamp,
+			State:      state,
+		}
+		if req != nil {
+			b, err := json.Marshal(req)
+			if err != nil {
+				g.Log.Error("failed to marshal annotation for rule event", rule.Name, err)
+			} else {
+				event.Annotation = b
+			}
+		}
+		rm.enqueue(rule, event)
+	}
+}
+
+// enqueue passes the firing to the action workers, firing is dropped (and counted) when the queue is full
+func (rm *RuleManager) enqueue(rule *models.Rule, event *models.RuleEvent) {
+	select {
+	case rm.firings <- ruleFiring{rule: rule, event: event}:
+		return
+	default:
+	}
+	g.Log.Warn("event rule action queue full, dropping firing", rule.Name, event.DeviceName)
+
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	if stats, ok := rm.stats[rule.Name]; ok {
+		stats.Fired--
+		stats.Dropped++
+		rm.dirty[rule.Name] = true
+	}
+}
+
+// actionWorker performs actions of queued firings until stopped
+func (rm *RuleManager) actionWorker(firings <-chan ruleFiring) {
+	for {
+		select {
+		case f := <-firings:
+			rm.fire(f.rule, f.event)
+		case <-rm.stop:
+			return
+		}
+	}
+}
+
+// hit updates counters and checks the cooldown. Returns true if rule should fire.
+func (rm *RuleManager) hit(rule *models.Rule, deviceName string) bool {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+
+	stats, ok := rm.stats[rule.Name]
+	if !ok {
+		stats = &models.RuleStats{Name: rule.Name}
+		rm.stats[rule.Name] = stats
+	}
+	rm.dirty[rule.Name] = true
+	stats.Hits++
+
+	now := time.Now()
+	cooldownKey := rule.Name + "/" + deviceName
+	if last, ok := rm.lastFired[cooldownKey]; ok && now.Sub(last) < time.Duration(rule.CooldownSeconds)*time.Second {
+		stats.Suppressed++
+		return false
+	}
+	rm.lastFired[cooldownKey] = now
+	stats.Fired++
+	stats.LastFired = now.Unix() * 1000
+	return true
+}
+
+func (rm *RuleManager) matches(rule *models.Rule, deviceName, state string, req *pb.AnnotateRequest, timestamp int64) bool {
+	c := rule.Condition
+	if c == nil {
+		return true
+	}
+	if c.DeviceName != "" && c.DeviceName != deviceName {
+		return false
+	}
+	if c.State != "" && c.State != state {
+		return false
+	}
+	if c.TimeFrom != "" && !ruleTimeInWindow(timestamp, c.TimeFrom, c.TimeTo) {
+		return false
+	}
+	if req != nil {
+		if c.Type != "" && c.Type != req.Type {
+			return false
+		}
+		if c.ObjectType != "" && c.ObjectType != req.ObjectType {
+			return false
+		}
+		if c.MinConfidence > 0 && req.Confidence <= c.MinConfidence {
+			return false
+		}
+		if c.Zone != "" && (rm.countingManager == nil || !rm.countingManager.InZone(req, c.Zone)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (rm *RuleManager) fire(rule *models.Rule, event *models.RuleEvent) {
+	for _, action := range rule.Actions {
+		var err error
+		switch action.Type {
+		case models.RuleActionWebhook:
+			err = rm.webhook(action, event)
+		case models.RuleActionMQTT:
+			err = rm.publishMQTT(action, event)
+		case models.RuleActionClip:
+			err = rm.saveClip(action, event)
+		}
+		if err != nil {
+			g.Log.Error("event rule action failed", rule.Name, action.Type, err)
+		}
+	}
+}
+
+func (rm *RuleManager) webhook(action *models.RuleAction, event *models.RuleEvent) error {
+	resp, err := rm.restClient.R().SetHeader("Content-Type", "application/json").SetBody(event).Post(action.URL)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("webhook %v responded with status code %d", action.URL, resp.StatusCode())
+	}
+	return nil
+}
+
+// publishMQTT forwards the event to MQTT gateway over local redis pub/sub
+func (rm *RuleManager) publishMQTT(action *models.RuleAction, event *models.RuleEvent) error {
+	if rm.rdb == nil {
+		return errors.New("redis not available")
+	}
+	msg, err := json.Marshal(&models.RuleMQTTMessage{Topic: action.Topic, Event: event})
+	if err != nil {
+		return err
+	}
+	return utils.PublishToRedis(rm.rdb, event.DeviceName, models.MQTTProcessOperation(models.DeviceOperationRuleFired), models.ProcessTypeRule, msg)
+}
+
+// saveClip schedules copying of on-disk mp4 segments overlapping the event once post seconds pass
+// (pending clip is stored and rescheduled on restart)
+func (rm *RuleManager) saveClip(action *models.RuleAction, event *models.RuleEvent) error {
+	if g.Conf.Buffer == nil || !g.Conf.Buffer.OnDisk || g.Conf.Buffer.OnDiskFolder == "" {
+		return errors.New("on disk buffer not enabled, can't save clip")
+	}
+	deviceName, err := rm.clipDeviceName(event.DeviceName)
+	if err != nil {
+		return err
+	}
+	clip := &models.RuleClip{
+		Rule:       event.Rule,
+		DeviceName: deviceName,
+		Timestamp:  event.Timestamp,
+		From:       event.Timestamp - int64(action.PreSeconds)*1000,
+		To:         event.Timestamp + int64(action.PostSeconds)*1000,
+	}
+	b, err := json.Marshal(clip)
+	if err != nil {
+		return err
+	}
+	if err := rm.storage.Put(models.PrefixRuleClip, ruleClipKey(clip), b); err != nil {
+		return err
+	}
+	rm.scheduleClip(clip)
+	return nil
+}
+
+// clipDeviceName returns the name of the stored camera (device name of the event is not used in file paths as given)
+func (rm *RuleManager) clipDeviceName(deviceName string) (string, error) {
+	if deviceName == "" || deviceName == "." || deviceName == ".." || filepath.Base(deviceName) != deviceName {
+		return "", models.ErrInvalidInputParameters
+	}
+	b, err := rm.storage.Get(models.PrefixRTSPProcess, deviceName)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return "", models.ErrProcessNotFound
+		}
+		return "", err
+	}
+	var process models.StreamProcess
+	if err := json.Unmarshal(b, &process); err != nil {
+		return "", err
+	}
+	if process.Name != filepath.Base(process.Name) {
+		return "", models.ErrInvalidInputParameters
+	}
+	return process.Name, nil
+}
+
+// scheduleClip writes the clip once the end of the clip has passed and removes the pending clip
+func (rm *RuleManager) scheduleClip(clip *models.RuleClip) {
+	time.AfterFunc(time.Until(time.Unix(0, clip.To*int64(time.Millisecond))), func() {
+		if err := rm.writeClip(clip); err != nil {
+			g.Log.Error("failed to save event rule clip", clip.Rule, clip.DeviceName, err)
+		}
+		if err := rm.storage.Del(models.PrefixRuleClip, ruleClipKey(clip)); err != nil && err != badger.ErrKeyNotFound {
+			g.Log.Error("failed to remove pending event rule clip", clip.Rule, clip.DeviceName, err)
+		}
+	})
+}
+
+// schedulePendingClips reschedules clips stored before restart
+func (rm *RuleManager) schedulePendingClips() error {
+	objects, err := rm.storage.List(models.PrefixRuleClip)
+	if err != nil {
+		return err
+	}
+	for k, v := range objects {
+		var clip models.RuleClip
+		if err := json.Unmarshal(v, &clip); err != nil {
+			g.Log.Error("failed to unmarshal pending event rule clip", k, err)
+			continue
+		}
+		rm.scheduleClip(&clip)
+	}
+	return nil
+}
+
+// writeClip copies on-disk mp4 segments overlapping the clip into the clips folder
+func (rm *RuleManager) writeClip(clip *models.RuleClip) error {
+	if g.Conf.Buffer == nil || !g.Conf.Buffer.OnDisk || g.Conf.Buffer.OnDiskFolder == "" {
+		return errors.New("on disk buffer not enabled, can't save clip")
+	}
+	segmentsFolder := filepath.Join(g.Conf.Buffer.OnDiskFolder, clip.DeviceName)
+	files, err := ioutil.ReadDir(segmentsFolder)
+	if err != nil {
+		return err
+	}
+	clipFolder := filepath.Join(g.Conf.Buffer.OnDiskFolder, "clips", clip.DeviceName, clip.Rule+"_"+strconv.FormatInt(clip.Timestamp, 10))
+	copied := 0
+	for _, f := range files {
+		start, length, ok := parseSegmentName(f.Name())
+		if !ok || start > clip.To || start+length < clip.From {
+			continue
+		}
+		if copied == 0 {
+			if err := os.MkdirAll(clipFolder, 0755); err != nil {
+				return err
+			}
+		}
+		if err := copyFile(filepath.Join(segmentsFolder, f.Name()), filepath.Join(clipFolder, f.Name())); err != nil {
+			return err
+		}
+		copied++
+	}
+	if copied == 0 {
+		return errors.New("no video segments found for clip of " + clip.DeviceName)
+	}
+	g.Log.Info("event rule clip saved", clipFolder, copied)
+	return nil
+}
+
+func ruleClipKey(clip *models.RuleClip) string {
+	return fmt.Sprintf("%s/%s/%013d", clip.Rule, clip.DeviceName, clip.Timestamp)
+}
+
+func (rm *RuleManager) withStats(rule *models.Rule) *models.Rule {
+	r := *rule
+	stats := models.RuleStats{Name: rule.Name}
+	if s, ok := rm.stats[rule.Name]; ok {
+		stats = *s
+	}
+	r.Stats = &stats
+	return &r
+}
+
+func (rm *RuleManager) persistStats() {
+	rm.mux.Lock()
+	toStore := make([]models.RuleStats, 0, len(rm.dirty))
+	for name := range rm.dirty {
+		if s, ok := rm.stats[name]; ok {
+			toStore = append(toStore, *s)
+		}
+	}
+	rm.dirty = make(map[string]bool)
+	rm.mux.Unlock()
+
+	for _, s := range toStore {
+		b, err := json.Marshal(s)
+		if err != nil {
+			g.Log.Error("failed to marshal rule stats", s.Name, err)
+			continue
+		}
+		err = rm.storage.Put(models.PrefixRuleStats, s.Name, b)
+		if err != nil {
+			g.Log.Error("failed to store rule stats", s.Name, err)
+		}
+	}
+}
+
+func (rm *RuleManager) loadRules() error {
+	objects, err := rm.storage.List(models.PrefixRule)
+	if err != nil {
+		return err
+	}
+	stats, err := rm.storage.List(models.PrefixRuleStats)
+	if err != nil {
+		return err
+	}
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	for _, v := range objects {
+		var rule models.Rule
+		err := json.Unmarshal(v, &rule)
+		if err != nil {
+			g.Log.Error("failed to unmarshal rule", err)
+			continue
+		}
+		rm.rules[rule.Name] = &rule
+	}
+	for _, v := range stats {
+		var s models.RuleStats
+		err := json.Unmarshal(v, &s)
+		if err != nil {
+			g.Log.Error("failed to unmarshal rule stats", err)
+			continue
+		}
+		rm.stats[s.Name] = &s
+	}
+	return nil
+}
+
+// ruleTimeInWindow checks if local time of day of the timestamp (ms) is within [from, to) (HH:MM), wrapping over midnight
+func ruleTimeInWindow(timestamp int64, from, to string) bool {
+	f, err := time.Parse("15:04", from)
+	if err != nil {
+		return false
+	}
+	t, err := time.Parse("15:04", to)
+	if err != nil {
+		return false
+	}
+	tm := time.Unix(0, timestamp*int64(time.Millisecond)).Local()
+	minute := tm.Hour()*60 + tm.Minute()
+	fromMinute := f.Hour()*60 + f.Minute()
+	toMinute := t.Hour()*60 + t.Minute()
+	if fromMinute <= toMinute {
+		return minute >= fromMinute && minute < toMinute
+	}
+	return minute >= fromMinute || minute < toMinute
+}
+
+// parseSegmentName parses on-disk segment file name: <start timestamp ms>_<length ms>.mp4
+func parseSegmentName(name string) (int64, int64, bool) {
+	if !strings.HasSuffix(name, ".mp4") {
+		return 0, 0, false
+	}
+	parts := strings.Split(strings.TrimSuffix(name, ".mp4"), "_")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	length, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, length, true
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cErr := out.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
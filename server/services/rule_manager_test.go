@@ -0,0 +1,251 @@
+package services
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func TestRuleTimeWindow(t *testing.T) {
+	at := func(hour, minute int) int64 {
+		return time.Date(2020, 10, 10, hour, minute, 0, 0, time.Local).Unix() * 1000
+	}
+	if !ruleTimeInWindow(at(23, 0), "22:00", "06:00") || !ruleTimeInWindow(at(5, 59), "22:00", "06:00") {
+		t.Fatal("expected time within window wrapping over midnight")
+	}
+	if ruleTimeInWindow(at(12, 0), "22:00", "06:00") || ruleTimeInWindow(at(6, 0), "22:00", "06:00") {
+		t.Fatal("expected time outside of window")
+	}
+	if !ruleTimeInWindow(at(9, 30), "09:00", "17:00") {
+		t.Fatal("expected time within daytime window")
+	}
+}
+
+func TestRuleEvaluation(t *testing.T) {
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	events := make(chan *models.RuleEvent, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.RuleEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Error(err)
+		}
+		events <- &event
+	}))
+	defer srv.Close()
+
+	cm := NewCountingManager(storage)
+	defer cm.Close()
+	_, err = cm.PutZone(&models.CountingZone{
+		Name:       "loading_dock",
+		DeviceName: "rulecam",
+		Type:       models.CountingZoneTypeArea,
+		Points:     []*models.Point{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rm := NewRuleManager(storage, nil, cm, NewMemoryRuntime())
+	defer rm.Stop()
+	_, err = rm.Put(&models.Rule{
+		Name:    "dock_person",
+		Enabled: true,
+		Trigger: models.RuleTriggerAnnotation,
+		Condition: &models.RuleCondition{
+			DeviceName:    "rulecam",
+			ObjectType:    "person",
+			Zone:          "loading_dock",
+			MinConfidence: 0.7,
+		},
+		Actions:         []*models.RuleAction{{Type: models.RuleActionWebhook, URL: srv.URL}},
+		CooldownSeconds: 60,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rm.Put(&models.Rule{Name: "invalid", Trigger: models.RuleTriggerAnnotation, Actions: []*models.RuleAction{{Type: "email"}}}); err != models.ErrInvalidInputParameters {
+		t.Fatalf("expected invalid rule, got %v", err)
+	}
+
+	ts := time.Now().Unix() * 1000
+	annotation := func(x float64, confidence float64) *pb.AnnotateRequest {
+		return &pb.AnnotateRequest{
+			DeviceName:       "rulecam",
+			Type:             "moving",
+			ObjectType:       "person",
+			Confidence:       confidence,
+			StartTimestamp:   ts,
+			ObjectCoordinate: &pb.Coordinate{X: x, Y: 50},
+		}
+	}
+
+	results, err := rm.Test(nil, annotation(50, 0.9), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Matched || results[0].InCooldown {
+		t.Fatalf("expected sample annotation to match, got %v", results[0])
+	}
+
+	rm.EvaluateAnnotation(annotation(150, 0.9)) // outside of zone
+	rm.EvaluateAnnotation(annotation(50, 0.5))  // low confidence
+	rm.EvaluateAnnotation(annotation(50, 0.9))
+	rm.EvaluateAnnotation(annotation(60, 0.95)) // cooldown
+
+	select {
+	case event := <-events:
+		if event.Rule != "dock_person" || event.DeviceName != "rulecam" || len(event.Annotation) == 0 {
+			t.Fatalf("unexpected rule event %v", event)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("webhook not called")
+	}
+
+	rule, err := rm.Get("dock_person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Stats.Hits != 2 || rule.Stats.Fired != 1 || rule.Stats.Suppressed != 1 {
+		t.Fatalf("unexpected rule stats %v", rule.Stats)
+	}
+
+	results, err = rm.Test(nil, annotation(50, 0.9), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].InCooldown {
+		t.Fatal("expected rule to be in cooldown")
+	}
+
+	err = rm.Delete("dock_person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rm.List()) != 0 {
+		t.Fatal("expected no rules after delete")
+	}
+}
+
+func TestRuleClip(t *testing.T) {
+	defer setupMemoryRuntimeConf()()
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	folder, err := ioutil.TempDir("", "ruleclips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+	g.Conf.Buffer.OnDisk = true
+	g.Conf.Buffer.OnDiskFolder = folder
+
+	b, _ := json.Marshal(&models.StreamProcess{Name: "clipcam"})
+	if err := storage.Put(models.PrefixRTSPProcess, "clipcam", b); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Now().Add(-time.Minute).Unix() * 1000
+	if err := os.MkdirAll(filepath.Join(folder, "clipcam"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	segment := filepath.Join(folder, "clipcam", strconv.FormatInt(ts-1000, 10)+"_2000.mp4")
+	if err := ioutil.WriteFile(segment, []byte("mp4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rm := NewRuleManager(storage, nil, nil, NewMemoryRuntime())
+	defer rm.Stop()
+	action := &models.RuleAction{Type: models.RuleActionClip, PreSeconds: 5, PostSeconds: 5}
+	if err := rm.saveClip(action, &models.RuleEvent{Rule: "door", DeviceName: "../clipcam", Timestamp: ts}); err != models.ErrInvalidInputParameters {
+		t.Fatalf("expected invalid device name, got %v", err)
+	}
+	if err := rm.saveClip(action, &models.RuleEvent{Rule: "door", DeviceName: "unknown", Timestamp: ts}); err != models.ErrProcessNotFound {
+		t.Fatalf("expected unknown device, got %v", err)
+	}
+
+	// pending clip stored before restart is saved by a new rule manager
+	pending, _ := json.Marshal(&models.RuleClip{Rule: "door", DeviceName: "clipcam", Timestamp: ts, From: ts - 5000, To: ts + 5000})
+	if err := storage.Put(models.PrefixRuleClip, "door/clipcam/"+strconv.FormatInt(ts, 10), pending); err != nil {
+		t.Fatal(err)
+	}
+	defer NewRuleManager(storage, nil, nil, NewMemoryRuntime()).Stop()
+
+	clipFolder := filepath.Join(folder, "clips", "clipcam", "door_"+strconv.FormatInt(ts, 10))
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		clips, _ := storage.List(models.PrefixRuleClip)
+		if files, _ := ioutil.ReadDir(clipFolder); len(files) == 1 && len(clips) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pending clip not saved")
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+}
+
+func TestRuleActionOverflow(t *testing.T) {
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	rm := NewRuleManager(storage, nil, nil, NewMemoryRuntime())
+	// no worker takes firings from the queue
+	rm.firings = make(chan ruleFiring, 2)
+	_, err = rm.Put(&models.Rule{
+		Name:    "busy",
+		Enabled: true,
+		Trigger: models.RuleTriggerAnnotation,
+		Actions: []*models.RuleAction{{Type: models.RuleActionWebhook, URL: "http://127.0.0.1:1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		rm.EvaluateAnnotation(&pb.AnnotateRequest{DeviceName: "busycam", Type: "moving", StartTimestamp: time.Now().Unix() * 1000})
+	}
+	rule, err := rm.Get("busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Stats.Hits != 5 || rule.Stats.Fired != 2 || rule.Stats.Dropped != 3 || len(rm.firings) != 2 {
+		t.Fatalf("expected 2 queued and 3 dropped firings, got %v", rule.Stats)
+	}
+
+	// hit counters are stored on stop
+	rm.Stop()
+	b, err := storage.Get(models.PrefixRuleStats, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats models.RuleStats
+	if err := json.Unmarshal(b, &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Hits != 5 || stats.Dropped != 3 {
+		t.Fatalf("expected hit counters stored on stop, got %v", stats)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	masterKeySize = 32
+	// key derivation context, changing it makes stored secrets unreadable
+	secretKeyInfo = "chrysalis edge proxy secrets v1"
+)
+
+var errInvalidEncryptedValue = errors.New("invalid encrypted value")
+
+// SecretManager - encrypts secrets stored in the datastore with a key derived from the local master key
+type SecretManager struct {
+	aead cipher.AEAD
+}
+
+// LoadMasterKey reads the master key file or creates a new random one (readable only by the owner) when missing
+func LoadMasterKey(path string) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err == nil {
+		if len(key) < masterKeySize {
+			g.Log.Error("master key file too short", path)
+			return nil, errInvalidEncryptedValue
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		g.Log.Error("failed to read master key file", path, err)
+		return nil, err
+	}
+	key = make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := writeFileDurably(path, key); err != nil {
+		g.Log.Error("failed to create master key file", path, err)
+		return nil, err
+	}
+	g.Log.Info("new master key created", path)
+	return key, nil
+}
+
+// writeFileDurably writes a temp file (readable only by the owner) in the same folder, syncs it and renames it to path,
+// so that after a power cut the file is either missing or complete
+func writeFileDurably(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath) // no-op after rename
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	// persist the rename
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func NewSecretManager(masterKey []byte) (*SecretManager, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, masterKey, nil, []byte(secretKeyInfo)), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretManager{aead: aead}, nil
+}
+
+// Encrypt returns the value to be stored (empty stays empty)
+func (sm *SecretManager) Encrypt(plaintext []byte) (string, error) {
+	if len(plaintext) == 0 {
+		return "", nil
+	}
+	nonce := make([]byte, sm.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := sm.aead.Seal(nonce, nonce, plaintext, nil)
+	return models.EncryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of the stored value. Values stored before encryption are returned as they are.
+func (sm *SecretManager) Decrypt(value string) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return []byte(value), nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, models.EncryptedValuePrefix))
+	if err != nil || len(sealed) < sm.aead.NonceSize() {
+		return nil, errInvalidEncryptedValue
+	}
+	nonce, ciphertext := sealed[:sm.aead.NonceSize()], sealed[sm.aead.NonceSize():]
+	plaintext, err := sm.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		g.Log.Error("failed to decrypt stored secret (was the master key changed?)", err)
+		return nil, errInvalidEncryptedValue
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted checks if the stored value was encrypted by the SecretManager
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, models.EncryptedValuePrefix)
+}
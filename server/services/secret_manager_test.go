@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testSecretManager(t *testing.T) *SecretManager {
+	sm, err := NewSecretManager(bytes.Repeat([]byte{7}, masterKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+func TestSecretManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyPath := filepath.Join(dir, "master.key")
+
+	key, err := LoadMasterKey(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(keyPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected master key file readable only by the owner, got %v %v", info, err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Fatalf("expected only the master key file in %v, got %d files", dir, len(files))
+	}
+	reloaded, err := LoadMasterKey(keyPath)
+	if err != nil || !bytes.Equal(key, reloaded) {
+		t.Fatal("expected the existing master key reused")
+	}
+
+	sm, err := NewSecretManager(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := sm.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(encrypted) || bytes.Contains([]byte(encrypted), []byte("secret")) {
+		t.Fatalf("expected encrypted value, got %v", encrypted)
+	}
+	if plaintext, err := sm.Decrypt(encrypted); err != nil || string(plaintext) != "secret" {
+		t.Fatalf("unexpected decrypted value %s %v", plaintext, err)
+	}
+	// values stored before encryption are readable
+	if plaintext, _ := sm.Decrypt("plain"); string(plaintext) != "plain" {
+		t.Fatal("expected plaintext value passed through")
+	}
+	// other master key can't read the secret
+	if _, err := testSecretManager(t).Decrypt(encrypted); err == nil {
+		t.Fatal("expected decryption with another master key to fail")
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/chryscloud/go-microkit-plugins/docker"
 	dockerhub "github.com/chryscloud/go-microkit-plugins/dockerhub"
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	"github.com/chryscloud/video-edge-ai-proxy/models"
@@ -40,13 +39,17 @@ type SettingsManager struct {
 	current_edge_secret string
 	mux                 *sync.RWMutex
 	apiClient           *resty.Client
+	containerRuntime    ContainerRuntime
+	secrets             *SecretManager
 }
 
-func NewSettingsManager(storage *Storage) *SettingsManager {
+func NewSettingsManager(storage *Storage, containerRuntime ContainerRuntime, secrets *SecretManager) *SettingsManager {
 	return &SettingsManager{
-		storage:   storage,
-		mux:       &sync.RWMutex{},
-		apiClient: resty.New(),
+		storage:          storage,
+		mux:              &sync.RWMutex{},
+		apiClient:        resty.New(),
+		containerRuntime: containerRuntime,
+		secrets:          secrets,
 	}
 }
 
@@ -116,6 +119,9 @@ func (sm *SettingsManager) getDefault() (*models.Settings, error) {
 			g.Log.Error("failed to unmarshal settings", unmErr)
 			return nil, unmErr
 		}
+		if err := sm.decryptSecrets(&settings); err != nil {
+			return nil, err
+		}
 	}
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
@@ -142,6 +148,10 @@ func (sm *SettingsManager) Overwrite(settings *models.Settings) (*models.Setting
 	if existingSettings != nil {
 		sysInfo.GatewayID = existingSettings.GatewayID
 		sysInfo.RegistryID = existingSettings.RegistryID
+		// redacted secret (as returned by the API) keeps the stored one
+		if settings.EdgeSecret == models.RedactedSecret {
+			settings.EdgeSecret = existingSettings.EdgeSecret
+		}
 	}
 
 	updSettings, err := sm.updateSettingsWithMQTTCredentials(sysInfo, settings)
@@ -207,28 +217,97 @@ func (sm *SettingsManager) updateSettingsWithMQTTCredentials(sysInfo *models.Sys
 	}
 	settings.Modified = time.Now().Unix() * 1000
 
-	settingsBytes, err := json.Marshal(settings)
-	if err != nil {
-		g.Log.Error("failed to marshal settings", err)
-		return nil, err
-	}
 	sm.mux.Lock()
 	defer sm.mux.Unlock()
 	sm.current_edge_key = settings.EdgeKey
 	sm.current_edge_secret = settings.EdgeSecret
-	newSettingsErr := sm.storage.Put(models.PrefixSettingsKey, settings.Name, settingsBytes)
+	newSettingsErr := sm.store(settings)
 
 	return settings, newSettingsErr
 }
 
+// store stores the settings with the edge secret and private key encrypted
+func (sm *SettingsManager) store(settings *models.Settings) error {
+	stored := *settings
+	edgeSecret, err := sm.secrets.Encrypt([]byte(settings.EdgeSecret))
+	if err != nil {
+		g.Log.Error("failed to encrypt edge secret", err)
+		return err
+	}
+	stored.EdgeSecret = edgeSecret
+	privateKey, err := sm.secrets.Encrypt(settings.PrivateRSAKey)
+	if err != nil {
+		g.Log.Error("failed to encrypt private key", err)
+		return err
+	}
+	stored.PrivateRSAKey = []byte(privateKey)
+	if privateKey == "" {
+		stored.PrivateRSAKey = nil
+	}
+
+	settingsBytes, err := json.Marshal(&stored)
+	if err != nil {
+		g.Log.Error("failed to marshal settings", err)
+		return err
+	}
+	return sm.storage.Put(models.PrefixSettingsKey, settings.Name, settingsBytes)
+}
+
+// decryptSecrets decrypts the stored edge secret and private key (settings stored before encryption are plaintext)
+func (sm *SettingsManager) decryptSecrets(settings *models.Settings) error {
+	edgeSecret, err := sm.secrets.Decrypt(settings.EdgeSecret)
+	if err != nil {
+		g.Log.Error("failed to decrypt edge secret", err)
+		return err
+	}
+	settings.EdgeSecret = string(edgeSecret)
+	if len(settings.PrivateRSAKey) > 0 {
+		privateKey, err := sm.secrets.Decrypt(string(settings.PrivateRSAKey))
+		if err != nil {
+			g.Log.Error("failed to decrypt private key", err)
+			return err
+		}
+		settings.PrivateRSAKey = privateKey
+	}
+	return nil
+}
+
+// EncryptStoredSecrets encrypts the settings stored before encryption
+func (sm *SettingsManager) EncryptStoredSecrets() error {
+	settingsBytes, err := sm.storage.Get(models.PrefixSettingsKey, models.SettingDefaultKey)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		g.Log.Error("failed to retrieve settings", err)
+		return err
+	}
+	var stored models.Settings
+	if err := json.Unmarshal(settingsBytes, &stored); err != nil {
+		g.Log.Error("failed to unmarshal settings", err)
+		return err
+	}
+	if (stored.EdgeSecret == "" || IsEncrypted(stored.EdgeSecret)) && (len(stored.PrivateRSAKey) == 0 || IsEncrypted(string(stored.PrivateRSAKey))) {
+		return nil
+	}
+	settings, err := sm.getDefault()
+	if err != nil {
+		return err
+	}
+	if err := sm.store(settings); err != nil {
+		return err
+	}
+	g.Log.Info("stored settings secrets encrypted")
+	return nil
+}
+
 // Get settings from datastore
 func (sm *SettingsManager) Get() (*models.Settings, error) {
 	return sm.getDefault()
 }
 
 func (sm *SettingsManager) ListLocalDockerImages() ([]types.ImageSummary, error) {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
-	images, err := cl.ImagesList()
+	images, err := sm.containerRuntime.ImagesList()
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +316,6 @@ func (sm *SettingsManager) ListLocalDockerImages() ([]types.ImageSummary, error)
 
 // ListDockerImages - listing local docker images based on tag name and checking if there is a newer version available
 func (sm *SettingsManager) ListDockerImages(nameTag string) (*models.ImageUpgrade, error) {
-	// cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
 	images, err := sm.ListLocalDockerImages()
 	if err != nil {
 		return nil, err
@@ -320,8 +398,6 @@ func (sm *SettingsManager) ListDockerImages(nameTag string) (*models.ImageUpgrad
 
 // PullDockerImage - pull docker image from dockerhub
 func (sm *SettingsManager) PullDockerImage(name, version string) (*models.PullDockerResponse, error) {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
-
 	arch := runtime.GOARCH
 	versionSuffix := ""
 	if suffix, ok := ArchitectureSuffixMap[arch]; ok {
@@ -329,7 +405,7 @@ func (sm *SettingsManager) PullDockerImage(name, version string) (*models.PullDo
 	}
 	version = version + versionSuffix
 
-	resp, err := cl.ImagePullDockerHub(name, version, "", "")
+	resp, err := sm.containerRuntime.ImagePull(name, version)
 	if err != nil {
 		g.Log.Error("failed to pull image from dockerhub", name, version, err)
 		return nil, err
@@ -399,9 +475,7 @@ func (sm *SettingsManager) findHighestVersion(versionsRaw []string) *version.Ver
 
 // getting dockers host system info
 func (sm *SettingsManager) GetSystemInfo() (*models.SystemInfo, error) {
-	cl := docker.NewSocketClient(docker.Log(g.Log), docker.Host("unix:///var/run/docker.sock"))
-
-	sys, _, err := cl.SystemWideInfo()
+	sys, _, err := sm.containerRuntime.SystemWideInfo()
 	if err != nil {
 		g.Log.Error("Failed to get host system info", err)
 		return nil, err
@@ -78,7 +78,7 @@ func teardown() error {
 
 func TestVersionComparison(t *testing.T) {
 
-	sm := NewSettingsManager(storage)
+	sm := NewSettingsManager(storage, NewMemoryRuntime(), testSecretManager(t))
 
 	rtspImageTag := models.CameraTypeToImageTag["rtsp"]
 
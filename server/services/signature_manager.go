@@ -0,0 +1,451 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	SignatureMetricCosine = "cosine"
+	SignatureMetricL2     = "l2"
+
+	defaultSignatureMaxEntries   = 10000
+	defaultSignatureRetention    = time.Hour * 24
+	defaultSignatureK            = 10
+	defaultSignatureMaxDimension = 2048 // hyperplanes of one dimension take signatureTables*signatureBits*dim*4 bytes
+	defaultSignatureMaxIndexes   = 256
+
+	// random hyperplane LSH parameters
+	signatureTables = 8
+	signatureBits   = 12
+)
+
+// SignatureMatch annotation found by signature search
+type SignatureMatch struct {
+	Distance   float64
+	Annotation *pb.AnnotateRequest
+}
+
+// SignatureQuery filters for signature search (empty values are ignored)
+type SignatureQuery struct {
+	Vector        []float64
+	K             int
+	DeviceName    string
+	ObjectType    string
+	TimestampFrom int64
+	TimestampTo   int64
+	Metric        string
+}
+
+// SignatureManager - in-memory approximate nearest neighbour index of annotation object signatures
+// (one index per device and object type, bounded total number of entries, indexes, vector dimension and retention)
+type SignatureManager struct {
+	mux          sync.RWMutex
+	indexes      map[string]*signatureIndex
+	planes       map[int][][]float32 // random hyperplanes per vector dimension shared by indexes
+	order        []signatureRef      // insertion order of entries across all indexes used for eviction
+	maxEntries   int
+	maxDimension int
+	maxIndexes   int
+	retention    time.Duration
+	ticker       *time.Ticker
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+// signatureRef entry within one of the indexes
+type signatureRef struct {
+	key   string
+	id    uint64
+	added time.Time
+}
+
+type signatureEntry struct {
+	id         uint64
+	vector     []float32
+	norm       float64
+	hashes     []uint32
+	added      time.Time
+	annotation *pb.AnnotateRequest // annotation without the signature
+}
+
+type signatureIndex struct {
+	deviceName string
+	objectType string
+	dim        int
+	planes     [][]float32           // signatureTables * signatureBits random hyperplanes (shared per dimension)
+	tables     []map[uint32][]uint64 // bucket hash -> entry ids, one map per table
+	entries    map[uint64]*signatureEntry
+	nextID     uint64
+}
+
+func NewSignatureManager() *SignatureManager {
+	sm := &SignatureManager{
+		indexes:      make(map[string]*signatureIndex),
+		planes:       make(map[int][][]float32),
+		order:        make([]signatureRef, 0),
+		maxEntries:   defaultSignatureMaxEntries,
+		maxDimension: defaultSignatureMaxDimension,
+		maxIndexes:   defaultSignatureMaxIndexes,
+		retention:    defaultSignatureRetention,
+		ticker:       time.NewTicker(time.Minute),
+		stop:         make(chan struct{}),
+	}
+	if g.Conf.Signature != nil {
+		if g.Conf.Signature.MaxEntries > 0 {
+			sm.maxEntries = g.Conf.Signature.MaxEntries
+		}
+		if g.Conf.Signature.MaxDimension > 0 {
+			sm.maxDimension = g.Conf.Signature.MaxDimension
+		}
+		if g.Conf.Signature.MaxIndexes > 0 {
+			sm.maxIndexes = g.Conf.Signature.MaxIndexes
+		}
+		if g.Conf.Signature.RetentionMinutes > 0 {
+			sm.retention = time.Duration(g.Conf.Signature.RetentionMinutes) * time.Minute
+		}
+	}
+
+	// evict expired signatures every minute
+	go func() {
+		for {
+			select {
+			case t := <-sm.ticker.C:
+				sm.evictExpired(t)
+			case <-sm.stop:
+				return
+			}
+		}
+	}()
+
+	return sm
+}
+
+// Stop stops evicting expired signatures
+func (sm *SignatureManager) Stop() {
+	sm.stopOnce.Do(func() {
+		sm.ticker.Stop()
+		close(sm.stop)
+	})
+}
+
+// Add indexes the object signature of the annotation (annotations without signature are ignored)
+func (sm *SignatureManager) Add(req *pb.AnnotateRequest) {
+	if len(req.ObjectSignature) == 0 {
+		return
+	}
+	if len(req.ObjectSignature) > sm.maxDimension {
+		g.Log.Warn("object signature dimension above maximum, skipping", req.DeviceName, req.ObjectType, len(req.ObjectSignature), sm.maxDimension)
+		return
+	}
+	annotation := proto.Clone(req).(*pb.AnnotateRequest)
+	annotation.ObjectSignature = nil
+
+	vector := make([]float32, len(req.ObjectSignature))
+	for i, v := range req.ObjectSignature {
+		vector[i] = float32(v)
+	}
+
+	key := req.DeviceName + "/" + req.ObjectType
+
+	sm.mux.Lock()
+	defer sm.mux.Unlock()
+
+	index, ok := sm.indexes[key]
+	if !ok {
+		if len(sm.indexes) >= sm.maxIndexes {
+			g.Log.Warn("maximum number of object signature indexes reached, skipping", req.DeviceName, req.ObjectType, sm.maxIndexes)
+			return
+		}
+		planes, ok := sm.planes[len(vector)]
+		if !ok {
+			planes = signaturePlanes(len(vector))
+			sm.planes[len(vector)] = planes
+		}
+		index = newSignatureIndex(req.DeviceName, req.ObjectType, planes)
+		sm.indexes[key] = index
+	}
+	if index.dim != len(vector) {
+		g.Log.Warn("object signature dimension mismatch, skipping index", req.DeviceName, req.ObjectType, index.dim, len(vector))
+		return
+	}
+	entry := index.add(vector, annotation)
+	sm.order = append(sm.order, signatureRef{key: key, id: entry.id, added: entry.added})
+	for len(sm.order) > sm.maxEntries {
+		sm.evictOldest()
+	}
+}
+
+// Search returns k nearest annotations to the query vector
+func (sm *SignatureManager) Search(query *SignatureQuery) ([]*SignatureMatch, error) {
+	if len(query.Vector) == 0 {
+		return nil, models.ErrMissingInputParameters
+	}
+	if len(query.Vector) > sm.maxDimension {
+		return nil, models.ErrInvalidInputParameters
+	}
+	metric := query.Metric
+	if metric == "" {
+		metric = SignatureMetricCosine
+	}
+	if metric != SignatureMetricCosine && metric != SignatureMetricL2 {
+		return nil, models.ErrInvalidInputParameters
+	}
+	k := query.K
+	if k <= 0 {
+		k = defaultSignatureK
+	}
+
+	vector := make([]float32, len(query.Vector))
+	for i, v := range query.Vector {
+		vector[i] = float32(v)
+	}
+	norm := vectorNorm(vector)
+
+	sm.mux.RLock()
+	defer sm.mux.RUnlock()
+
+	matches := make([]*SignatureMatch, 0)
+	for _, index := range sm.indexes {
+		if query.DeviceName != "" && index.deviceName != query.DeviceName {
+			continue
+		}
+		if query.ObjectType != "" && index.objectType != query.ObjectType {
+			continue
+		}
+		if index.dim != len(vector) {
+			continue
+		}
+		for _, entry := range index.candidates(vector, k, query.matches) {
+			matches = append(matches, &SignatureMatch{
+				Distance:   signatureDistance(metric, vector, norm, entry),
+				Annotation: entry.annotation,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// matches checks the time range of the query
+func (query *SignatureQuery) matches(entry *signatureEntry) bool {
+	ts := entry.annotation.StartTimestamp
+	return (query.TimestampFrom <= 0 || ts >= query.TimestampFrom) && (query.TimestampTo <= 0 || ts <= query.TimestampTo)
+}
+
+func (sm *SignatureManager) evictExpired(now time.Time) {
+	sm.mux.Lock()
+	defer sm.mux.Unlock()
+	for len(sm.order) > 0 && now.Sub(sm.order[0].added) >= sm.retention {
+		sm.evictOldest()
+	}
+}
+
+// evictOldest removes the oldest entry of all indexes (expected to be called under lock)
+func (sm *SignatureManager) evictOldest() {
+	if len(sm.order) == 0 {
+		return
+	}
+	ref := sm.order[0]
+	sm.order = sm.order[1:]
+	index, ok := sm.indexes[ref.key]
+	if !ok {
+		return
+	}
+	index.remove(ref.id)
+	if len(index.entries) == 0 {
+		delete(sm.indexes, ref.key)
+		sm.releasePlanes(index.dim)
+	}
+}
+
+// releasePlanes forgets the hyperplanes of the dimension when no index uses them (expected to be called under lock)
+func (sm *SignatureManager) releasePlanes(dim int) {
+	for _, index := range sm.indexes {
+		if index.dim == dim {
+			return
+		}
+	}
+	delete(sm.planes, dim)
+}
+
+// signaturePlanes generates signatureTables * signatureBits deterministic random hyperplanes of the dimension
+func signaturePlanes(dim int) [][]float32 {
+	rnd := rand.New(rand.NewSource(int64(dim)))
+	planes := make([][]float32, signatureTables*signatureBits)
+	for i := range planes {
+		plane := make([]float32, dim)
+		for j := range plane {
+			plane[j] = float32(rnd.NormFloat64())
+		}
+		planes[i] = plane
+	}
+	return planes
+}
+
+func newSignatureIndex(deviceName, objectType string, planes [][]float32) *signatureIndex {
+	tables := make([]map[uint32][]uint64, signatureTables)
+	for i := range tables {
+		tables[i] = make(map[uint32][]uint64)
+	}
+	return &signatureIndex{
+		deviceName: deviceName,
+		objectType: objectType,
+		dim:        len(planes[0]),
+		planes:     planes,
+		tables:     tables,
+		entries:    make(map[uint64]*signatureEntry),
+	}
+}
+
+func (si *signatureIndex) add(vector []float32, annotation *pb.AnnotateRequest) *signatureEntry {
+	entry := &signatureEntry{
+		id:         si.nextID,
+		vector:     vector,
+		norm:       vectorNorm(vector),
+		hashes:     si.hash(vector),
+		added:      time.Now(),
+		annotation: annotation,
+	}
+	si.nextID++
+	si.entries[entry.id] = entry
+	for t, h := range entry.hashes {
+		si.tables[t][h] = append(si.tables[t][h], entry.id)
+	}
+	return entry
+}
+
+func (si *signatureIndex) remove(id uint64) {
+	entry, ok := si.entries[id]
+	if !ok {
+		return
+	}
+	delete(si.entries, id)
+	for t, h := range entry.hashes {
+		bucket := si.tables[t][h]
+		for i, bid := range bucket {
+			if bid == id {
+				bucket = append(bucket[:i], bucket[i+1:]...)
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(si.tables[t], h)
+		} else {
+			si.tables[t][h] = bucket
+		}
+	}
+}
+
+// hash computes a bucket per table from signs of projections on random hyperplanes
+func (si *signatureIndex) hash(vector []float32) []uint32 {
+	hashes := make([]uint32, signatureTables)
+	for t := 0; t < signatureTables; t++ {
+		var h uint32
+		for b := 0; b < signatureBits; b++ {
+			plane := si.planes[t*signatureBits+b]
+			dot := float32(0)
+			for i, v := range vector {
+				dot += v * plane[i]
+			}
+			if dot >= 0 {
+				h |= 1 << uint(b)
+			}
+		}
+		hashes[t] = h
+	}
+	return hashes
+}
+
+// candidates collects entries accepted by the filter from query buckets and their 1-bit neighbours (multi-probe),
+// falling back to all accepted entries when not enough candidates have been found
+func (si *signatureIndex) candidates(vector []float32, k int, filter func(*signatureEntry) bool) []*signatureEntry {
+	if len(si.entries) <= k {
+		return si.all(filter)
+	}
+	seen := make(map[uint64]bool)
+	result := make([]*signatureEntry, 0)
+	for t, h := range si.hash(vector) {
+		probes := []uint32{h}
+		for b := 0; b < signatureBits; b++ {
+			probes = append(probes, h^(1<<uint(b)))
+		}
+		for _, p := range probes {
+			for _, id := range si.tables[t][p] {
+				if !seen[id] {
+					seen[id] = true
+					if entry := si.entries[id]; filter(entry) {
+						result = append(result, entry)
+					}
+				}
+			}
+		}
+	}
+	if len(result) < k {
+		return si.all(filter)
+	}
+	return result
+}
+
+func (si *signatureIndex) all(filter func(*signatureEntry) bool) []*signatureEntry {
+	result := make([]*signatureEntry, 0, len(si.entries))
+	for _, e := range si.entries {
+		if filter(e) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func signatureDistance(metric string, query []float32, queryNorm float64, entry *signatureEntry) float64 {
+	if metric == SignatureMetricL2 {
+		sum := float64(0)
+		for i, v := range query {
+			d := float64(v - entry.vector[i])
+			sum += d * d
+		}
+		return math.Sqrt(sum)
+	}
+	if queryNorm == 0 || entry.norm == 0 {
+		return 1
+	}
+	dot := float64(0)
+	for i, v := range query {
+		dot += float64(v) * float64(entry.vector[i])
+	}
+	return 1 - dot/(queryNorm*entry.norm)
+}
+
+func vectorNorm(vector []float32) float64 {
+	sum := float64(0)
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum)
+}
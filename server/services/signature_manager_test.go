@@ -0,0 +1,144 @@
+package services
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func TestSignatureSearch(t *testing.T) {
+	sm := NewSignatureManager()
+	defer sm.Stop()
+
+	rnd := rand.New(rand.NewSource(1))
+	vectors := make([][]float64, 0)
+	for i := 0; i < 500; i++ {
+		v := make([]float64, 64)
+		for j := range v {
+			v[j] = rnd.NormFloat64()
+		}
+		vectors = append(vectors, v)
+		device := "cam1"
+		if i%2 == 1 {
+			device = "cam2"
+		}
+		sm.Add(&pb.AnnotateRequest{
+			DeviceName:       device,
+			Type:             "moving",
+			ObjectType:       "person",
+			ObjectTrackingId: string(rune('a' + i%26)),
+			StartTimestamp:   int64(1000 + i),
+			ObjectSignature:  v,
+		})
+	}
+	sm.Add(&pb.AnnotateRequest{DeviceName: "cam1", Type: "moving", ObjectType: "person", StartTimestamp: 1})
+
+	// slightly perturbed query should return the original annotation first
+	query := make([]float64, 64)
+	for j := range query {
+		query[j] = vectors[42][j] + rnd.NormFloat64()*0.01
+	}
+	matches, err := sm.Search(&SignatureQuery{Vector: query, K: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 5 || matches[0].Annotation.StartTimestamp != 1042 {
+		t.Fatalf("expected nearest match to be annotation 42, got %v", matches)
+	}
+	if matches[0].Annotation.ObjectSignature != nil {
+		t.Fatal("signature shouldn't be returned with the annotation")
+	}
+
+	matches, err = sm.Search(&SignatureQuery{Vector: query, K: 3, DeviceName: "cam1", Metric: SignatureMetricL2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range matches {
+		if m.Annotation.DeviceName != "cam1" {
+			t.Fatalf("expected only cam1 matches, got %v", m.Annotation.DeviceName)
+		}
+	}
+
+	matches, err = sm.Search(&SignatureQuery{Vector: query, K: 5, TimestampFrom: 1100, TimestampTo: 1200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 5 {
+		t.Fatalf("expected 5 matches within time range, got %v", len(matches))
+	}
+	for _, m := range matches {
+		if m.Annotation.StartTimestamp < 1100 || m.Annotation.StartTimestamp > 1200 {
+			t.Fatalf("match outside of time range: %v", m.Annotation.StartTimestamp)
+		}
+	}
+
+	if _, err = sm.Search(&SignatureQuery{}); err == nil {
+		t.Fatal("expected error on empty query vector")
+	}
+}
+
+func TestSignatureMaxEntries(t *testing.T) {
+	sm := NewSignatureManager()
+	defer sm.Stop()
+	sm.maxEntries = 10
+
+	for i := 0; i < 30; i++ {
+		sm.Add(&pb.AnnotateRequest{
+			DeviceName:      "cam" + string(rune('a'+i%3)),
+			ObjectType:      "person",
+			StartTimestamp:  int64(i),
+			ObjectSignature: []float64{float64(i), 1},
+		})
+	}
+	total := 0
+	for _, index := range sm.indexes {
+		total += len(index.entries)
+	}
+	if total != 10 {
+		t.Fatalf("expected 10 signatures across all indexes, got %v", total)
+	}
+	matches, err := sm.Search(&SignatureQuery{Vector: []float64{0, 1}, K: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range matches {
+		if m.Annotation.StartTimestamp < 20 {
+			t.Fatalf("expected oldest signatures to be evicted, got %v", m.Annotation.StartTimestamp)
+		}
+	}
+}
+
+func TestSignatureLimits(t *testing.T) {
+	sm := NewSignatureManager()
+	defer sm.Stop()
+	sm.maxDimension = 8
+	sm.maxIndexes = 2
+
+	// vectors above max dimension are neither indexed nor searched
+	sm.Add(&pb.AnnotateRequest{DeviceName: "cam1", ObjectType: "person", ObjectSignature: make([]float64, 9)})
+	if len(sm.indexes) != 0 {
+		t.Fatalf("expected no index for oversized signature, got %v", len(sm.indexes))
+	}
+	if _, err := sm.Search(&SignatureQuery{Vector: make([]float64, 9)}); err == nil {
+		t.Fatal("expected error on oversized query vector")
+	}
+
+	// number of indexes is capped, hyperplanes are shared per dimension
+	for _, objectType := range []string{"person", "car", "bicycle"} {
+		sm.Add(&pb.AnnotateRequest{DeviceName: "cam1", ObjectType: objectType, ObjectSignature: []float64{1, 2, 3, 4}})
+	}
+	if len(sm.indexes) != 2 {
+		t.Fatalf("expected 2 indexes, got %v", len(sm.indexes))
+	}
+	if len(sm.planes) != 1 || &sm.indexes["cam1/person"].planes[0][0] != &sm.indexes["cam1/car"].planes[0][0] {
+		t.Fatal("expected hyperplanes shared by indexes of the same dimension")
+	}
+
+	// hyperplanes are released with the last index of the dimension
+	sm.evictExpired(time.Now().Add(sm.retention))
+	if len(sm.indexes) != 0 || len(sm.planes) != 0 {
+		t.Fatalf("expected all indexes and hyperplanes released, got %v indexes, %v planes", len(sm.indexes), len(sm.planes))
+	}
+}
@@ -15,6 +15,8 @@
 package services
 
 import (
+	"time"
+
 	g "github.com/chryscloud/video-edge-ai-proxy/globals"
 	badger "github.com/dgraph-io/badger/v2"
 )
@@ -42,6 +44,15 @@ func (s *Storage) Put(prefix, key string, value []byte) error {
 	return err
 }
 
+// PutWithTTL stores the value which expires automatically after ttl
+func (s *Storage) PutWithTTL(prefix, key string, value []byte, ttl time.Duration) error {
+	err := s.db.Update(func(txn *badger.Txn) error {
+		e := badger.NewEntry([]byte(prefix+key), value).WithTTL(ttl)
+		return txn.SetEntry(e)
+	})
+	return err
+}
+
 func (s *Storage) Get(prefix, key string) ([]byte, error) {
 	var valCopy []byte
 	err := s.db.View(func(txn *badger.Txn) error {
@@ -88,3 +99,69 @@ func (s *Storage) List(prefix string) (map[string][]byte, error) {
 	})
 	return results, err
 }
+
+// ListRange lists the values with keys between prefix+from and prefix+to (both inclusive) in key order
+func (s *Storage) ListRange(prefix, from, to string) (map[string][]byte, error) {
+	results := make(map[string][]byte, 0)
+	err := s.IterateRange(prefix, from, to, false, func(key string, value []byte) bool {
+		results[key] = value
+		return true
+	})
+	return results, err
+}
+
+// IterateRange calls fn with the values with keys between prefix+from and prefix+to (both inclusive, all following keys when to is empty)
+// in key order (or reverse key order) until fn returns false
+func (s *Storage) IterateRange(prefix, from, to string, reverse bool, fn func(key string, value []byte) bool) error {
+	return s.iterateRange(prefix, from, to, reverse, true, func(item *badger.Item) (bool, error) {
+		v, err := item.ValueCopy(nil)
+		if err != nil {
+			return false, err
+		}
+		return fn(string(item.Key()), v), nil
+	})
+}
+
+// IterateKeysRange is IterateRange without reading the values
+func (s *Storage) IterateKeysRange(prefix, from, to string, reverse bool, fn func(key string) bool) error {
+	return s.iterateRange(prefix, from, to, reverse, false, func(item *badger.Item) (bool, error) {
+		return fn(string(item.Key())), nil
+	})
+}
+
+func (s *Storage) iterateRange(prefix, from, to string, reverse bool, values bool, fn func(item *badger.Item) (bool, error)) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchSize = 128
+		opts.PrefetchValues = values
+		opts.Reverse = reverse
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		pfix := []byte(prefix)
+		first, last := prefix+from, prefix+to
+		if to == "" {
+			// past all keys with the prefix
+			last = prefix + "\xff"
+		}
+		seek := first
+		if reverse {
+			seek = last
+		}
+		for it.Seek([]byte(seek)); it.ValidForPrefix(pfix); it.Next() {
+			item := it.Item()
+			k := string(item.Key())
+			if k > last || k < first {
+				break
+			}
+			next, err := fn(item)
+			if err != nil {
+				g.Log.Error("failed to iterate in db", err)
+				return err
+			}
+			if !next {
+				break
+			}
+		}
+		return nil
+	})
+}
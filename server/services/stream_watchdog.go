@@ -0,0 +1,238 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/chryscloud/video-edge-ai-proxy/utils"
+	"github.com/go-redis/redis/v7"
+)
+
+const (
+	defaultWatchdogCheckInterval     = time.Second * 5
+	defaultWatchdogStallTimeout      = time.Second * 30
+	defaultWatchdogRestartBackoff    = time.Second * 30
+	defaultWatchdogRestartMaxBackoff = time.Minute * 10
+)
+
+// StreamWatchdog - detects camera streams that stopped producing packets/frames while the container is still running
+// and restarts them with backoff
+type StreamWatchdog struct {
+	storage        *Storage
+	processManager *ProcessManager
+	rdb            *redis.Client
+	streams        map[string]*watchedStream
+}
+
+type watchedStream struct {
+	health      string
+	restarts    int       // consecutive restarts without the stream recovering
+	nextRestart time.Time // earliest time of the next restart
+}
+
+func NewStreamWatchdog(storage *Storage, processManager *ProcessManager, rdb *redis.Client) *StreamWatchdog {
+	return &StreamWatchdog{
+		storage:        storage,
+		processManager: processManager,
+		rdb:            rdb,
+		streams:        make(map[string]*watchedStream),
+	}
+}
+
+// Start checking stream health in the background
+func (sw *StreamWatchdog) Start() {
+	interval := defaultWatchdogCheckInterval
+	if g.Conf.Watchdog != nil && g.Conf.Watchdog.CheckIntervalMs > 0 {
+		interval = time.Duration(g.Conf.Watchdog.CheckIntervalMs) * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		for t := range ticker.C {
+			sw.check(t)
+		}
+	}()
+}
+
+func (sw *StreamWatchdog) check(now time.Time) {
+	objects, err := sw.storage.List(models.PrefixRTSPProcess)
+	if err != nil {
+		g.Log.Error("watchdog failed to list devices", err)
+		return
+	}
+
+	cl := sw.processManager.containerRuntime
+
+	watched := make(map[string]bool)
+	for _, v := range objects {
+		var process models.StreamProcess
+		if err := json.Unmarshal(v, &process); err != nil {
+			g.Log.Error("watchdog failed to unmarshal stored process", err)
+			continue
+		}
+		if process.Paused {
+			continue
+		}
+		c, err := sw.processManager.stateCache.Get(process.Name)
+		if err != nil || c.State == nil || !c.State.Running || c.State.Paused {
+			continue
+		}
+		startedAt, err := time.Parse(time.RFC3339Nano, c.State.StartedAt)
+		if err != nil {
+			g.Log.Warn("watchdog failed to parse container start time", process.Name, err)
+			continue
+		}
+		watched[process.Name] = true
+
+		stream, ok := sw.streams[process.Name]
+		if !ok {
+			stream = &watchedStream{}
+			sw.streams[process.Name] = stream
+		}
+
+		newest, observed := sw.newestEntry(process.Name, now)
+		health := ""
+		if observed {
+			health = streamHealth(now, startedAt, newest, watchdogStallTimeout())
+		}
+		if health != stream.health {
+			sw.setHealth(process.Name, stream, health)
+		}
+
+		switch health {
+		case models.StreamHealthOK:
+			stream.restarts = 0
+		case models.StreamHealthStalled, models.StreamHealthNoSignal:
+			if g.Conf.Watchdog != nil && !g.Conf.Watchdog.Restart {
+				continue
+			}
+			if now.Before(stream.nextRestart) {
+				continue
+			}
+			g.Log.Warn("stream watchdog restarting camera", process.Name, health, stream.restarts)
+			if err := cl.ContainerRestart(c.ID, time.Second*10); err != nil {
+				g.Log.Error("stream watchdog failed to restart camera", process.Name, err)
+			}
+			stream.restarts++
+			stream.nextRestart = now.Add(restartBackoff(stream.restarts))
+		}
+	}
+
+	// forget removed or stopped cameras
+	for name, stream := range sw.streams {
+		if !watched[name] {
+			if stream.health != "" {
+				sw.setHealth(name, stream, "")
+			}
+			delete(sw.streams, name)
+		}
+	}
+}
+
+func (sw *StreamWatchdog) setHealth(deviceID string, stream *watchedStream, health string) {
+	g.Log.Info("stream health changed", deviceID, stream.health, health)
+	stream.health = health
+	sw.processManager.SetStreamHealth(deviceID, health)
+	if health != "" {
+		err := utils.PublishToRedis(sw.rdb, deviceID, models.MQTTProcessOperation(models.DeviceOperationHealth), models.ProcessTypeRTSP, nil)
+		if err != nil {
+			g.Log.Error("failed to publish stream health change", deviceID, err)
+		}
+	}
+}
+
+// newestEntry returns the timestamp (ms) of the newest packet or frame in redis. Packets are only stored with
+// in-memory buffering enabled and frames are decoded only on request, so false is returned when neither can be observed.
+func (sw *StreamWatchdog) newestEntry(deviceID string, now time.Time) (int64, bool) {
+	newest := int64(0)
+	observed := false
+
+	if g.Conf.Buffer != nil && g.Conf.Buffer.InMemory > 0 {
+		observed = true
+		if ts := sw.newestStreamEntry(models.RedisInMemoryQueue + deviceID); ts > newest {
+			newest = ts
+		}
+	}
+
+	lastQuery, err := sw.rdb.HGet(models.RedisLastAccessPrefix+deviceID, models.RedisLastAccessQueryTimeKey).Result()
+	if err == nil {
+		queried, pErr := strconv.ParseInt(lastQuery, 10, 64)
+		if pErr == nil && now.UnixNano()/int64(time.Millisecond)-queried < int64(watchdogStallTimeout()/time.Millisecond) {
+			observed = true
+			if ts := sw.newestStreamEntry(deviceID); ts > newest {
+				newest = ts
+			}
+		}
+	}
+	return newest, observed
+}
+
+func (sw *StreamWatchdog) newestStreamEntry(streamName string) int64 {
+	msgs, err := sw.rdb.XRevRangeN(streamName, "+", "-", 1).Result()
+	if err != nil || len(msgs) == 0 {
+		return 0
+	}
+	return redisIDTimestamp(msgs[0].ID)
+}
+
+// streamHealth from the newest packet/frame timestamp (ms). Streams get one stall timeout of grace after the container started.
+func streamHealth(now time.Time, startedAt time.Time, newest int64, stallTimeout time.Duration) string {
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	startedMs := startedAt.UnixNano() / int64(time.Millisecond)
+	timeoutMs := int64(stallTimeout / time.Millisecond)
+
+	if newest < startedMs {
+		if nowMs-startedMs > timeoutMs {
+			return models.StreamHealthNoSignal
+		}
+		return ""
+	}
+	if nowMs-newest > timeoutMs {
+		return models.StreamHealthStalled
+	}
+	return models.StreamHealthOK
+}
+
+// restartBackoff doubles the wait after every consecutive restart
+func restartBackoff(restarts int) time.Duration {
+	backoff := defaultWatchdogRestartBackoff
+	maxBackoff := defaultWatchdogRestartMaxBackoff
+	if g.Conf.Watchdog != nil {
+		if g.Conf.Watchdog.RestartBackoffMs > 0 {
+			backoff = time.Duration(g.Conf.Watchdog.RestartBackoffMs) * time.Millisecond
+		}
+		if g.Conf.Watchdog.RestartMaxBackoffMs > 0 {
+			maxBackoff = time.Duration(g.Conf.Watchdog.RestartMaxBackoffMs) * time.Millisecond
+		}
+	}
+	for i := 1; i < restarts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func watchdogStallTimeout() time.Duration {
+	if g.Conf.Watchdog != nil && g.Conf.Watchdog.StallTimeoutMs > 0 {
+		return time.Duration(g.Conf.Watchdog.StallTimeoutMs) * time.Millisecond
+	}
+	return defaultWatchdogStallTimeout
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+func TestStreamHealth(t *testing.T) {
+	now := time.Now()
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	timeout := time.Second * 30
+
+	startedAt := now.Add(-time.Minute)
+	if h := streamHealth(now, startedAt, ms(now.Add(-time.Second)), timeout); h != models.StreamHealthOK {
+		t.Fatalf("expected ok, got %v", h)
+	}
+	if h := streamHealth(now, startedAt, ms(now.Add(-time.Second*45)), timeout); h != models.StreamHealthStalled {
+		t.Fatalf("expected stalled, got %v", h)
+	}
+	// entries from before the container started don't count
+	if h := streamHealth(now, startedAt, ms(now.Add(-time.Minute*2)), timeout); h != models.StreamHealthNoSignal {
+		t.Fatalf("expected no-signal, got %v", h)
+	}
+	if h := streamHealth(now, now.Add(-time.Second*10), 0, timeout); h != "" {
+		t.Fatalf("expected unknown health within start grace period, got %v", h)
+	}
+}
+
+func TestStreamWatchdogBackoff(t *testing.T) {
+	expected := []time.Duration{time.Second * 30, time.Minute, time.Minute * 2, time.Minute * 4, time.Minute * 8, time.Minute * 10, time.Minute * 10}
+	for i, e := range expected {
+		if b := restartBackoff(i + 1); b != e {
+			t.Fatalf("restart %v: expected backoff %v, got %v", i+1, e, b)
+		}
+	}
+}
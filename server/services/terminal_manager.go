@@ -0,0 +1,142 @@
+// Copyright 2020 Wearless Tech Inc All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+)
+
+// TerminalManager - interactive terminal sessions into camera and app containers managed by the edge proxy
+type TerminalManager struct {
+	storage          *Storage
+	containerRuntime ContainerRuntime
+	stateCache       *ProcessStateCache
+	auditManager     *AuditManager
+}
+
+// TerminalSession - terminal attached to the shell in the container. Closing the session records its end in the audit trail.
+type TerminalSession struct {
+	ExecSession
+	manager     *TerminalManager
+	processType string
+	name        string
+	actor       string
+	started     time.Time
+	once        sync.Once
+}
+
+func NewTerminalManager(storage *Storage, containerRuntime ContainerRuntime, stateCache *ProcessStateCache, auditManager *AuditManager) *TerminalManager {
+	return &TerminalManager{
+		storage:          storage,
+		containerRuntime: containerRuntime,
+		stateCache:       stateCache,
+		auditManager:     auditManager,
+	}
+}
+
+// Enabled - terminal sessions are allowed unless disabled in conf.yaml
+func (tm *TerminalManager) Enabled() bool {
+	return g.Conf.Terminal == nil || g.Conf.Terminal.Enabled
+}
+
+// Open starts the shell in the running camera (models.ProcessTypeRTSP) or app (models.ProcessTypeApplication) container
+func (tm *TerminalManager) Open(ctx context.Context, processType string, name string, actor string) (*TerminalSession, error) {
+	if !tm.Enabled() {
+		return nil, models.ErrForbidden
+	}
+	prefix := models.PrefixRTSPProcess
+	if processType == models.ProcessTypeApplication {
+		prefix = models.PrefixAppProcess
+	} else if processType != models.ProcessTypeRTSP {
+		return nil, models.ErrInvalidInputParameters
+	}
+	if _, err := tm.storage.Get(prefix, name); err != nil {
+		return nil, models.ErrProcessNotFoundDatastore
+	}
+	c, err := tm.stateCache.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	// containers created by the edge proxy are labeled, adopted camera containers run the camera image
+	managed := c.Config != nil && (c.Config.Labels[models.ContainerLabelManaged] == processType || (processType == models.ProcessTypeRTSP && isCameraImage(c.Config.Image)))
+	if !managed {
+		g.Log.Warn("terminal refused for container not managed by the edge proxy", name)
+		return nil, models.ErrForbidden
+	}
+	if c.State == nil || !c.State.Running || c.State.Paused {
+		return nil, models.ErrProcessNotRunning
+	}
+
+	shell := models.DefaultTerminalShell
+	if g.Conf.Terminal != nil && g.Conf.Terminal.Shell != "" {
+		shell = g.Conf.Terminal.Shell
+	}
+	record := &models.AuditRecord{
+		Actor:      actor,
+		Source:     models.AuditSourceREST,
+		Action:     models.AuditActionTerminalStart,
+		TargetType: processType,
+		Target:     name,
+		Result:     models.AuditResultSuccess,
+	}
+	exec, err := tm.containerRuntime.ContainerExec(ctx, c.ID, []string{shell})
+	if err != nil {
+		g.Log.Error("failed to start terminal session", name, err)
+		record.Result = models.AuditResultFailed
+		record.Message = err.Error()
+		tm.auditManager.Record(record)
+		return nil, err
+	}
+	if err := tm.auditManager.Record(record); err != nil {
+		// sessions aren't allowed without audit trail
+		exec.Close()
+		return nil, err
+	}
+	g.Log.Info("terminal session started", processType, name, actor)
+
+	return &TerminalSession{
+		ExecSession: exec,
+		manager:     tm,
+		processType: processType,
+		name:        name,
+		actor:       actor,
+		started:     time.Now(),
+	}, nil
+}
+
+// Close ends the shell and records the end of the session (only the first call is recorded)
+func (ts *TerminalSession) Close() error {
+	var err error
+	ts.once.Do(func() {
+		err = ts.ExecSession.Close()
+		duration := time.Since(ts.started).Round(time.Second)
+		g.Log.Info("terminal session ended", ts.processType, ts.name, ts.actor, duration)
+		ts.manager.auditManager.Record(&models.AuditRecord{
+			Actor:      ts.actor,
+			Source:     models.AuditSourceREST,
+			Action:     models.AuditActionTerminalEnd,
+			TargetType: ts.processType,
+			Target:     ts.name,
+			Result:     models.AuditResultSuccess,
+			Message:    "session duration " + duration.String(),
+		})
+	})
+	return err
+}
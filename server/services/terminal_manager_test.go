@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	g "github.com/chryscloud/video-edge-ai-proxy/globals"
+	"github.com/chryscloud/video-edge-ai-proxy/models"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestTerminalSession(t *testing.T) {
+	defer setupMemoryRuntimeConf()()
+
+	db, err := setupDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storage := NewStorage(db)
+
+	cameraImage := models.CameraTypeToImageTag["rtsp"]
+	rt := NewMemoryRuntime()
+	rt.AddImage(cameraImage+":0.0.7", "someone/miner:latest")
+	stateCache := NewProcessStateCache(rt)
+	pm := NewProcessManager(storage, nil, rt, stateCache, testSecretManager(t))
+	auditManager := NewAuditManager(storage)
+	tm := NewTerminalManager(storage, rt, stateCache, auditManager)
+
+	process := &models.StreamProcess{Name: "frontdoor", RTSPEndpoint: "rtsp://frontdoor"}
+	imageUpgrade := &models.ImageUpgrade{HasImage: true, Name: cameraImage, CurrentVersion: "0.0.7"}
+	if err := pm.Start(process, imageUpgrade, true); err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := tm.Open(context.Background(), models.ProcessTypeRTSP, "frontdoor", "10.0.0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Resize(40, 120); err != nil {
+		t.Fatal(err)
+	}
+	if h, w := session.ExecSession.(*MemoryExecSession).Size(); h != 40 || w != 120 {
+		t.Fatalf("expected resized terminal, got %dx%d", w, h)
+	}
+	go session.Write([]byte("ls\n"))
+	buf := make([]byte, 16)
+	n, err := session.Read(buf)
+	if err != nil || string(buf[:n]) != "ls\n" {
+		t.Fatalf("expected echoed input, got %q %v", buf[:n], err)
+	}
+	session.Close()
+	session.Close()
+
+	records, err := auditManager.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Action != models.AuditActionTerminalEnd || records[1].Action != models.AuditActionTerminalStart {
+		t.Fatalf("expected terminal start and end audit records, got %v", records)
+	}
+	if records[1].Actor != "10.0.0.5" || records[1].Target != "frontdoor" || records[1].TargetType != models.ProcessTypeRTSP {
+		t.Fatalf("unexpected audit record %v", records[1])
+	}
+
+	// camera is not an app
+	if _, err := tm.Open(context.Background(), models.ProcessTypeApplication, "frontdoor", "10.0.0.5"); err != models.ErrProcessNotFoundDatastore {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	// unmanaged container with a record of the same name
+	if err := rt.ContainerCreate("miner", &container.Config{Image: "someone/miner:latest"}, &container.HostConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	rt.ContainerStart("miner")
+	storage.Put(models.PrefixAppProcess, "miner", []byte(`{"name":"miner"}`))
+	if _, err := tm.Open(context.Background(), models.ProcessTypeApplication, "miner", "10.0.0.5"); err != models.ErrForbidden {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+
+	// stopped container
+	rt.ContainerStop("frontdoor", nil)
+	stateCache.Refresh("frontdoor")
+	if _, err := tm.Open(context.Background(), models.ProcessTypeRTSP, "frontdoor", "10.0.0.5"); err != models.ErrProcessNotRunning {
+		t.Fatalf("expected not running, got %v", err)
+	}
+
+	g.Conf.Terminal = &g.TerminalSubconfig{Enabled: false}
+	if _, err := tm.Open(context.Background(), models.ProcessTypeRTSP, "frontdoor", "10.0.0.5"); err != models.ErrForbidden {
+		t.Fatalf("expected disabled terminal, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+	"github.com/golang/protobuf/proto"
+)
+
+// AnnotationCenter returns the object coordinate if given, otherwise the center of the bounding box
+func AnnotationCenter(req *pb.AnnotateRequest) (*pb.Coordinate, bool) {
+	if req.ObjectCoordinate != nil {
+		return req.ObjectCoordinate, true
+	}
+	if req.ObjectBoudingBox != nil {
+		bb := req.ObjectBoudingBox
+		return &pb.Coordinate{X: float64(bb.Left) + float64(bb.Width)/2, Y: float64(bb.Top) + float64(bb.Height)/2}, true
+	}
+	return nil, false
+}
+
+// ExpandAnnotationObjects returns one single-object annotation per detected object (frame level fields copied,
+// object attributes and labels merged over request attributes and labels). Requests without objects are returned as they are.
+func ExpandAnnotationObjects(req *pb.AnnotateRequest) []*pb.AnnotateRequest {
+	if len(req.Objects) == 0 {
+		return []*pb.AnnotateRequest{req}
+	}
+
+	frame := proto.Clone(req).(*pb.AnnotateRequest)
+	frame.Objects = nil
+
+	expanded := make([]*pb.AnnotateRequest, 0, len(req.Objects))
+	for _, o := range req.Objects {
+		if o == nil {
+			continue
+		}
+		obj := proto.Clone(o).(*pb.DetectedObject)
+		single := proto.Clone(frame).(*pb.AnnotateRequest)
+		single.ObjectType = obj.ObjectType
+		single.ObjectId = obj.ObjectId
+		single.ObjectTrackingId = obj.ObjectTrackingId
+		single.Confidence = obj.Confidence
+		single.ObjectBoudingBox = obj.ObjectBoudingBox
+		single.ObjectCoordinate = obj.ObjectCoordinate
+		single.Mask = obj.Mask
+		single.ObjectSignature = obj.ObjectSignature
+		single.Labels = mergeLabels(single.Labels, obj.Labels)
+		if len(obj.Attributes) > 0 {
+			if single.Attributes == nil {
+				single.Attributes = make(map[string]string, len(obj.Attributes))
+			}
+			for k, v := range obj.Attributes {
+				single.Attributes[k] = v
+			}
+		}
+		expanded = append(expanded, single)
+	}
+	return expanded
+}
+
+// mergeLabels returns object labels followed by frame labels not classified on the object
+func mergeLabels(frameLabels, objectLabels []*pb.Classification) []*pb.Classification {
+	if len(frameLabels) == 0 {
+		return objectLabels
+	}
+	labels := make([]*pb.Classification, 0, len(objectLabels)+len(frameLabels))
+	seen := make(map[string]bool, len(objectLabels))
+	for _, l := range objectLabels {
+		if l == nil {
+			continue
+		}
+		seen[l.Label] = true
+		labels = append(labels, l)
+	}
+	for _, l := range frameLabels {
+		if l != nil && !seen[l.Label] {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
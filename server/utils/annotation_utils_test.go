@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	pb "github.com/chryscloud/video-edge-ai-proxy/proto"
+)
+
+func TestExpandAnnotationObjectsLabels(t *testing.T) {
+	req := &pb.AnnotateRequest{
+		DeviceName: "cam1",
+		Type:       "moving",
+		Labels:     []*pb.Classification{{Label: "night", Confidence: 0.8}, {Label: "person", Confidence: 0.5}},
+		Objects: []*pb.DetectedObject{
+			{ObjectType: "person", Labels: []*pb.Classification{{Label: "person", Confidence: 0.9}}},
+			{ObjectType: "car"},
+		},
+	}
+	expanded := ExpandAnnotationObjects(req)
+	if len(expanded) != 2 {
+		t.Fatalf("expected 2 annotations, got %v", len(expanded))
+	}
+
+	// object labels win over frame labels with the same name
+	person := expanded[0].Labels
+	if len(person) != 2 || person[0].Label != "person" || person[0].Confidence != 0.9 || person[1].Label != "night" {
+		t.Fatalf("expected object label and frame label, got %v", person)
+	}
+	// objects without labels keep the frame classifications
+	car := expanded[1].Labels
+	if len(car) != 2 || car[0].Label != "night" || car[1].Label != "person" || car[1].Confidence != 0.5 {
+		t.Fatalf("expected frame labels, got %v", car)
+	}
+	if len(req.Labels) != 2 || len(req.Objects[0].Labels) != 1 {
+		t.Fatal("expected request to be unchanged")
+	}
+}
@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// 5x7 bitmap font used for overlay labels (lower case letters are drawn as upper case)
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'A': {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B': {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C': {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D': {0b11100, 0b10010, 0b10001, 0b10001, 0b10001, 0b10010, 0b11100},
+	'E': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G': {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H': {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I': {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J': {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K': {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L': {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M': {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N': {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O': {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P': {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q': {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R': {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S': {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T': {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W': {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X': {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y': {0b10001, 0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100},
+	'Z': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	' ': {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b00000},
+	'.': {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b01100, 0b01100},
+	':': {0b00000, 0b01100, 0b01100, 0b00000, 0b01100, 0b01100, 0b00000},
+	'-': {0b00000, 0b00000, 0b00000, 0b11111, 0b00000, 0b00000, 0b00000},
+	'_': {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b11111},
+	'#': {0b01010, 0b01010, 0b11111, 0b01010, 0b11111, 0b01010, 0b01010},
+	'%': {0b11000, 0b11001, 0b00010, 0b00100, 0b01000, 0b10011, 0b00011},
+	'/': {0b00000, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b00000},
+	'?': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b00000, 0b00100},
+}
+
+// DrawLine draws a line between two points (Bresenham). The line is clipped to the image bounds first,
+// so only the visible part of the segment is iterated.
+func DrawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA, thickness int) {
+	x0, y0, x1, y1, visible := clipLine(img.Rect.Inset(-thickness), x0, y0, x1, y1)
+	if !visible {
+		return
+	}
+	dx := absInt(x1 - x0)
+	dy := -absInt(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		fillSquare(img, x0, y0, thickness, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+const (
+	outcodeLeft = 1 << iota
+	outcodeRight
+	outcodeTop
+	outcodeBottom
+)
+
+// clipLine clips the segment to the rectangle (Cohen-Sutherland), returns false if the segment is outside of it
+func clipLine(r image.Rectangle, x0, y0, x1, y1 int) (int, int, int, int, bool) {
+	if r.Empty() {
+		return 0, 0, 0, 0, false
+	}
+	// intersections are computed in floating point, coordinates can be arbitrary large
+	minX, minY := float64(r.Min.X), float64(r.Min.Y)
+	maxX, maxY := float64(r.Max.X-1), float64(r.Max.Y-1)
+	outcode := func(x, y float64) int {
+		code := 0
+		if x < minX {
+			code |= outcodeLeft
+		} else if x > maxX {
+			code |= outcodeRight
+		}
+		if y < minY {
+			code |= outcodeTop
+		} else if y > maxY {
+			code |= outcodeBottom
+		}
+		return code
+	}
+	fx0, fy0, fx1, fy1 := float64(x0), float64(y0), float64(x1), float64(y1)
+	code0, code1 := outcode(fx0, fy0), outcode(fx1, fy1)
+	for {
+		if code0|code1 == 0 {
+			return int(math.Round(fx0)), int(math.Round(fy0)), int(math.Round(fx1)), int(math.Round(fy1)), true
+		}
+		if code0&code1 != 0 {
+			return 0, 0, 0, 0, false
+		}
+		code := code0
+		if code == 0 {
+			code = code1
+		}
+		var x, y float64
+		switch {
+		case code&outcodeBottom != 0:
+			x, y = fx0+(fx1-fx0)*(maxY-fy0)/(fy1-fy0), maxY
+		case code&outcodeTop != 0:
+			x, y = fx0+(fx1-fx0)*(minY-fy0)/(fy1-fy0), minY
+		case code&outcodeRight != 0:
+			x, y = maxX, fy0+(fy1-fy0)*(maxX-fx0)/(fx1-fx0)
+		default:
+			x, y = minX, fy0+(fy1-fy0)*(minX-fx0)/(fx1-fx0)
+		}
+		if code == code0 {
+			fx0, fy0 = x, y
+			code0 = outcode(fx0, fy0)
+		} else {
+			fx1, fy1 = x, y
+			code1 = outcode(fx1, fy1)
+		}
+	}
+}
+
+// DrawRect draws the outline of the rectangle
+func DrawRect(img *image.RGBA, r image.Rectangle, c color.RGBA, thickness int) {
+	DrawPolygon(img, []image.Point{r.Min, {X: r.Max.X, Y: r.Min.Y}, r.Max, {X: r.Min.X, Y: r.Max.Y}}, c, thickness)
+}
+
+// DrawPolygon draws the outline of the closed polygon
+func DrawPolygon(img *image.RGBA, points []image.Point, c color.RGBA, thickness int) {
+	for i := range points {
+		from := points[i]
+		to := points[(i+1)%len(points)]
+		DrawLine(img, from.X, from.Y, to.X, to.Y, c, thickness)
+	}
+}
+
+// DrawText draws the text with its top left corner at x, y on a filled background
+func DrawText(img *image.RGBA, x, y int, text string, c, background color.RGBA, scale int) {
+	if scale < 1 {
+		scale = 1
+	}
+	text = strings.ToUpper(text)
+	runes := []rune(text)
+	w := (len(runes)*(glyphWidth+1) + 1) * scale
+	h := (glyphHeight + 2) * scale
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			setPixel(img, px, py, background)
+		}
+	}
+	for i, r := range runes {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		gx := x + (i*(glyphWidth+1)+1)*scale
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]&(1<<uint(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				for sy := 0; sy < scale; sy++ {
+					for sx := 0; sx < scale; sx++ {
+						setPixel(img, gx+col*scale+sx, y+(row+1)*scale+sy, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TextSize returns width and height of the text drawn with DrawText
+func TextSize(text string, scale int) (int, int) {
+	if scale < 1 {
+		scale = 1
+	}
+	return (len([]rune(text))*(glyphWidth+1) + 1) * scale, (glyphHeight + 2) * scale
+}
+
+func fillSquare(img *image.RGBA, x, y, size int, c color.RGBA) {
+	half := size / 2
+	for py := y - half; py < y-half+size; py++ {
+		for px := x - half; px < x-half+size; px++ {
+			setPixel(img, px, py, c)
+		}
+	}
+}
+
+func setPixel(img *image.RGBA, x, y int, c color.RGBA) {
+	if !(image.Point{X: x, Y: y}.In(img.Rect)) {
+		return
+	}
+	img.SetRGBA(x, y, c)
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
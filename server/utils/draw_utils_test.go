@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawLineClipping(t *testing.T) {
+	r := image.Rect(0, 0, 10, 10)
+	x0, y0, x1, y1, ok := clipLine(r, -1000000000, 5, 1000000000, 5)
+	if !ok || x0 != 0 || y0 != 5 || x1 != 9 || y1 != 5 {
+		t.Fatalf("expected horizontal line clipped to 0,5 - 9,5, got %v,%v - %v,%v", x0, y0, x1, y1)
+	}
+	if _, _, _, _, ok := clipLine(r, -100, -100, 100, -50); ok {
+		t.Fatal("expected line above the image to be rejected")
+	}
+
+	img := image.NewRGBA(r)
+	red := color.RGBA{R: 255, A: 255}
+	DrawLine(img, -1<<40, -1<<40, 1<<40, 1<<40, red, 1)
+	for i := 0; i < 10; i++ {
+		if img.RGBAAt(i, i) != red {
+			t.Fatalf("expected diagonal pixel at %v,%v", i, i)
+		}
+	}
+}
@@ -103,6 +103,16 @@ func DetachGatewayDevice(gatewayID string, client qtt.Client, mqttMsg *models.MQ
 	return publishTelemetry(gatewayID, client, mqttMsg)
 }
 
+// PublishRuleEvent publishes the event rule payload to the gateways events subfolder (topic)
+func PublishRuleEvent(gatewayID string, client qtt.Client, topic string, payload []byte) error {
+	events := fmt.Sprintf("/devices/%v/events/%v", gatewayID, topic)
+	if token := client.Publish(events, 1, false, payload); token.WaitTimeout(time.Second*5) && token.Error() != nil {
+		g.Log.Error("failed to publish event rule payload", topic, token.Error())
+		return token.Error()
+	}
+	return nil
+}
+
 // mqttLocalPublish publishing to redis pub/sub to be then forwarded to Chrysalis Cloud over MQTT protocol
 func PublishToRedis(rdb *redis.Client, deviceID string, operation models.MQTTProcessOperation, processType string, customMessage []byte) error {
 	// publish to chrysalis cloud the change
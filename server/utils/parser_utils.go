@@ -3,11 +3,33 @@ package utils
 import (
 	"errors"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/chryscloud/video-edge-ai-proxy/models"
 )
 
+// password in URLs within free text (e.g. logged payloads)
+var urlPasswordRegex = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@"']*:)[^@\s"'/]+@`)
+
+// RedactURL replaces the password in the URL (user info) with xxxxx
+func RedactURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.User == nil {
+		return endpoint
+	}
+	if _, ok := u.User.Password(); !ok {
+		return endpoint
+	}
+	u.User = url.UserPassword(u.User.Username(), models.RedactedSecret)
+	return u.String()
+}
+
+// RedactURLs replaces passwords of all URLs within the text (safe for logging)
+func RedactURLs(text string) string {
+	return urlPasswordRegex.ReplaceAllString(text, "${1}"+models.RedactedSecret+"@")
+}
+
 // ParseRTMPKey takes the complete RTMP url and extracts the streaming key
 func ParseRTMPKey(rtmpURL string) (string, error) {
 	u, err := url.Parse(rtmpURL)